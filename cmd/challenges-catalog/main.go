@@ -0,0 +1,98 @@
+// Package main provides the challenges-catalog CLI. It prints
+// the assertion types known to the assertion engine, with their
+// value schemas and examples, for bank authors.
+//
+// Usage:
+//
+//	challenges-catalog [-format markdown|json] [-plugins panoptic]
+//
+// Types of plugins with heavier dependencies (such as userflow)
+// are listed by the Catalog of an engine they are registered
+// with.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/panoptic"
+)
+
+// Exit codes.
+const (
+	exitSuccess = 0
+	exitError   = 2
+)
+
+// plugins registers the evaluators of the plugins the catalog
+// can include, by plugin name.
+var plugins = map[string]func(*assertion.DefaultEngine) error{
+	panoptic.PluginName: panoptic.RegisterEvaluators,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run prints the catalog and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("challenges-catalog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String(
+		"format", "markdown",
+		"Output format (markdown, json)",
+	)
+	names := fs.String(
+		"plugins", panoptic.PluginName,
+		"Comma-separated plugins whose assertion types are "+
+			"included; empty lists the built-in types only",
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: challenges-catalog [flags]")
+		fs.PrintDefaults()
+		return exitError
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format: %s\n", *format)
+		return exitError
+	}
+
+	engine := assertion.NewEngine()
+	for _, name := range strings.Split(*names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		register, ok := plugins[name]
+		if !ok {
+			fmt.Fprintf(stderr, "unknown plugin: %s\n", name)
+			return exitError
+		}
+		if err := register(engine); err != nil {
+			fmt.Fprintf(stderr, "register plugin %s: %v\n", name, err)
+			return exitError
+		}
+	}
+
+	var err error
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(engine.Catalog())
+	} else {
+		_, err = io.WriteString(stdout, assertion.CatalogMarkdown(engine.Catalog()))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "write catalog: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
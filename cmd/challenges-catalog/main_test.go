@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"digital.vasic.challenges/pkg/assertion"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_Markdown(t *testing.T) {
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitSuccess, run(nil, &out, &errOut))
+	assert.Contains(t, out.String(), "# Assertion Catalog")
+	assert.Contains(t, out.String(), "### `between`")
+	assert.Contains(t, out.String(), "## Plugin panoptic")
+	assert.Contains(t, out.String(), "latency | between(50ms, 250ms)")
+}
+
+func TestRun_JSON(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := run([]string{"-format", "json", "-plugins", ""}, &out, &errOut)
+	require.Equal(t, exitSuccess, code)
+
+	var descs []assertion.Descriptor
+	require.NoError(t, json.Unmarshal(out.Bytes(), &descs))
+	assert.Len(t, descs, 39)
+	for _, d := range descs {
+		assert.Empty(t, d.Plugin, d.Name)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitError, run([]string{"-format", "xml"}, &out, &errOut))
+	assert.Equal(t, exitError, run([]string{"-plugins", "bogus"}, &out, &errOut))
+	assert.Contains(t, errOut.String(), "unknown plugin: bogus")
+	assert.Equal(t, exitError, run([]string{"extra"}, &out, &errOut))
+}
@@ -0,0 +1,145 @@
+// Package main provides the challenges-compare CLI. It compares
+// a baseline run with a current run and exits non-zero when a
+// challenge regressed, for gating merges.
+//
+// Usage:
+//
+//	challenges-compare [-format markdown|html|json] [-output file] \
+//	    [-duration-threshold 0.2] [-min-duration-delta 100ms] \
+//	    [-metric-threshold 0.1] [-higher-is-better m1,m2] \
+//	    [-fail-on-performance] <baseline.json> <current.json>
+//
+// Both files may be master summaries written by
+// report.SaveMasterSummary or summaries of the JSON reporter.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/report"
+)
+
+// Exit codes.
+const (
+	exitSuccess   = 0
+	exitRegressed = 1
+	exitError     = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run compares the two summaries named in args and returns the
+// exit code: exitRegressed when a challenge newly failed, new
+// challenges that fail included (or,
+// with -fail-on-performance, regressed in performance) and
+// exitError for invalid usage or unreadable summaries.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("challenges-compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String(
+		"format", "markdown",
+		"Output format (markdown, html, json)",
+	)
+	output := fs.String(
+		"output", "",
+		"Write the comparison to this file instead of stdout",
+	)
+	durationThreshold := fs.Float64(
+		"duration-threshold", 0.2,
+		"Relative duration increase that is a regression "+
+			"(0 disables)",
+	)
+	minDurationDelta := fs.Duration(
+		"min-duration-delta", 100*time.Millisecond,
+		"Ignore duration increases smaller than this",
+	)
+	metricThreshold := fs.Float64(
+		"metric-threshold", 0.1,
+		"Relative metric change that is a regression (0 disables)",
+	)
+	higherIsBetter := fs.String(
+		"higher-is-better", "",
+		"Comma-separated metrics for which a decrease is a "+
+			"regression",
+	)
+	failOnPerformance := fs.Bool(
+		"fail-on-performance", false,
+		"Also exit non-zero on performance regressions",
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr,
+			"usage: challenges-compare [flags] <baseline.json> <current.json>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitError
+	}
+	switch *format {
+	case "markdown", "html", "json":
+	default:
+		fmt.Fprintf(stderr, "unknown format: %s\n", *format)
+		return exitError
+	}
+
+	baseline, err := report.LoadMasterSummary(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline: %v\n", err)
+		return exitError
+	}
+	current, err := report.LoadMasterSummary(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "current: %v\n", err)
+		return exitError
+	}
+
+	opts := report.CompareOptions{
+		DurationThreshold: *durationThreshold,
+		MinDurationDelta:  *minDurationDelta,
+		MetricThreshold:   *metricThreshold,
+	}
+	for _, name := range strings.Split(*higherIsBetter, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.HigherIsBetter = append(opts.HigherIsBetter, name)
+		}
+	}
+	cmp := report.CompareSummaries(baseline, current, opts)
+
+	var data []byte
+	switch *format {
+	case "markdown":
+		data = []byte(cmp.Markdown())
+	case "html":
+		data = []byte(cmp.HTML())
+	case "json":
+		if data, err = cmp.JSON(); err != nil {
+			fmt.Fprintf(stderr, "encode comparison: %v\n", err)
+			return exitError
+		}
+		data = append(data, '\n')
+	}
+	if *output != "" {
+		err = os.WriteFile(*output, data, 0o644)
+	} else {
+		_, err = stdout.Write(data)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "write comparison: %v\n", err)
+		return exitError
+	}
+
+	if cmp.Regressed(*failOnPerformance) {
+		return exitRegressed
+	}
+	return exitSuccess
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/report"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSummary writes a master summary of challenges with the
+// given statuses and durations and returns its path.
+func writeSummary(t *testing.T, name string, challenges ...report.ChallengeSummary) string {
+	t.Helper()
+	data, err := json.Marshal(report.MasterSummary{ID: name, Challenges: challenges})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), name+".json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func summary(id, status string, d time.Duration) report.ChallengeSummary {
+	return report.ChallengeSummary{
+		ChallengeID: challenge.ID(id), ChallengeName: id,
+		Status: status, Duration: d,
+	}
+}
+
+func TestRun_NoRegression(t *testing.T) {
+	base := writeSummary(t, "base", summary("a", challenge.StatusFailed, time.Second))
+	cur := writeSummary(t, "cur", summary("a", challenge.StatusPassed, time.Second))
+
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitSuccess, run([]string{base, cur}, &out, &errOut))
+	assert.Contains(t, out.String(), "| a | Fixed | FAILED | PASSED |")
+}
+
+func TestRun_NewFailure(t *testing.T) {
+	base := writeSummary(t, "base", summary("a", challenge.StatusPassed, time.Second))
+	cur := writeSummary(t, "cur", summary("a", challenge.StatusFailed, time.Second))
+
+	var out, errOut bytes.Buffer
+	code := run([]string{"-format", "json", base, cur}, &out, &errOut)
+	assert.Equal(t, exitRegressed, code)
+
+	var cmp report.Comparison
+	require.NoError(t, json.Unmarshal(out.Bytes(), &cmp))
+	assert.Equal(t, 1, cmp.Counts[report.ChangeNewFailure])
+}
+
+func TestRun_AddedFailure(t *testing.T) {
+	base := writeSummary(t, "base", summary("a", challenge.StatusPassed, time.Second))
+	cur := writeSummary(t, "cur",
+		summary("a", challenge.StatusPassed, time.Second),
+		summary("b", challenge.StatusFailed, time.Second))
+
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitRegressed, run([]string{base, cur}, &out, &errOut))
+	assert.Contains(t, out.String(), "| b | New Failure | - | FAILED |")
+}
+
+func TestRun_PerformanceRegression(t *testing.T) {
+	base := writeSummary(t, "base", summary("a", challenge.StatusPassed, time.Second))
+	cur := writeSummary(t, "cur", summary("a", challenge.StatusPassed, 2*time.Second))
+	html := filepath.Join(t.TempDir(), "cmp.html")
+
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitSuccess,
+		run([]string{"-format", "html", "-output", html, base, cur}, &out, &errOut))
+	assert.Empty(t, out.String())
+	data, err := os.ReadFile(html)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Performance Regressed")
+
+	assert.Equal(t, exitRegressed,
+		run([]string{"-fail-on-performance", base, cur}, &out, &errOut))
+	assert.Equal(t, exitSuccess, run([]string{
+		"-fail-on-performance", "-duration-threshold", "0", base, cur,
+	}, &out, &errOut))
+}
+
+func TestRun_Errors(t *testing.T) {
+	base := writeSummary(t, "base")
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitError, run([]string{base}, &out, &errOut))
+	assert.Equal(t, exitError, run([]string{"-format", "xml", base, base}, &out, &errOut))
+	assert.Equal(t, exitError,
+		run([]string{base, filepath.Join(t.TempDir(), "missing.json")}, &out, &errOut))
+	assert.Contains(t, errOut.String(), "current: read summary")
+}
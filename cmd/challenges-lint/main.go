@@ -0,0 +1,168 @@
+// Package main provides the challenges-lint CLI. It validates
+// challenge bank files (JSON, YAML and step scripts) and exits
+// non-zero when errors are found, for use in CI.
+//
+// Usage:
+//
+//	challenges-lint [-format text|json] [-strict] <file-or-dir>...
+//	challenges-lint [-format text|json] -assertion '<line>'
+//
+// With -assertion it parses a compact assertion line (e.g.
+// 'latency | between(50ms, 250ms) | too slow') and prints its
+// canonical form, or the definition as JSON.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/bank"
+)
+
+// Exit codes.
+const (
+	exitSuccess  = 0
+	exitFailures = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run lints the paths named in args and returns the exit code:
+// exitFailures when errors (or, with -strict, warnings) were
+// found and exitError for invalid usage.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("challenges-lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String(
+		"format", "text",
+		"Output format (text, json)",
+	)
+	strict := fs.Bool(
+		"strict", false,
+		"Treat warnings as failures",
+	)
+	noAssertions := fs.Bool(
+		"no-assertion-check", false,
+		"Do not check assertion types against the built-in "+
+			"assertion engine",
+	)
+	line := fs.String(
+		"assertion", "",
+		"Parse a compact assertion line and print it instead of "+
+			"linting files",
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr,
+			"usage: challenges-lint [flags] <file-or-dir>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() == 0 && *line == "" {
+		fs.Usage()
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format: %s\n", *format)
+		return exitError
+	}
+
+	opts := bank.LintOptions{}
+	if !*noAssertions {
+		opts.Engine = assertion.NewEngine()
+	}
+	if *line != "" {
+		return parseAssertion(*line, *format, opts.Engine, stdout, stderr)
+	}
+	issues := bank.Lint(fs.Args(), opts)
+
+	if err := writeIssues(stdout, issues, *format); err != nil {
+		fmt.Fprintf(stderr, "write report: %v\n", err)
+		return exitError
+	}
+
+	if bank.HasErrors(issues) || (*strict && len(issues) > 0) {
+		return exitFailures
+	}
+	return exitSuccess
+}
+
+// writeIssues prints the issues in the requested format. The
+// text format ends with a summary line.
+func writeIssues(w io.Writer, issues []bank.Issue, format string) error {
+	if format == "json" {
+		if issues == nil {
+			issues = []bank.Issue{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(issues)
+	}
+
+	var errs, warns int
+	for _, i := range issues {
+		if _, err := fmt.Fprintln(w, i.String()); err != nil {
+			return err
+		}
+		if i.Severity == bank.SeverityError {
+			errs++
+		} else {
+			warns++
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d error(s), %d warning(s)\n", errs, warns)
+	return err
+}
+
+// parseAssertion prints the canonical form or the JSON
+// definition of a compact assertion line. Syntax errors and,
+// with an engine, unknown types exit with exitFailures; syntax
+// errors are pointed at under the line.
+func parseAssertion(
+	line, format string,
+	engine assertion.Engine,
+	stdout, stderr io.Writer,
+) int {
+	def, err := assertion.ParseDefinition(line)
+	if err != nil {
+		var syntaxErr *assertion.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			fmt.Fprintf(stderr, "%s\n%s^\n",
+				line, strings.Repeat(" ", syntaxErr.Pos))
+		}
+		fmt.Fprintln(stderr, err)
+		return exitFailures
+	}
+	if checker, ok := engine.(interface {
+		HasEvaluator(string) bool
+	}); ok && !checker.HasEvaluator(def.Type) {
+		fmt.Fprintf(stderr, "unknown assertion type %s\n", def.Type)
+		return exitFailures
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(def)
+	} else {
+		var canonical string
+		if canonical, err = assertion.FormatDefinition(def); err == nil {
+			_, err = fmt.Fprintln(stdout, canonical)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "write assertion: %v\n", err)
+		return exitError
+	}
+	return exitSuccess
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"digital.vasic.challenges/pkg/bank"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBank(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bank.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestRun_Clean(t *testing.T) {
+	path := writeBank(t, `{"version": "1", "challenges": [
+  {"id": "a", "name": "A", "assertions": [{"type": "not_empty", "target": "x"}]}
+]}`)
+	var out, errOut bytes.Buffer
+	code := run([]string{path}, &out, &errOut)
+	assert.Equal(t, exitSuccess, code)
+	assert.Contains(t, out.String(), "0 error(s), 0 warning(s)")
+}
+
+func TestRun_ErrorsExitNonZero(t *testing.T) {
+	path := writeBank(t, `{"version": "1", "challenges": [
+  {"id": "a", "name": "A", "assertions": [{"type": "bogus", "target": "x"}]}
+]}`)
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitFailures, run([]string{path}, &out, &errOut))
+	assert.Contains(t, out.String(), "unknown assertion type bogus")
+
+	out.Reset()
+	assert.Equal(t, exitSuccess,
+		run([]string{"-no-assertion-check", path}, &out, &errOut))
+}
+
+func TestRun_StrictWarnings(t *testing.T) {
+	path := writeBank(t, `{"version": "1", "challenges": [
+  {"id": "a", "name": "A", "extra": true}
+]}`)
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitSuccess, run([]string{path}, &out, &errOut))
+	assert.Equal(t, exitFailures,
+		run([]string{"-strict", path}, &out, &errOut))
+}
+
+func TestRun_JSONFormat(t *testing.T) {
+	path := writeBank(t, `{"challenges": [{"id": "a", "name": "A"}]}`)
+	var out, errOut bytes.Buffer
+	code := run([]string{"-format", "json", path}, &out, &errOut)
+	assert.Equal(t, exitFailures, code)
+
+	var issues []bank.Issue
+	require.NoError(t, json.Unmarshal(out.Bytes(), &issues))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "version", issues[0].Field)
+}
+
+func TestRun_Usage(t *testing.T) {
+	var out, errOut bytes.Buffer
+	assert.Equal(t, exitError, run(nil, &out, &errOut))
+	assert.Contains(t, errOut.String(), "usage: challenges-lint")
+	assert.Equal(t, exitError,
+		run([]string{"-format", "xml", "x"}, &out, &errOut))
+	assert.Equal(t, exitError,
+		run([]string{"-bogus-flag"}, &out, &errOut))
+}
+
+func TestRun_Assertion(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := run([]string{
+		"-assertion", "latency|between( 50ms,250 , severity=warning )|slow",
+	}, &out, &errOut)
+	assert.Equal(t, exitSuccess, code)
+	assert.Equal(t,
+		"latency | between(50ms, 250, severity=\"warning\") | slow\n",
+		out.String())
+
+	out.Reset()
+	code = run([]string{
+		"-format", "json", "-assertion", "out | contains_any([a, 1])",
+	}, &out, &errOut)
+	assert.Equal(t, exitSuccess, code)
+	var def map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &def))
+	assert.Equal(t, "contains_any", def["type"])
+	assert.Equal(t, []any{"a", float64(1)}, def["value"])
+
+	out.Reset()
+	code = run([]string{"-assertion", "out | equals(1 2)"}, &out, &errOut)
+	assert.Equal(t, exitFailures, code)
+	assert.Contains(t, errOut.String(),
+		"out | equals(1 2)\n               ^\ninvalid assertion")
+
+	errOut.Reset()
+	code = run([]string{"-assertion", "out | bogus()"}, &out, &errOut)
+	assert.Equal(t, exitFailures, code)
+	assert.Contains(t, errOut.String(), "unknown assertion type bogus")
+}
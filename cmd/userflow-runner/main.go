@@ -17,9 +17,13 @@ import (
 	"time"
 
 	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
+	"digital.vasic.challenges/pkg/logging"
+	"digital.vasic.challenges/pkg/monitor"
 	"digital.vasic.challenges/pkg/registry"
 	"digital.vasic.challenges/pkg/report"
 	"digital.vasic.challenges/pkg/runner"
+	"digital.vasic.challenges/pkg/store"
 	"digital.vasic.challenges/pkg/userflow"
 )
 
@@ -30,6 +34,10 @@ const (
 	exitError    = 2
 )
 
+// historyFileName is the name of the run history log in the
+// output directory.
+const historyFileName = "history.jsonl"
+
 // platformGroups holds the caller-supplied container service groups
 // for each platform. The runner loads the groups at startup from the
 // file named by --platform-groups; HelixQA's Challenges library
@@ -127,7 +135,7 @@ func run() int {
 	)
 	reportFmt := flag.String(
 		"report", "markdown",
-		"Report format (markdown, json, html)",
+		"Report format (markdown, json, html, junit, tap, bundle)",
 	)
 	composeFile := flag.String(
 		"compose", "docker-compose.test.yml",
@@ -169,6 +177,39 @@ func run() int {
 			"present (Constitution §11.4 captured-evidence rule). "+
 			"Sets CHALLENGE_ANTIBLUFF_STRICT=1 in process env.",
 	)
+	resumeRun := flag.String(
+		"resume", "",
+		"Resume an interrupted run by its run ID, re-using the "+
+			"journaled results under <output>/runs/<run-id> and "+
+			"executing only challenges without a final result",
+	)
+	updateSnapshots := flag.Bool(
+		"update-snapshots", false,
+		"Rewrite the recorded snapshots of matches_snapshot "+
+			"assertions under <output>/snapshots instead of "+
+			"comparing against them",
+	)
+	storePath := flag.String(
+		"store", "",
+		"Record every result in the results database at this "+
+			"path (disabled when empty)",
+	)
+	keepRuns := flag.Int(
+		"keep-runs", 0,
+		"Prune the results database to this many recent runs "+
+			"(0 keeps all)",
+	)
+	dashboardAddr := flag.String(
+		"dashboard", "",
+		"Serve the live monitor dashboard at this address, "+
+			"with the stored runs of --store under /runs "+
+			"(disabled when empty)",
+	)
+	keepDays := flag.Int(
+		"keep-days", 0,
+		"Prune runs older than this many days from the results "+
+			"database (0 keeps all)",
+	)
 	flag.Parse()
 
 	// Phase 23.6 — propagate the flag to the env var the runner reads.
@@ -199,13 +240,14 @@ func run() int {
 		"root", *projectRoot,
 		"timeout", *timeout,
 		"output", *outputDir,
+		"update_snapshots", *updateSnapshots,
 		"platform_groups_file", *platformGroupsFile,
 		"platform_groups_loaded", len(platformGroups),
 	)
 
 	// Validate report format.
 	switch strings.ToLower(*reportFmt) {
-	case "markdown", "json", "html":
+	case "markdown", "json", "html", "junit", "tap", "bundle":
 		// valid
 	default:
 		logger.Error("unsupported report format",
@@ -213,7 +255,7 @@ func run() int {
 		)
 		fmt.Fprintf(os.Stderr,
 			"Error: unsupported report format: %s "+
-				"(use markdown, json, or html)\n",
+				"(use markdown, json, html, junit, tap, or bundle)\n",
 			*reportFmt,
 		)
 		return exitError
@@ -246,6 +288,19 @@ func run() int {
 		return exitError
 	}
 
+	// Open the results database, if requested.
+	var db *store.Store
+	if *storePath != "" {
+		var err error
+		if db, err = store.Open(*storePath); err != nil {
+			logger.Error("open results store", "error", err)
+			fmt.Fprintf(os.Stderr,
+				"Error: cannot open results store: %v\n", err,
+			)
+			return exitError
+		}
+	}
+
 	// Set up context with timeout and signal handling.
 	ctx, cancel := context.WithTimeout(
 		context.Background(), *timeout,
@@ -320,18 +375,60 @@ func run() int {
 	}()
 
 	// Create runner with configured timeouts.
+	// Every run is journaled so that an interrupted run can be
+	// continued with --resume.
+	runID := *resumeRun
+	if runID == "" {
+		runID = runner.NewRunID()
+	}
 	reg := registry.Default
-	r := runner.NewRunner(
+	opts := []runner.RunnerOption{
 		runner.WithRegistry(reg),
 		runner.WithLogger(logger),
 		runner.WithTimeout(*timeout),
-		runner.WithStaleThreshold(5*time.Minute),
+		runner.WithStaleThreshold(5 * time.Minute),
 		runner.WithResultsDir(absOutput),
-	)
+		runner.WithRunID(runID),
+		runner.WithUpdateSnapshots(*updateSnapshots),
+	}
+	// Record results in the results database as they complete.
+	if db != nil {
+		opts = append(opts, runner.WithResultHook(db.Recorder(runID)))
+	}
+	// Append the results to the run history log, which the HTML
+	// report draws its trends from. Results a resumed run reuses
+	// from its journal were appended when they first ran.
+	historyPath := filepath.Join(absOutput, historyFileName)
+	opts = append(opts, runner.WithResultHook(
+		historyRecorder(historyPath, absOutput),
+	))
+	// Serve the live dashboard of the run, and the stored runs
+	// of the results database, while the runner is running.
+	if *dashboardAddr != "" {
+		collector := monitor.NewEventCollector()
+		server := newDashboardServer(
+			*dashboardAddr, runID, collector, db,
+		)
+		serverCtx, stopServer := context.WithCancel(
+			context.Background(),
+		)
+		defer stopServer()
+		go func() {
+			if err := server.Start(serverCtx); err != nil {
+				logger.Warn("dashboard server failed",
+					"error", err,
+				)
+			}
+		}()
+		logger.Info("serving dashboard", "addr", *dashboardAddr)
+		opts = append(opts, runner.WithEventCollector(collector))
+	}
+	r := runner.NewRunner(opts...)
 
 	// Run all registered challenges in dependency order.
 	logger.Info("running challenges",
 		"registered", reg.Count(),
+		"run_id", runID,
 	)
 
 	cfg := &challenge.Config{
@@ -351,13 +448,20 @@ func run() int {
 	cfg.Environment["COMPOSE_FILE"] = *composeFile
 	cfg.Environment["PLATFORM"] = *platform
 
-	results, runErr := r.RunAll(ctx, cfg)
+	var results []*challenge.Result
+	var runErr error
+	if *resumeRun != "" {
+		results, runErr = r.Resume(ctx, runID, cfg)
+	} else {
+		results, runErr = r.RunAll(ctx, cfg)
+	}
 
 	// Check for context cancellation (signal or timeout).
 	if ctx.Err() != nil && runErr != nil {
 		logger.Warn("run interrupted",
 			"reason", ctx.Err(),
 			"completed", len(results),
+			"resume_with", "--resume "+runID,
 		)
 	} else if runErr != nil {
 		logger.Error("run error",
@@ -372,8 +476,24 @@ func run() int {
 		"results", len(results),
 	)
 
+	// The HTML reports draw their trends from the results
+	// database when there is one, otherwise from the history
+	// log.
+	in := newReportInputs(reg, *reportFmt)
+	format := strings.ToLower(*reportFmt)
+	if format == "html" || format == "bundle" {
+		if db != nil {
+			in.history = db.History()
+		} else if len(results) > 0 {
+			if in.history, err = history.Load(historyPath); err != nil {
+				logger.Warn("load run history failed",
+					"error", err,
+				)
+			}
+		}
+	}
 	if err := generateReport(
-		results, absOutput, *reportFmt,
+		results, absOutput, *reportFmt, in,
 	); err != nil {
 		logger.Error("report generation failed",
 			"error", err,
@@ -385,8 +505,14 @@ func run() int {
 		// are still useful.
 	}
 
-	// Save structured master summary.
+	// Save structured master summary, identified by the run in
+	// the results database when there is one.
 	summary := report.BuildMasterSummary(results)
+	if db != nil {
+		if stored, err := db.MasterSummary(runID); err == nil {
+			summary = stored
+		}
+	}
 	if err := report.SaveMasterSummary(
 		summary, absOutput,
 	); err != nil {
@@ -395,6 +521,23 @@ func run() int {
 		)
 	}
 
+	// Apply the retention policy of the results database.
+	if db != nil {
+		removed, err := db.Prune(store.Retention{
+			KeepRuns: *keepRuns,
+			MaxAge:   time.Duration(*keepDays) * 24 * time.Hour,
+		})
+		if err != nil {
+			logger.Warn("prune results store failed",
+				"error", err,
+			)
+		} else if len(removed) > 0 {
+			logger.Info("pruned results store",
+				"runs", len(removed),
+			)
+		}
+	}
+
 	// Print summary to stdout.
 	printSummary(results, logger)
 
@@ -403,7 +546,7 @@ func run() int {
 		return exitError
 	}
 	for _, res := range results {
-		if res.Status != challenge.StatusPassed &&
+		if !challenge.IsPassing(res.Status) &&
 			res.Status != challenge.StatusSkipped {
 			return exitFailures
 		}
@@ -456,12 +599,84 @@ func resolveGroups(
 	return []userflow.PlatformGroup{group}, nil
 }
 
+// reportInputs carries what the reporters need besides the
+// results. Each format reads only its own fields.
+type reportInputs struct {
+	// categories names the JUnit suite of each challenge.
+	categories map[challenge.ID]string
+
+	// bundle configures the HTML bundle.
+	bundle report.BundleOptions
+
+	// history is the run history the HTML report and bundle
+	// draw their trends from.
+	history *history.History
+}
+
+// newReportInputs collects the inputs of the requested report
+// format from the registered challenges: their categories for
+// JUnit, and for the HTML bundle also their dependencies and a
+// video thumbnail extractor.
+func newReportInputs(
+	reg registry.Registry,
+	format string,
+) reportInputs {
+	var in reportInputs
+	switch strings.ToLower(format) {
+	case "junit":
+		in.categories = make(map[challenge.ID]string)
+		for _, c := range reg.List() {
+			in.categories[c.ID()] = c.Category()
+		}
+	case "bundle":
+		in.bundle = report.BundleOptions{
+			Categories:   make(map[challenge.ID]string),
+			Dependencies: make(map[challenge.ID][]challenge.ID),
+			Thumbnails: userflow.NewRecordingValidator(
+				logging.NullLogger{},
+			),
+		}
+		for _, c := range reg.List() {
+			in.bundle.Categories[c.ID()] = c.Category()
+			if deps := c.Dependencies(); len(deps) > 0 {
+				in.bundle.Dependencies[c.ID()] = deps
+			}
+		}
+	}
+	return in
+}
+
+// newDashboardServer creates the monitor server of a run. When
+// db is set, the server also serves its stored runs.
+func newDashboardServer(
+	addr, runID string,
+	collector *monitor.EventCollector,
+	db *store.Store,
+) *monitor.WebSocketServer {
+	server := monitor.NewWebSocketServer(
+		addr, collector, monitor.NewDashboardData(runID),
+	)
+	if db != nil {
+		server.SetRunSource(db)
+	}
+	return server
+}
+
+// historyRecorder returns a result hook appending an entry for
+// each result it receives to the run history log at path.
+func historyRecorder(path, resultsPath string) runner.ResultHook {
+	return func(_ context.Context, res *challenge.Result) error {
+		return report.AppendToHistory(path, res, resultsPath)
+	}
+}
+
 // generateReport creates a report file in the requested format
 // using the appropriate reporter implementation.
 func generateReport(
 	results []*challenge.Result,
 	outputDir string,
 	format string,
+	in reportInputs,
 ) error {
 	if len(results) == 0 {
 		return nil
@@ -478,8 +693,21 @@ func generateReport(
 		reporter = report.NewJSONReporter(outputDir, true)
 		ext = "json"
 	case "html":
-		reporter = report.NewHTMLReporter(outputDir)
+		html := report.NewHTMLReporter(outputDir)
+		html.SetHistory(in.history)
+		reporter = html
 		ext = "html"
+	case "junit":
+		reporter = report.NewJUnitReporter(outputDir, in.categories)
+		ext = "xml"
+	case "tap":
+		reporter = report.NewTAPReporter(outputDir)
+		ext = "tap"
+	case "bundle":
+		bundle := report.NewBundleReporter(outputDir, in.bundle)
+		bundle.SetHistory(in.history)
+		reporter = bundle
+		ext = "bundle.html"
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
@@ -543,6 +771,7 @@ func printSummary(
 	}
 
 	passed := 0
+	warned := 0
 	failed := 0
 	skipped := 0
 	errored := 0
@@ -562,6 +791,9 @@ func printSummary(
 		switch res.Status {
 		case challenge.StatusPassed:
 			passed++
+		case challenge.StatusPassedWithWarnings:
+			passed++
+			warned++
 		case challenge.StatusFailed:
 			failed++
 		case challenge.StatusSkipped:
@@ -579,6 +811,9 @@ func printSummary(
 		len(results),
 	)
 	fmt.Printf("  Passed:     %d\n", passed)
+	if warned > 0 {
+		fmt.Printf("  Warnings:   %d\n", warned)
+	}
 	fmt.Printf("  Failed:     %d\n", failed)
 	fmt.Printf("  Skipped:    %d\n", skipped)
 	fmt.Printf("  Errors:     %d\n", errored)
@@ -593,7 +828,7 @@ func printSummary(
 	if failed+errored > 0 {
 		fmt.Println("Failed/Errored Challenges:")
 		for _, res := range results {
-			if res.Status != challenge.StatusPassed &&
+			if !challenge.IsPassing(res.Status) &&
 				res.Status != challenge.StatusSkipped {
 				fmt.Printf(
 					"  - [%s] %s: %s\n",
@@ -2,8 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +15,12 @@ import (
 	"time"
 
 	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
+	"digital.vasic.challenges/pkg/monitor"
+	"digital.vasic.challenges/pkg/registry"
+	"digital.vasic.challenges/pkg/report"
+	"digital.vasic.challenges/pkg/runner"
+	"digital.vasic.challenges/pkg/store"
 	"digital.vasic.challenges/pkg/userflow"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -324,7 +334,7 @@ func TestCliLogger_PrintArgs_OddCount(t *testing.T) {
 
 func TestGenerateReport_EmptyResults(t *testing.T) {
 	dir := t.TempDir()
-	err := generateReport(nil, dir, "markdown")
+	err := generateReport(nil, dir, "markdown", reportInputs{})
 	assert.NoError(t, err, "empty results should return nil immediately")
 }
 
@@ -348,7 +358,7 @@ func TestGenerateReport_Markdown(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "markdown")
+	err := generateReport(results, dir, "markdown", reportInputs{})
 	require.NoError(t, err)
 
 	// Check individual report file was created.
@@ -371,7 +381,7 @@ func TestGenerateReport_JSON(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "json")
+	err := generateReport(results, dir, "json", reportInputs{})
 	require.NoError(t, err)
 
 	reportPath := filepath.Join(dir, "CH-TEST-002.json")
@@ -392,7 +402,7 @@ func TestGenerateReport_HTML(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "html")
+	err := generateReport(results, dir, "html", reportInputs{})
 	require.NoError(t, err)
 
 	reportPath := filepath.Join(dir, "CH-TEST-003.html")
@@ -402,6 +412,215 @@ func TestGenerateReport_HTML(t *testing.T) {
 	assert.FileExists(t, summaryPath)
 }
 
+func TestGenerateReport_HTMLHistory(t *testing.T) {
+	dir := t.TempDir()
+	historyPath := filepath.Join(dir, historyFileName)
+	end := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, status := range []string{
+		challenge.StatusFailed, challenge.StatusPassed,
+	} {
+		require.NoError(t, historyRecorder(historyPath, dir)(
+			context.Background(), &challenge.Result{
+				ChallengeID: "CH-TEST-007",
+				Status:      status,
+				EndTime:     end.Add(time.Duration(i) * time.Hour),
+				Duration:    time.Second,
+			},
+		))
+	}
+	h, err := history.Load(historyPath)
+	require.NoError(t, err)
+	require.Equal(t, 2, h.Len())
+	assert.Equal(t, dir, h.Entries()[0].ResultsPath)
+
+	results := []*challenge.Result{{
+		ChallengeID:   "CH-TEST-007",
+		ChallengeName: "History Test",
+		Status:        challenge.StatusPassed,
+	}}
+	err = generateReport(results, dir, "html", reportInputs{history: h})
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "summary.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<h2>Trends</h2>")
+}
+
+func TestHistoryRecorder_SkipsResumedResults(t *testing.T) {
+	dir := t.TempDir()
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register(challenge.NewShellChallenge(
+		"CH-TEST-008", "Done", "", "api", nil, "true", nil, "",
+	)))
+	require.NoError(t, reg.Register(challenge.NewShellChallenge(
+		"CH-TEST-009", "Left", "", "api", nil, "true", nil, "",
+	)))
+	journal := runner.NewJournal(filepath.Join(dir, "runs", "run_1"), "run_1")
+	require.NoError(t, journal.Record(&challenge.Result{
+		ChallengeID: "CH-TEST-008",
+		Status:      challenge.StatusPassed,
+	}, "", false))
+
+	historyPath := filepath.Join(dir, historyFileName)
+	r := runner.NewRunner(
+		runner.WithRegistry(reg),
+		runner.WithResultsDir(dir),
+		runner.WithResultHook(historyRecorder(historyPath, dir)),
+	)
+	results, err := r.Resume(context.Background(), "run_1", &challenge.Config{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	h, err := history.Load(historyPath)
+	require.NoError(t, err)
+	require.Equal(t, 1, h.Len())
+	assert.Equal(t, "CH-TEST-009", h.Entries()[0].ChallengeID)
+}
+
+func TestGenerateReport_BundleHistory(t *testing.T) {
+	dir := t.TempDir()
+	db, err := store.Open(filepath.Join(dir, "results.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Save("run-1", &challenge.Result{
+		ChallengeID: "CH-TEST-008",
+		Status:      challenge.StatusFailed,
+		EndTime:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Duration:    time.Second,
+	}))
+
+	results := []*challenge.Result{{
+		ChallengeID:   "CH-TEST-008",
+		ChallengeName: "Stored History Test",
+		Status:        challenge.StatusPassed,
+	}}
+	err = generateReport(
+		results, dir, "bundle", reportInputs{history: db.History()},
+	)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "summary.bundle.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<h2>Trends</h2>")
+}
+
+func TestNewDashboardServer_ServesStoredRuns(t *testing.T) {
+	db, err := store.Open(filepath.Join(t.TempDir(), "results.db"))
+	require.NoError(t, err)
+	require.NoError(t, db.Save("run-1", &challenge.Result{
+		ChallengeID: "CH-TEST-009", Status: challenge.StatusPassed,
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	server := newDashboardServer(
+		addr, "run-2", monitor.NewEventCollector(), db,
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = server.Start(ctx) }()
+
+	var body []byte
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/runs/run-1")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		body, err = io.ReadAll(resp.Body)
+		return err == nil && resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 20*time.Millisecond)
+	var dashboard monitor.DashboardData
+	require.NoError(t, json.Unmarshal(body, &dashboard))
+	assert.Equal(t, "run-1", dashboard.RunID)
+	assert.Equal(t, "completed", dashboard.Status)
+}
+
+func TestGenerateReport_JUnitAndTAP(t *testing.T) {
+	dir := t.TempDir()
+	results := []*challenge.Result{
+		{
+			ChallengeID:   "CH-TEST-005",
+			ChallengeName: "CI Test",
+			Status:        challenge.StatusFailed,
+			Duration:      time.Second,
+			Error:         "exit status 1",
+		},
+	}
+	in := reportInputs{
+		categories: map[challenge.ID]string{"CH-TEST-005": "api"},
+	}
+
+	err := generateReport(results, dir, "junit", in)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "summary.xml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `<testsuite name="api"`)
+	assert.FileExists(t, filepath.Join(dir, "CH-TEST-005.xml"))
+
+	err = generateReport(results, dir, "tap", in)
+	require.NoError(t, err)
+	data, err = os.ReadFile(filepath.Join(dir, "summary.tap"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "not ok 1 - CI Test (CH-TEST-005)")
+	assert.FileExists(t, filepath.Join(dir, "CH-TEST-005.tap"))
+}
+
+func TestGenerateReport_Bundle(t *testing.T) {
+	dir := t.TempDir()
+	results := []*challenge.Result{
+		{
+			ChallengeID:   "CH-TEST-005",
+			ChallengeName: "Bundle Dependency",
+			Status:        challenge.StatusPassed,
+		},
+		{
+			ChallengeID:   "CH-TEST-006",
+			ChallengeName: "Bundle Test",
+			Status:        challenge.StatusPassed,
+		},
+	}
+	in := reportInputs{bundle: report.BundleOptions{
+		Dependencies: map[challenge.ID][]challenge.ID{
+			"CH-TEST-006": {"CH-TEST-005"},
+		},
+	}}
+
+	err := generateReport(results, dir, "bundle", in)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "summary.bundle.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Dependency Graph")
+	assert.FileExists(t, filepath.Join(dir, "CH-TEST-006.bundle.html"))
+}
+
+func TestNewReportInputs(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register(challenge.NewShellChallenge(
+		"CH-TEST-005", "API", "", "api", nil, "true", nil, "",
+	)))
+	require.NoError(t, reg.Register(challenge.NewShellChallenge(
+		"CH-TEST-006", "Web", "", "web",
+		[]challenge.ID{"CH-TEST-005"}, "true", nil, "",
+	)))
+
+	in := newReportInputs(reg, "JUnit")
+	assert.Equal(t, map[challenge.ID]string{
+		"CH-TEST-005": "api", "CH-TEST-006": "web",
+	}, in.categories)
+	assert.Nil(t, in.bundle.Thumbnails)
+
+	in = newReportInputs(reg, "bundle")
+	assert.Nil(t, in.categories)
+	assert.Equal(t, "web", in.bundle.Categories["CH-TEST-006"])
+	assert.Equal(t, map[challenge.ID][]challenge.ID{
+		"CH-TEST-006": {"CH-TEST-005"},
+	}, in.bundle.Dependencies)
+	assert.NotNil(t, in.bundle.Thumbnails)
+
+	assert.Equal(t, reportInputs{}, newReportInputs(reg, "markdown"))
+}
+
 func TestGenerateReport_UnsupportedFormat(t *testing.T) {
 	dir := t.TempDir()
 	results := []*challenge.Result{
@@ -412,7 +631,7 @@ func TestGenerateReport_UnsupportedFormat(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "xml")
+	err := generateReport(results, dir, "xml", reportInputs{})
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported format")
 }
@@ -441,7 +660,7 @@ func TestGenerateReport_CaseInsensitiveFormat(t *testing.T) {
 				},
 			}
 
-			err := generateReport(results, dir, tc.format)
+			err := generateReport(results, dir, tc.format, reportInputs{})
 			require.NoError(t, err)
 
 			reportPath := filepath.Join(
@@ -475,7 +694,7 @@ func TestGenerateReport_MultipleResults(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "json")
+	err := generateReport(results, dir, "json", reportInputs{})
 	require.NoError(t, err)
 
 	for _, r := range results {
@@ -504,6 +723,7 @@ func TestGenerateReport_InvalidOutputDir(t *testing.T) {
 		results,
 		"/dev/null/impossible/path",
 		"json",
+		reportInputs{},
 	)
 	assert.Error(t, err)
 }
@@ -771,6 +991,9 @@ func TestReportFormatValidation(t *testing.T) {
 		{"Markdown mixed case", "Markdown", true},
 		{"JSON uppercase", "JSON", true},
 		{"HTML uppercase", "HTML", true},
+		{"junit lowercase", "junit", true},
+		{"tap lowercase", "tap", true},
+		{"bundle lowercase", "bundle", true},
 		{"xml invalid", "xml", false},
 		{"csv invalid", "csv", false},
 		{"empty invalid", "", false},
@@ -792,7 +1015,7 @@ func TestReportFormatValidation(t *testing.T) {
 // effects like flag.Parse and os.Exit).
 func isValidReportFormat(format string) bool {
 	switch strings.ToLower(format) {
-	case "markdown", "json", "html":
+	case "markdown", "json", "html", "junit", "tap", "bundle":
 		return true
 	default:
 		return false
@@ -894,7 +1117,7 @@ func TestGenerateReport_WithAssertions(t *testing.T) {
 		},
 	}
 
-	err := generateReport(results, dir, "json")
+	err := generateReport(results, dir, "json", reportInputs{})
 	require.NoError(t, err)
 
 	reportPath := filepath.Join(dir, "CH-ASSERT.json")
@@ -0,0 +1,167 @@
+package assertion
+
+import "digital.vasic.challenges/pkg/challenge"
+
+// ChallengeAdapter wraps an Engine to implement
+// challenge.AssertionEngine, bridging the structurally
+// equivalent challenge.AssertionDef/AssertionResult and
+// Definition/Result types.
+type ChallengeAdapter struct {
+	engine Engine
+}
+
+var _ challenge.ScopedAssertionEngine = (*ChallengeAdapter)(nil)
+
+// NewChallengeAdapter creates an adapter for engine. A nil
+// engine selects NewEngine().
+func NewChallengeAdapter(engine Engine) *ChallengeAdapter {
+	if engine == nil {
+		engine = NewEngine()
+	}
+	return &ChallengeAdapter{engine: engine}
+}
+
+// Engine returns the wrapped assertion engine.
+func (a *ChallengeAdapter) Engine() Engine { return a.engine }
+
+// Evaluate delegates to the wrapped engine, converting types.
+func (a *ChallengeAdapter) Evaluate(
+	def challenge.AssertionDef,
+	value any,
+) challenge.AssertionResult {
+	return ToChallengeResult(
+		a.engine.Evaluate(FromChallengeDef(def), value),
+	)
+}
+
+// EvaluateAllScoped evaluates assertions with a SnapshotStore
+// for the scope, implementing challenge.ScopedAssertionEngine.
+// Engines other than *DefaultEngine are evaluated without
+// snapshots.
+func (a *ChallengeAdapter) EvaluateAllScoped(
+	defs []challenge.AssertionDef,
+	values map[string]any,
+	scope challenge.AssertionScope,
+) ([]challenge.AssertionResult, map[string]string) {
+	engine, ok := a.engine.(*DefaultEngine)
+	if !ok {
+		return a.EvaluateAll(defs, values), nil
+	}
+	store := NewSnapshotStore(
+		scope.SnapshotsDir, scope.ArtifactsDir, scope.UpdateSnapshots,
+	)
+	results := engine.EvaluateAllWithSnapshots(
+		FromChallengeDefs(defs), values, store,
+	)
+	return ToChallengeResults(results), store.Artifacts()
+}
+
+// EvaluateAll delegates to the wrapped engine, converting types
+// for each assertion.
+func (a *ChallengeAdapter) EvaluateAll(
+	defs []challenge.AssertionDef,
+	values map[string]any,
+) []challenge.AssertionResult {
+	results := a.engine.EvaluateAll(
+		FromChallengeDefs(defs), values,
+	)
+	return ToChallengeResults(results)
+}
+
+// FromChallengeDef converts a challenge.AssertionDef into a
+// Definition.
+func FromChallengeDef(d challenge.AssertionDef) Definition {
+	return Definition{
+		Type:              d.Type,
+		Target:            d.Target,
+		Value:             d.Value,
+		Values:            d.Values,
+		Tolerance:         d.Tolerance,
+		RelativeTolerance: d.RelativeTolerance,
+		Assertions:        fromChallengeChildren(d.Assertions),
+		Ignore:            d.Ignore,
+		Severity:          d.Severity,
+		Message:           d.Message,
+	}
+}
+
+// fromChallengeChildren converts sub-assertions, keeping nil
+// for leaf assertions.
+func fromChallengeChildren(defs []challenge.AssertionDef) []Definition {
+	if defs == nil {
+		return nil
+	}
+	return FromChallengeDefs(defs)
+}
+
+// FromChallengeDefs converts a slice of challenge.AssertionDef.
+func FromChallengeDefs(defs []challenge.AssertionDef) []Definition {
+	out := make([]Definition, len(defs))
+	for i, d := range defs {
+		out[i] = FromChallengeDef(d)
+	}
+	return out
+}
+
+// ToChallengeDef converts a Definition into a
+// challenge.AssertionDef.
+func ToChallengeDef(d Definition) challenge.AssertionDef {
+	out := challenge.AssertionDef{
+		Type:              d.Type,
+		Target:            d.Target,
+		Value:             d.Value,
+		Values:            d.Values,
+		Tolerance:         d.Tolerance,
+		RelativeTolerance: d.RelativeTolerance,
+		Ignore:            d.Ignore,
+		Severity:          d.Severity,
+		Message:           d.Message,
+	}
+	if d.Assertions != nil {
+		out.Assertions = ToChallengeDefs(d.Assertions)
+	}
+	return out
+}
+
+// ToChallengeDefs converts a slice of Definition.
+func ToChallengeDefs(defs []Definition) []challenge.AssertionDef {
+	out := make([]challenge.AssertionDef, len(defs))
+	for i, d := range defs {
+		out[i] = ToChallengeDef(d)
+	}
+	return out
+}
+
+// ToChallengeResult converts a Result into a
+// challenge.AssertionResult.
+func ToChallengeResult(r Result) challenge.AssertionResult {
+	return challenge.AssertionResult{
+		Type:     r.Type,
+		Target:   r.Target,
+		Expected: r.Expected,
+		Actual:   r.Actual,
+		Passed:   r.Passed,
+		Message:  r.Message,
+		Severity: r.Severity,
+		Stats:    r.Stats,
+		Children: toChallengeChildren(r.Children),
+	}
+}
+
+// toChallengeChildren converts sub-results, keeping nil for
+// leaf results.
+func toChallengeChildren(results []Result) []challenge.AssertionResult {
+	if results == nil {
+		return nil
+	}
+	return ToChallengeResults(results)
+}
+
+// ToChallengeResults converts a slice of Result.
+func ToChallengeResults(results []Result) []challenge.AssertionResult {
+	out := make([]challenge.AssertionResult, len(results))
+	for i, r := range results {
+		out[i] = ToChallengeResult(r)
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package assertion
+
+import (
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallengeAdapter_Evaluate(t *testing.T) {
+	a := NewChallengeAdapter(nil)
+
+	r := a.Evaluate(challenge.AssertionDef{
+		Type: "contains", Target: "out", Value: "hello",
+	}, "say hello world")
+	assert.True(t, r.Passed)
+	assert.Equal(t, "contains", r.Type)
+	assert.Equal(t, "out", r.Target)
+	assert.Equal(t, "hello", r.Expected)
+}
+
+func TestChallengeAdapter_EvaluateAll(t *testing.T) {
+	a := NewChallengeAdapter(NewEngine())
+
+	results := a.EvaluateAll([]challenge.AssertionDef{
+		{Type: "not_empty", Target: "a"},
+		{Type: "min_count", Target: "b", Value: 3},
+		{Type: "not_empty", Target: "missing"},
+	}, map[string]any{"a": "x", "b": 2})
+
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.False(t, results[1].Passed)
+	assert.False(t, results[2].Passed)
+	assert.Contains(t, results[2].Message, "target not found")
+}
+
+func TestFromChallengeDef(t *testing.T) {
+	d := FromChallengeDef(challenge.AssertionDef{
+		Type: "contains_any", Target: "t",
+		Values: []any{"a", "b"}, Message: "m",
+	})
+	assert.Equal(t, "contains_any", d.Type)
+	assert.Equal(t, []any{"a", "b"}, d.Values)
+	assert.Equal(t, "m", d.Message)
+}
+
+func TestToChallengeDef(t *testing.T) {
+	def := Definition{
+		Type: "all_of", Target: "t", Severity: "warning",
+		Assertions: []Definition{{Type: "contains", Value: "a"}},
+	}
+	d := ToChallengeDef(def)
+	assert.Equal(t, "all_of", d.Type)
+	assert.Equal(t, "warning", d.Severity)
+	require.Len(t, d.Assertions, 1)
+	assert.Nil(t, d.Assertions[0].Assertions)
+	assert.Equal(t, def, FromChallengeDef(d))
+}
+
+func TestChallengeAdapter_CompositeTree(t *testing.T) {
+	a := NewChallengeAdapter(nil)
+	results := a.EvaluateAll([]challenge.AssertionDef{{
+		Type: "not", Target: "status",
+		Assertions: []challenge.AssertionDef{{Type: "equals", Value: "down"}},
+	}}, map[string]any{"status": "ok"})
+
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed)
+	require.Len(t, results[0].Children, 1)
+	assert.Equal(t, "status", results[0].Children[0].Target)
+	assert.False(t, results[0].Children[0].Passed)
+	assert.Nil(t, results[0].Children[0].Children)
+}
+
+func TestChallengeAdapter_Severity(t *testing.T) {
+	a := NewChallengeAdapter(nil)
+	results := a.EvaluateAll([]challenge.AssertionDef{
+		{Type: "max_latency", Target: "latency", Value: 100, Severity: "warning"},
+		{Type: "not_empty", Target: "items[*]", Severity: "info"},
+		{Type: "not_empty", Target: "missing", Severity: "critical"},
+	}, map[string]any{"latency": 250, "items": []any{"a", ""}})
+
+	require.Len(t, results, 4)
+	assert.False(t, results[0].Passed)
+	assert.Equal(t, challenge.SeverityWarning, results[0].Severity)
+	assert.Equal(t, challenge.SeverityInfo, results[1].Severity)
+	assert.Equal(t, challenge.SeverityInfo, results[2].Severity)
+	assert.Equal(t, challenge.SeverityCritical, results[3].Severity)
+	assert.Equal(t, challenge.StatusFailed, challenge.AssertionStatus(results))
+	assert.Equal(t,
+		challenge.StatusPassedWithWarnings,
+		challenge.AssertionStatus(results[:3]))
+
+	r := a.Evaluate(challenge.AssertionDef{
+		Type: "not_empty", Severity: "warning",
+	}, "x")
+	assert.Equal(t, challenge.SeverityWarning, r.Severity)
+}
@@ -0,0 +1,202 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ArgKind names a kind of value in a Descriptor: the kind of
+// a Definition Value, or of the target value an assertion
+// checks.
+type ArgKind string
+
+// Argument kinds. Numbers include numeric strings; durations
+// and sizes accept plain numbers (milliseconds and bytes).
+const (
+	ArgAny      ArgKind = "any"
+	ArgString   ArgKind = "string"
+	ArgNumber   ArgKind = "number"
+	ArgInteger  ArgKind = "integer"
+	ArgBool     ArgKind = "bool"
+	ArgDuration ArgKind = "duration"
+	ArgSize     ArgKind = "size"
+	ArgList     ArgKind = "list"
+	ArgObject   ArgKind = "object"
+
+	// ArgValues is the target kind of assertions evaluated
+	// against all named values rather than a single target.
+	ArgValues ArgKind = "values"
+)
+
+// accepts reports whether v is of kind k.
+func (k ArgKind) accepts(v any) bool {
+	kind, n := classify(v)
+	rv := reflect.ValueOf(v)
+	switch k {
+	case ArgAny:
+		return true
+	case ArgString:
+		_, ok := v.(string)
+		return ok
+	case ArgNumber:
+		return kind == kindNumber
+	case ArgInteger:
+		f, ok := n.(float64)
+		return kind == kindNumber && ok && f == math.Trunc(f)
+	case ArgBool:
+		return kind == kindBool
+	case ArgDuration:
+		return kind == kindDuration || kind == kindNumber
+	case ArgSize:
+		return kind == kindSize || kind == kindNumber
+	case ArgList:
+		return v != nil && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array)
+	case ArgObject:
+		return v != nil && rv.Kind() == reflect.Map
+	}
+	return false
+}
+
+// ValueSchema describes the fields of a Definition an
+// assertion type reads.
+type ValueSchema struct {
+	// Kinds lists the accepted kinds of Value; empty means the
+	// type takes no Value.
+	Kinds []ArgKind `json:"kinds,omitempty"`
+
+	// Required means Value (or Values, when the type reads
+	// them) must be set.
+	Required bool `json:"required,omitempty"`
+
+	// Values describes how Values is used; empty means it is
+	// not read.
+	Values string `json:"values,omitempty"`
+
+	// Assertions means the type evaluates sub-assertions.
+	Assertions bool `json:"assertions,omitempty"`
+
+	// Fields lists the other Definition fields read (e.g.,
+	// "tolerance", "ignore").
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Descriptor documents an assertion type for Engine.Catalog
+// and is used to validate definitions before evaluation.
+type Descriptor struct {
+	// Name is the assertion type.
+	Name string `json:"name"`
+
+	// Description explains what the assertion checks.
+	Description string `json:"description,omitempty"`
+
+	// Value describes the expected Definition fields.
+	Value ValueSchema `json:"value"`
+
+	// Target is the kind of target value checked.
+	Target ArgKind `json:"target,omitempty"`
+
+	// Examples are compact assertion lines (see
+	// ParseDefinition).
+	Examples []string `json:"examples,omitempty"`
+
+	// Plugin names the plugin that registered the type (e.g.,
+	// "panoptic", "userflow"); empty for built-in types.
+	Plugin string `json:"plugin,omitempty"`
+}
+
+// Validate checks the arguments of def against the descriptor:
+// a required Value is present and Value is of an accepted
+// kind. Sub-assertions are checked by the composite types.
+func (d Descriptor) Validate(def Definition) error {
+	s := d.Value
+	if s.Required && def.Value == nil && (s.Values == "" || len(def.Values) == 0) {
+		return fmt.Errorf("%s requires a value", d.Name)
+	}
+	if def.Value == nil || len(s.Kinds) == 0 {
+		return nil
+	}
+	for _, k := range s.Kinds {
+		if k.accepts(def.Value) {
+			return nil
+		}
+	}
+	kinds := make([]string, len(s.Kinds))
+	for i, k := range s.Kinds {
+		kinds[i] = string(k)
+	}
+	return fmt.Errorf("%s value must be %s, got %s",
+		d.Name, strings.Join(kinds, " or "), describeValue(def.Value))
+}
+
+// sortDescriptors orders descriptors by plugin (built-in types
+// first) and name.
+func sortDescriptors(descs []Descriptor) {
+	sort.Slice(descs, func(i, j int) bool {
+		if descs[i].Plugin != descs[j].Plugin {
+			return descs[i].Plugin < descs[j].Plugin
+		}
+		return descs[i].Name < descs[j].Name
+	})
+}
+
+// CatalogMarkdown renders descriptors as a Markdown reference
+// with one section per plugin.
+func CatalogMarkdown(descs []Descriptor) string {
+	var b strings.Builder
+	b.WriteString("# Assertion Catalog\n")
+	section := "\x00"
+	for _, d := range descs {
+		if d.Plugin != section {
+			section = d.Plugin
+			title := "Built-in"
+			if section != "" {
+				title = "Plugin " + section
+			}
+			fmt.Fprintf(&b, "\n## %s\n", title)
+		}
+		fmt.Fprintf(&b, "\n### `%s`\n\n", d.Name)
+		if d.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", d.Description)
+		}
+		if d.Target != "" {
+			fmt.Fprintf(&b, "- **Target:** %s\n", d.Target)
+		}
+		b.WriteString("- **Value:** " + d.Value.summary() + "\n")
+		if d.Value.Values != "" {
+			fmt.Fprintf(&b, "- **Values:** %s\n", d.Value.Values)
+		}
+		if d.Value.Assertions {
+			b.WriteString("- **Assertions:** required\n")
+		}
+		if len(d.Value.Fields) > 0 {
+			fmt.Fprintf(&b, "- **Fields:** %s\n", strings.Join(d.Value.Fields, ", "))
+		}
+		if len(d.Examples) > 0 {
+			b.WriteString("\n```\n")
+			for _, ex := range d.Examples {
+				b.WriteString(ex + "\n")
+			}
+			b.WriteString("```\n")
+		}
+	}
+	return b.String()
+}
+
+// summary describes the accepted Value.
+func (s ValueSchema) summary() string {
+	if len(s.Kinds) == 0 {
+		return "none"
+	}
+	kinds := make([]string, len(s.Kinds))
+	for i, k := range s.Kinds {
+		kinds[i] = string(k)
+	}
+	out := strings.Join(kinds, " | ")
+	if s.Required {
+		return out + " (required)"
+	}
+	return out + " (optional)"
+}
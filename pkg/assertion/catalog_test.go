@@ -0,0 +1,139 @@
+package assertion
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Catalog tests =====
+
+func TestEngine_Catalog(t *testing.T) {
+	e := NewEngine()
+	catalog := e.Catalog()
+	require.Len(t, catalog, 39)
+
+	names := make([]string, len(catalog))
+	for i, d := range catalog {
+		names[i] = d.Name
+		assert.Empty(t, d.Plugin, d.Name)
+		assert.NotEmpty(t, d.Description, d.Name)
+		assert.True(t, e.HasEvaluator(d.Name), d.Name)
+	}
+	assert.True(t, sort.StringsAreSorted(names))
+}
+
+// bareEngine implements Engine without a catalog.
+type bareEngine struct{}
+
+func (bareEngine) Evaluate(Definition, any) Result { return Result{} }
+
+func (bareEngine) EvaluateAll([]Definition, map[string]any) []Result {
+	return nil
+}
+
+func (bareEngine) Register(string, Evaluator) error { return nil }
+
+func TestEngine_Cataloger(t *testing.T) {
+	var e Engine = NewEngine()
+	c, ok := e.(Cataloger)
+	require.True(t, ok)
+	assert.Len(t, c.Catalog(), 39)
+
+	e = bareEngine{}
+	_, ok = e.(Cataloger)
+	assert.False(t, ok)
+}
+
+func TestEngine_Catalog_ExamplesAreValid(t *testing.T) {
+	e := NewEngine()
+	for _, d := range e.Catalog() {
+		require.NotEmpty(t, d.Examples, d.Name)
+		for _, ex := range d.Examples {
+			def, err := ParseDefinition(ex)
+			require.NoError(t, err, ex)
+			assert.Equal(t, d.Name, def.Type, ex)
+			assert.NoError(t, e.Validate(def), ex)
+		}
+	}
+}
+
+func TestEngine_RegisterDescriptor(t *testing.T) {
+	e := NewEngine()
+	noop := func(Definition, any) (bool, string) { return true, "" }
+
+	require.NoError(t, e.RegisterDescriptor(Descriptor{
+		Name: "custom_check", Plugin: "custom",
+		Value: ValueSchema{Kinds: []ArgKind{ArgNumber}, Required: true},
+	}, noop))
+	require.NoError(t, e.Register("bare_check", noop))
+
+	assert.Error(t, e.RegisterDescriptor(Descriptor{Name: "custom_check"}, noop))
+	assert.Error(t, e.RegisterDescriptor(Descriptor{}, noop))
+
+	catalog := e.Catalog()
+	require.Len(t, catalog, 41)
+	assert.Equal(t, "custom_check", catalog[40].Name)
+	assert.Equal(t, "custom", catalog[40].Plugin)
+
+	d, ok := e.Describe("bare_check")
+	require.True(t, ok)
+	assert.Equal(t, Descriptor{Name: "bare_check"}, d)
+	_, ok = e.Describe("missing")
+	assert.False(t, ok)
+
+	r := e.Evaluate(Definition{Type: "custom_check", Value: "x"}, nil)
+	assert.False(t, r.Passed)
+	assert.Equal(t, "invalid assertion: custom_check value must be number, got \"x\"", r.Message)
+	assert.True(t, e.Evaluate(Definition{Type: "custom_check", Value: 1}, nil).Passed)
+}
+
+func TestEngine_Validate(t *testing.T) {
+	e := NewEngine()
+	tests := []struct {
+		name string
+		def  Definition
+		err  string
+	}{
+		{"valid", Definition{Type: "contains", Value: "ok"}, ""},
+		{"no value needed", Definition{Type: "not_empty"}, ""},
+		{"missing value", Definition{Type: "contains"}, "contains requires a value"},
+		{"values instead of value", Definition{Type: "between", Values: []any{1, 2}}, ""},
+		{"wrong kind", Definition{Type: "min_length", Value: "ten"}, "min_length value must be integer, got \"ten\""},
+		{"not an integer", Definition{Type: "min_count", Value: 2.5}, "min_count value must be integer"},
+		{"numeric string", Definition{Type: "max_latency", Value: "500"}, ""},
+		{"duration", Definition{Type: "p95_below", Value: "200ms"}, ""},
+		{"size", Definition{Type: "less_than", Value: "1GB"}, ""},
+		{"object", Definition{Type: "json_schema", Value: map[string]any{}}, ""},
+		{"unknown type", Definition{Type: "nope"}, "unknown assertion type: nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := e.Validate(tt.def)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestCatalogMarkdown(t *testing.T) {
+	md := CatalogMarkdown([]Descriptor{
+		{
+			Name: "contains", Description: "Substring check.", Target: ArgString,
+			Value:    ValueSchema{Kinds: []ArgKind{ArgString}, Required: true},
+			Examples: []string{`out | contains("ok")`},
+		},
+		{Name: "app_count", Plugin: "panoptic", Value: ValueSchema{Fields: []string{"tolerance"}}},
+	})
+
+	assert.Contains(t, md, "# Assertion Catalog\n\n## Built-in\n\n### `contains`\n\nSubstring check.\n")
+	assert.Contains(t, md, "- **Target:** string\n- **Value:** string (required)\n")
+	assert.Contains(t, md, "```\nout | contains(\"ok\")\n```\n")
+	assert.Contains(t, md, "## Plugin panoptic\n\n### `app_count`\n\n- **Value:** none\n- **Fields:** tolerance\n")
+}
@@ -0,0 +1,437 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparison assertion types. Values are coerced to a common
+// unit before comparing:
+//
+//   - numbers and numeric strings compare as numbers;
+//   - durations (time.Duration or "150ms", "2s") compare in
+//     milliseconds, and a plain number next to a duration is
+//     taken as milliseconds;
+//   - sizes ("512B", "1.5KB", "10MiB") compare in bytes, and a
+//     plain number next to a size is taken as bytes;
+//   - bools compare with bools and "true"/"false";
+//   - other strings compare lexically.
+//
+// Result.Expected and Result.Actual hold the coerced values.
+// Tolerance (absolute, in the comparison unit or as a duration
+// or size string) and RelativeTolerance (a fraction of the
+// expected value) apply to equals, not_equals, approx and
+// between; the larger of the two wins.
+const (
+	TypeEquals         = "equals"
+	TypeNotEquals      = "not_equals"
+	TypeGreaterThan    = "greater_than"
+	TypeGreaterOrEqual = "greater_or_equal"
+	TypeLessThan       = "less_than"
+	TypeLessOrEqual    = "less_or_equal"
+	TypeBetween        = "between"
+	TypeApprox         = "approx"
+	TypeRegex          = "regex"
+)
+
+// valueKind is the unit two values were coerced to.
+type valueKind int
+
+const (
+	kindOther valueKind = iota
+	kindNumber
+	kindDuration
+	kindSize
+	kindBool
+	kindString
+)
+
+// comparison is the outcome of a comparison evaluator.
+type comparison struct {
+	passed           bool
+	message          string
+	expected, actual any
+}
+
+// comparator evaluates an assertion and reports the compared
+// values in their normalized units.
+type comparator func(assertion Definition, value any) comparison
+
+// evaluator adapts c to the Evaluator signature.
+func (c comparator) evaluator() Evaluator {
+	return func(a Definition, value any) (bool, string) {
+		r := c(a, value)
+		return r.passed, r.message
+	}
+}
+
+// comparators returns the built-in comparison evaluators.
+func comparators() map[string]comparator {
+	return map[string]comparator{
+		TypeEquals:         compareEquals(false),
+		TypeNotEquals:      compareEquals(true),
+		TypeGreaterThan:    compareOrder(">", func(c int) bool { return c > 0 }),
+		TypeGreaterOrEqual: compareOrder(">=", func(c int) bool { return c >= 0 }),
+		TypeLessThan:       compareOrder("<", func(c int) bool { return c < 0 }),
+		TypeLessOrEqual:    compareOrder("<=", func(c int) bool { return c <= 0 }),
+		TypeBetween:        compareBetween,
+		TypeApprox:         compareApprox,
+		TypeRegex:          compareRegex,
+	}
+}
+
+// compareEquals checks equality, or inequality when negate is
+// set, within the assertion's tolerance.
+func compareEquals(negate bool) comparator {
+	return func(a Definition, value any) comparison {
+		kind, actual, expected, err := coercePair(value, a.Value)
+		if err != nil {
+			return comparison{message: err.Error(), expected: a.Value, actual: value}
+		}
+		c := comparison{expected: expected, actual: actual}
+
+		equal := false
+		detail := ""
+		if isNumericKind(kind) {
+			tol, err := tolerance(a, kind, expected.(float64))
+			if err != nil {
+				c.message = err.Error()
+				return c
+			}
+			diff := math.Abs(actual.(float64) - expected.(float64))
+			equal = diff <= tol
+			if tol > 0 {
+				detail = fmt.Sprintf(" (difference %s, tolerance %s)",
+					formatUnit(kind, diff), formatUnit(kind, tol))
+			}
+		} else {
+			equal = reflect.DeepEqual(actual, expected)
+		}
+
+		c.passed = equal != negate
+		op := "=="
+		if !equal {
+			op = "!="
+		}
+		c.message = fmt.Sprintf("%s %s %s%s",
+			formatUnit(kind, actual), op, formatUnit(kind, expected), detail)
+		return c
+	}
+}
+
+// compareOrder checks an ordering relation.
+func compareOrder(op string, ok func(int) bool) comparator {
+	return func(a Definition, value any) comparison {
+		kind, actual, expected, err := coercePair(value, a.Value)
+		if err != nil {
+			return comparison{message: err.Error(), expected: a.Value, actual: value}
+		}
+		c := comparison{expected: expected, actual: actual}
+		order, err := compareCoerced(kind, actual, expected)
+		if err != nil {
+			c.message = err.Error()
+			return c
+		}
+		c.passed = ok(order)
+		verdict := "is"
+		if !c.passed {
+			verdict = "is not"
+		}
+		c.message = fmt.Sprintf("%s %s %s %s",
+			formatUnit(kind, actual), verdict, op, formatUnit(kind, expected))
+		return c
+	}
+}
+
+// compareBetween checks lo <= value <= hi. The bounds come from
+// Values, or from Value when it is a two-element list.
+func compareBetween(a Definition, value any) comparison {
+	bounds := a.Values
+	if len(bounds) == 0 {
+		if list, ok := a.Value.([]any); ok {
+			bounds = list
+		}
+	}
+	if len(bounds) != 2 {
+		return comparison{
+			message:  "between requires two bounds in values",
+			expected: a.Values, actual: value,
+		}
+	}
+
+	kind, actual, lo, err := coercePair(value, bounds[0])
+	if err != nil {
+		return comparison{message: err.Error(), expected: bounds, actual: value}
+	}
+	hiKind, _, hi, err := coercePair(value, bounds[1])
+	if err == nil && hiKind != kind {
+		err = fmt.Errorf("bounds %v and %v have different units", bounds[0], bounds[1])
+	}
+	if err != nil {
+		return comparison{message: err.Error(), expected: bounds, actual: value}
+	}
+	c := comparison{expected: []any{lo, hi}, actual: actual}
+
+	var tolLo, tolHi float64
+	if isNumericKind(kind) {
+		if tolLo, err = tolerance(a, kind, lo.(float64)); err == nil {
+			tolHi, err = tolerance(a, kind, hi.(float64))
+		}
+		if err != nil {
+			c.message = err.Error()
+			return c
+		}
+	}
+	above, err := compareCoerced(kind, actual, shift(lo, -tolLo))
+	if err == nil {
+		var below int
+		below, err = compareCoerced(kind, actual, shift(hi, tolHi))
+		c.passed = above >= 0 && below <= 0
+	}
+	if err != nil {
+		c.message = err.Error()
+		return c
+	}
+	verdict := "is"
+	if !c.passed {
+		verdict = "is not"
+	}
+	c.message = fmt.Sprintf("%s %s between %s and %s",
+		formatUnit(kind, actual), verdict,
+		formatUnit(kind, lo), formatUnit(kind, hi))
+	return c
+}
+
+// compareApprox is equals for numeric units that requires an
+// explicit tolerance.
+func compareApprox(a Definition, value any) comparison {
+	if a.Tolerance == nil && a.RelativeTolerance == 0 {
+		return comparison{
+			message:  "approx requires tolerance or relative_tolerance",
+			expected: a.Value, actual: value,
+		}
+	}
+	kind, _, _, err := coercePair(value, a.Value)
+	if err == nil && !isNumericKind(kind) {
+		err = fmt.Errorf("approx needs numeric values, got %v and %v", value, a.Value)
+	}
+	if err != nil {
+		return comparison{message: err.Error(), expected: a.Value, actual: value}
+	}
+	return compareEquals(false)(a, value)
+}
+
+// compareRegex matches the string form of the value against the
+// expected pattern.
+func compareRegex(a Definition, value any) comparison {
+	pattern, ok := a.Value.(string)
+	if !ok {
+		return comparison{
+			message: "regex pattern must be a string", expected: a.Value, actual: value,
+		}
+	}
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	c := comparison{expected: pattern, actual: s}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		c.message = fmt.Sprintf("invalid regex: %v", err)
+		return c
+	}
+	c.passed = re.MatchString(s)
+	if c.passed {
+		c.message = fmt.Sprintf("%q matches /%s/", s, pattern)
+	} else {
+		c.message = fmt.Sprintf("%q does not match /%s/", s, pattern)
+	}
+	return c
+}
+
+// coercePair converts actual and expected to a common unit.
+func coercePair(actual, expected any) (valueKind, any, any, error) {
+	ak, av := classify(actual)
+	ek, ev := classify(expected)
+
+	switch {
+	case ak == ek:
+		return ak, av, ev, nil
+	case ak == kindNumber && (ek == kindDuration || ek == kindSize):
+		return ek, av, ev, nil
+	case ek == kindNumber && (ak == kindDuration || ak == kindSize):
+		return ak, av, ev, nil
+	case ak == kindBool || ek == kindBool:
+		ab, aok := asBool(actual)
+		eb, eok := asBool(expected)
+		if aok && eok {
+			return kindBool, ab, eb, nil
+		}
+	case ak == kindString || ek == kindString:
+		if ak != kindOther && ek != kindOther {
+			return kindString, fmt.Sprint(actual), fmt.Sprint(expected), nil
+		}
+	}
+	if ak == kindOther && ek == kindOther {
+		return kindOther, actual, expected, nil
+	}
+	return kindOther, actual, expected, fmt.Errorf(
+		"cannot compare %v with %v", describeValue(actual), describeValue(expected),
+	)
+}
+
+// classify determines the unit of a single value and converts
+// it to its normalized form.
+func classify(v any) (valueKind, any) {
+	switch x := v.(type) {
+	case nil:
+		return kindOther, nil
+	case bool:
+		return kindBool, x
+	case time.Duration:
+		return kindDuration, float64(x) / float64(time.Millisecond)
+	case string:
+		s := strings.TrimSpace(x)
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return kindNumber, f
+		}
+		if d, err := time.ParseDuration(s); err == nil {
+			return kindDuration, float64(d) / float64(time.Millisecond)
+		}
+		if n, ok := parseSize(s); ok {
+			return kindSize, n
+		}
+		return kindString, x
+	}
+	if f, ok := toFloat64(v); ok {
+		return kindNumber, f
+	}
+	if n, ok := toInt64(v); ok {
+		return kindNumber, float64(n)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return kindNumber, float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kindNumber, float64(rv.Uint())
+	case reflect.Float32:
+		return kindNumber, rv.Float()
+	}
+	return kindOther, v
+}
+
+// sizeUnits maps size suffixes to bytes.
+var sizeUnits = map[string]float64{
+	"b":  1,
+	"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+}
+
+var sizeRe = regexp.MustCompile(`^([0-9]*\.?[0-9]+)\s*([a-zA-Z]+)$`)
+
+// parseSize parses sizes such as "512B", "1.5 KB" or "10MiB".
+func parseSize(s string) (float64, bool) {
+	m := sizeRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	unit, ok := sizeUnits[strings.ToLower(m[2])]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * unit, true
+}
+
+// tolerance returns the effective absolute tolerance for an
+// expected value of the given unit.
+func tolerance(a Definition, kind valueKind, expected float64) (float64, error) {
+	abs := 0.0
+	if a.Tolerance != nil {
+		tk, tv := classify(a.Tolerance)
+		if tk != kind && tk != kindNumber {
+			return 0, fmt.Errorf("tolerance %v does not match the compared unit", a.Tolerance)
+		}
+		f, ok := tv.(float64)
+		if !ok || f < 0 {
+			return 0, fmt.Errorf("invalid tolerance %v", a.Tolerance)
+		}
+		abs = f
+	}
+	if a.RelativeTolerance < 0 {
+		return 0, fmt.Errorf("invalid relative tolerance %v", a.RelativeTolerance)
+	}
+	return math.Max(abs, a.RelativeTolerance*math.Abs(expected)), nil
+}
+
+// compareCoerced orders two coerced values.
+func compareCoerced(kind valueKind, a, b any) (int, error) {
+	if isNumericKind(kind) {
+		return cmpFloat(a.(float64), b.(float64)), nil
+	}
+	if kind == kindString {
+		return strings.Compare(a.(string), b.(string)), nil
+	}
+	return 0, fmt.Errorf(
+		"%s and %s cannot be ordered", describeValue(a), describeValue(b),
+	)
+}
+
+// shift adds delta to numeric coerced values.
+func shift(v any, delta float64) any {
+	if f, ok := v.(float64); ok {
+		return f + delta
+	}
+	return v
+}
+
+func isNumericKind(k valueKind) bool {
+	return k == kindNumber || k == kindDuration || k == kindSize
+}
+
+func asBool(v any) (bool, bool) {
+	switch x := v.(type) {
+	case bool:
+		return x, true
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(x))
+		return b, err == nil
+	}
+	return false, false
+}
+
+// formatUnit renders a coerced value with its unit.
+func formatUnit(kind valueKind, v any) string {
+	f, _ := v.(float64)
+	switch kind {
+	case kindNumber:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case kindDuration:
+		return time.Duration(f * float64(time.Millisecond)).String()
+	case kindSize:
+		return strconv.FormatFloat(f, 'f', -1, 64) + "B"
+	case kindString:
+		return strconv.Quote(v.(string))
+	}
+	return fmt.Sprint(v)
+}
+
+// describeValue renders a raw value for error messages.
+func describeValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%v (%T)", v, v)
+}
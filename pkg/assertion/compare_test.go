@@ -0,0 +1,133 @@
+package assertion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Comparison family tests =====
+
+func TestComparisons(t *testing.T) {
+	e := NewEngine()
+	tests := []struct {
+		name   string
+		def    Definition
+		value  any
+		passed bool
+	}{
+		{"equals string", Definition{Type: "equals", Value: "BUILD SUCCESS"}, "BUILD SUCCESS", true},
+		{"equals string mismatch", Definition{Type: "equals", Value: "BUILD SUCCESS"}, "BUILD FAILED", false},
+		{"equals numeric string", Definition{Type: "equals", Value: 3}, "3.0", true},
+		{"equals int64", Definition{Type: "equals", Value: float64(7)}, int64(7), true},
+		{"equals uint", Definition{Type: "equals", Value: 7}, uint8(7), true},
+		{"equals bool", Definition{Type: "equals", Value: true}, "true", true},
+		{"equals durations", Definition{Type: "equals", Value: "1.5s"}, 1500 * time.Millisecond, true},
+		{"equals sizes", Definition{Type: "equals", Value: "1KiB"}, "1024B", true},
+		{"equals lists", Definition{Type: "equals", Value: []any{"a"}}, []any{"a"}, true},
+		{"equals tolerance", Definition{Type: "equals", Value: 100, Tolerance: 0.5}, 100.4, true},
+		{"not_equals", Definition{Type: "not_equals", Value: "ok"}, "failed", true},
+		{"not_equals same", Definition{Type: "not_equals", Value: 2}, 2, false},
+		{"greater_than", Definition{Type: "greater_than", Value: 10}, 11, true},
+		{"greater_than equal", Definition{Type: "greater_than", Value: 10}, 10, false},
+		{"greater_or_equal", Definition{Type: "greater_or_equal", Value: 10}, "10", true},
+		{"less_than duration", Definition{Type: "less_than", Value: "2s"}, "1500ms", true},
+		{"less_than duration ms number", Definition{Type: "less_than", Value: "1s"}, 999, true},
+		{"less_or_equal size", Definition{Type: "less_or_equal", Value: "10MB"}, 10_000_000, true},
+		{"less_than strings", Definition{Type: "less_than", Value: "b"}, "a", true},
+		{"between", Definition{Type: "between", Values: []any{1, 5}}, 5, true},
+		{"between below", Definition{Type: "between", Values: []any{1, 5}}, 0.5, false},
+		{"between value list", Definition{Type: "between", Value: []any{"1s", "2s"}}, "1200ms", true},
+		{"between tolerance", Definition{Type: "between", Values: []any{1, 5}, Tolerance: 1}, 5.9, true},
+		{"approx relative", Definition{Type: "approx", Value: 200, RelativeTolerance: 0.05}, 209, true},
+		{"approx relative outside", Definition{Type: "approx", Value: 200, RelativeTolerance: 0.05}, 211, false},
+		{"approx duration tolerance", Definition{Type: "approx", Value: "1s", Tolerance: "50ms"}, "1.04s", true},
+		{"regex", Definition{Type: "regex", Value: `^v\d+\.\d+`}, "v1.2.3", true},
+		{"regex number", Definition{Type: "regex", Value: `^4\d\d$`}, 404, true},
+		{"regex mismatch", Definition{Type: "regex", Value: `^ok$`}, "nope", false},
+	}
+	for _, tt := range tests {
+		r := e.Evaluate(tt.def, tt.value)
+		assert.Equal(t, tt.passed, r.Passed, "%s: %s", tt.name, r.Message)
+	}
+}
+
+func TestComparisons_Errors(t *testing.T) {
+	e := NewEngine()
+	tests := []struct {
+		def   Definition
+		value any
+		msg   string
+	}{
+		{Definition{Type: "greater_than", Value: "2s"}, "10MB", "cannot compare"},
+		{Definition{Type: "greater_than", Value: true}, false, "greater_than value must be number"},
+		{Definition{Type: "equals", Value: true}, 1, "cannot compare"},
+		{Definition{Type: "between", Values: []any{1}}, 1, "two bounds"},
+		{Definition{Type: "between", Values: []any{"1s", "1KB"}}, "1s", "cannot compare"},
+		{Definition{Type: "approx", Value: 1}, 1, "requires tolerance"},
+		{Definition{Type: "approx", Value: "a", Tolerance: 1}, "a", "approx value must be number"},
+		{Definition{Type: "equals", Value: "1s", Tolerance: "1KB"}, "1s", "does not match"},
+		{Definition{Type: "equals", Value: 1, RelativeTolerance: -1}, 1, "invalid relative"},
+		{Definition{Type: "regex", Value: "("}, "x", "invalid regex"},
+		{Definition{Type: "regex", Value: 1}, "x", "regex value must be string"},
+	}
+	for _, tt := range tests {
+		r := e.Evaluate(tt.def, tt.value)
+		assert.False(t, r.Passed, tt.msg)
+		assert.Contains(t, r.Message, tt.msg)
+	}
+}
+
+func TestComparisons_NormalizedResult(t *testing.T) {
+	e := NewEngine()
+
+	r := e.Evaluate(Definition{
+		Type: "less_than", Target: "latency", Value: "2s",
+	}, 2500*time.Millisecond)
+	assert.False(t, r.Passed)
+	assert.Equal(t, float64(2000), r.Expected)
+	assert.Equal(t, float64(2500), r.Actual)
+	assert.Equal(t, "2.5s is not < 2s", r.Message)
+
+	r = e.Evaluate(Definition{Type: "equals", Value: "1.5KB", Tolerance: "10B"}, 1505)
+	assert.True(t, r.Passed)
+	assert.Equal(t, float64(1500), r.Expected)
+	assert.Equal(t, float64(1505), r.Actual)
+	assert.Equal(t, "1505B == 1500B (difference 5B, tolerance 10B)", r.Message)
+
+	r = e.Evaluate(Definition{Type: "between", Values: []any{"1", "3"}}, "2")
+	assert.Equal(t, []any{float64(1), float64(3)}, r.Expected)
+	assert.Equal(t, "2 is between 1 and 3", r.Message)
+}
+
+func TestComparisons_ThroughEvaluateAll(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{
+		{Type: "equals", Target: "build_result", Value: "BUILD SUCCESS"},
+		{Type: "greater_than", Target: "resp.items[*].score", Value: 2},
+	}, map[string]any{
+		"build_result": "BUILD SUCCESS",
+		"resp":         `{"items": [{"score": 3}, {"score": 1}]}`,
+	})
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.True(t, results[1].Passed)
+	assert.False(t, results[2].Passed)
+	assert.Equal(t, "resp.items[1].score", results[2].Target)
+}
+
+func TestParseSize(t *testing.T) {
+	for s, want := range map[string]float64{
+		"512B": 512, "1.5 KB": 1500, "2mib": 2 << 20, "1GB": 1e9,
+	} {
+		got, ok := parseSize(s)
+		require.True(t, ok, s)
+		assert.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"10", "10 parsecs", "MB"} {
+		_, ok := parseSize(s)
+		assert.False(t, ok, s)
+	}
+}
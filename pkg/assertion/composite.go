@@ -1,6 +1,9 @@
 package assertion
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // AllPassComposite creates an Evaluator that checks whether all
 // results in a slice have passed. It is a wrapper around the
@@ -98,3 +101,154 @@ func CompositeAnyPass(
 		return r.Passed, r.Message
 	}
 }
+
+// Composite assertion types combine the results of their
+// sub-assertions (Definition.Assertions). A sub-assertion
+// without a Target inherits the Target of its parent.
+const (
+	TypeAllOf   = "all_of"
+	TypeAnyOf   = "any_of"
+	TypeNoneOf  = "none_of"
+	TypeAtLeast = "at_least" // Value holds the required count.
+	TypeNot     = "not"      // Takes exactly one sub-assertion.
+)
+
+// compositeRule decides a composite assertion from the number
+// of passed and total sub-assertions.
+type compositeRule func(a Definition, passed, total int) (bool, error)
+
+// compositeRules returns the built-in composite rules.
+func compositeRules() map[string]compositeRule {
+	return map[string]compositeRule{
+		TypeAllOf: func(_ Definition, passed, total int) (bool, error) {
+			return passed == total, nil
+		},
+		TypeAnyOf: func(_ Definition, passed, _ int) (bool, error) {
+			return passed > 0, nil
+		},
+		TypeNoneOf: func(_ Definition, passed, _ int) (bool, error) {
+			return passed == 0, nil
+		},
+		TypeAtLeast: func(a Definition, passed, total int) (bool, error) {
+			n, err := atLeastCount(a.Value)
+			if err != nil {
+				return false, err
+			}
+			if n > total {
+				return false, fmt.Errorf(
+					"at_least %d exceeds the %d sub-assertions", n, total,
+				)
+			}
+			return passed >= n, nil
+		},
+		TypeNot: func(_ Definition, passed, total int) (bool, error) {
+			if total != 1 {
+				return false, fmt.Errorf(
+					"not takes exactly one sub-assertion, got %d", total,
+				)
+			}
+			return passed == 0, nil
+		},
+	}
+}
+
+// IsComposite reports whether assertionType is a built-in
+// composite type that evaluates sub-assertions.
+func IsComposite(assertionType string) bool {
+	_, ok := compositeRules()[assertionType]
+	return ok
+}
+
+// atLeastCount reads the count of an at_least assertion.
+func atLeastCount(v any) (int, error) {
+	kind, n := classify(v)
+	f, _ := n.(float64)
+	if kind != kindNumber || f < 0 || f != float64(int(f)) {
+		return 0, fmt.Errorf("at_least requires a non-negative integer value, got %v", v)
+	}
+	return int(f), nil
+}
+
+// evaluateComposite evaluates a composite assertion against the
+// values map, keeping each sub-result in Result.Children.
+func (e *DefaultEngine) evaluateComposite(
+	a Definition,
+	rule compositeRule,
+	values map[string]any,
+	snapshots *SnapshotStore,
+) Result {
+	r := Result{Type: a.Type, Target: a.Target, Expected: a.Value}
+	if len(a.Assertions) == 0 {
+		r.Message = fmt.Sprintf("%s requires sub-assertions", a.Type)
+		return r
+	}
+
+	passed := 0
+	var failed, succeeded []string
+	for _, sub := range a.Assertions {
+		if sub.Target == "" {
+			sub.Target = a.Target
+		}
+		child := e.evaluateChild(sub, values, snapshots)
+		r.Children = append(r.Children, child)
+		line := fmt.Sprintf("%s %s: %s", child.Type, child.Target, child.Message)
+		if child.Passed {
+			passed++
+			succeeded = append(succeeded, line)
+		} else {
+			failed = append(failed, line)
+		}
+	}
+	r.Actual = passed
+
+	ok, err := rule(a, passed, len(a.Assertions))
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	r.Passed = ok
+	r.Message = fmt.Sprintf(
+		"%s: %d of %d sub-assertions passed",
+		a.Type, passed, len(a.Assertions),
+	)
+	switch {
+	case ok:
+	case a.Type == TypeNoneOf || a.Type == TypeNot:
+		r.Message += " (passed: " + strings.Join(succeeded, "; ") + ")"
+	default:
+		r.Message += " (failed: " + strings.Join(failed, "; ") + ")"
+	}
+	return r
+}
+
+// evaluateChild evaluates one sub-assertion. When its target
+// selects several elements, the per-element results are grouped
+// under a single Result that passes only if all of them pass.
+func (e *DefaultEngine) evaluateChild(
+	sub Definition,
+	values map[string]any,
+	snapshots *SnapshotStore,
+) Result {
+	results := e.evaluateAll([]Definition{sub}, values, snapshots)
+	if len(results) == 1 {
+		return results[0]
+	}
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+	return Result{
+		Type:     sub.Type,
+		Target:   sub.Target,
+		Expected: sub.Value,
+		Actual:   passed,
+		Passed:   passed == len(results),
+		Message: fmt.Sprintf(
+			"%d of %d matches passed", passed, len(results),
+		),
+		Severity: sub.Severity,
+		Children: results,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAllPassComposite_AllPass(t *testing.T) {
@@ -134,3 +135,135 @@ func TestParseAssertionString(t *testing.T) {
 		})
 	}
 }
+
+// ===== Declarative composite tests =====
+
+func TestDefaultEngine_CompositeTypes(t *testing.T) {
+	e := NewEngine()
+	values := map[string]any{"status": "degraded", "latency_ms": 120}
+	status := func(v string) Definition {
+		return Definition{Type: "equals", Value: v}
+	}
+
+	tests := []struct {
+		name   string
+		def    Definition
+		passed bool
+	}{
+		{"any_of", Definition{Type: "any_of", Target: "status",
+			Assertions: []Definition{status("ok"), status("degraded")}}, true},
+		{"all_of", Definition{Type: "all_of", Target: "status",
+			Assertions: []Definition{status("ok"), status("degraded")}}, false},
+		{"none_of", Definition{Type: "none_of", Target: "status",
+			Assertions: []Definition{status("down"), status("failed")}}, true},
+		{"at_least", Definition{Type: "at_least", Value: 2, Assertions: []Definition{
+			{Type: "less_than", Target: "latency_ms", Value: 200},
+			{Type: "equals", Target: "status", Value: "ok"},
+			{Type: "not_empty", Target: "status"},
+		}}, true},
+		{"at_least unmet", Definition{Type: "at_least", Value: 3, Target: "status",
+			Assertions: []Definition{status("ok"), status("degraded"), status("down")}}, false},
+		{"not", Definition{Type: "not", Target: "status",
+			Assertions: []Definition{status("down")}}, true},
+		{"nested", Definition{Type: "all_of", Assertions: []Definition{
+			{Type: "less_than", Target: "latency_ms", Value: 200},
+			{Type: "any_of", Target: "status",
+				Assertions: []Definition{status("ok"), status("degraded")}},
+		}}, true},
+	}
+	for _, tt := range tests {
+		results := e.EvaluateAll([]Definition{tt.def}, values)
+		require.Len(t, results, 1, tt.name)
+		assert.Equal(t, tt.passed, results[0].Passed, "%s: %s", tt.name, results[0].Message)
+		assert.Len(t, results[0].Children, len(tt.def.Assertions), tt.name)
+	}
+}
+
+func TestDefaultEngine_CompositeResultTree(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{{
+		Type: "all_of",
+		Assertions: []Definition{
+			{Type: "less_than", Target: "latency_ms", Value: 200},
+			{Type: "any_of", Target: "status", Assertions: []Definition{
+				{Type: "equals", Value: "ok"},
+				{Type: "equals", Value: "degraded"},
+			}},
+		},
+	}}, map[string]any{"status": "down", "latency_ms": 120})
+
+	require.Len(t, results, 1)
+	root := results[0]
+	assert.False(t, root.Passed)
+	assert.Equal(t, 1, root.Actual)
+	assert.Contains(t, root.Message, "all_of: 1 of 2 sub-assertions passed")
+	assert.Contains(t, root.Message, "failed: any_of status")
+
+	require.Len(t, root.Children, 2)
+	assert.True(t, root.Children[0].Passed)
+	branch := root.Children[1]
+	assert.False(t, branch.Passed)
+	require.Len(t, branch.Children, 2)
+	assert.Equal(t, "status", branch.Children[0].Target)
+	assert.Equal(t, `"down" != "ok"`, branch.Children[0].Message)
+}
+
+func TestDefaultEngine_CompositeWildcardChild(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{{
+		Type: "any_of",
+		Assertions: []Definition{
+			{Type: "greater_than", Target: "items[*].score", Value: 2},
+			{Type: "equals", Target: "items[0].score", Value: 3},
+		},
+	}}, map[string]any{"items": `[{"score": 3}, {"score": 1}]`})
+
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed)
+	grouped := results[0].Children[0]
+	assert.False(t, grouped.Passed)
+	assert.Equal(t, "1 of 2 matches passed", grouped.Message)
+	require.Len(t, grouped.Children, 2)
+	assert.Equal(t, "items[1].score", grouped.Children[1].Target)
+}
+
+func TestDefaultEngine_CompositeErrors(t *testing.T) {
+	e := NewEngine()
+	tests := []struct {
+		def Definition
+		msg string
+	}{
+		{Definition{Type: "all_of"}, "requires sub-assertions"},
+		{Definition{Type: "not", Assertions: []Definition{
+			{Type: "not_empty"}, {Type: "not_empty"},
+		}}, "exactly one"},
+		{Definition{Type: "at_least", Value: "two", Assertions: []Definition{
+			{Type: "not_empty"},
+		}}, "at_least value must be integer"},
+		{Definition{Type: "at_least", Value: 2, Assertions: []Definition{
+			{Type: "not_empty"},
+		}}, "exceeds"},
+	}
+	for _, tt := range tests {
+		r := e.Evaluate(tt.def, "x")
+		assert.False(t, r.Passed, tt.msg)
+		assert.Contains(t, r.Message, tt.msg)
+	}
+}
+
+func TestDefaultEngine_CompositeSingleValue(t *testing.T) {
+	e := NewEngine()
+	def := Definition{Type: "any_of", Assertions: []Definition{
+		{Type: "contains", Value: "error"},
+		{Type: "min_length", Value: 5},
+	}}
+	r := e.Evaluate(def, "ok")
+	assert.False(t, r.Passed)
+	require.Len(t, r.Children, 2)
+
+	evaluator := CompositeAllPass(e, []Definition{def})
+	passed, _ := evaluator(Definition{}, "hello world")
+	assert.True(t, passed)
+	assert.True(t, IsComposite("none_of"))
+	assert.False(t, IsComposite("all_pass"))
+}
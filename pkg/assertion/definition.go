@@ -1,5 +1,5 @@
 // Package assertion provides an extensible assertion evaluation
-// engine for the Challenges module. It ships with 16 built-in
+// engine for the Challenges module. It ships with 39 built-in
 // evaluator types and supports custom evaluator registration.
 package assertion
 
@@ -10,16 +10,38 @@ type Definition struct {
 	// "not_empty", "min_length").
 	Type string `json:"type"`
 
-	// Target is the name of the output or metric to check.
+	// Target is the name of the output or metric to check, or
+	// a Path into nested values (e.g., "response.items[*].id").
 	Target string `json:"target"`
 
 	// Value is the expected value for single-value assertions.
 	Value any `json:"value,omitempty"`
 
 	// Values holds expected values for multi-value assertions
-	// (e.g., "contains_any").
+	// (e.g., "contains_any", "between").
 	Values []any `json:"values,omitempty"`
 
+	// Tolerance is the absolute tolerance of numeric
+	// comparisons: a number in the compared unit, or a
+	// duration or size string (e.g., "5ms", "1KB").
+	Tolerance any `json:"tolerance,omitempty"`
+
+	// RelativeTolerance is a tolerance as a fraction of the
+	// expected value (e.g., 0.05 for 5%).
+	RelativeTolerance float64 `json:"relative_tolerance,omitempty"`
+
+	// Assertions holds the sub-assertions of composite types
+	// ("all_of", "any_of", "none_of", "at_least", "not").
+	Assertions []Definition `json:"assertions,omitempty"`
+
+	// Ignore lists paths skipped when comparing JSON snapshots
+	// (e.g., "items[*].created_at").
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Severity is copied to the Result ("critical", "error",
+	// "warning" or "info"); the engine does not interpret it.
+	Severity string `json:"severity,omitempty"`
+
 	// Message is a human-readable description shown on
 	// failure.
 	Message string `json:"message"`
@@ -44,4 +66,16 @@ type Result struct {
 
 	// Message is a human-readable description of the outcome.
 	Message string `json:"message"`
+
+	// Severity is the Severity of the Definition.
+	Severity string `json:"severity,omitempty"`
+
+	// Stats holds the statistics computed by statistical
+	// assertions (e.g., "mean", "p95"), in the unit of the
+	// samples.
+	Stats map[string]float64 `json:"stats,omitempty"`
+
+	// Children holds the results of the sub-assertions of a
+	// composite assertion, in definition order.
+	Children []Result `json:"children,omitempty"`
 }
@@ -0,0 +1,292 @@
+package assertion
+
+// Value kinds shared by the built-in descriptors.
+var (
+	quantityKinds = []ArgKind{ArgNumber, ArgDuration, ArgSize}
+	orderedKinds  = []ArgKind{ArgNumber, ArgDuration, ArgSize, ArgString}
+	toleranceArgs = []string{"tolerance", "relative_tolerance"}
+)
+
+// builtinDescriptors documents the 39 built-in assertion types.
+func builtinDescriptors() []Descriptor {
+	required := func(kinds ...ArgKind) ValueSchema {
+		return ValueSchema{Kinds: kinds, Required: true}
+	}
+	composite := func(name, desc, example string) Descriptor {
+		return Descriptor{
+			Name: name, Description: desc, Target: ArgAny,
+			Value:    ValueSchema{Assertions: true},
+			Examples: []string{example},
+		}
+	}
+	statistic := func(name, desc, example string) Descriptor {
+		return Descriptor{
+			Name: name, Description: desc, Target: ArgList,
+			Value: required(quantityKinds...), Examples: []string{example},
+		}
+	}
+
+	return []Descriptor{
+		{
+			Name:        "not_empty",
+			Description: "The value is not nil, a blank string, an empty list or an empty map.",
+			Target:      ArgAny,
+			Examples:    []string{"response | not_empty()"},
+		},
+		{
+			Name:        "not_mock",
+			Description: "The string does not contain mock or placeholder text such as \"lorem ipsum\" or \"TODO\".",
+			Target:      ArgString,
+			Examples:    []string{"response | not_mock()"},
+		},
+		{
+			Name:        "contains",
+			Description: "The string contains value, ignoring case.",
+			Target:      ArgString,
+			Value:       required(ArgString),
+			Examples:    []string{`response | contains("success")`},
+		},
+		{
+			Name:        "contains_any",
+			Description: "The string contains at least one of the substrings, ignoring case.",
+			Target:      ArgString,
+			Value: ValueSchema{
+				Kinds: []ArgKind{ArgList, ArgString}, Required: true,
+				Values: "substrings, when value is not set",
+			},
+			Examples: []string{
+				`response | contains_any(["ok", "done"])`,
+				`response | contains_any("ok,done")`,
+			},
+		},
+		{
+			Name:        "min_length",
+			Description: "The string has at least value characters.",
+			Target:      ArgString,
+			Value:       required(ArgInteger),
+			Examples:    []string{"response | min_length(100)"},
+		},
+		{
+			Name:        "quality_score",
+			Description: "The number is at least value.",
+			Target:      ArgNumber,
+			Value:       required(ArgNumber),
+			Examples:    []string{"score | quality_score(0.8)"},
+		},
+		{
+			Name:        "reasoning_present",
+			Description: "The string contains reasoning words such as \"because\" or \"therefore\".",
+			Target:      ArgString,
+			Examples:    []string{"response | reasoning_present()"},
+		},
+		{
+			Name:        "code_valid",
+			Description: "The string contains recognizable code or a code block.",
+			Target:      ArgString,
+			Examples:    []string{"response | code_valid()"},
+		},
+		{
+			Name:        "min_count",
+			Description: "The count (a number, or the length of a list or map) is at least value.",
+			Target:      ArgList,
+			Value:       required(ArgInteger),
+			Examples:    []string{"items | min_count(3)"},
+		},
+		{
+			Name:        "exact_count",
+			Description: "The count (a number, or the length of a list or map) equals value.",
+			Target:      ArgList,
+			Value:       required(ArgInteger),
+			Examples:    []string{"items | exact_count(10)"},
+		},
+		{
+			Name:        "max_latency",
+			Description: "The latency in milliseconds is at most value.",
+			Target:      ArgNumber,
+			Value:       required(ArgNumber),
+			Examples:    []string{"latency_ms | max_latency(500)"},
+		},
+		{
+			Name:        "all_valid",
+			Description: "Every item of the list is not nil and not empty.",
+			Target:      ArgList,
+			Examples:    []string{"items | all_valid()"},
+		},
+		{
+			Name:        "no_duplicates",
+			Description: "The list has no duplicate items.",
+			Target:      ArgList,
+			Examples:    []string{"ids | no_duplicates()"},
+		},
+		{
+			Name:        "all_pass",
+			Description: "Every result in the list passed.",
+			Target:      ArgList,
+			Examples:    []string{"results | all_pass()"},
+		},
+		{
+			Name:        "no_mock_responses",
+			Description: "No item of the list contains mock or placeholder text.",
+			Target:      ArgList,
+			Examples:    []string{"responses | no_mock_responses()"},
+		},
+		{
+			Name:        "min_score",
+			Description: "The number is at least value (alias of quality_score).",
+			Target:      ArgNumber,
+			Value:       required(ArgNumber),
+			Examples:    []string{"score | min_score(0.5)"},
+		},
+		{
+			Name: "expr",
+			Description: "The boolean expression over all named values is true " +
+				"(comparison, arithmetic, in, =~, and, or, not and functions such as len).",
+			Target:   ArgValues,
+			Value:    required(ArgString),
+			Examples: []string{`expr("latency_ms < 200 && len(items) >= 3")`},
+		},
+		{
+			Name:        TypeEquals,
+			Description: "The value equals value, after coercing numbers, durations, sizes and bools.",
+			Target:      ArgAny,
+			Value:       ValueSchema{Kinds: []ArgKind{ArgAny}, Fields: toleranceArgs},
+			Examples:    []string{"status | equals(200)", `state | equals("ready")`},
+		},
+		{
+			Name:        TypeNotEquals,
+			Description: "The value does not equal value.",
+			Target:      ArgAny,
+			Value:       ValueSchema{Kinds: []ArgKind{ArgAny}, Fields: toleranceArgs},
+			Examples:    []string{`state | not_equals("error")`},
+		},
+		{
+			Name:        TypeGreaterThan,
+			Description: "The value is greater than value.",
+			Target:      ArgAny,
+			Value:       required(orderedKinds...),
+			Examples:    []string{"count | greater_than(0)"},
+		},
+		{
+			Name:        TypeGreaterOrEqual,
+			Description: "The value is greater than or equal to value.",
+			Target:      ArgAny,
+			Value:       required(orderedKinds...),
+			Examples:    []string{"free_disk | greater_or_equal(1GB)"},
+		},
+		{
+			Name:        TypeLessThan,
+			Description: "The value is less than value.",
+			Target:      ArgAny,
+			Value:       required(orderedKinds...),
+			Examples:    []string{"latency | less_than(200ms)"},
+		},
+		{
+			Name:        TypeLessOrEqual,
+			Description: "The value is less than or equal to value.",
+			Target:      ArgAny,
+			Value:       required(orderedKinds...),
+			Examples:    []string{"errors | less_or_equal(5)"},
+		},
+		{
+			Name:        TypeBetween,
+			Description: "The value is between two bounds, inclusive.",
+			Target:      ArgAny,
+			Value: ValueSchema{
+				Kinds: []ArgKind{ArgList}, Required: true,
+				Values: "the lower and upper bound", Fields: toleranceArgs,
+			},
+			Examples: []string{"latency | between(50ms, 250ms)"},
+		},
+		{
+			Name:        TypeApprox,
+			Description: "The value equals value within tolerance or relative_tolerance.",
+			Target:      ArgAny,
+			Value: ValueSchema{
+				Kinds: quantityKinds, Required: true, Fields: toleranceArgs,
+			},
+			Examples: []string{"ratio | approx(1, tolerance=0.05)"},
+		},
+		{
+			Name:        TypeRegex,
+			Description: "The string form of the value matches the regular expression.",
+			Target:      ArgAny,
+			Value:       required(ArgString),
+			Examples:    []string{`version | regex("^v\\d+\\.\\d+")`},
+		},
+		composite(TypeAllOf, "Every sub-assertion passes.",
+			`body | all_of(not_empty(), contains("ok"))`),
+		composite(TypeAnyOf, "At least one sub-assertion passes.",
+			`status | any_of(equals("ok"), equals("degraded"))`),
+		composite(TypeNoneOf, "No sub-assertion passes.",
+			`body | none_of(contains("error"), contains("panic"))`),
+		{
+			Name:        TypeAtLeast,
+			Description: "At least value sub-assertions pass.",
+			Target:      ArgAny,
+			Value: ValueSchema{
+				Kinds: []ArgKind{ArgInteger}, Required: true, Assertions: true,
+			},
+			Examples: []string{
+				`body | at_least(2, contains("a"), contains("b"), contains("c"))`,
+			},
+		},
+		composite(TypeNot, "The single sub-assertion fails.",
+			`status | not(equals("down"))`),
+		{
+			Name: TypeJSONSchema,
+			Description: "The JSON value validates against a JSON Schema (draft 2020-12 subset) " +
+				"given inline or as a file path.",
+			Target:   ArgAny,
+			Value:    required(ArgObject, ArgString),
+			Examples: []string{`body | json_schema("schemas/user.json")`},
+		},
+		{
+			Name:        TypeJSONShapeMatches,
+			Description: "The JSON value has the types and keys of a recorded example.",
+			Target:      ArgAny,
+			Value:       required(ArgObject, ArgList, ArgString),
+			Examples:    []string{`body | json_shape_matches({id: 1, tags: ["a"]})`},
+		},
+		{
+			Name: TypeMatchesSnapshot,
+			Description: "The value matches the golden file named by value or the target, " +
+				"which is recorded on the first run.",
+			Target: ArgAny,
+			Value: ValueSchema{
+				Kinds:  []ArgKind{ArgString},
+				Fields: []string{"ignore", "tolerance", "relative_tolerance"},
+			},
+			Examples: []string{`body | matches_snapshot(ignore=["items[*].created_at"])`},
+		},
+		statistic(TypeP95Below, "The 95th percentile of the samples is below value.",
+			"latency_ms | p95_below(200ms)"),
+		statistic(TypeP99Below, "The 99th percentile of the samples is below value.",
+			"latency_ms | p99_below(500)"),
+		{
+			Name:        TypeMeanWithin,
+			Description: "The mean of the samples is within tolerance of value, or between two bounds.",
+			Target:      ArgList,
+			Value: ValueSchema{
+				Kinds: append([]ArgKind{ArgList}, quantityKinds...), Required: true,
+				Values: "the lower and upper bound", Fields: toleranceArgs,
+			},
+			Examples: []string{
+				"latency_ms | mean_within(100, tolerance=10)",
+				"latency_ms | mean_within(50ms, 150ms)",
+			},
+		},
+		statistic(TypeStddevBelow, "The sample standard deviation is below value.",
+			"latency_ms | stddev_below(25)"),
+		{
+			Name: TypeNoRegressionVsBaseline,
+			Description: "The mean of the samples is at most value percent above the baseline " +
+				"samples, or the baseline recorded in the snapshot store.",
+			Target: ArgList,
+			Value: ValueSchema{
+				Kinds: []ArgKind{ArgNumber, ArgString}, Required: true,
+				Values: "baseline samples",
+			},
+			Examples: []string{"parse_ms | no_regression_vs_baseline(10%)"},
+		},
+	}
+}
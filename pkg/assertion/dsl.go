@@ -0,0 +1,678 @@
+package assertion
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ParseDefinition parses a compact assertion line:
+//
+//	response.status | equals(200) | status should be 200
+//	latency | between(50ms, 250ms, severity=warning)
+//	body | all_of(not_empty(), contains("ok"), max_length(1024))
+//	items | contains_any(["a", "b"])
+//
+// The line is a target path, an assertion and an optional
+// message separated by "|". A line without "|" is an assertion
+// without target, and an empty target segment (" | not_empty()
+// | msg") leaves the target unset. The message is the rest of
+// the line; quote it to keep surrounding spaces.
+//
+// Arguments are typed literals: numbers (float64), true, false,
+// null, durations (250ms, 1h30m; time.Duration), quoted strings
+// ("..." with Go escapes, or '...'), lists ([1, 2]), objects
+// ({"id": 1}) and bare words, which are strings. Numbers with a
+// size or percent suffix (1KB, 10%) are strings, as the
+// comparison family expects them. A single positional argument
+// is the Value, two or more are the Values, and nested
+// assertions (calls) are the sub-assertions of composite types.
+// Named arguments set the other fields: value, values,
+// tolerance, relative_tolerance, severity, ignore, and target
+// and message for nested assertions.
+//
+// Errors are *SyntaxError values locating the problem.
+func ParseDefinition(src string) (Definition, error) {
+	p := &dslParser{src: src}
+	return p.parseLine()
+}
+
+// FormatDefinition renders d as a compact assertion line that
+// ParseDefinition parses back into an equivalent definition
+// (integers become float64). Values other than the literal
+// types, such as structs, cannot be rendered.
+func FormatDefinition(d Definition) (string, error) {
+	call, err := formatCall(d, false)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if d.Target != "" || d.Message != "" {
+		b.WriteString(d.Target)
+		b.WriteString(" | ")
+	}
+	b.WriteString(call)
+	if d.Message != "" {
+		b.WriteString(" | ")
+		b.WriteString(formatMessage(d.Message))
+	}
+	return strings.TrimLeft(b.String(), " "), nil
+}
+
+// SyntaxError reports an invalid compact assertion. Pos is the
+// byte offset of the problem in Source.
+type SyntaxError struct {
+	Source string
+	Pos    int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid assertion: %s at position %d", e.Msg, e.Pos)
+}
+
+// dslFields are the named arguments of an assertion call.
+var dslFields = map[string]bool{
+	"value": true, "values": true, "tolerance": true,
+	"relative_tolerance": true, "severity": true, "ignore": true,
+	"target": true, "message": true,
+}
+
+type dslParser struct {
+	src string
+	pos int
+}
+
+func (p *dslParser) errorf(pos int, format string, args ...any) error {
+	return &SyntaxError{Source: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseLine parses the "target | call | message" segments.
+func (p *dslParser) parseLine() (Definition, error) {
+	first, err := p.segmentEnd(0)
+	if err != nil {
+		return Definition{}, err
+	}
+	if first == len(p.src) {
+		return p.parseSegment(0, first)
+	}
+
+	target := strings.TrimSpace(p.src[:first])
+	if target != "" {
+		if _, err := ParsePath(target); err != nil {
+			return Definition{}, p.errorf(p.skipFrom(0), "invalid target: %v", err)
+		}
+	}
+	second, err := p.segmentEnd(first + 1)
+	if err != nil {
+		return Definition{}, err
+	}
+	def, err := p.parseSegment(first+1, second)
+	if err != nil {
+		return Definition{}, err
+	}
+	if def.Target != "" && target != "" {
+		return Definition{}, p.errorf(p.skipFrom(first+1), "target is given twice")
+	}
+	if target != "" {
+		def.Target = target
+	}
+	if second < len(p.src) {
+		msg, err := p.parseMessage(second + 1)
+		if err != nil {
+			return Definition{}, err
+		}
+		if def.Message != "" && msg != "" {
+			return Definition{}, p.errorf(p.skipFrom(second+1), "message is given twice")
+		}
+		if msg != "" {
+			def.Message = msg
+		}
+	}
+	return def, nil
+}
+
+// segmentEnd returns the offset of the first "|" at or after
+// start outside quotes and brackets, or len(src).
+func (p *dslParser) segmentEnd(start int) (int, error) {
+	depth := 0
+	for i := start; i < len(p.src); i++ {
+		switch c := p.src[i]; c {
+		case '"', '\'':
+			end, err := p.quoteEnd(i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '|':
+			if depth <= 0 {
+				return i, nil
+			}
+		}
+	}
+	return len(p.src), nil
+}
+
+// quoteEnd returns the offset after the string starting at
+// start.
+func (p *dslParser) quoteEnd(start int) (int, error) {
+	q := p.src[start]
+	for i := start + 1; i < len(p.src); i++ {
+		switch p.src[i] {
+		case '\\':
+			i++
+		case q:
+			return i + 1, nil
+		}
+	}
+	return 0, p.errorf(start, "unterminated string")
+}
+
+// parseSegment parses the assertion call in src[start:end].
+func (p *dslParser) parseSegment(start, end int) (Definition, error) {
+	p.pos = start
+	src := p.src
+	p.src = src[:end]
+	def, err := p.parseCall()
+	if err == nil {
+		p.skipSpace()
+		if p.pos < end {
+			err = p.errorf(p.pos, "unexpected %q after assertion", p.src[p.pos:])
+		}
+	}
+	p.src = src
+	if se, ok := err.(*SyntaxError); ok {
+		se.Source = src
+	}
+	return def, err
+}
+
+// parseMessage parses the message segment starting at start.
+func (p *dslParser) parseMessage(start int) (string, error) {
+	msg := strings.TrimSpace(p.src[start:])
+	if msg == "" || (msg[0] != '"' && msg[0] != '\'') {
+		return msg, nil
+	}
+	p.pos = start + strings.Index(p.src[start:], msg[:1])
+	end, err := p.quoteEnd(p.pos)
+	if err != nil {
+		return "", err
+	}
+	if end != start+len(strings.TrimRight(p.src[start:], " \t")) {
+		return msg, nil // a message that starts with a quote
+	}
+	return p.parseString()
+}
+
+func (p *dslParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipFrom returns the offset of the first non-space byte at or
+// after start.
+func (p *dslParser) skipFrom(start int) int {
+	p.pos = start
+	p.skipSpace()
+	return p.pos
+}
+
+func (p *dslParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *dslParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf(p.pos, "expected %q, got %s", c, p.describeNext())
+	}
+	p.pos++
+	return nil
+}
+
+// describeNext describes the input at the current position for
+// error messages.
+func (p *dslParser) describeNext() string {
+	if p.pos >= len(p.src) {
+		return "end of input"
+	}
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return strconv.QuoteRune(r)
+}
+
+// ident scans an assertion type or argument name.
+func (p *dslParser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !isIdentByte(c) && (p.pos == start || !isDigit(c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parseCall parses "type" or "type(arg, ...)".
+func (p *dslParser) parseCall() (Definition, error) {
+	p.skipSpace()
+	start := p.pos
+	def := Definition{Type: p.ident()}
+	if def.Type == "" {
+		return def, p.errorf(start, "expected assertion type, got %s", p.describeNext())
+	}
+	if p.peek() != '(' {
+		return def, nil
+	}
+	p.pos++
+
+	var positional []any
+	named := map[string]bool{}
+	for p.peek() != ')' {
+		if err := p.parseArg(&def, &positional, named); err != nil {
+			return def, err
+		}
+		if p.peek() == ',' {
+			p.pos++
+			continue
+		}
+		if p.peek() != ')' {
+			return def, p.errorf(p.pos, `expected "," or ")", got %s`, p.describeNext())
+		}
+	}
+	p.pos++
+
+	switch {
+	case len(positional) == 1 && named["value"],
+		len(positional) > 1 && named["values"]:
+		return def, p.errorf(start, "%s has both positional and named values", def.Type)
+	case len(positional) == 1:
+		def.Value = positional[0]
+	case len(positional) > 1:
+		def.Values = positional
+	}
+	return def, nil
+}
+
+// parseArg parses a positional value, a named argument or a
+// nested assertion.
+func (p *dslParser) parseArg(def *Definition, positional *[]any, named map[string]bool) error {
+	start := p.pos
+	if name := p.ident(); name != "" {
+		switch p.peek() {
+		case '=':
+			p.pos++
+			return p.parseNamed(def, name, start, named)
+		case '(':
+			p.pos = start
+			sub, err := p.parseCall()
+			if err != nil {
+				return err
+			}
+			def.Assertions = append(def.Assertions, sub)
+			return nil
+		}
+		p.pos = start
+	}
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	*positional = append(*positional, v)
+	return nil
+}
+
+// parseNamed parses the value of the named argument name and
+// stores it in def.
+func (p *dslParser) parseNamed(def *Definition, name string, at int, named map[string]bool) error {
+	if !dslFields[name] {
+		return p.errorf(at, "unknown argument %s", name)
+	}
+	if named[name] {
+		return p.errorf(at, "argument %s is given twice", name)
+	}
+	named[name] = true
+
+	valueAt := p.pos
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	str, isStr := v.(string)
+	switch name {
+	case "value":
+		def.Value = v
+	case "values":
+		list, ok := v.([]any)
+		if !ok {
+			return p.errorf(valueAt, "values must be a list")
+		}
+		def.Values = list
+	case "tolerance":
+		def.Tolerance = v
+	case "relative_tolerance":
+		f, ok := v.(float64)
+		if !ok {
+			return p.errorf(valueAt, "relative_tolerance must be a number")
+		}
+		def.RelativeTolerance = f
+	case "ignore":
+		if isStr {
+			v = []any{str}
+		}
+		list, _ := v.([]any)
+		if list == nil {
+			return p.errorf(valueAt, "ignore must be a list of paths")
+		}
+		for _, item := range list {
+			path, ok := item.(string)
+			if !ok {
+				return p.errorf(valueAt, "ignore must be a list of paths")
+			}
+			def.Ignore = append(def.Ignore, path)
+		}
+	default:
+		if !isStr {
+			return p.errorf(valueAt, "%s must be a string", name)
+		}
+		switch name {
+		case "severity":
+			def.Severity = str
+		case "target":
+			if _, err := ParsePath(str); err != nil {
+				return p.errorf(valueAt, "invalid target: %v", err)
+			}
+			def.Target = str
+		case "message":
+			def.Message = str
+		}
+	}
+	return nil
+}
+
+// parseValue parses a literal.
+func (p *dslParser) parseValue() (any, error) {
+	switch c := p.peek(); {
+	case c == '"' || c == '\'':
+		return p.parseString()
+	case c == '[':
+		p.pos++
+		list := []any{}
+		for p.peek() != ']' {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+		return list, p.expect(']')
+	case c == '{':
+		return p.parseObject()
+	case isDigit(c) || c == '-' || c == '+' || c == '.':
+		return p.parseNumber()
+	case isIdentByte(c):
+		start := p.pos
+		for p.pos < len(p.src) && isWordByte(p.src[p.pos]) {
+			p.pos++
+		}
+		switch word := p.src[start:p.pos]; word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			if p.peek() == '(' {
+				return nil, p.errorf(start, "assertion %s is not a value", word)
+			}
+			return word, nil
+		}
+	}
+	return nil, p.errorf(p.pos, "expected a value, got %s", p.describeNext())
+}
+
+// isWordByte reports whether c continues a bare word.
+func isWordByte(c byte) bool {
+	return isIdentByte(c) || isDigit(c) || c == '.' || c == '-' || c == '/'
+}
+
+// parseObject parses {"key": value, ...}; keys may be bare words.
+func (p *dslParser) parseObject() (any, error) {
+	p.pos++
+	obj := map[string]any{}
+	for p.peek() != '}' {
+		keyAt := p.pos
+		var key string
+		if c := p.peek(); c == '"' || c == '\'' {
+			s, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		} else if key = p.ident(); key == "" {
+			return nil, p.errorf(keyAt, "expected object key, got %s", p.describeNext())
+		}
+		if _, dup := obj[key]; dup {
+			return nil, p.errorf(keyAt, "duplicate key %q", key)
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = v
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	return obj, p.expect('}')
+}
+
+// parseString parses a quoted string. Double-quoted strings use
+// Go escapes; single-quoted strings only escape \' and \\.
+func (p *dslParser) parseString() (string, error) {
+	start := p.pos
+	end, err := p.quoteEnd(start)
+	if err != nil {
+		return "", err
+	}
+	p.pos = end
+	raw := p.src[start:end]
+	if raw[0] == '\'' {
+		r := strings.NewReplacer(`\'`, `'`, `\\`, `\`)
+		return r.Replace(raw[1 : len(raw)-1]), nil
+	}
+	s, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", p.errorf(start, "invalid string %s", raw)
+	}
+	return s, nil
+}
+
+// parseNumber parses a number, a duration, or a number with a
+// size or percent suffix.
+func (p *dslParser) parseNumber() (any, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		sign := (c == '+' || c == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E')
+		if !isDigit(c) && !isIdentByte(c) && c != '.' && c != '%' && !sign &&
+			!strings.HasPrefix(p.src[p.pos:], "µ") {
+			break
+		}
+		if c >= utf8.RuneSelf {
+			p.pos++ // the two bytes of µ
+		}
+		p.pos++
+	}
+	text := p.src[start:p.pos]
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f, nil
+	}
+	if d, err := time.ParseDuration(text); err == nil {
+		return d, nil
+	}
+	if _, ok := parseSize(text); ok {
+		return text, nil
+	}
+	if pct := strings.TrimSuffix(text, "%"); pct != text {
+		if _, err := strconv.ParseFloat(pct, 64); err == nil {
+			return text, nil
+		}
+	}
+	return nil, p.errorf(start, "invalid number %s", text)
+}
+
+// ===== Formatting =====
+
+// formatCall renders "type(args)". nested calls carry their
+// target and message as named arguments.
+func formatCall(d Definition, nested bool) (string, error) {
+	var args []string
+	add := func(name string, v any) error {
+		s, err := formatValue(v)
+		if err != nil {
+			return fmt.Errorf("format %s of %s: %w", name, d.Type, err)
+		}
+		if name != "" {
+			s = name + "=" + s
+		}
+		args = append(args, s)
+		return nil
+	}
+
+	var err error
+	switch {
+	case d.Value == nil && len(d.Values) > 1:
+		for _, v := range d.Values {
+			if err = add("", v); err != nil {
+				return "", err
+			}
+		}
+	case d.Value != nil:
+		err = add("", d.Value)
+		fallthrough
+	default:
+		if err == nil && len(d.Values) > 0 {
+			err = add("values", d.Values)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, sub := range d.Assertions {
+		s, err := formatCall(sub, true)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, s)
+	}
+	fields := []struct {
+		name string
+		v    any
+		set  bool
+	}{
+		{"tolerance", d.Tolerance, d.Tolerance != nil},
+		{"relative_tolerance", d.RelativeTolerance, d.RelativeTolerance != 0},
+		{"ignore", d.Ignore, len(d.Ignore) > 0},
+		{"severity", d.Severity, d.Severity != ""},
+		{"target", d.Target, nested && d.Target != ""},
+		{"message", d.Message, nested && d.Message != ""},
+	}
+	for _, f := range fields {
+		if f.set {
+			if err := add(f.name, f.v); err != nil {
+				return "", err
+			}
+		}
+	}
+	return d.Type + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+// formatMessage quotes a message that would not parse back as
+// written.
+func formatMessage(msg string) string {
+	if msg != strings.TrimSpace(msg) || strings.ContainsAny(msg, "\n\r") ||
+		msg[0] == '"' || msg[0] == '\'' {
+		return strconv.Quote(msg)
+	}
+	return msg
+}
+
+// formatValue renders a literal.
+func formatValue(v any) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case string:
+		return strconv.Quote(x), nil
+	case time.Duration:
+		return x.String(), nil
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64), nil
+	}
+	if k, n := classify(v); k == kindNumber {
+		return strconv.FormatFloat(n.(float64), 'g', -1, 64), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, rv.Len())
+		for i := range items {
+			s, err := formatValue(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = s
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			s, err := formatValue(rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = strconv.Quote(k) + ": " + s
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("cannot render %T", v)
+}
@@ -0,0 +1,201 @@
+package assertion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== DSL parser tests =====
+
+func TestParseDefinition(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Definition
+	}{
+		{
+			name:  "type only",
+			input: "not_empty",
+			want:  Definition{Type: "not_empty"},
+		},
+		{
+			name:  "target and call",
+			input: "response | contains(\"a:b\")",
+			want:  Definition{Type: "contains", Target: "response", Value: "a:b"},
+		},
+		{
+			name:  "message",
+			input: "response.status | equals(200) | status should be 200",
+			want: Definition{
+				Type: "equals", Target: "response.status", Value: float64(200),
+				Message: "status should be 200",
+			},
+		},
+		{
+			name:  "quoted message keeps pipes and spaces",
+			input: `out | not_empty() | " a | b "`,
+			want:  Definition{Type: "not_empty", Target: "out", Message: " a | b "},
+		},
+		{
+			name:  "empty target",
+			input: " | not_empty() | required",
+			want:  Definition{Type: "not_empty", Message: "required"},
+		},
+		{
+			name:  "values and named arguments",
+			input: "latency | between(50ms, 1.5s, severity=warning)",
+			want: Definition{
+				Type: "between", Target: "latency",
+				Values:   []any{50 * time.Millisecond, 1500 * time.Millisecond},
+				Severity: "warning",
+			},
+		},
+		{
+			name:  "list value",
+			input: "tags | contains_any(['a', \"b\", ok])",
+			want: Definition{
+				Type: "contains_any", Target: "tags", Value: []any{"a", "b", "ok"},
+			},
+		},
+		{
+			name:  "literals",
+			input: "x | equals([true, false, null, -2.5e3, 1KB, 10%, {id: 1, \"n\": [1]}])",
+			want: Definition{Type: "equals", Target: "x", Value: []any{
+				true, false, nil, -2500.0, "1KB", "10%",
+				map[string]any{"id": float64(1), "n": []any{float64(1)}},
+			}},
+		},
+		{
+			name: "tolerances and ignore",
+			input: "t | approx(100, tolerance=5ms, relative_tolerance=0.05, " +
+				"ignore=\"items[*].id\")",
+			want: Definition{
+				Type: "approx", Target: "t", Value: float64(100),
+				Tolerance: 5 * time.Millisecond, RelativeTolerance: 0.05,
+				Ignore: []string{"items[*].id"},
+			},
+		},
+		{
+			name:  "value and baseline values",
+			input: "ms | no_regression_vs_baseline(10%, values=[100])",
+			want: Definition{
+				Type: "no_regression_vs_baseline", Target: "ms",
+				Value: "10%", Values: []any{float64(100)},
+			},
+		},
+		{
+			name: "nested assertions",
+			input: "body | at_least(1, not_empty(), " +
+				"equals(ok, target=\"status\", message=\"not ok\")) | body check",
+			want: Definition{
+				Type: "at_least", Target: "body", Value: float64(1),
+				Assertions: []Definition{
+					{Type: "not_empty"},
+					{Type: "equals", Target: "status", Value: "ok", Message: "not ok"},
+				},
+				Message: "body check",
+			},
+		},
+		{
+			name:  "quoted target segment",
+			input: `items[?(@.name == "a|b")] | exists`,
+			want:  Definition{Type: "exists", Target: `items[?(@.name == "a|b")]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDefinition(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDefinition_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{"", 0, "expected assertion type"},
+		{"x | equals(1", 12, `expected "," or ")"`},
+		{"x | equals(\"a)", 11, "unterminated string"},
+		{"x | equals(1 2)", 13, `expected "," or ")"`},
+		{"x | equals(12abc)", 11, "invalid number 12abc"},
+		{"x | equals(colour=1)", 11, "unknown argument colour"},
+		{"x | equals(1, severity=1)", 23, "severity must be a string"},
+		{"x | equals(1, value=2)", 4, "both positional and named values"},
+		{"x | equals([not_empty()])", 12, "assertion not_empty is not a value"},
+		{"x | equals(1) extra", 14, "unexpected \"extra\""},
+		{" x..y | equals(1)", 1, "invalid target"},
+		{"x | all_of(exists(target=\"a[\"))", 25, "invalid target"},
+		{"x | equals(1, target=y)", 4, "target is given twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseDefinition(tt.input)
+			require.Error(t, err)
+			var se *SyntaxError
+			require.True(t, errors.As(err, &se), err.Error())
+			assert.Equal(t, tt.input, se.Source)
+			assert.Equal(t, tt.pos, se.Pos, err.Error())
+			assert.Contains(t, se.Msg, tt.msg)
+		})
+	}
+}
+
+// ===== DSL formatter tests =====
+
+func TestFormatDefinition(t *testing.T) {
+	s, err := FormatDefinition(Definition{
+		Type: "between", Target: "latency",
+		Values: []any{50 * time.Millisecond, 250}, Severity: "warning",
+		Message: "too slow",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `latency | between(50ms, 250, severity="warning") | too slow`, s)
+
+	s, err = FormatDefinition(Definition{Type: "not_empty", Message: " padded "})
+	require.NoError(t, err)
+	assert.Equal(t, `| not_empty() | " padded "`, s)
+
+	_, err = FormatDefinition(Definition{Type: "equals", Value: struct{}{}})
+	assert.Error(t, err)
+}
+
+func TestFormatDefinition_RoundTrip(t *testing.T) {
+	defs := []Definition{
+		{Type: "not_empty"},
+		{Type: "contains", Target: "out", Value: "a:b|c", Message: "\"quoted\" start"},
+		{Type: "between", Target: "n", Values: []any{float64(1), float64(2)}},
+		{Type: "between", Target: "n", Value: []any{float64(1), float64(2)}},
+		{Type: "one_of", Target: "s", Values: []any{"x"}},
+		{
+			Type: "approx", Target: "t", Value: 90 * time.Minute,
+			Tolerance: "5%", RelativeTolerance: 0.1,
+			Ignore: []string{"a.b"}, Severity: "info", Message: "m",
+		},
+		{Type: "json_shape_matches", Target: "body", Value: map[string]any{
+			"id": "number", "tags": []any{"string"}, "odd key": nil,
+		}},
+		{Type: "none_of", Target: "r", Assertions: []Definition{
+			{Type: "contains", Value: "error", Message: "m"},
+			{Type: "not", Assertions: []Definition{
+				{Type: "exists", Target: "a.b"},
+			}},
+		}},
+	}
+	for _, d := range defs {
+		s, err := FormatDefinition(d)
+		require.NoError(t, err)
+		got, err := ParseDefinition(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, d, got, s)
+	}
+}
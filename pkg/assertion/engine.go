@@ -13,7 +13,8 @@ type Engine interface {
 
 	// EvaluateAll checks multiple assertions against a map of
 	// named values. Each assertion's Target field is used as
-	// the key into the values map.
+	// the key into the values map, or as a Path into nested
+	// values when no such key exists.
 	EvaluateAll(
 		assertions []Definition,
 		values map[string]any,
@@ -24,24 +25,74 @@ type Engine interface {
 	Register(assertionType string, evaluator Evaluator) error
 }
 
+// Cataloger is implemented by engines that document their
+// assertion types. DefaultEngine implements it; callers
+// holding an Engine type-assert for it.
+type Cataloger interface {
+	// Catalog returns the descriptors of all registered
+	// assertion types, built-in types first, sorted by plugin
+	// and name.
+	Catalog() []Descriptor
+}
+
+var _ Cataloger = (*DefaultEngine)(nil)
+
 // DefaultEngine is the standard Engine implementation. It is
 // safe for concurrent use.
 type DefaultEngine struct {
 	mu         sync.RWMutex
 	evaluators map[string]Evaluator
+
+	// wholeValues lists the types evaluated against the whole
+	// values map instead of a single target.
+	wholeValues map[string]bool
+
+	// comparators holds the comparison evaluators, which also
+	// report expected and actual values in normalized units.
+	comparators map[string]comparator
+
+	// composites holds the rules of the composite types, which
+	// evaluate the sub-assertions of a Definition.
+	composites map[string]compositeRule
+
+	// structural holds evaluators that build the whole Result,
+	// reporting each violation as a child result.
+	structural map[string]resultEvaluator
+
+	// descriptors documents every registered type and
+	// validates definitions before evaluation.
+	descriptors map[string]Descriptor
 }
 
-// NewEngine creates a DefaultEngine with all 16 built-in
+// resultEvaluator evaluates an assertion into a complete
+// Result; Type and Target are filled in by the engine.
+type resultEvaluator func(assertion Definition, value any) Result
+
+// NewEngine creates a DefaultEngine with all 39 built-in
 // evaluators pre-registered.
 func NewEngine() *DefaultEngine {
 	e := &DefaultEngine{
-		evaluators: make(map[string]Evaluator),
+		evaluators:  make(map[string]Evaluator),
+		wholeValues: make(map[string]bool),
+		comparators: comparators(),
+		composites:  compositeRules(),
+		structural: map[string]resultEvaluator{
+			TypeJSONSchema:       evaluateJSONSchema,
+			TypeJSONShapeMatches: evaluateJSONShape,
+		},
+		descriptors: make(map[string]Descriptor),
+	}
+	for name, st := range statistics() {
+		e.structural[name] = st.evaluator()
 	}
 	e.registerDefaults()
+	for _, d := range builtinDescriptors() {
+		e.descriptors[d.Name] = d
+	}
 	return e
 }
 
-// registerDefaults registers all 16 built-in evaluators.
+// registerDefaults registers all 39 built-in evaluators.
 func (e *DefaultEngine) registerDefaults() {
 	e.evaluators["not_empty"] = evaluateNotEmpty
 	e.evaluators["not_mock"] = evaluateNotMock
@@ -59,35 +110,128 @@ func (e *DefaultEngine) registerDefaults() {
 	e.evaluators["all_pass"] = evaluateAllPass
 	e.evaluators["no_mock_responses"] = evaluateNoMockResponses
 	e.evaluators["min_score"] = evaluateMinScore
+	e.evaluators["expr"] = evaluateExpr
+	e.wholeValues["expr"] = true
+	for name, c := range e.comparators {
+		e.evaluators[name] = c.evaluator()
+	}
+	for name, eval := range e.structural {
+		e.evaluators[name] = func(a Definition, value any) (bool, string) {
+			r := eval(a, value)
+			return r.Passed, r.Message
+		}
+	}
+	for name := range e.composites {
+		e.evaluators[name] = func(a Definition, value any) (bool, string) {
+			r := e.Evaluate(a, value)
+			return r.Passed, r.Message
+		}
+	}
+	e.evaluators[TypeMatchesSnapshot] = func(a Definition, value any) (bool, string) {
+		r := evaluateSnapshot(nil, a, value)
+		return r.Passed, r.Message
+	}
+	e.evaluators[TypeNoRegressionVsBaseline] = func(a Definition, value any) (bool, string) {
+		r := evaluateRegression(nil, a, value)
+		return r.Passed, r.Message
+	}
 }
 
 // Register adds a custom evaluator for the given assertion type.
-// Returns an error if the type is already registered.
+// Returns an error if the type is already registered. The type
+// is listed in the Catalog without documentation; use
+// RegisterDescriptor to describe it.
 func (e *DefaultEngine) Register(
 	assertionType string,
 	evaluator Evaluator,
+) error {
+	return e.RegisterDescriptor(
+		Descriptor{Name: assertionType}, evaluator,
+	)
+}
+
+// RegisterDescriptor adds a custom evaluator for the type named
+// by desc. Definitions of the type are validated against
+// desc.Value before evaluation. Returns an error if the type is
+// already registered.
+func (e *DefaultEngine) RegisterDescriptor(
+	desc Descriptor,
+	evaluator Evaluator,
 ) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if _, exists := e.evaluators[assertionType]; exists {
+	if desc.Name == "" {
+		return fmt.Errorf("assertion type name is required")
+	}
+	if _, exists := e.evaluators[desc.Name]; exists {
 		return fmt.Errorf(
 			"assertion type already registered: %s",
-			assertionType,
+			desc.Name,
 		)
 	}
 
-	e.evaluators[assertionType] = evaluator
+	e.evaluators[desc.Name] = evaluator
+	e.descriptors[desc.Name] = desc
 	return nil
 }
 
+// Catalog returns the descriptors of all registered assertion
+// types, built-in types first, sorted by plugin and name.
+func (e *DefaultEngine) Catalog() []Descriptor {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	out := make([]Descriptor, 0, len(e.descriptors))
+	for _, d := range e.descriptors {
+		out = append(out, d)
+	}
+	sortDescriptors(out)
+	return out
+}
+
+// Describe returns the descriptor of an assertion type.
+func (e *DefaultEngine) Describe(assertionType string) (Descriptor, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	d, ok := e.descriptors[assertionType]
+	return d, ok
+}
+
+// Validate checks that the type of assertion is registered and
+// that its arguments match the type's descriptor. It does not
+// validate sub-assertions.
+func (e *DefaultEngine) Validate(assertion Definition) error {
+	d, ok := e.Describe(assertion.Type)
+	if !ok {
+		return fmt.Errorf("unknown assertion type: %s", assertion.Type)
+	}
+	return d.Validate(assertion)
+}
+
 // Evaluate runs a single assertion against the provided value.
 func (e *DefaultEngine) Evaluate(
 	assertion Definition,
 	value any,
+) Result {
+	r := e.evaluate(assertion, value, nil)
+	r.Severity = assertion.Severity
+	return r
+}
+
+// evaluate runs a single assertion, using snapshots for
+// matches_snapshot assertions.
+func (e *DefaultEngine) evaluate(
+	assertion Definition,
+	value any,
+	snapshots *SnapshotStore,
 ) Result {
 	e.mu.RLock()
 	evaluator, exists := e.evaluators[assertion.Type]
+	compare := e.comparators[assertion.Type]
+	rule := e.composites[assertion.Type]
+	structural := e.structural[assertion.Type]
+	desc := e.descriptors[assertion.Type]
 	e.mu.RUnlock()
 
 	if !exists {
@@ -101,6 +245,48 @@ func (e *DefaultEngine) Evaluate(
 			),
 		}
 	}
+	if err := desc.Validate(assertion); err != nil {
+		return Result{
+			Type:     assertion.Type,
+			Target:   assertion.Target,
+			Expected: assertion.Value,
+			Actual:   value,
+			Message:  "invalid assertion: " + err.Error(),
+		}
+	}
+
+	switch assertion.Type {
+	case TypeMatchesSnapshot:
+		return evaluateSnapshot(snapshots, assertion, value)
+	case TypeNoRegressionVsBaseline:
+		return evaluateRegression(snapshots, assertion, value)
+	}
+
+	if rule != nil {
+		return e.evaluateComposite(
+			assertion, rule, map[string]any{assertion.Target: value},
+			snapshots,
+		)
+	}
+
+	if structural != nil {
+		r := structural(assertion, value)
+		r.Type = assertion.Type
+		r.Target = assertion.Target
+		return r
+	}
+
+	if compare != nil {
+		c := compare(assertion, value)
+		return Result{
+			Type:     assertion.Type,
+			Target:   assertion.Target,
+			Expected: c.expected,
+			Actual:   c.actual,
+			Passed:   c.passed,
+			Message:  c.message,
+		}
+	}
 
 	passed, message := evaluator(assertion, value)
 
@@ -115,31 +301,119 @@ func (e *DefaultEngine) Evaluate(
 }
 
 // EvaluateAll runs multiple assertions against a map of named
-// values. Each assertion's Target field is used as the key into
-// the values map. If a target is missing, the assertion fails.
+// values. Each assertion's Target is first used as a key into
+// the values map; otherwise it is resolved as a Path (e.g.,
+// "response.data.items[3].id"). Wildcard targets produce one
+// Result per selected element, with the concrete path as the
+// Result Target. If a target cannot be resolved, the assertion
+// fails. "expr" assertions are evaluated against the whole
+// values map and need no target. Composite assertions (all_of,
+// any_of, none_of, at_least, not) resolve the targets of their
+// sub-assertions against values and record the sub-results in
+// Result.Children.
 func (e *DefaultEngine) EvaluateAll(
 	assertions []Definition,
 	values map[string]any,
+) []Result {
+	return e.evaluateAll(assertions, values, nil)
+}
+
+// EvaluateAllWithSnapshots is EvaluateAll with a snapshot store
+// for matches_snapshot assertions.
+func (e *DefaultEngine) EvaluateAllWithSnapshots(
+	assertions []Definition,
+	values map[string]any,
+	snapshots *SnapshotStore,
+) []Result {
+	return e.evaluateAll(assertions, values, snapshots)
+}
+
+func (e *DefaultEngine) evaluateAll(
+	assertions []Definition,
+	values map[string]any,
+	snapshots *SnapshotStore,
 ) []Result {
 	results := make([]Result, 0, len(assertions))
 
 	for _, a := range assertions {
-		value, exists := values[a.Target]
-		if !exists {
-			results = append(results, Result{
-				Type:   a.Type,
-				Target: a.Target,
-				Passed: false,
-				Message: fmt.Sprintf(
-					"target not found: %s", a.Target,
-				),
-			})
-			continue
+		start := len(results)
+		results = e.appendResults(results, a, values, snapshots)
+		for i := start; i < len(results); i++ {
+			results[i].Severity = a.Severity
 		}
+	}
 
-		results = append(results, e.Evaluate(a, value))
+	return results
+}
+
+// appendResults appends the results of assertion a to results.
+func (e *DefaultEngine) appendResults(
+	results []Result,
+	a Definition,
+	values map[string]any,
+	snapshots *SnapshotStore,
+) []Result {
+	e.mu.RLock()
+	whole := e.wholeValues[a.Type]
+	rule := e.composites[a.Type]
+	e.mu.RUnlock()
+	if rule != nil {
+		return append(results,
+			e.evaluateComposite(a, rule, values, snapshots))
+	}
+	if whole {
+		r := e.evaluate(a, values, snapshots)
+		r.Actual = nil
+		return append(results, r)
+	}
+	if value, exists := values[a.Target]; exists {
+		return append(results, e.evaluate(a, value, snapshots))
+	}
+	return append(results, e.evaluatePath(a, values, snapshots)...)
+}
+
+// evaluatePath evaluates an assertion whose target is a path.
+func (e *DefaultEngine) evaluatePath(
+	a Definition,
+	values map[string]any,
+	snapshots *SnapshotStore,
+) []Result {
+	failed := func(target, msg string) []Result {
+		return []Result{{
+			Type:    a.Type,
+			Target:  target,
+			Passed:  false,
+			Message: msg,
+		}}
+	}
+
+	path, err := ParsePath(a.Target)
+	if err != nil {
+		return failed(a.Target, err.Error())
+	}
+	if len(path.segments) == 1 && !path.Wildcard() {
+		return failed(a.Target, fmt.Sprintf(
+			"target not found: %s", a.Target,
+		))
 	}
 
+	matches := path.ResolveRoot(values)
+	if len(matches) == 0 {
+		return failed(a.Target, fmt.Sprintf(
+			"path %s matched no elements", a.Target,
+		))
+	}
+
+	results := make([]Result, 0, len(matches))
+	for _, m := range matches {
+		if m.Err != nil {
+			results = append(results, failed(m.Path, m.Err.Error())...)
+			continue
+		}
+		sub := a
+		sub.Target = m.Path
+		results = append(results, e.evaluate(sub, m.Value, snapshots))
+	}
 	return results
 }
 
@@ -16,7 +16,11 @@ func TestNewEngine_RegistersAllBuiltins(t *testing.T) {
 		"reasoning_present", "code_valid", "min_count",
 		"exact_count", "max_latency", "all_valid",
 		"no_duplicates", "all_pass", "no_mock_responses",
-		"min_score",
+		"min_score", "expr", "equals", "not_equals",
+		"greater_than", "greater_or_equal", "less_than", "less_or_equal",
+		"between", "approx", "regex", "all_of", "any_of",
+		"none_of", "at_least", "not", "json_schema",
+		"json_shape_matches",
 	}
 
 	for _, name := range builtins {
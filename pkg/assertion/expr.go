@@ -0,0 +1,1080 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits that keep expression evaluation bounded. Expressions
+// have no loops and no I/O; the step budget also covers work
+// done by functions over lists and strings.
+const (
+	maxExprLength  = 4096
+	maxExprNodes   = 1000
+	maxExprSteps   = 100000
+	maxRegexLength = 1024
+)
+
+// Expr is a compiled boolean expression for the "expr"
+// assertion type. It is evaluated against a map of named values:
+//
+//	latency_ms < 200 && len(items) >= 3 && status in ["ok", "degraded"]
+//
+// Supported syntax: number, string ('...' or "..."), bool, null
+// and list literals; names with member and index access
+// (response.items[0].id); arithmetic (+ - * / %); comparison
+// (== != < <= > >=); in and not in; regex matching (=~, !~);
+// && || ! and the keywords and, or, not. Numeric strings compare
+// as numbers, strings holding JSON are decoded for member and
+// index access, and durations and times compare with each other
+// and with strings that parse as such. Functions are listed in
+// exprFuncs.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// CompileExpr parses an expression.
+func CompileExpr(src string) (*Expr, error) {
+	if len(src) > maxExprLength {
+		return nil, fmt.Errorf(
+			"expression is longer than %d bytes", maxExprLength,
+		)
+	}
+	p := &exprParser{src: src}
+	if err := p.lex(); err != nil {
+		return nil, err
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	if p.nodes > maxExprNodes {
+		return nil, fmt.Errorf(
+			"expression has more than %d nodes", maxExprNodes,
+		)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the expression source.
+func (e *Expr) String() string { return e.src }
+
+// Eval evaluates the expression against values.
+func (e *Expr) Eval(values map[string]any) (any, error) {
+	env := &exprEnv{values: values}
+	return e.root.eval(env)
+}
+
+// EvalBool evaluates the expression and requires a bool result.
+// The returned trace lists the evaluated sub-expressions with
+// their values (e.g., "latency_ms = 250, latency_ms < 200 =
+// false"), in evaluation order.
+func (e *Expr) EvalBool(values map[string]any) (bool, string, error) {
+	env := &exprEnv{values: values, tracing: true}
+	v, err := e.root.eval(env)
+	trace := env.traceString()
+	if err != nil {
+		return false, trace, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, trace, fmt.Errorf(
+			"expression evaluated to %s, not a bool", formatExprValue(v),
+		)
+	}
+	return b, trace, nil
+}
+
+// evaluateExpr is the "expr" evaluator. The assertion Value is
+// the expression; value is the map of all named values (see
+// DefaultEngine.EvaluateAll). Any other value is exposed to the
+// expression as "value".
+func evaluateExpr(
+	assertion Definition,
+	value any,
+) (bool, string) {
+	src, ok := assertion.Value.(string)
+	if !ok || strings.TrimSpace(src) == "" {
+		return false, "expr assertion requires a string expression as value"
+	}
+	expr, err := CompileExpr(src)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	values, ok := value.(map[string]any)
+	if !ok {
+		values = map[string]any{"value": value}
+	}
+	passed, trace, err := expr.EvalBool(values)
+	switch {
+	case err != nil:
+		return false, fmt.Sprintf("expression %q failed: %v", src, err)
+	case !passed:
+		return false, fmt.Sprintf("expression %q is false: %s", src, trace)
+	}
+	return true, fmt.Sprintf("expression %q is true", src)
+}
+
+// ===== Lexer and parser =====
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	val  any
+	pos  int
+	end  int
+}
+
+type exprParser struct {
+	src    string
+	tokens []exprToken
+	i      int
+	nodes  int
+}
+
+// exprOps lists operators, longest first.
+var exprOps = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "=~", "!~",
+	"<", ">", "+", "-", "*", "/", "%", "!",
+	"(", ")", "[", "]", ",", ".",
+}
+
+func (p *exprParser) lex() error {
+	s := p.src
+	i := 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.' ||
+				s[j] == 'e' || s[j] == 'E' ||
+				((s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E'))) {
+				j++
+			}
+			f, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q at position %d", s[i:j], i)
+			}
+			p.tokens = append(p.tokens, exprToken{tokNumber, s[i:j], f, i, j})
+			i = j
+		case r == '"' || r == '\'':
+			j := i + 1
+			var b strings.Builder
+			for j < len(s) && s[j] != byte(r) {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+					switch s[j] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(s[j])
+					}
+				} else {
+					b.WriteByte(s[j])
+				}
+				j++
+			}
+			if j >= len(s) {
+				return fmt.Errorf("unterminated string at position %d", i)
+			}
+			p.tokens = append(p.tokens, exprToken{tokString, s[i : j+1], b.String(), i, j + 1})
+			i = j + 1
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			p.tokens = append(p.tokens, exprToken{tokIdent, s[i:j], nil, i, j})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range exprOps {
+				if strings.HasPrefix(s[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			p.tokens = append(p.tokens, exprToken{tokOp, op, nil, i, i + len(op)})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, exprToken{kind: tokEOF, pos: len(s), end: len(s)})
+	return nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.i] }
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token when it is one of the given
+// operators or keywords.
+func (p *exprParser) accept(texts ...string) (exprToken, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return t, false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.i++
+			return t, true
+		}
+	}
+	return t, false
+}
+
+func (p *exprParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return p.errorf(t, "expected %q, got end of expression", text)
+		}
+		return p.errorf(t, "expected %q, got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *exprParser) errorf(t exprToken, format string, args ...any) error {
+	return fmt.Errorf(
+		"invalid expression: %s at position %d",
+		fmt.Sprintf(format, args...), t.pos,
+	)
+}
+
+// span builds the node metadata for source range [start, end).
+func (p *exprParser) span(start, end int) exprSpan {
+	p.nodes++
+	return exprSpan{src: strings.TrimSpace(p.src[start:end]), start: start}
+}
+
+func (p *exprParser) last() int { return p.tokens[p.i-1].end }
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{p.span(left.pos(), p.last()), "||", left, right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicNode{p.span(left.pos(), p.last()), "&&", left, right}
+	}
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op := ""
+	if t, ok := p.accept("==", "!=", "<", "<=", ">", ">=", "=~", "!~", "in", "matches"); ok {
+		op = t.text
+	} else if t.kind == tokIdent && t.text == "not" &&
+		p.tokens[p.i+1].kind == tokIdent && p.tokens[p.i+1].text == "in" {
+		p.i += 2
+		op = "not in"
+	}
+	if op == "" {
+		return left, nil
+	}
+	if op == "matches" {
+		op = "=~"
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{p.span(left.pos(), p.last()), op, left, right}, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{p.span(left.pos(), p.last()), t.text, left, right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{p.span(left.pos(), p.last()), t.text, left, right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if t, ok := p.accept("!", "not", "-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		op := t.text
+		if op == "not" {
+			op = "!"
+		}
+		return &unaryNode{p.span(t.pos, p.last()), op, x}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *exprParser) parsePostfix() (exprNode, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().kind == tokOp && p.peek().text == ".":
+			p.next()
+			t := p.next()
+			if t.kind != tokIdent {
+				return nil, p.errorf(t, "expected a name after \".\"")
+			}
+			x = &memberNode{p.span(x.pos(), p.last()), x, t.text}
+		case p.peek().kind == tokOp && p.peek().text == "[":
+			p.next()
+			idx, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = &indexNode{p.span(x.pos(), p.last()), x, idx}
+		default:
+			return x, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber, tokString:
+		return &literalNode{p.span(t.pos, t.end), t.val}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return &literalNode{p.span(t.pos, t.end), t.text == "true"}, nil
+		case "null", "nil":
+			return &literalNode{p.span(t.pos, t.end), nil}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		return &identNode{p.span(t.pos, t.end), t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			var items []exprNode
+			for {
+				if _, ok := p.accept("]"); ok {
+					break
+				}
+				if len(items) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			}
+			return &listNode{p.span(t.pos, p.last()), items}, nil
+		}
+	case tokEOF:
+		return nil, p.errorf(t, "unexpected end of expression")
+	}
+	return nil, p.errorf(t, "unexpected %q", t.text)
+}
+
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %s", name.text)
+	}
+	var args []exprNode
+	for {
+		if _, ok := p.accept(")"); ok {
+			break
+		}
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, p.errorf(name, "wrong number of arguments to %s", name.text)
+	}
+	return &callNode{p.span(name.pos, p.last()), name.text, fn, args}, nil
+}
+
+// ===== Evaluation =====
+
+type exprEnv struct {
+	values  map[string]any
+	steps   int
+	tracing bool
+	trace   []string
+	seen    map[string]bool
+}
+
+// step charges n units against the step budget.
+func (env *exprEnv) step(n int) error {
+	env.steps += n
+	if env.steps > maxExprSteps {
+		return fmt.Errorf("expression exceeded %d evaluation steps", maxExprSteps)
+	}
+	return nil
+}
+
+// record adds a sub-expression value to the trace.
+func (env *exprEnv) record(s exprSpan, v any) {
+	if !env.tracing {
+		return
+	}
+	entry := s.src + " = " + formatExprValue(v)
+	if env.seen == nil {
+		env.seen = make(map[string]bool)
+	}
+	if env.seen[entry] {
+		return
+	}
+	env.seen[entry] = true
+	env.trace = append(env.trace, entry)
+}
+
+func (env *exprEnv) traceString() string {
+	return strings.Join(env.trace, ", ")
+}
+
+type exprNode interface {
+	eval(env *exprEnv) (any, error)
+	pos() int
+}
+
+// exprSpan is the source text of a node.
+type exprSpan struct {
+	src   string
+	start int
+}
+
+func (s exprSpan) pos() int { return s.start }
+
+type literalNode struct {
+	exprSpan
+	val any
+}
+
+func (n *literalNode) eval(env *exprEnv) (any, error) {
+	return n.val, env.step(1)
+}
+
+type identNode struct {
+	exprSpan
+	name string
+}
+
+func (n *identNode) eval(env *exprEnv) (any, error) {
+	if err := env.step(1); err != nil {
+		return nil, err
+	}
+	v, ok := env.values[n.name]
+	if !ok {
+		return nil, fmt.Errorf("undefined name %s", n.name)
+	}
+	env.record(n.exprSpan, v)
+	return v, nil
+}
+
+type listNode struct {
+	exprSpan
+	items []exprNode
+}
+
+func (n *listNode) eval(env *exprEnv) (any, error) {
+	out := make([]any, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, env.step(1)
+}
+
+type memberNode struct {
+	exprSpan
+	x    exprNode
+	name string
+}
+
+func (n *memberNode) eval(env *exprEnv) (any, error) {
+	x, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	v, err := accessSegment(n.src, x, pathSegment{
+		kind: segmentField, name: n.name, text: n.name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	env.record(n.exprSpan, v)
+	return v, env.step(1)
+}
+
+type indexNode struct {
+	exprSpan
+	x, index exprNode
+}
+
+func (n *indexNode) eval(env *exprEnv) (any, error) {
+	x, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := n.index.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	seg := pathSegment{kind: segmentField}
+	if f, ok := exprNumber(idx); ok && !isStringValue(idx) {
+		seg = pathSegment{kind: segmentIndex, index: int(f)}
+		seg.text = fmt.Sprintf("[%d]", seg.index)
+	} else {
+		seg.name = fmt.Sprint(idx)
+		seg.text = strconv.Quote(seg.name)
+	}
+	v, err := accessSegment(n.src, x, seg)
+	if err != nil {
+		return nil, err
+	}
+	env.record(n.exprSpan, v)
+	return v, env.step(1)
+}
+
+// accessSegment applies one path segment to v, so expressions
+// and targets share their access rules.
+func accessSegment(src string, v any, seg pathSegment) (any, error) {
+	p := &Path{raw: src, segments: []pathSegment{seg}}
+	m := p.step(Match{Value: v}, seg)[0]
+	return m.Value, m.Err
+}
+
+type unaryNode struct {
+	exprSpan
+	op string
+	x  exprNode
+}
+
+func (n *unaryNode) eval(env *exprEnv) (any, error) {
+	x, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.step(1); err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("! needs a bool, got %s", formatExprValue(x))
+		}
+		return !b, nil
+	}
+	if d, ok := x.(time.Duration); ok {
+		return -d, nil
+	}
+	f, ok := exprNumber(x)
+	if !ok {
+		return nil, fmt.Errorf("- needs a number, got %s", formatExprValue(x))
+	}
+	return -f, nil
+}
+
+type logicNode struct {
+	exprSpan
+	op          string
+	left, right exprNode
+}
+
+func (n *logicNode) eval(env *exprEnv) (any, error) {
+	operand := func(x exprNode) (bool, error) {
+		v, err := x.eval(env)
+		if err != nil {
+			return false, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return false, fmt.Errorf(
+				"%s needs bool operands, got %s", n.op, formatExprValue(v),
+			)
+		}
+		return b, nil
+	}
+	if err := env.step(1); err != nil {
+		return nil, err
+	}
+	l, err := operand(n.left)
+	if err != nil {
+		return nil, err
+	}
+	if (n.op == "&&" && !l) || (n.op == "||" && l) {
+		return l, nil
+	}
+	return operand(n.right)
+}
+
+type binaryNode struct {
+	exprSpan
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(env *exprEnv) (any, error) {
+	l, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if err := env.step(1); err != nil {
+		return nil, err
+	}
+
+	var v any
+	switch n.op {
+	case "==":
+		v = exprEqual(l, r)
+	case "!=":
+		v = !exprEqual(l, r)
+	case "<", "<=", ">", ">=":
+		c, err := exprCompare(l, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.src, err)
+		}
+		v = map[string]bool{"<": c < 0, "<=": c <= 0, ">": c > 0, ">=": c >= 0}[n.op]
+	case "in", "not in":
+		in, err := exprContains(env, r, l)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.src, err)
+		}
+		v = in == (n.op == "in")
+	case "=~", "!~":
+		ok, err := exprMatch(l, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.src, err)
+		}
+		v = ok == (n.op == "=~")
+	default:
+		v, err = exprArith(n.op, l, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.src, err)
+		}
+	}
+	env.record(n.exprSpan, v)
+	return v, nil
+}
+
+type callNode struct {
+	exprSpan
+	name string
+	fn   exprFunc
+	args []exprNode
+}
+
+func (n *callNode) eval(env *exprEnv) (any, error) {
+	args := make([]any, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	if err := env.step(1); err != nil {
+		return nil, err
+	}
+	v, err := n.fn.call(env, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.src, err)
+	}
+	env.record(n.exprSpan, v)
+	return v, nil
+}
+
+// ===== Value semantics =====
+
+// exprNumber converts numbers and numeric strings to float64.
+func exprNumber(v any) (float64, bool) {
+	if f, ok := toFloat64(v); ok {
+		return f, true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String:
+		f, err := strconv.ParseFloat(strings.TrimSpace(rv.String()), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isStringValue(v any) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+// exprEqual compares numbers numerically when at least one side
+// is a number, and everything else by value.
+func exprEqual(l, r any) bool {
+	if !isStringValue(l) || !isStringValue(r) {
+		if a, ok := exprNumber(l); ok {
+			if b, ok := exprNumber(r); ok {
+				return a == b
+			}
+		}
+	}
+	if c, err := exprCompareTimes(l, r); err == nil {
+		return c == 0
+	}
+	return reflect.DeepEqual(l, r)
+}
+
+// exprCompare orders numbers, strings, durations and times.
+func exprCompare(l, r any) (int, error) {
+	if c, err := exprCompareTimes(l, r); err == nil {
+		return c, nil
+	}
+	ls, lok := l.(string)
+	rs, rok := r.(string)
+	if !lok || !rok {
+		a, aok := exprNumber(l)
+		b, bok := exprNumber(r)
+		if aok && bok {
+			return cmpFloat(a, b), nil
+		}
+	}
+	if lok && rok {
+		return strings.Compare(ls, rs), nil
+	}
+	return 0, fmt.Errorf(
+		"cannot compare %s with %s", formatExprValue(l), formatExprValue(r),
+	)
+}
+
+// exprCompareTimes compares durations or times, parsing a string
+// on the other side when needed.
+func exprCompareTimes(l, r any) (int, error) {
+	if d, ok := l.(time.Duration); ok {
+		o, err := asDuration(r)
+		if err != nil {
+			return 0, err
+		}
+		return cmpFloat(float64(d), float64(o)), nil
+	}
+	if _, ok := r.(time.Duration); ok {
+		c, err := exprCompareTimes(r, l)
+		return -c, err
+	}
+	if t, ok := l.(time.Time); ok {
+		o, err := asTime(r)
+		if err != nil {
+			return 0, err
+		}
+		return t.Compare(o), nil
+	}
+	if _, ok := r.(time.Time); ok {
+		c, err := exprCompareTimes(r, l)
+		return -c, err
+	}
+	return 0, fmt.Errorf("not a time value")
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// exprContains implements "in": list membership, map key
+// membership and substring search.
+func exprContains(env *exprEnv, container, item any) (bool, error) {
+	container, err := decodeJSONString(container)
+	if err != nil {
+		return false, err
+	}
+	if s, ok := container.(string); ok {
+		sub, ok := item.(string)
+		if !ok {
+			sub = fmt.Sprint(item)
+		}
+		return strings.Contains(s, sub), env.step(len(s) / 64)
+	}
+	rv := reflect.ValueOf(container)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if err := env.step(rv.Len()); err != nil {
+			return false, err
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if exprEqual(rv.Index(i).Interface(), item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		key := reflect.ValueOf(fmt.Sprint(item)).Convert(rv.Type().Key())
+		return rv.MapIndex(key).IsValid(), nil
+	}
+	return false, fmt.Errorf(
+		"in needs a list, object or string, got %s",
+		formatExprValue(container),
+	)
+}
+
+// exprMatch reports whether l matches the regular expression r.
+func exprMatch(l, r any) (bool, error) {
+	s, ok := l.(string)
+	if !ok {
+		s = fmt.Sprint(l)
+	}
+	re, err := compileExprRegex(r)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+func compileExprRegex(v any) (*regexp.Regexp, error) {
+	pattern, ok := v.(string)
+	if !ok {
+		return nil, fmt.Errorf("regex must be a string, got %s", formatExprValue(v))
+	}
+	if len(pattern) > maxRegexLength {
+		return nil, fmt.Errorf("regex is longer than %d bytes", maxRegexLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return re, nil
+}
+
+// exprArith implements + - * / % over numbers, + over strings
+// and lists, and duration and time arithmetic.
+func exprArith(op string, l, r any) (any, error) {
+	switch a := l.(type) {
+	case time.Time:
+		switch b := r.(type) {
+		case time.Time:
+			if op == "-" {
+				return a.Sub(b), nil
+			}
+		case time.Duration:
+			switch op {
+			case "+":
+				return a.Add(b), nil
+			case "-":
+				return a.Add(-b), nil
+			}
+		}
+	case time.Duration:
+		if b, ok := r.(time.Duration); ok {
+			switch op {
+			case "+":
+				return a + b, nil
+			case "-":
+				return a - b, nil
+			case "/":
+				if b == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				return float64(a) / float64(b), nil
+			}
+		}
+		if f, ok := exprNumber(r); ok && !isStringValue(r) {
+			switch op {
+			case "*":
+				return time.Duration(float64(a) * f), nil
+			case "/":
+				if f == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				return time.Duration(float64(a) / f), nil
+			}
+		}
+	case string:
+		if b, ok := r.(string); ok && op == "+" {
+			if len(a)+len(b) > maxExprLength*16 {
+				return nil, fmt.Errorf("string result too long")
+			}
+			return a + b, nil
+		}
+	case []any:
+		if b, ok := r.([]any); ok && op == "+" {
+			return append(append([]any(nil), a...), b...), nil
+		}
+	}
+
+	a, aok := exprNumber(l)
+	b, bok := exprNumber(r)
+	if !aok || !bok {
+		return nil, fmt.Errorf(
+			"cannot apply %s to %s and %s",
+			op, formatExprValue(l), formatExprValue(r),
+		)
+	}
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	}
+	if b == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	return math.Mod(a, b), nil
+}
+
+// formatExprValue renders a value for traces and errors.
+func formatExprValue(v any) string {
+	var s string
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		s = strconv.Quote(x)
+	case time.Time:
+		s = x.Format(time.RFC3339)
+	case float64:
+		s = strconv.FormatFloat(x, 'g', -1, 64)
+	default:
+		s = fmt.Sprintf("%v", x)
+	}
+	if len(s) > 80 {
+		s = s[:77] + "..."
+	}
+	return s
+}
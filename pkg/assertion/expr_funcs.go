@@ -0,0 +1,330 @@
+package assertion
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// exprFunc is a function callable from an Expr. maxArgs is -1
+// for variadic functions.
+type exprFunc struct {
+	minArgs, maxArgs int
+	call             func(env *exprEnv, args []any) (any, error)
+}
+
+// exprFuncs lists the functions available to expressions. None
+// of them performs I/O.
+//
+//	len(x)                      length of a string, list or object
+//	lower(s) upper(s) trim(s)   string case and whitespace
+//	contains(s, sub)            substring or list membership
+//	startsWith(s, p) endsWith(s, p)
+//	matches(s, re)              regular expression match
+//	split(s, sep) join(list, sep)
+//	number(x) string(x)         conversions
+//	abs(n) floor(n) ceil(n) round(n) min(...) max(...) sum(list)
+//	has(path)                   whether a target path resolves
+//	now() time(s) duration(s)   current time, RFC 3339 or Unix
+//	since(t) seconds(d)         time and Go duration helpers
+var exprFuncs = map[string]exprFunc{
+	"len":        {1, 1, fnLen},
+	"lower":      {1, 1, stringFunc(strings.ToLower)},
+	"upper":      {1, 1, stringFunc(strings.ToUpper)},
+	"trim":       {1, 1, stringFunc(strings.TrimSpace)},
+	"contains":   {2, 2, fnContains},
+	"startsWith": {2, 2, stringPredicate(strings.HasPrefix)},
+	"endsWith":   {2, 2, stringPredicate(strings.HasSuffix)},
+	"matches":    {2, 2, fnMatches},
+	"split":      {2, 2, fnSplit},
+	"join":       {2, 2, fnJoin},
+	"number":     {1, 1, fnNumber},
+	"string":     {1, 1, fnString},
+	"abs":        {1, 1, mathFunc(math.Abs)},
+	"floor":      {1, 1, mathFunc(math.Floor)},
+	"ceil":       {1, 1, mathFunc(math.Ceil)},
+	"round":      {1, 1, mathFunc(math.Round)},
+	"min":        {1, -1, extremeFunc(-1)},
+	"max":        {1, -1, extremeFunc(1)},
+	"sum":        {1, 1, fnSum},
+	"has":        {1, 1, fnHas},
+	"now":        {0, 0, fnNow},
+	"time":       {1, 1, fnTime},
+	"duration":   {1, 1, fnDuration},
+	"since":      {1, 1, fnSince},
+	"seconds":    {1, 1, fnSeconds},
+}
+
+func fnLen(env *exprEnv, args []any) (any, error) {
+	v, err := decodeJSONString(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := v.(string); ok {
+		return float64(utf8.RuneCountInString(s)), env.step(len(s) / 64)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), nil
+	}
+	return nil, fmt.Errorf("len needs a string, list or object, got %s", formatExprValue(v))
+}
+
+func fnContains(env *exprEnv, args []any) (any, error) {
+	return exprContains(env, args[0], args[1])
+}
+
+func fnMatches(_ *exprEnv, args []any) (any, error) {
+	return exprMatch(args[0], args[1])
+}
+
+func fnSplit(env *exprEnv, args []any) (any, error) {
+	s, sep, err := twoStrings(args)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(s, sep)
+	if err := env.step(len(parts)); err != nil {
+		return nil, err
+	}
+	out := make([]any, len(parts))
+	for i, p := range parts {
+		out[i] = p
+	}
+	return out, nil
+}
+
+func fnJoin(env *exprEnv, args []any) (any, error) {
+	items, err := exprList(env, args[0])
+	if err != nil {
+		return nil, err
+	}
+	sep, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("join separator must be a string")
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func fnNumber(_ *exprEnv, args []any) (any, error) {
+	f, ok := exprNumber(args[0])
+	if !ok {
+		return nil, fmt.Errorf("%s is not a number", formatExprValue(args[0]))
+	}
+	return f, nil
+}
+
+func fnString(_ *exprEnv, args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	}
+	return fmt.Sprint(args[0]), nil
+}
+
+func fnSum(env *exprEnv, args []any) (any, error) {
+	items, err := exprList(env, args[0])
+	if err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for _, item := range items {
+		f, ok := exprNumber(item)
+		if !ok {
+			return nil, fmt.Errorf("sum: %s is not a number", formatExprValue(item))
+		}
+		total += f
+	}
+	return total, nil
+}
+
+func fnHas(env *exprEnv, args []any) (any, error) {
+	target, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("has needs a path string")
+	}
+	p, err := ParsePath(target)
+	if err != nil {
+		return nil, err
+	}
+	matches := p.ResolveRoot(env.values)
+	if err := env.step(len(matches)); err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		if m.Err != nil {
+			return false, nil
+		}
+	}
+	return len(matches) > 0, nil
+}
+
+func fnNow(_ *exprEnv, _ []any) (any, error) {
+	return time.Now(), nil
+}
+
+func fnTime(_ *exprEnv, args []any) (any, error) {
+	return asTime(args[0])
+}
+
+func fnDuration(_ *exprEnv, args []any) (any, error) {
+	return asDuration(args[0])
+}
+
+func fnSince(_ *exprEnv, args []any) (any, error) {
+	t, err := asTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return time.Since(t), nil
+}
+
+func fnSeconds(_ *exprEnv, args []any) (any, error) {
+	d, err := asDuration(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return d.Seconds(), nil
+}
+
+// stringFunc adapts a string transformation.
+func stringFunc(f func(string) string) func(*exprEnv, []any) (any, error) {
+	return func(_ *exprEnv, args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a string", formatExprValue(args[0]))
+		}
+		return f(s), nil
+	}
+}
+
+// stringPredicate adapts a two-string predicate.
+func stringPredicate(f func(string, string) bool) func(*exprEnv, []any) (any, error) {
+	return func(_ *exprEnv, args []any) (any, error) {
+		a, b, err := twoStrings(args)
+		if err != nil {
+			return nil, err
+		}
+		return f(a, b), nil
+	}
+}
+
+// mathFunc adapts a numeric function.
+func mathFunc(f func(float64) float64) func(*exprEnv, []any) (any, error) {
+	return func(_ *exprEnv, args []any) (any, error) {
+		n, ok := exprNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("%s is not a number", formatExprValue(args[0]))
+		}
+		return f(n), nil
+	}
+}
+
+// extremeFunc returns min (sign -1) or max (sign 1) of its
+// arguments, or of a single list argument.
+func extremeFunc(sign int) func(*exprEnv, []any) (any, error) {
+	return func(env *exprEnv, args []any) (any, error) {
+		items := args
+		if len(args) == 1 {
+			list, err := exprList(env, args[0])
+			if err != nil {
+				return nil, err
+			}
+			items = list
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("no values")
+		}
+		best := items[0]
+		for _, item := range items[1:] {
+			c, err := exprCompare(item, best)
+			if err != nil {
+				return nil, err
+			}
+			if c*sign > 0 {
+				best = item
+			}
+		}
+		return best, nil
+	}
+}
+
+// exprList converts a list (or a JSON string holding one) to
+// []any.
+func exprList(env *exprEnv, v any) ([]any, error) {
+	v, err := decodeJSONString(v)
+	if err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%s is not a list", formatExprValue(v))
+	}
+	if err := env.step(rv.Len()); err != nil {
+		return nil, err
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, nil
+}
+
+func twoStrings(args []any) (string, string, error) {
+	a, ok := args[0].(string)
+	if !ok {
+		return "", "", fmt.Errorf("%s is not a string", formatExprValue(args[0]))
+	}
+	b, ok := args[1].(string)
+	if !ok {
+		return "", "", fmt.Errorf("%s is not a string", formatExprValue(args[1]))
+	}
+	return a, b, nil
+}
+
+// asDuration converts a Duration or a Go duration string.
+func asDuration(v any) (time.Duration, error) {
+	switch x := v.(type) {
+	case time.Duration:
+		return x, nil
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(x))
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", x)
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("%s is not a duration", formatExprValue(v))
+}
+
+// asTime converts a Time, an RFC 3339 string or Unix seconds.
+func asTime(v any) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(x))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", x)
+		}
+		return t, nil
+	}
+	if f, ok := exprNumber(v); ok {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(frac*1e9)), nil
+	}
+	return time.Time{}, fmt.Errorf("%s is not a time", formatExprValue(v))
+}
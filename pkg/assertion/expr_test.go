@@ -0,0 +1,192 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exprValues() map[string]any {
+	return map[string]any{
+		"latency_ms": 120,
+		"status":     "ok",
+		"items":      []any{"a", "b", "c"},
+		"body":       `{"user": {"name": "Ada", "roles": ["admin", "dev"]}}`,
+		"count":      "42",
+		"started":    "2026-01-02T15:04:05Z",
+		"elapsed":    "1m30s",
+		"scores":     []any{float64(3), float64(9), float64(7)},
+		"headers":    map[string]any{"content-type": "application/json"},
+	}
+}
+
+func evalExpr(t *testing.T, src string) any {
+	t.Helper()
+	e, err := CompileExpr(src)
+	require.NoError(t, err, src)
+	v, err := e.Eval(exprValues())
+	require.NoError(t, err, src)
+	return v
+}
+
+// ===== Expr evaluation tests =====
+
+func TestExpr_Eval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`latency_ms < 200 && len(items) >= 3 && status in ["ok", "degraded"]`, true},
+		{`latency_ms * 2 + 10`, float64(250)},
+		{`(latency_ms - 20) / 10 % 3`, float64(1)},
+		{`-latency_ms`, float64(-120)},
+		{`count == 42 and count > 40`, true},
+		{`count == "42"`, true},
+		{`status != "failed" or false`, true},
+		{`not (status == "ok")`, false},
+		{`!true || null == nil`, true},
+		{`"b" in items && "z" not in items`, true},
+		{`"content-type" in headers`, true},
+		{`"min" in "admin"`, true},
+		{`body.user.name == "Ada"`, true},
+		{`body.user.roles[1]`, "dev"},
+		{`body["user"]["roles"][-1]`, "dev"},
+		{`headers["content-type"]`, "application/json"},
+		{`status =~ "^o"`, true},
+		{`status !~ "^x"`, true},
+		{`status matches "k$"`, true},
+		{`lower("ABC") + upper("d") + trim("  e ")`, "abcDe"},
+		{`contains(status, "o") && startsWith(status, "o") && endsWith(status, "k")`, true},
+		{`matches("v1.2.3", "^v\\d+\\.\\d+")`, true},
+		{`split("a,b", ",")[1]`, "b"},
+		{`join(items, "-")`, "a-b-c"},
+		{`number("3.5") + 1`, 4.5},
+		{`string(7) + "x"`, "7x"},
+		{`abs(-2) + floor(1.7) + ceil(1.2) + round(2.5)`, float64(8)},
+		{`min(scores) + max(4, 8, 6) + sum(scores)`, float64(30)},
+		{`has("body.user.name") && !has("body.user.email")`, true},
+		{`len(body.user.roles) + len("héllo") + len(headers)`, float64(8)},
+		{`duration(elapsed) > duration("1m")`, true},
+		{`duration(elapsed) < "2m"`, true},
+		{`seconds(elapsed)`, float64(90)},
+		{`time(started) < now() && since(started) > "1h"`, true},
+		{`time(started) + duration("1h") > time("2026-01-02T16:00:00Z")`, true},
+		{`seconds(time("2026-01-02T16:04:05Z") - time(started))`, float64(3600)},
+		{`time(0) == time("1970-01-01T00:00:00Z")`, true},
+		{`[1, 2] + [3]`, []any{float64(1), float64(2), float64(3)}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, evalExpr(t, tt.src), tt.src)
+	}
+}
+
+func TestExpr_EvalErrors(t *testing.T) {
+	tests := []struct {
+		src, msg string
+	}{
+		{`missing > 1`, "undefined name missing"},
+		{`status > 1`, `cannot compare "ok" with 1`},
+		{`1 / 0`, "division by zero"},
+		{`items.x`, "not found at segment"},
+		{`status && true`, "&& needs bool operands"},
+		{`status =~ "("`, "invalid regex"},
+		{`len(1)`, "len needs"},
+		{`duration("soon")`, `invalid duration "soon"`},
+	}
+	for _, tt := range tests {
+		e, err := CompileExpr(tt.src)
+		require.NoError(t, err, tt.src)
+		_, err = e.Eval(exprValues())
+		require.Error(t, err, tt.src)
+		assert.Contains(t, err.Error(), tt.msg, tt.src)
+	}
+}
+
+func TestCompileExpr_Errors(t *testing.T) {
+	tests := []struct {
+		src, msg string
+	}{
+		{`a <`, "unexpected end of expression"},
+		{`(a`, `expected ")"`},
+		{`a b`, `unexpected "b" at position 2`},
+		{`nope(1)`, "unknown function nope"},
+		{`len(1, 2)`, "wrong number of arguments to len"},
+		{`"open`, "unterminated string"},
+		{`a # b`, "unexpected character"},
+		{`a.`, "expected a name"},
+		{strings.Repeat("a", maxExprLength+1), "longer than"},
+		{strings.Repeat("1+", maxExprNodes) + "1", "more than"},
+	}
+	for _, tt := range tests {
+		_, err := CompileExpr(tt.src)
+		require.Error(t, err, tt.src)
+		assert.Contains(t, err.Error(), tt.msg, tt.src)
+	}
+}
+
+func TestExpr_StepBudget(t *testing.T) {
+	big := make([]any, maxExprSteps+1)
+	e, err := CompileExpr(`sum(xs) > 0`)
+	require.NoError(t, err)
+	_, err = e.Eval(map[string]any{"xs": big})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "evaluation steps")
+}
+
+func TestExpr_EvalBoolTrace(t *testing.T) {
+	e, err := CompileExpr(`latency_ms < 100 || len(items) > 5`)
+	require.NoError(t, err)
+	ok, trace, err := e.EvalBool(exprValues())
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t,
+		"latency_ms = 120, latency_ms < 100 = false, "+
+			`items = [a b c], len(items) = 3, len(items) > 5 = false`,
+		trace)
+
+	e, err = CompileExpr(`latency_ms + 1`)
+	require.NoError(t, err)
+	_, _, err = e.EvalBool(exprValues())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a bool")
+}
+
+// ===== expr assertion tests =====
+
+func TestDefaultEngine_ExprAssertion(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{
+		{Type: "expr", Value: `latency_ms < 200 && status == "ok"`},
+		{Type: "expr", Target: "budget", Value: `latency_ms < 100`},
+		{Type: "expr", Value: `latency_ms <`},
+		{Type: "expr"},
+	}, exprValues())
+
+	require.Len(t, results, 4)
+	assert.True(t, results[0].Passed)
+	assert.Nil(t, results[0].Actual)
+	assert.Equal(t, `latency_ms < 200 && status == "ok"`, results[0].Expected)
+
+	assert.False(t, results[1].Passed)
+	assert.Equal(t, "budget", results[1].Target)
+	assert.Equal(t,
+		`expression "latency_ms < 100" is false: latency_ms = 120, latency_ms < 100 = false`,
+		results[1].Message)
+
+	assert.False(t, results[2].Passed)
+	assert.Contains(t, results[2].Message, "invalid expression")
+	assert.False(t, results[3].Passed)
+	assert.Contains(t, results[3].Message, "expr requires a value")
+}
+
+func TestEvaluateExpr_SingleValue(t *testing.T) {
+	e := NewEngine()
+	r := e.Evaluate(Definition{Type: "expr", Value: "value > 3"}, 5)
+	assert.True(t, r.Passed)
+
+	r = e.Evaluate(Definition{Type: "expr", Value: "value > 3"}, "many")
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "failed")
+}
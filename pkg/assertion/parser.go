@@ -11,6 +11,10 @@ import "strings"
 //	"contains:func"  -> ("contains", "func")
 //	"not_empty"      -> ("not_empty", nil)
 //	"min_length:100" -> ("min_length", "100")
+//
+// Deprecated: the value is always a string and cannot be a list.
+// Use ParseDefinition, which parses typed arguments, targets and
+// messages.
 func ParseAssertionString(
 	s string,
 ) (assertionType string, value any) {
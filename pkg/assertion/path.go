@@ -0,0 +1,545 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled target path. The syntax follows a JSONPath
+// subset, with an optional leading "$":
+//
+//	response.data.items[3].id      field and index access
+//	items[-1]                      index from the end
+//	items[*].id  items.*.id        wildcard over elements
+//	items[?(@.status == "ok")].id  filter predicate
+//	headers["content-type"]        quoted key
+//
+// Filter predicates compare a relative path ("@" or "@.a.b")
+// with a string, number, bool or null literal using ==, !=, <,
+// <=, > or >=; a bare path tests for existence. Strings holding
+// JSON objects or arrays are decoded while walking.
+type Path struct {
+	raw      string
+	segments []pathSegment
+}
+
+// Match is a value selected by a Path. Path is the concrete path
+// of the value (wildcards replaced by indices or keys). Err is
+// set when the path could not be followed to the end.
+type Match struct {
+	Path  string
+	Value any
+	Err   error
+}
+
+// PathError reports where a path could not be followed.
+type PathError struct {
+	Path    string
+	Segment string
+	Reason  string
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf(
+		"path %s not found at segment %s: %s",
+		e.Path, e.Segment, e.Reason,
+	)
+}
+
+type segmentKind int
+
+const (
+	segmentField segmentKind = iota
+	segmentIndex
+	segmentWildcard
+	segmentFilter
+)
+
+// pathSegment is a single step of a Path.
+type pathSegment struct {
+	kind   segmentKind
+	name   string
+	index  int
+	filter *pathFilter
+	text   string
+}
+
+// pathFilter is a "[?(...)]" predicate.
+type pathFilter struct {
+	left    *Path
+	op      string
+	literal any
+}
+
+// ParsePath compiles a target path.
+func ParsePath(s string) (*Path, error) {
+	p := &Path{raw: s}
+	rest := strings.TrimPrefix(strings.TrimPrefix(s, "$"), ".")
+	pos := len(s) - len(rest)
+	first := true
+
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end, err := closingBracket(rest)
+			if err != nil {
+				return nil, pathSyntaxError(s, pos, err.Error())
+			}
+			seg, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, pathSyntaxError(s, pos, err.Error())
+			}
+			p.segments = append(p.segments, seg)
+			rest, pos = rest[end+1:], pos+end+1
+		case rest[0] == '.' && !first:
+			rest, pos = rest[1:], pos+1
+			fallthrough
+		default:
+			n := strings.IndexAny(rest, ".[")
+			if n < 0 {
+				n = len(rest)
+			}
+			name := rest[:n]
+			if name == "" || strings.Contains(name, "]") {
+				return nil, pathSyntaxError(s, pos, "empty or malformed segment")
+			}
+			seg := pathSegment{kind: segmentField, name: name, text: name}
+			if name == "*" {
+				seg = pathSegment{kind: segmentWildcard, text: "*"}
+			}
+			p.segments = append(p.segments, seg)
+			rest, pos = rest[n:], pos+n
+		}
+		first = false
+	}
+	return p, nil
+}
+
+// String returns the source form of the path.
+func (p *Path) String() string { return p.raw }
+
+// Wildcard reports whether the path can select more than one
+// value.
+func (p *Path) Wildcard() bool {
+	for _, seg := range p.segments {
+		if seg.kind == segmentWildcard || seg.kind == segmentFilter {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve walks root along the path. A path without wildcards
+// returns exactly one Match; a wildcard path returns one Match
+// per selected element, possibly none.
+func (p *Path) Resolve(root any) []Match {
+	return p.resolve(Match{Value: root})
+}
+
+// resolve walks the path from start.
+func (p *Path) resolve(start Match) []Match {
+	current := []Match{start}
+	for _, seg := range p.segments {
+		var next []Match
+		for _, m := range current {
+			if m.Err != nil {
+				next = append(next, m)
+				continue
+			}
+			next = append(next, p.step(m, seg)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// ResolveRoot resolves the path against a map of named values,
+// as EvaluateAll does. When the leading field segments joined
+// with "." name an existing key (e.g., a metric called
+// "latency.p95"), the longest such key is used as the root.
+func (p *Path) ResolveRoot(values map[string]any) []Match {
+	fields := 0
+	for fields < len(p.segments) && p.segments[fields].kind == segmentField {
+		fields++
+	}
+	for n := fields; n > 1; n-- {
+		names := make([]string, n)
+		for i := range names {
+			names[i] = p.segments[i].name
+		}
+		key := strings.Join(names, ".")
+		if v, ok := values[key]; ok {
+			sub := &Path{raw: p.raw, segments: p.segments[n:]}
+			return sub.resolve(Match{Path: key, Value: v})
+		}
+	}
+	return p.Resolve(values)
+}
+
+// LookupPath resolves a path against root and returns the
+// selected value. Wildcard paths return the selected values as
+// a slice.
+func LookupPath(root any, path string) (any, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	matches := p.Resolve(root)
+	if !p.Wildcard() {
+		return matches[0].Value, matches[0].Err
+	}
+	out := make([]any, 0, len(matches))
+	for _, m := range matches {
+		if m.Err != nil {
+			return nil, m.Err
+		}
+		out = append(out, m.Value)
+	}
+	return out, nil
+}
+
+// step applies one segment to a match.
+func (p *Path) step(m Match, seg pathSegment) []Match {
+	notFound := func(reason string, args ...any) []Match {
+		return []Match{{Path: m.Path, Err: &PathError{
+			Path: p.raw, Segment: strconv.Quote(seg.text),
+			Reason: fmt.Sprintf(reason, args...),
+		}}}
+	}
+
+	value, err := decodeJSONString(m.Value)
+	if err != nil {
+		return notFound("%v", err)
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			break
+		}
+		rv = rv.Elem()
+	}
+
+	switch seg.kind {
+	case segmentField:
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return notFound("%s is not an object", describe(value))
+		}
+		v := rv.MapIndex(reflect.ValueOf(seg.name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return notFound("key %q does not exist", seg.name)
+		}
+		return []Match{{Path: joinField(m.Path, seg.name), Value: v.Interface()}}
+
+	case segmentIndex:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return notFound("%s is not an array", describe(value))
+		}
+		i := seg.index
+		if i < 0 {
+			i += rv.Len()
+		}
+		if i < 0 || i >= rv.Len() {
+			return notFound("index %d out of range (length %d)", seg.index, rv.Len())
+		}
+		return []Match{{
+			Path:  fmt.Sprintf("%s[%d]", m.Path, i),
+			Value: rv.Index(i).Interface(),
+		}}
+
+	default:
+		var out []Match
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				out = append(out, Match{
+					Path:  fmt.Sprintf("%s[%d]", m.Path, i),
+					Value: rv.Index(i).Interface(),
+				})
+			}
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return notFound("%s is not an object", describe(value))
+			}
+			keys := make([]string, 0, rv.Len())
+			for _, k := range rv.MapKeys() {
+				keys = append(keys, k.String())
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				out = append(out, Match{
+					Path:  joinField(m.Path, k),
+					Value: rv.MapIndex(reflect.ValueOf(k).Convert(rv.Type().Key())).Interface(),
+				})
+			}
+		default:
+			return notFound("%s is not an array or object", describe(value))
+		}
+		if seg.kind == segmentFilter {
+			kept := out[:0]
+			for _, e := range out {
+				if seg.filter.match(e.Value) {
+					kept = append(kept, e)
+				}
+			}
+			out = kept
+		}
+		return out
+	}
+}
+
+// match evaluates the filter against one element.
+func (f *pathFilter) match(elem any) bool {
+	matches := f.left.Resolve(elem)
+	if len(matches) != 1 || matches[0].Err != nil {
+		return false
+	}
+	v := matches[0].Value
+	if f.op == "" {
+		return true
+	}
+
+	if a, ok := toFloat64(v); ok {
+		if b, ok := toFloat64(f.literal); ok {
+			switch f.op {
+			case "==":
+				return a == b
+			case "!=":
+				return a != b
+			case "<":
+				return a < b
+			case "<=":
+				return a <= b
+			case ">":
+				return a > b
+			case ">=":
+				return a >= b
+			}
+		}
+	}
+	equal := reflect.DeepEqual(v, f.literal)
+	if s, ok := v.(string); ok {
+		if lit, ok := f.literal.(string); ok {
+			switch f.op {
+			case "<":
+				return s < lit
+			case "<=":
+				return s <= lit
+			case ">":
+				return s > lit
+			case ">=":
+				return s >= lit
+			}
+		}
+	}
+	switch f.op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	}
+	return false
+}
+
+// parseBracket parses the inside of a "[...]" segment.
+func parseBracket(inner string) (pathSegment, error) {
+	text := "[" + inner + "]"
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return pathSegment{kind: segmentWildcard, text: text}, nil
+	case strings.HasPrefix(inner, "?"):
+		f, err := parseFilter(strings.TrimSpace(inner[1:]))
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentFilter, filter: f, text: text}, nil
+	case strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'"):
+		name, err := unquote(inner)
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segmentField, name: name, text: text}, nil
+	}
+	i, err := strconv.Atoi(inner)
+	if err != nil {
+		return pathSegment{}, fmt.Errorf("invalid index %q", inner)
+	}
+	return pathSegment{kind: segmentIndex, index: i, text: text}, nil
+}
+
+// filterOps lists comparison operators, longest first.
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseFilter parses "(@.a.b op literal)" or "@.a.b op literal".
+func parseFilter(expr string) (*pathFilter, error) {
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	if !strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("filter must start with @")
+	}
+
+	left, op, right := expr, "", ""
+	var quote byte
+	for i := 0; i < len(expr) && op == ""; i++ {
+		switch c := expr[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '"' || c == '\'':
+			quote = c
+			continue
+		}
+		for _, candidate := range filterOps {
+			if strings.HasPrefix(expr[i:], candidate) {
+				left, op = expr[:i], candidate
+				right = expr[i+len(candidate):]
+				break
+			}
+		}
+	}
+
+	leftPath, err := ParsePath(strings.TrimSpace(strings.TrimPrefix(
+		strings.TrimSpace(left), "@",
+	)))
+	if err != nil {
+		return nil, fmt.Errorf("filter operand: %w", err)
+	}
+	f := &pathFilter{left: leftPath, op: op}
+	if op == "" {
+		return f, nil
+	}
+	f.literal, err = parseLiteral(strings.TrimSpace(right))
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// parseLiteral parses a filter literal.
+func parseLiteral(s string) (any, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'") {
+		return unquote(s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter literal %q", s)
+	}
+	return f, nil
+}
+
+// unquote strips matching single or double quotes.
+func unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	if s[0] == '"' {
+		return strconv.Unquote(s)
+	}
+	return s[1 : len(s)-1], nil
+}
+
+// closingBracket returns the index of the "]" closing the "["
+// at s[0], skipping quoted strings and nested brackets.
+func closingBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed [")
+}
+
+func pathSyntaxError(path string, pos int, msg string) error {
+	return fmt.Errorf("invalid path %q: %s at position %d", path, msg, pos)
+}
+
+// decodeJSONString decodes strings and raw bytes holding a JSON
+// object or array; other values are returned unchanged.
+func decodeJSONString(v any) (any, error) {
+	var raw []byte
+	switch x := v.(type) {
+	case string:
+		raw = []byte(x)
+	case json.RawMessage:
+		raw = x
+	case []byte:
+		raw = x
+	default:
+		return v, nil
+	}
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return v, nil
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+		return nil, fmt.Errorf("string is not valid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+// joinField appends a field to a concrete path, quoting keys
+// that would not parse back as a plain field.
+func joinField(path, name string) string {
+	if strings.ContainsAny(name, `.[]"' `) || name == "" {
+		return fmt.Sprintf("%s[%s]", path, strconv.Quote(name))
+	}
+	if path == "" {
+		return path + name
+	}
+	return path + "." + name
+}
+
+// describe names the kind of v for error messages.
+func describe(v any) string {
+	if v == nil {
+		return "null"
+	}
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,184 @@
+package assertion
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pathFixture(t *testing.T) map[string]any {
+	t.Helper()
+	var response map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"data": {
+			"items": [
+				{"id": "a", "status": "ok", "score": 3},
+				{"id": "b", "status": "failed", "score": 9},
+				{"id": "c", "status": "ok", "score": 7}
+			],
+			"meta": {"content-type": "json"}
+		}
+	}`), &response))
+	return map[string]any{
+		"response":    response,
+		"raw":         `{"nested": {"list": [1, 2, 3]}}`,
+		"plain":       "text",
+		"latency.p95": map[string]any{"ms": 120},
+		"typed":       map[string][]string{"tags": {"x", "y"}},
+	}
+}
+
+// ===== ParsePath tests =====
+
+func TestParsePath_Errors(t *testing.T) {
+	for _, s := range []string{
+		"a..b", "a[", "a[x]", "a[?(b == 1)]", `a["open]`,
+		"a[?(@.x == nope)]",
+	} {
+		_, err := ParsePath(s)
+		assert.Error(t, err, s)
+	}
+
+	_, err := ParsePath("items[oops")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "at position 5")
+}
+
+func TestPath_Wildcard(t *testing.T) {
+	for s, want := range map[string]bool{
+		"a.b[0]":           false,
+		"a[*]":             true,
+		"a.*.b":            true,
+		`a[?(@.x == "y")]`: true,
+	} {
+		p, err := ParsePath(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, p.Wildcard(), s)
+		assert.Equal(t, s, p.String())
+	}
+}
+
+// ===== Resolve tests =====
+
+func TestLookupPath(t *testing.T) {
+	values := pathFixture(t)
+	tests := []struct {
+		path string
+		want any
+	}{
+		{"response.data.items[1].id", "b"},
+		{"$.response.data.items[-1].id", "c"},
+		{`response.data.meta["content-type"]`, "json"},
+		{"response['data'].items[0].score", float64(3)},
+		{"raw.nested.list[2]", float64(3)},
+		{"typed.tags[1]", "y"},
+		{"response.data.items[*].id", []any{"a", "b", "c"}},
+		{"response.data.items.*.score", []any{float64(3), float64(9), float64(7)}},
+		{`response.data.items[?(@.status == "ok")].id`, []any{"a", "c"}},
+		{"response.data.items[?(@.score >= 7)].id", []any{"b", "c"}},
+		{"response.data.items[?(@.score != 3)].id", []any{"b", "c"}},
+		{"response.data.items[?@.id < 'b'].id", []any{"a"}},
+		{"response.data.items[?(@.missing)]", []any{}},
+		{"response.data.meta[*]", []any{"json"}},
+	}
+	for _, tt := range tests {
+		got, err := LookupPath(values, tt.path)
+		require.NoError(t, err, tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+	}
+}
+
+func TestLookupPath_NotFound(t *testing.T) {
+	values := pathFixture(t)
+	tests := []struct {
+		path, segment, reason string
+	}{
+		{"response.data.items[5].id", `"[5]"`, "index 5 out of range (length 3)"},
+		{"response.data.nope.id", `"nope"`, `key "nope" does not exist`},
+		{"plain.x", `"x"`, "string is not an object"},
+		{"response.data[0]", `"[0]"`, "object is not an array"},
+		{"response.data.items[0].id.x", `"x"`, "string is not an object"},
+	}
+	for _, tt := range tests {
+		_, err := LookupPath(values, tt.path)
+		require.Error(t, err, tt.path)
+		var pe *PathError
+		require.ErrorAs(t, err, &pe, tt.path)
+		assert.Equal(t, tt.segment, pe.Segment, tt.path)
+		assert.Equal(t, tt.reason, pe.Reason, tt.path)
+		assert.Contains(t, err.Error(), "not found at segment "+tt.segment)
+	}
+}
+
+func TestPath_ResolveConcretePaths(t *testing.T) {
+	p, err := ParsePath("response.data.items[?(@.score > 5)].id")
+	require.NoError(t, err)
+	matches := p.ResolveRoot(pathFixture(t))
+	require.Len(t, matches, 2)
+	assert.Equal(t, "response.data.items[1].id", matches[0].Path)
+	assert.Equal(t, "response.data.items[2].id", matches[1].Path)
+
+	p, err = ParsePath("response.data.meta.*")
+	require.NoError(t, err)
+	matches = p.Resolve(pathFixture(t))
+	require.Len(t, matches, 1)
+	assert.Equal(t, "response.data.meta.content-type", matches[0].Path)
+}
+
+func TestPath_ResolveRootDottedKey(t *testing.T) {
+	p, err := ParsePath("latency.p95.ms")
+	require.NoError(t, err)
+	matches := p.ResolveRoot(pathFixture(t))
+	require.Len(t, matches, 1)
+	require.NoError(t, matches[0].Err)
+	assert.Equal(t, 120, matches[0].Value)
+	assert.Equal(t, "latency.p95.ms", matches[0].Path)
+}
+
+// ===== EvaluateAll path tests =====
+
+func TestDefaultEngine_EvaluateAll_Paths(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{
+		{Type: "contains", Target: "response.data.items[0].status", Value: "ok"},
+		{Type: "contains", Target: "response.data.items[*].status", Value: "ok"},
+		{Type: "not_empty", Target: "response.data.items[9].id"},
+		{Type: "not_empty", Target: "response.data.items[?(@.score > 100)]"},
+		{Type: "not_empty", Target: "response.data.items[bad"},
+	}, pathFixture(t))
+
+	require.Len(t, results, 7)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "response.data.items[0].status", results[0].Target)
+
+	assert.Equal(t, "response.data.items[0].status", results[1].Target)
+	assert.True(t, results[1].Passed)
+	assert.Equal(t, "response.data.items[1].status", results[2].Target)
+	assert.False(t, results[2].Passed)
+	assert.True(t, results[3].Passed)
+
+	assert.False(t, results[4].Passed)
+	assert.Contains(t, results[4].Message, `not found at segment "[9]"`)
+
+	assert.False(t, results[5].Passed)
+	assert.Contains(t, results[5].Message, "matched no elements")
+
+	assert.False(t, results[6].Passed)
+	assert.Contains(t, results[6].Message, "invalid path")
+}
+
+func TestDefaultEngine_EvaluateAll_ExactKeyWins(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{
+		{Type: "not_empty", Target: "a.b"},
+		{Type: "not_empty", Target: "missing"},
+	}, map[string]any{
+		"a.b": "flat",
+		"a":   map[string]any{"b": ""},
+	})
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Passed)
+	assert.Equal(t, "target not found: missing", results[1].Message)
+}
@@ -0,0 +1,644 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Structural assertion types. Both accept the target as a
+// decoded value or as a raw JSON string and report one child
+// Result per violation, with the violating path as its Target.
+//
+// json_schema validates against a JSON Schema given inline as
+// Value (an object or a JSON string) or as the path of a JSON
+// file. The supported subset of draft 2020-12 is: type, enum,
+// const, required, properties, additionalProperties, items,
+// pattern, minLength, maxLength, minimum, maximum,
+// exclusiveMinimum, exclusiveMaximum, minItems and maxItems.
+// Annotations ($schema, $id, $comment, title, description,
+// examples, default, deprecated, readOnly, writeOnly and
+// format) are ignored; any other keyword, such as anyOf or
+// $ref, is an "unsupported keyword" error rather than a schema
+// that accepts everything.
+//
+// json_shape_matches compares against a recorded example
+// (inline, or the path of a JSON file) by type only: every
+// property of an example object must be present with the same
+// JSON type, array elements must match the shape of an example
+// element, and a null example value matches anything, including
+// a missing property. Extra properties are allowed.
+const (
+	TypeJSONSchema       = "json_schema"
+	TypeJSONShapeMatches = "json_shape_matches"
+)
+
+// maxReportedViolations caps the violations listed in a Result
+// message; all of them are kept in Result.Children.
+const maxReportedViolations = 5
+
+// Violation is a single structural mismatch.
+type Violation struct {
+	// Path locates the mismatch relative to the checked value
+	// (e.g., "user.roles[2]"); empty for the value itself.
+	Path string `json:"path"`
+
+	// Message describes the mismatch.
+	Message string `json:"message"`
+}
+
+// String renders the violation as "path: message".
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	reject bool // the false schema
+
+	types      []string
+	enum       []any
+	hasConst   bool
+	constValue any
+	required   []string
+	properties map[string]*Schema
+	additional *Schema
+	items      *Schema
+	pattern    *regexp.Regexp
+
+	minLength, maxLength *int
+	minItems, maxItems   *int
+	minimum, maximum     *float64
+	exclMinimum          *float64
+	exclMaximum          *float64
+}
+
+// schemaAnnotations are the keywords that do not constrain a
+// value and are ignored.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true,
+	"description": true, "examples": true, "default": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+	"format": true,
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+// ParseSchema parses a JSON Schema given as a decoded value, a
+// bool, or a JSON string.
+func ParseSchema(src any) (*Schema, error) {
+	doc, err := normalizeJSON(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return parseSchemaNode(doc, "")
+}
+
+// LoadSchema parses an inline schema, or reads one from the
+// JSON file named by a string that is not itself JSON.
+func LoadSchema(src any) (*Schema, error) {
+	doc, err := loadJSONDocument(src)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(doc)
+}
+
+func parseSchemaNode(node any, at string) (*Schema, error) {
+	fail := func(format string, args ...any) (*Schema, error) {
+		where := ""
+		if at != "" {
+			where = " at " + at
+		}
+		return nil, fmt.Errorf("invalid schema%s: %s", where, fmt.Sprintf(format, args...))
+	}
+
+	switch n := node.(type) {
+	case bool:
+		return &Schema{reject: !n}, nil
+	case map[string]any:
+		s := &Schema{}
+		for _, key := range sortedKeys(n) {
+			v := n[key]
+			var err error
+			switch key {
+			case "type":
+				s.types, err = schemaTypeList(v)
+			case "enum":
+				list, ok := v.([]any)
+				if !ok {
+					return fail("enum must be an array")
+				}
+				s.enum = list
+			case "const":
+				s.hasConst, s.constValue = true, v
+			case "required":
+				s.required, err = stringList(v)
+			case "properties":
+				props, ok := v.(map[string]any)
+				if !ok {
+					return fail("properties must be an object")
+				}
+				s.properties = make(map[string]*Schema, len(props))
+				for name, sub := range props {
+					child, err := parseSchemaNode(
+						sub, joinField(joinField(at, "properties"), name),
+					)
+					if err != nil {
+						return nil, err
+					}
+					s.properties[name] = child
+				}
+			case "additionalProperties":
+				if s.additional, err = parseSchemaNode(v, joinField(at, key)); err != nil {
+					return nil, err
+				}
+			case "items":
+				if s.items, err = parseSchemaNode(v, joinField(at, key)); err != nil {
+					return nil, err
+				}
+			case "pattern":
+				p, ok := v.(string)
+				if !ok {
+					return fail("pattern must be a string")
+				}
+				if len(p) > maxRegexLength {
+					return fail("pattern is longer than %d characters", maxRegexLength)
+				}
+				if s.pattern, err = regexp.Compile(p); err != nil {
+					return fail("invalid pattern: %v", err)
+				}
+			case "minLength":
+				s.minLength, err = schemaCount(key, v)
+			case "maxLength":
+				s.maxLength, err = schemaCount(key, v)
+			case "minItems":
+				s.minItems, err = schemaCount(key, v)
+			case "maxItems":
+				s.maxItems, err = schemaCount(key, v)
+			case "minimum":
+				s.minimum, err = schemaNumber(key, v)
+			case "maximum":
+				s.maximum, err = schemaNumber(key, v)
+			case "exclusiveMinimum":
+				s.exclMinimum, err = schemaNumber(key, v)
+			case "exclusiveMaximum":
+				s.exclMaximum, err = schemaNumber(key, v)
+			default:
+				if !schemaAnnotations[key] {
+					return fail("unsupported keyword %q", key)
+				}
+			}
+			if err != nil {
+				return fail("%v", err)
+			}
+		}
+		return s, nil
+	}
+	return fail("expected an object or a bool, got %s", describe(node))
+}
+
+// Validate checks value (a decoded value or a JSON string)
+// against the schema and returns the violations in a stable
+// order.
+func (s *Schema) Validate(value any) ([]Violation, error) {
+	v, err := normalizeJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	var out []Violation
+	s.validate(v, "", &out)
+	return out, nil
+}
+
+func (s *Schema) validate(v any, at string, out *[]Violation) {
+	add := func(format string, args ...any) {
+		*out = append(*out, Violation{Path: at, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.reject {
+		add("value is not allowed")
+		return
+	}
+	if len(s.types) > 0 && !matchesSchemaType(v, s.types) {
+		add("expected %s, got %s", strings.Join(s.types, " or "), jsonType(v))
+		return
+	}
+	if s.hasConst && !reflect.DeepEqual(v, s.constValue) {
+		add("%s is not %s", formatJSON(v), formatJSON(s.constValue))
+	}
+	if s.enum != nil && !containsDeep(s.enum, v) {
+		add("%s is not one of %s", formatJSON(v), formatJSON(s.enum))
+	}
+
+	switch x := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(x)
+		if s.minLength != nil && n < *s.minLength {
+			add("length %d is less than minLength %d", n, *s.minLength)
+		}
+		if s.maxLength != nil && n > *s.maxLength {
+			add("length %d is greater than maxLength %d", n, *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(x) {
+			add("%q does not match pattern %s", x, s.pattern)
+		}
+	case float64:
+		num := strconv.FormatFloat(x, 'g', -1, 64)
+		if s.minimum != nil && x < *s.minimum {
+			add("%s is less than minimum %g", num, *s.minimum)
+		}
+		if s.maximum != nil && x > *s.maximum {
+			add("%s is greater than maximum %g", num, *s.maximum)
+		}
+		if s.exclMinimum != nil && x <= *s.exclMinimum {
+			add("%s is not greater than exclusiveMinimum %g", num, *s.exclMinimum)
+		}
+		if s.exclMaximum != nil && x >= *s.exclMaximum {
+			add("%s is not less than exclusiveMaximum %g", num, *s.exclMaximum)
+		}
+	case []any:
+		if s.minItems != nil && len(x) < *s.minItems {
+			add("%d items is less than minItems %d", len(x), *s.minItems)
+		}
+		if s.maxItems != nil && len(x) > *s.maxItems {
+			add("%d items is greater than maxItems %d", len(x), *s.maxItems)
+		}
+		if s.items != nil {
+			for i, elem := range x {
+				s.items.validate(elem, fmt.Sprintf("%s[%d]", at, i), out)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := x[name]; !ok {
+				*out = append(*out, Violation{
+					Path: joinField(at, name), Message: "required property is missing",
+				})
+			}
+		}
+		for _, name := range sortedKeys(x) {
+			sub, declared := s.properties[name]
+			switch {
+			case declared:
+				sub.validate(x[name], joinField(at, name), out)
+			case s.additional != nil && s.additional.reject:
+				*out = append(*out, Violation{
+					Path: joinField(at, name), Message: "additional property is not allowed",
+				})
+			case s.additional != nil:
+				s.additional.validate(x[name], joinField(at, name), out)
+			}
+		}
+	}
+}
+
+// MatchShape compares value (a decoded value or a JSON string)
+// with example by JSON type only and returns the mismatches.
+func MatchShape(example, value any) ([]Violation, error) {
+	ex, err := normalizeJSON(example)
+	if err != nil {
+		return nil, fmt.Errorf("invalid example: %w", err)
+	}
+	v, err := normalizeJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	var out []Violation
+	matchShape(ex, v, "", &out)
+	return out, nil
+}
+
+func matchShape(example, v any, at string, out *[]Violation) {
+	if example == nil {
+		return
+	}
+	if jsonType(example) != jsonType(v) {
+		*out = append(*out, Violation{
+			Path:    at,
+			Message: fmt.Sprintf("expected %s, got %s", jsonType(example), jsonType(v)),
+		})
+		return
+	}
+	switch ex := example.(type) {
+	case map[string]any:
+		obj := v.(map[string]any)
+		for _, name := range sortedKeys(ex) {
+			field := joinField(at, name)
+			actual, ok := obj[name]
+			switch {
+			case ok:
+				matchShape(ex[name], actual, field, out)
+			case ex[name] != nil:
+				*out = append(*out, Violation{
+					Path:    field,
+					Message: fmt.Sprintf("missing property (example has %s)", jsonType(ex[name])),
+				})
+			}
+		}
+	case []any:
+		if len(ex) == 0 {
+			return
+		}
+		for i, elem := range v.([]any) {
+			matchElement(ex, elem, fmt.Sprintf("%s[%d]", at, i), out)
+		}
+	}
+}
+
+// matchElement matches an array element against the example
+// elements, reporting the mismatches against the first one
+// when none matches.
+func matchElement(examples []any, elem any, at string, out *[]Violation) {
+	var first []Violation
+	for i, ex := range examples {
+		var got []Violation
+		matchShape(ex, elem, at, &got)
+		if len(got) == 0 {
+			return
+		}
+		if i == 0 {
+			first = got
+		}
+	}
+	*out = append(*out, first...)
+}
+
+// evaluateStructure adapts a violation check to a
+// resultEvaluator.
+func evaluateStructure(
+	describeOK string,
+	check func(a Definition, value any) ([]Violation, error),
+) resultEvaluator {
+	return func(a Definition, value any) Result {
+		r := Result{Expected: a.Value, Actual: value}
+		violations, err := check(a, value)
+		if err != nil {
+			r.Message = err.Error()
+			return r
+		}
+		if len(violations) == 0 {
+			r.Passed = true
+			r.Message = describeOK
+			return r
+		}
+		listed := make([]string, 0, maxReportedViolations)
+		for i, v := range violations {
+			r.Children = append(r.Children, Result{
+				Type:    a.Type,
+				Target:  joinPath(a.Target, v.Path),
+				Passed:  false,
+				Message: v.Message,
+			})
+			if i < maxReportedViolations {
+				listed = append(listed, v.String())
+			}
+		}
+		if extra := len(violations) - len(listed); extra > 0 {
+			listed = append(listed, fmt.Sprintf("and %d more", extra))
+		}
+		r.Message = fmt.Sprintf("%d violation(s): %s",
+			len(violations), strings.Join(listed, "; "))
+		return r
+	}
+}
+
+var (
+	evaluateJSONSchema = evaluateStructure(
+		"value matches the schema",
+		func(a Definition, value any) ([]Violation, error) {
+			s, err := LoadSchema(a.Value)
+			if err != nil {
+				return nil, err
+			}
+			return s.Validate(value)
+		},
+	)
+	evaluateJSONShape = evaluateStructure(
+		"value matches the example shape",
+		func(a Definition, value any) ([]Violation, error) {
+			example, err := loadJSONDocument(a.Value)
+			if err != nil {
+				return nil, err
+			}
+			return MatchShape(example, value)
+		},
+	)
+)
+
+// ResolveFileReferences resolves the relative schema and example
+// file paths of json_schema and json_shape_matches assertions in
+// defs, and in their sub-assertions, against dir. Bank loaders
+// pass the directory of the bank file, so a path is relative to
+// the file that declares it rather than to the process working
+// directory. defs are modified in place.
+func ResolveFileReferences(defs []challenge.AssertionDef, dir string) {
+	for i := range defs {
+		d := &defs[i]
+		if d.Type == TypeJSONSchema || d.Type == TypeJSONShapeMatches {
+			if name, ok := fileReference(d.Value); ok &&
+				!filepath.IsAbs(name) {
+				d.Value = filepath.Join(dir, name)
+			}
+		}
+		ResolveFileReferences(d.Assertions, dir)
+	}
+}
+
+// fileReference returns the file path src names when it is a
+// string that is not JSON.
+func fileReference(src any) (string, bool) {
+	name, ok := src.(string)
+	if !ok {
+		return "", false
+	}
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" || trimmed[0] == '{' || trimmed[0] == '[' {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// loadJSONDocument returns src, or the decoded contents of the
+// file it names when src is a string that is not JSON.
+func loadJSONDocument(src any) (any, error) {
+	if name, ok := src.(string); ok && strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("empty schema or example reference")
+	}
+	trimmed, ok := fileReference(src)
+	if !ok {
+		return src, nil
+	}
+	data, err := os.ReadFile(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", trimmed, err)
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", trimmed, err)
+	}
+	return doc, nil
+}
+
+// normalizeJSON converts v to the types produced by
+// encoding/json (map[string]any, []any, float64, ...),
+// decoding JSON strings.
+func normalizeJSON(v any) (any, error) {
+	v, err := decodeJSONString(v)
+	if err != nil {
+		return nil, err
+	}
+	switch x := v.(type) {
+	case nil, bool, float64, string:
+		return v, nil
+	case []byte:
+		return string(x), nil
+	case json.RawMessage:
+		return string(x), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("value is not representable as JSON: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// formatJSON renders a normalized value as compact JSON.
+func formatJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// jsonType names the JSON type of a normalized value.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func matchesSchemaType(v any, types []string) bool {
+	actual := jsonType(v)
+	for _, t := range types {
+		if t == actual {
+			return true
+		}
+		if f, ok := v.(float64); ok && t == "integer" && f == math.Trunc(f) {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaTypeList(v any) ([]string, error) {
+	var list []string
+	if s, ok := v.(string); ok {
+		list = []string{s}
+	} else {
+		var err error
+		if list, err = stringList(v); err != nil {
+			return nil, fmt.Errorf("type must be a string or an array of strings")
+		}
+	}
+	for _, t := range list {
+		if !schemaTypes[t] {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return list, nil
+}
+
+func stringList(v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of strings")
+	}
+	out := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an array of strings")
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func schemaCount(key string, v any) (*int, error) {
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	n := int(f)
+	return &n, nil
+}
+
+func schemaNumber(key string, v any) (*float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &f, nil
+}
+
+func containsDeep(list []any, v any) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath appends a relative violation path to a target.
+func joinPath(target, rel string) string {
+	switch {
+	case rel == "":
+		return target
+	case target == "", strings.HasPrefix(rel, "["):
+		return target + rel
+	}
+	return target + "." + rel
+}
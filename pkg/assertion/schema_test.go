@@ -0,0 +1,201 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+func userSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []any{"id", "name", "roles"},
+		"properties": map[string]any{
+			"id":    map[string]any{"type": "integer", "minimum": 1},
+			"name":  map[string]any{"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+			"email": map[string]any{"type": []any{"string", "null"}},
+			"roles": map[string]any{
+				"type": "array", "minItems": 1,
+				"items": map[string]any{"enum": []any{"admin", "dev"}},
+			},
+			"score": map[string]any{"exclusiveMaximum": 100},
+		},
+		"additionalProperties": false,
+	}
+}
+
+// ===== JSON Schema tests =====
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := ParseSchema(userSchema())
+	require.NoError(t, err)
+
+	violations, err := s.Validate(`{"id": 7, "name": "Ada", "email": null, "roles": ["dev"]}`)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = s.Validate(map[string]any{
+		"id": 1.5, "name": "a", "roles": []string{"dev", "ops"},
+		"score": 100, "extra": true,
+	})
+	require.NoError(t, err)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{
+		"extra: additional property is not allowed",
+		"id: expected integer, got number",
+		"name: length 1 is less than minLength 2",
+		`name: "a" does not match pattern ^[A-Z]`,
+		`roles[1]: "ops" is not one of ["admin","dev"]`,
+		"score: 100 is not less than exclusiveMaximum 100",
+	}, got)
+
+	violations, err = s.Validate(`{"name": "Ada"}`)
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, Violation{Path: "id", Message: "required property is missing"}, violations[0])
+}
+
+func TestParseSchema_IgnoresAnnotations(t *testing.T) {
+	s, err := ParseSchema(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "user", "title": "User", "description": "A user",
+		"type": "string", "format": "email", "examples": ["a@b.c"]
+	}`)
+	require.NoError(t, err)
+	violations, err := s.Validate(42)
+	require.NoError(t, err)
+	assert.Len(t, violations, 1)
+}
+
+func TestParseSchema_Errors(t *testing.T) {
+	tests := []struct {
+		schema any
+		msg    string
+	}{
+		{`{"type": "text"}`, `unknown type "text"`},
+		{map[string]any{"minLength": -1}, "minLength must be a non-negative integer"},
+		{map[string]any{"properties": map[string]any{
+			"a": map[string]any{"pattern": "("},
+		}}, "invalid schema at properties.a: invalid pattern"},
+		{map[string]any{"items": "x"}, "at items: expected an object or a bool"},
+		{`{"type": `, "invalid schema"},
+		{`{"anyOf": [{"type": "string"}]}`, `unsupported keyword "anyOf"`},
+		{map[string]any{"properties": map[string]any{
+			"a": map[string]any{"$ref": "#/$defs/a"},
+		}}, `invalid schema at properties.a: unsupported keyword "$ref"`},
+		{`{"items": {"not": {"type": "null"}}}`, `at items: unsupported keyword "not"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseSchema(tt.schema)
+		require.Error(t, err, tt.msg)
+		assert.Contains(t, err.Error(), tt.msg)
+	}
+}
+
+func TestDefaultEngine_JSONSchema(t *testing.T) {
+	e := NewEngine()
+	results := e.EvaluateAll([]Definition{
+		{Type: "json_schema", Target: "response.user", Value: userSchema()},
+		{Type: "json_schema", Target: "response", Value: `{"required": ["user", "total"]}`},
+	}, map[string]any{
+		"response": `{"user": {"id": 0, "name": "Ada", "roles": []}}`,
+	})
+
+	require.Len(t, results, 2)
+	assert.False(t, results[0].Passed)
+	assert.Contains(t, results[0].Message, "2 violation(s): id: 0 is less than minimum 1")
+	require.Len(t, results[0].Children, 2)
+	assert.Equal(t, "response.user.id", results[0].Children[0].Target)
+	assert.Equal(t, "response.user.roles", results[0].Children[1].Target)
+
+	assert.False(t, results[1].Passed)
+	require.Len(t, results[1].Children, 1)
+	assert.Equal(t, "response.total", results[1].Children[0].Target)
+}
+
+func TestDefaultEngine_JSONSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "status.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"type": "string", "enum": ["ok", "degraded"]}`), 0o644))
+
+	e := NewEngine()
+	r := e.Evaluate(Definition{Type: "json_schema", Target: "status", Value: path}, "ok")
+	assert.True(t, r.Passed, r.Message)
+
+	r = e.Evaluate(Definition{Type: "json_schema", Value: filepath.Join(dir, "missing.json")}, "ok")
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "missing.json")
+}
+
+func TestResolveFileReferences(t *testing.T) {
+	defs := []challenge.AssertionDef{
+		{Type: "json_schema", Value: "schemas/user.json"},
+		{Type: "json_schema", Value: `{"type": "object"}`},
+		{Type: "json_shape_matches", Value: "/abs/example.json"},
+		{Type: "contains", Value: "schemas/user.json"},
+		{Type: "all_of", Assertions: []challenge.AssertionDef{
+			{Type: "json_shape_matches", Value: " example.json "},
+		}},
+	}
+	ResolveFileReferences(defs, "/banks")
+
+	assert.Equal(t, filepath.Join("/banks", "schemas/user.json"), defs[0].Value)
+	assert.Equal(t, `{"type": "object"}`, defs[1].Value)
+	assert.Equal(t, "/abs/example.json", defs[2].Value)
+	assert.Equal(t, "schemas/user.json", defs[3].Value)
+	assert.Equal(t, filepath.Join("/banks", "example.json"),
+		defs[4].Assertions[0].Value)
+}
+
+// ===== JSON shape tests =====
+
+func TestMatchShape(t *testing.T) {
+	example := `{"id": 1, "name": "x", "tags": ["a"], "meta": null,
+		"owner": {"login": "x", "admin": false}}`
+
+	violations, err := MatchShape(example, map[string]any{
+		"id": 42, "name": "Ada", "tags": []any{}, "meta": map[string]any{"k": 1},
+		"owner": map[string]any{"login": "ada", "admin": true, "extra": 1},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = MatchShape(example,
+		`{"id": "42", "tags": ["a", 2], "owner": {"login": "ada", "admin": "no"}}`)
+	require.NoError(t, err)
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{
+		"id: expected number, got string",
+		"name: missing property (example has string)",
+		"owner.admin: expected boolean, got string",
+		"tags[1]: expected string, got number",
+	}, got)
+}
+
+func TestDefaultEngine_JSONShapeMatches(t *testing.T) {
+	e := NewEngine()
+	r := e.Evaluate(Definition{
+		Type: "json_shape_matches", Target: "items",
+		Value: []any{map[string]any{"id": 1}},
+	}, `[{"id": 2}, {"id": "3"}]`)
+	assert.False(t, r.Passed)
+	require.Len(t, r.Children, 1)
+	assert.Equal(t, "items[1].id", r.Children[0].Target)
+	assert.Equal(t, "expected number, got string", r.Children[0].Message)
+
+	r = e.Evaluate(Definition{Type: "json_shape_matches", Value: `{"ok": true}`}, `{"ok": false}`)
+	assert.True(t, r.Passed)
+	assert.Equal(t, "value matches the example shape", r.Message)
+}
@@ -0,0 +1,582 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	// Register decoders for screenshot snapshots.
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// TypeMatchesSnapshot compares a value with a snapshot recorded
+// by an earlier run. The first evaluation records the snapshot
+// and passes; later evaluations diff against it, and a
+// SnapshotStore in update mode rewrites it.
+//
+// Value optionally names the snapshot (default: the Target).
+// The comparison depends on the value:
+//
+//   - images ([]byte image data, or the path of a .png, .jpg or
+//     .gif file) are compared pixel by pixel; a pixel differs
+//     when a channel differs by more than Tolerance (0-255),
+//     and the assertion passes when at most RelativeTolerance
+//     of the pixels differ;
+//   - JSON values (objects, arrays, or strings holding them)
+//     are compared semantically, skipping the paths listed in
+//     Ignore (e.g., "items[*].created_at");
+//   - anything else is compared as text, line by line.
+//
+// A value of a different kind than the recorded snapshot fails
+// outside update mode, as does an image path that cannot be
+// read.
+//
+// A failing comparison writes a diff into the store's artifacts
+// directory, listed by SnapshotStore.Artifacts.
+const TypeMatchesSnapshot = "matches_snapshot"
+
+// Snapshot kinds.
+const (
+	snapshotText  = "txt"
+	snapshotJSON  = "json"
+	snapshotImage = "png"
+)
+
+// maxLineDiffCells bounds the line diff table; larger inputs
+// fall back to a positional comparison.
+const maxLineDiffCells = 4_000_000
+
+var snapshotNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SnapshotStore holds the recorded snapshots of a challenge. It
+// is safe for concurrent use.
+type SnapshotStore struct {
+	dir          string
+	artifactsDir string
+	update       bool
+
+	mu        sync.Mutex
+	artifacts map[string]string
+}
+
+// NewSnapshotStore creates a store reading and writing
+// snapshots in dir and diffs in artifactsDir/snapshots. With
+// update set, evaluations rewrite the snapshots.
+func NewSnapshotStore(dir, artifactsDir string, update bool) *SnapshotStore {
+	return &SnapshotStore{
+		dir:          dir,
+		artifactsDir: artifactsDir,
+		update:       update,
+		artifacts:    make(map[string]string),
+	}
+}
+
+// Dir returns the snapshot directory.
+func (s *SnapshotStore) Dir() string { return s.dir }
+
+// Artifacts returns the diff files written so far, keyed by
+// "snapshot:<name>".
+func (s *SnapshotStore) Artifacts() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string, len(s.artifacts))
+	for k, v := range s.artifacts {
+		out[k] = v
+	}
+	return out
+}
+
+// snapshotValue is a value prepared for recording.
+type snapshotValue struct {
+	kind string
+	data []byte
+	doc  any         // decoded JSON, for snapshotJSON
+	img  image.Image // decoded image, for snapshotImage
+}
+
+// evaluateSnapshot checks a matches_snapshot assertion against
+// the store.
+func evaluateSnapshot(s *SnapshotStore, a Definition, value any) Result {
+	r := Result{Type: a.Type, Target: a.Target, Actual: value}
+	if s == nil {
+		r.Message = "matches_snapshot requires a snapshot store; " +
+			"evaluate it through a challenge or EvaluateAllWithSnapshots"
+		return r
+	}
+	name := a.Target
+	if n, ok := a.Value.(string); ok && n != "" {
+		name = n
+	}
+	name = snapshotName(name)
+	if name == "" {
+		r.Message = "matches_snapshot needs a target or a snapshot name in value"
+		return r
+	}
+
+	current, err := prepareSnapshot(value)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	path := filepath.Join(s.dir, name+".snap."+current.kind)
+	r.Expected = path
+
+	// A value that changed kind, e.g. a JSON body replaced by an
+	// HTML error page, must not record a second snapshot and pass.
+	others, err := otherSnapshots(s.dir, name, current.kind)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	if len(others) > 0 && !s.update {
+		r.Expected = others[0]
+		r.Message = fmt.Sprintf(
+			"snapshot %s records a %s value, got %s",
+			others[0], strings.TrimPrefix(filepath.Ext(others[0]), "."),
+			current.kind,
+		)
+		return r
+	}
+	for _, other := range others {
+		if err := os.Remove(other); err != nil {
+			r.Message = fmt.Sprintf("remove snapshot: %v", err)
+			return r
+		}
+	}
+
+	recorded, err := os.ReadFile(path)
+	switch {
+	case err == nil && !s.update:
+	case err == nil || os.IsNotExist(err):
+		verb := "recorded"
+		if err == nil {
+			verb = "updated"
+		}
+		if err := s.write(path, current.data); err != nil {
+			r.Message = err.Error()
+			return r
+		}
+		r.Passed = true
+		r.Message = fmt.Sprintf("%s snapshot %s", verb, path)
+		return r
+	default:
+		r.Message = fmt.Sprintf("read snapshot: %v", err)
+		return r
+	}
+
+	var summary string
+	var diff []byte
+	diffExt := ".diff"
+	switch current.kind {
+	case snapshotJSON:
+		summary, diff, err = diffJSONSnapshot(recorded, current, a.Ignore)
+	case snapshotImage:
+		summary, diff, err = diffImageSnapshot(recorded, current, a)
+		diffExt = ".diff.png"
+	default:
+		summary, diff = diffTextSnapshot(recorded, current.data)
+	}
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	if diff == nil {
+		r.Passed = true
+		r.Message = fmt.Sprintf("matches snapshot %s", path)
+		if summary != "" {
+			r.Message += " (" + summary + ")"
+		}
+		return r
+	}
+
+	diffPath := filepath.Join(s.artifactsDir, "snapshots", name+diffExt)
+	r.Message = fmt.Sprintf("does not match snapshot %s: %s", path, summary)
+	if err := s.write(diffPath, diff); err != nil {
+		r.Message += fmt.Sprintf(" (diff not written: %v)", err)
+		return r
+	}
+	s.mu.Lock()
+	s.artifacts["snapshot:"+name] = diffPath
+	s.mu.Unlock()
+	r.Message += "; diff: " + diffPath
+	return r
+}
+
+// otherSnapshots returns the recorded snapshots of name in dir
+// whose kind is not kind.
+func otherSnapshots(dir, name, kind string) ([]string, error) {
+	var others []string
+	for _, k := range []string{snapshotText, snapshotJSON, snapshotImage} {
+		if k == kind {
+			continue
+		}
+		path := filepath.Join(dir, name+".snap."+k)
+		_, err := os.Stat(path)
+		if err == nil {
+			others = append(others, path)
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read snapshot: %w", err)
+		}
+	}
+	return others, nil
+}
+
+// snapshotName turns a target or snapshot name into a file
+// name.
+func snapshotName(name string) string {
+	return strings.Trim(snapshotNameRe.ReplaceAllString(name, "_"), "_")
+}
+
+func (s *SnapshotStore) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create snapshot dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+// prepareSnapshot classifies value and renders its snapshot
+// form.
+func prepareSnapshot(value any) (snapshotValue, error) {
+	raw, ok, err := imageBytes(value)
+	if err != nil {
+		return snapshotValue{}, err
+	}
+	if ok {
+		img, _, err := image.Decode(bytes.NewReader(raw))
+		if err == nil {
+			var buf bytes.Buffer
+			if err := png.Encode(&buf, img); err != nil {
+				return snapshotValue{}, err
+			}
+			return snapshotValue{kind: snapshotImage, data: buf.Bytes(), img: img}, nil
+		}
+		if _, isPath := value.(string); isPath {
+			return snapshotValue{}, fmt.Errorf("decode image %v: %w", value, err)
+		}
+	}
+
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if s, ok := value.(string); ok {
+		decoded, err := decodeJSONString(s)
+		if _, isText := decoded.(string); err != nil || isText {
+			return snapshotValue{kind: snapshotText, data: []byte(s)}, nil
+		}
+		value = decoded
+	} else if kind, _ := classify(value); kind != kindOther {
+		return snapshotValue{kind: snapshotText, data: []byte(fmt.Sprint(value))}, nil
+	}
+
+	doc, err := normalizeJSON(value)
+	if err != nil {
+		return snapshotValue{}, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return snapshotValue{}, err
+	}
+	return snapshotValue{kind: snapshotJSON, data: append(data, '\n'), doc: doc}, nil
+}
+
+// imageBytes returns the image data of value: []byte holding a
+// known image format, or the contents of an image file path. A
+// path with an image extension that cannot be read is an error
+// rather than text, so a missing screenshot never matches.
+func imageBytes(value any) ([]byte, bool, error) {
+	switch v := value.(type) {
+	case []byte:
+		if _, _, err := image.DecodeConfig(bytes.NewReader(v)); err == nil {
+			return v, true, nil
+		}
+	case string:
+		switch strings.ToLower(filepath.Ext(v)) {
+		case ".png", ".jpg", ".jpeg", ".gif":
+			data, err := os.ReadFile(v)
+			if err != nil {
+				return nil, false, fmt.Errorf("read image: %w", err)
+			}
+			return data, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// diffTextSnapshot returns a line diff of two texts, or a nil
+// diff when they are equal.
+func diffTextSnapshot(recorded, current []byte) (string, []byte) {
+	if bytes.Equal(recorded, current) {
+		return "", nil
+	}
+	old := strings.Split(string(recorded), "\n")
+	cur := strings.Split(string(current), "\n")
+	lines := diffLines(old, cur)
+
+	var buf bytes.Buffer
+	buf.WriteString("--- snapshot\n+++ actual\n")
+	changed := 0
+	for _, l := range lines {
+		if l[0] != ' ' {
+			changed++
+		}
+		buf.WriteString(l)
+		buf.WriteByte('\n')
+	}
+	return fmt.Sprintf("%d line(s) differ", changed), buf.Bytes()
+}
+
+// diffLines returns old and cur as diff lines prefixed with
+// ' ', '-' or '+', using a longest common subsequence.
+func diffLines(old, cur []string) []string {
+	if len(old)*len(cur) > maxLineDiffCells {
+		return positionalDiff(old, cur)
+	}
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(cur)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(cur) - 1; j >= 0; j-- {
+			if old[i] == cur[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(old) && j < len(cur) {
+		switch {
+		case old[i] == cur[j]:
+			out = append(out, " "+old[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+old[i])
+			i++
+		default:
+			out = append(out, "+"+cur[j])
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		out = append(out, "-"+old[i])
+	}
+	for ; j < len(cur); j++ {
+		out = append(out, "+"+cur[j])
+	}
+	return out
+}
+
+func positionalDiff(old, cur []string) []string {
+	var out []string
+	for i := 0; i < max(len(old), len(cur)); i++ {
+		switch {
+		case i < len(old) && i < len(cur) && old[i] == cur[i]:
+			out = append(out, " "+old[i])
+		default:
+			if i < len(old) {
+				out = append(out, "-"+old[i])
+			}
+			if i < len(cur) {
+				out = append(out, "+"+cur[i])
+			}
+		}
+	}
+	return out
+}
+
+// diffJSONSnapshot compares JSON documents, skipping ignored
+// paths, and returns one diff line per difference.
+func diffJSONSnapshot(
+	recorded []byte,
+	current snapshotValue,
+	ignore []string,
+) (string, []byte, error) {
+	var old any
+	if err := json.Unmarshal(recorded, &old); err != nil {
+		return "", nil, fmt.Errorf("recorded snapshot is not valid JSON: %w", err)
+	}
+	skip := make(map[string]bool)
+	for _, pattern := range ignore {
+		p, err := ParsePath(pattern)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid ignore path: %w", err)
+		}
+		for _, doc := range []any{old, current.doc} {
+			for _, m := range p.Resolve(doc) {
+				if m.Err == nil {
+					skip[m.Path] = true
+				}
+			}
+		}
+	}
+
+	var diffs []Violation
+	diffJSON(old, current.doc, "", skip, &diffs)
+	if len(diffs) == 0 {
+		if len(skip) > 0 {
+			return fmt.Sprintf("%d path(s) ignored", len(skip)), nil, nil
+		}
+		return "", nil, nil
+	}
+	var buf bytes.Buffer
+	for _, d := range diffs {
+		path := d.Path
+		if path == "" {
+			path = "(root)"
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", path, d.Message)
+	}
+	summary := fmt.Sprintf("%d difference(s), first at %s", len(diffs), diffs[0].String())
+	return summary, buf.Bytes(), nil
+}
+
+func diffJSON(old, cur any, at string, skip map[string]bool, out *[]Violation) {
+	if skip[at] && at != "" {
+		return
+	}
+	switch o := old.(type) {
+	case map[string]any:
+		c, ok := cur.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := sortedKeys(o)
+		for k := range c {
+			if _, seen := o[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			field := joinField(at, k)
+			ov, inOld := o[k]
+			cv, inCur := c[k]
+			switch {
+			case skip[field]:
+			case !inCur:
+				*out = append(*out, Violation{Path: field, Message: "removed (was " + formatJSON(ov) + ")"})
+			case !inOld:
+				*out = append(*out, Violation{Path: field, Message: "added " + formatJSON(cv)})
+			default:
+				diffJSON(ov, cv, field, skip, out)
+			}
+		}
+		return
+	case []any:
+		c, ok := cur.([]any)
+		if !ok {
+			break
+		}
+		if len(o) != len(c) {
+			*out = append(*out, Violation{
+				Path:    at,
+				Message: fmt.Sprintf("length %d, was %d", len(c), len(o)),
+			})
+		}
+		for i := 0; i < min(len(o), len(c)); i++ {
+			diffJSON(o[i], c[i], fmt.Sprintf("%s[%d]", at, i), skip, out)
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, cur) {
+		*out = append(*out, Violation{
+			Path:    at,
+			Message: fmt.Sprintf("%s, was %s", formatJSON(cur), formatJSON(old)),
+		})
+	}
+}
+
+// diffImageSnapshot compares images pixel by pixel and returns
+// a PNG marking the differing pixels in red.
+func diffImageSnapshot(
+	recorded []byte,
+	current snapshotValue,
+	a Definition,
+) (string, []byte, error) {
+	old, _, err := image.Decode(bytes.NewReader(recorded))
+	if err != nil {
+		return "", nil, fmt.Errorf("recorded snapshot is not an image: %w", err)
+	}
+	channel := 0.0
+	if a.Tolerance != nil {
+		kind, v := classify(a.Tolerance)
+		f, _ := v.(float64)
+		if kind != kindNumber || f < 0 {
+			return "", nil, fmt.Errorf("invalid tolerance %v: want a channel difference 0-255", a.Tolerance)
+		}
+		channel = f
+	}
+	ob, cb := old.Bounds(), current.img.Bounds()
+	if ob.Dx() != cb.Dx() || ob.Dy() != cb.Dy() {
+		summary := fmt.Sprintf("size %dx%d, was %dx%d", cb.Dx(), cb.Dy(), ob.Dx(), ob.Dy())
+		return summary, current.data, nil
+	}
+
+	marked := image.NewRGBA(image.Rect(0, 0, cb.Dx(), cb.Dy()))
+	differing := 0
+	for y := 0; y < cb.Dy(); y++ {
+		for x := 0; x < cb.Dx(); x++ {
+			oc := old.At(ob.Min.X+x, ob.Min.Y+y)
+			cc := current.img.At(cb.Min.X+x, cb.Min.Y+y)
+			if pixelDistance(oc, cc) > channel {
+				differing++
+				marked.Set(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			g := color.GrayModel.Convert(cc).(color.Gray)
+			marked.Set(x, y, color.RGBA{R: g.Y / 3, G: g.Y / 3, B: g.Y / 3, A: 255})
+		}
+	}
+	total := cb.Dx() * cb.Dy()
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(differing) / float64(total)
+	}
+	summary := fmt.Sprintf("%d of %d pixels differ (%.2f%%)", differing, total, ratio*100)
+	if ratio <= a.RelativeTolerance {
+		if differing == 0 {
+			summary = ""
+		}
+		return summary, nil, nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, marked); err != nil {
+		return "", nil, err
+	}
+	return summary, buf.Bytes(), nil
+}
+
+// pixelDistance returns the largest channel difference of two
+// colors on a 0-255 scale.
+func pixelDistance(a, b color.Color) float64 {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	d := 0.0
+	for _, pair := range [][2]uint32{{ar, br}, {ag, bg}, {ab, bb}, {aa, ba}} {
+		diff := float64(pair[0]) - float64(pair[1])
+		if diff < 0 {
+			diff = -diff
+		}
+		d = max(d, diff/257)
+	}
+	return d
+}
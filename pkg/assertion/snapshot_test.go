@@ -0,0 +1,208 @@
+package assertion
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSnapshotStore(t *testing.T, update bool) (*SnapshotStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	return NewSnapshotStore(
+		filepath.Join(dir, "snapshots"), filepath.Join(dir, "logs"), update,
+	), dir
+}
+
+func snapshot(
+	e *DefaultEngine,
+	s *SnapshotStore,
+	def Definition,
+	values map[string]any,
+) Result {
+	def.Type = TypeMatchesSnapshot
+	results := e.EvaluateAllWithSnapshots([]Definition{def}, values, s)
+	return results[0]
+}
+
+func testPNG(t *testing.T, w, h int, marked int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		c := color.RGBA{R: 10, G: 20, B: 30, A: 255}
+		if i < marked {
+			c = color.RGBA{R: 200, G: 20, B: 30, A: 255}
+		}
+		img.Set(i%w, i/w, c)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// ===== Snapshot tests =====
+
+func TestSnapshot_TextRecordAndDiff(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	def := Definition{Target: "build.log"}
+
+	r := snapshot(e, store, def, map[string]any{"build.log": "a\nb\nc"})
+	require.True(t, r.Passed, r.Message)
+	assert.Contains(t, r.Message, "recorded snapshot")
+	recorded := filepath.Join(dir, "snapshots", "build.log.snap.txt")
+	assert.FileExists(t, recorded)
+
+	r = snapshot(e, store, def, map[string]any{"build.log": "a\nb\nc"})
+	assert.True(t, r.Passed, r.Message)
+	assert.Empty(t, store.Artifacts())
+
+	r = snapshot(e, store, def, map[string]any{"build.log": "a\nB\nc\nd"})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "3 line(s) differ")
+	diffPath := filepath.Join(dir, "logs", "snapshots", "build.log.diff")
+	assert.Equal(t, map[string]string{"snapshot:build.log": diffPath}, store.Artifacts())
+	diff, err := os.ReadFile(diffPath)
+	require.NoError(t, err)
+	assert.Equal(t, "--- snapshot\n+++ actual\n a\n-b\n+B\n c\n+d\n", string(diff))
+}
+
+func TestSnapshot_UpdateMode(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	def := Definition{Target: "out", Value: "greeting"}
+	snapshot(e, store, def, map[string]any{"out": "hello"})
+
+	updating := NewSnapshotStore(filepath.Join(dir, "snapshots"), filepath.Join(dir, "logs"), true)
+	r := snapshot(e, updating, def, map[string]any{"out": "goodbye"})
+	require.True(t, r.Passed)
+	assert.Contains(t, r.Message, "updated snapshot")
+
+	data, err := os.ReadFile(filepath.Join(dir, "snapshots", "greeting.snap.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "goodbye", string(data))
+	assert.True(t, snapshot(e, store, def, map[string]any{"out": "goodbye"}).Passed)
+}
+
+func TestSnapshot_JSONIgnoredPaths(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	def := Definition{Target: "resp", Ignore: []string{"items[*].created_at", "request_id"}}
+
+	first := `{"request_id": "a1", "items": [{"id": 1, "created_at": "t1"}]}`
+	require.True(t, snapshot(e, store, def, map[string]any{"resp": first}).Passed)
+	assert.FileExists(t, filepath.Join(dir, "snapshots", "resp.snap.json"))
+
+	second := map[string]any{
+		"request_id": "b2",
+		"items":      []any{map[string]any{"created_at": "t2", "id": 1}},
+	}
+	r := snapshot(e, store, def, map[string]any{"resp": second})
+	assert.True(t, r.Passed, r.Message)
+
+	third := `{"request_id": "c3", "items": [{"id": 2, "created_at": "t3"}], "extra": true}`
+	r = snapshot(e, store, def, map[string]any{"resp": third})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "2 difference(s), first at extra: added true")
+	diff, err := os.ReadFile(store.Artifacts()["snapshot:resp"])
+	require.NoError(t, err)
+	assert.Equal(t, "extra: added true\nitems[0].id: 2, was 1\n", string(diff))
+}
+
+func TestSnapshot_ImageThreshold(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	shot := filepath.Join(dir, "shot.png")
+	require.NoError(t, os.WriteFile(shot, testPNG(t, 10, 10, 0), 0o644))
+	def := Definition{Target: "screenshot", RelativeTolerance: 0.05}
+
+	require.True(t, snapshot(e, store, def, map[string]any{"screenshot": shot}).Passed)
+	assert.FileExists(t, filepath.Join(dir, "snapshots", "screenshot.snap.png"))
+
+	r := snapshot(e, store, def, map[string]any{"screenshot": testPNG(t, 10, 10, 3)})
+	assert.True(t, r.Passed, r.Message)
+	assert.Contains(t, r.Message, "3 of 100 pixels differ")
+
+	r = snapshot(e, store, def, map[string]any{"screenshot": testPNG(t, 10, 10, 8)})
+	assert.False(t, r.Passed)
+	diffPath := store.Artifacts()["snapshot:screenshot"]
+	assert.Equal(t, ".png", filepath.Ext(diffPath))
+	data, err := os.ReadFile(diffPath)
+	require.NoError(t, err)
+	diffImg, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(diffImg.At(0, 0)))
+
+	loose := def
+	loose.Tolerance = 200
+	assert.True(t, snapshot(e, store, loose, map[string]any{"screenshot": testPNG(t, 10, 10, 8)}).Passed)
+
+	r = snapshot(e, store, def, map[string]any{"screenshot": testPNG(t, 5, 5, 0)})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "size 5x5, was 10x10")
+}
+
+func TestSnapshot_KindChangeFails(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	def := Definition{Target: "body"}
+	require.True(t, snapshot(e, store, def, map[string]any{"body": `{"ok": true}`}).Passed)
+
+	r := snapshot(e, store, def, map[string]any{"body": "<html>502 Bad Gateway</html>"})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "records a json value, got txt")
+	assert.NoFileExists(t, filepath.Join(dir, "snapshots", "body.snap.txt"))
+
+	// Updating replaces the snapshot of the old kind.
+	updating := NewSnapshotStore(filepath.Join(dir, "snapshots"), filepath.Join(dir, "logs"), true)
+	require.True(t, snapshot(e, updating, def, map[string]any{"body": "plain"}).Passed)
+	assert.NoFileExists(t, filepath.Join(dir, "snapshots", "body.snap.json"))
+	assert.True(t, snapshot(e, store, def, map[string]any{"body": "plain"}).Passed)
+}
+
+func TestSnapshot_MissingImageFails(t *testing.T) {
+	e := NewEngine()
+	store, dir := newTestSnapshotStore(t, false)
+	def := Definition{Target: "screenshot"}
+	shot := filepath.Join(dir, "missing.png")
+
+	r := snapshot(e, store, def, map[string]any{"screenshot": shot})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "read image")
+	assert.NoDirExists(t, filepath.Join(dir, "snapshots"))
+}
+
+func TestSnapshot_WithoutStore(t *testing.T) {
+	e := NewEngine()
+	assert.True(t, e.HasEvaluator(TypeMatchesSnapshot))
+	r := e.Evaluate(Definition{Type: TypeMatchesSnapshot, Target: "x"}, "v")
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "requires a snapshot store")
+}
+
+func TestSnapshot_InsideComposite(t *testing.T) {
+	e := NewEngine()
+	store, _ := newTestSnapshotStore(t, false)
+	results := e.EvaluateAllWithSnapshots([]Definition{{
+		Type: "all_of", Target: "out",
+		Assertions: []Definition{{Type: TypeMatchesSnapshot}, {Type: "not_empty"}},
+	}}, map[string]any{"out": "v1"}, store)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Passed, results[0].Message)
+}
+
+func TestDiffLines(t *testing.T) {
+	assert.Equal(t,
+		[]string{" a", "-b", "+x", " c"},
+		diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c"}))
+	assert.Equal(t,
+		[]string{"-a", "+b", " c"},
+		positionalDiff([]string{"a", "c"}, []string{"b", "c"}))
+}
@@ -0,0 +1,349 @@
+package assertion
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statistical assertion types over a sample series: a
+// []float64, a list of numbers, durations or sizes, or a JSON
+// array string. Statistics are computed in the unit of the
+// samples (milliseconds for durations, bytes for sizes) and
+// recorded in Result.Stats. Thresholds are coerced like the
+// comparison family, so p95_below accepts 200 or "200ms".
+//
+//   - p95_below, p99_below: the percentile is below value;
+//   - mean_within: the mean is within tolerance or
+//     relative_tolerance of value, or between the two bounds in
+//     values;
+//   - stddev_below: the sample standard deviation is below
+//     value;
+//   - no_regression_vs_baseline: the mean is at most value
+//     percent (e.g., 10 or "10%") above the baseline mean. The
+//     baseline is the samples in values or, without them, the
+//     statistics recorded in the snapshot store on the first
+//     run and rewritten in update mode.
+const (
+	TypeP95Below               = "p95_below"
+	TypeP99Below               = "p99_below"
+	TypeMeanWithin             = "mean_within"
+	TypeStddevBelow            = "stddev_below"
+	TypeNoRegressionVsBaseline = "no_regression_vs_baseline"
+)
+
+// SampleStats summarizes a sample series.
+type SampleStats struct {
+	Count  int
+	Min    float64
+	Max    float64
+	Mean   float64
+	Stddev float64
+	P50    float64
+	P90    float64
+	P95    float64
+	P99    float64
+}
+
+// ComputeStats computes the statistics of samples. Stddev is
+// the sample standard deviation (zero for fewer than two
+// samples) and percentiles interpolate linearly between the
+// closest ranks.
+func ComputeStats(samples []float64) SampleStats {
+	st := SampleStats{Count: len(samples)}
+	if len(samples) == 0 {
+		return st
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	st.Min = sorted[0]
+	st.Max = sorted[len(sorted)-1]
+	st.Mean = sum / float64(len(sorted))
+	if len(sorted) > 1 {
+		sq := 0.0
+		for _, v := range sorted {
+			sq += (v - st.Mean) * (v - st.Mean)
+		}
+		st.Stddev = math.Sqrt(sq / float64(len(sorted)-1))
+	}
+	st.P50 = Percentile(sorted, 50)
+	st.P90 = Percentile(sorted, 90)
+	st.P95 = Percentile(sorted, 95)
+	st.P99 = Percentile(sorted, 99)
+	return st
+}
+
+// Percentile returns the p-th percentile (0-100) of sorted
+// samples, interpolating linearly between the closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := math.Max(0, math.Min(p, 100)) / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
+
+// Map returns the statistics keyed by name ("count", "mean",
+// "p95", ...), the form recorded in Result.Stats.
+func (s SampleStats) Map() map[string]float64 {
+	return map[string]float64{
+		"count":  float64(s.Count),
+		"min":    s.Min,
+		"max":    s.Max,
+		"mean":   s.Mean,
+		"stddev": s.Stddev,
+		"p50":    s.P50,
+		"p90":    s.P90,
+		"p95":    s.P95,
+		"p99":    s.P99,
+	}
+}
+
+// statistic selects the checked statistic of a series and
+// compares it in the unit of the samples.
+type statistic struct {
+	label   string
+	value   func(SampleStats) float64
+	compare comparator
+}
+
+// statistics returns the threshold-based statistical
+// assertions.
+func statistics() map[string]statistic {
+	below := compareOrder("<", func(c int) bool { return c < 0 })
+	return map[string]statistic{
+		TypeP95Below: {"p95", func(s SampleStats) float64 { return s.P95 }, below},
+		TypeP99Below: {"p99", func(s SampleStats) float64 { return s.P99 }, below},
+		TypeMeanWithin: {"mean", func(s SampleStats) float64 { return s.Mean },
+			compareMeanWithin},
+		TypeStddevBelow: {"stddev", func(s SampleStats) float64 { return s.Stddev }, below},
+	}
+}
+
+// evaluator returns a resultEvaluator checking the statistic.
+func (st statistic) evaluator() resultEvaluator {
+	return func(a Definition, value any) Result {
+		samples, kind, err := samplesOf(value)
+		if err != nil {
+			return Result{Expected: a.Value, Actual: value, Message: err.Error()}
+		}
+		stats := ComputeStats(samples)
+		c := st.compare(a, unitValue(kind, st.value(stats)))
+		return Result{
+			Expected: c.expected,
+			Actual:   c.actual,
+			Passed:   c.passed,
+			Message: fmt.Sprintf(
+				"%s of %d samples: %s", st.label, stats.Count, c.message,
+			),
+			Stats: stats.Map(),
+		}
+	}
+}
+
+// compareMeanWithin checks the mean against two bounds, or
+// against value with a tolerance.
+func compareMeanWithin(a Definition, mean any) comparison {
+	if _, ok := a.Value.([]any); ok || len(a.Values) > 0 {
+		return compareBetween(a, mean)
+	}
+	return compareApprox(a, mean)
+}
+
+// evaluateRegression checks a no_regression_vs_baseline
+// assertion, reading or recording the baseline in s when the
+// definition has no baseline samples.
+func evaluateRegression(s *SnapshotStore, a Definition, value any) Result {
+	r := Result{Type: a.Type, Target: a.Target, Expected: a.Value, Actual: value}
+	samples, kind, err := samplesOf(value)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	stats := ComputeStats(samples)
+	r.Stats = stats.Map()
+	allowed, err := percentValue(a.Value)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+
+	var baseline float64
+	switch {
+	case len(a.Values) > 0:
+		base, baseKind, err := samplesOf(a.Values)
+		if err == nil && baseKind != kind && baseKind != kindNumber && kind != kindNumber {
+			err = fmt.Errorf("baseline and samples have different units")
+		}
+		if err != nil {
+			r.Message = fmt.Sprintf("baseline: %v", err)
+			return r
+		}
+		baseline = ComputeStats(base).Mean
+	case s == nil:
+		r.Message = "no_regression_vs_baseline requires baseline samples " +
+			"in values or a snapshot store"
+		return r
+	default:
+		path := filepath.Join(s.dir, snapshotName(a.Target)+".baseline.json")
+		recorded, mean, err := s.baseline(path, stats)
+		if err != nil {
+			r.Message = err.Error()
+			return r
+		}
+		if recorded != "" {
+			r.Passed = true
+			r.Message = fmt.Sprintf("%s baseline %s (mean %s)",
+				recorded, path, formatUnit(kind, stats.Mean))
+			return r
+		}
+		baseline = mean
+	}
+
+	change := 0.0
+	switch {
+	case baseline != 0:
+		change = (stats.Mean - baseline) / math.Abs(baseline) * 100
+	case stats.Mean > 0:
+		r.Message = fmt.Sprintf("baseline mean is 0, mean is %s",
+			formatUnit(kind, stats.Mean))
+		return r
+	}
+	r.Stats["baseline_mean"] = baseline
+	r.Stats["change_pct"] = change
+	r.Expected = allowed
+	r.Actual = change
+	r.Passed = change <= allowed
+	r.Message = fmt.Sprintf(
+		"mean %s vs baseline %s (%+.1f%%, allowed %+g%%)",
+		formatUnit(kind, stats.Mean), formatUnit(kind, baseline), change, allowed,
+	)
+	return r
+}
+
+// baseline returns the mean recorded at path. A missing
+// baseline, or any baseline in update mode, is (re)written
+// from stats and reported as "recorded" or "updated".
+func (s *SnapshotStore) baseline(path string, stats SampleStats) (string, float64, error) {
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil && !s.update:
+		var recorded map[string]float64
+		if err := json.Unmarshal(data, &recorded); err != nil {
+			return "", 0, fmt.Errorf("read baseline %s: %w", path, err)
+		}
+		mean, ok := recorded["mean"]
+		if !ok {
+			return "", 0, fmt.Errorf("baseline %s has no mean", path)
+		}
+		return "", mean, nil
+	case err == nil || os.IsNotExist(err):
+		verb := "recorded"
+		if err == nil {
+			verb = "updated"
+		}
+		out, _ := json.MarshalIndent(stats.Map(), "", "  ")
+		if err := s.write(path, append(out, '\n')); err != nil {
+			return "", 0, err
+		}
+		return verb, 0, nil
+	default:
+		return "", 0, fmt.Errorf("read baseline: %w", err)
+	}
+}
+
+// samplesOf converts a sample series to numbers in a common
+// unit. A single value is a series of one sample.
+func samplesOf(v any) ([]float64, valueKind, error) {
+	switch x := v.(type) {
+	case []float64:
+		if len(x) == 0 {
+			return nil, kindNumber, fmt.Errorf("no samples")
+		}
+		return x, kindNumber, nil
+	case json.RawMessage:
+		v = string(x)
+	case []byte:
+		v = string(x)
+	}
+	if s, ok := v.(string); ok && strings.HasPrefix(strings.TrimSpace(s), "[") {
+		var list []any
+		if err := json.Unmarshal([]byte(s), &list); err != nil {
+			return nil, kindOther, fmt.Errorf("invalid sample list: %v", err)
+		}
+		v = list
+	}
+
+	items := []any{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		items = make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+	if len(items) == 0 {
+		return nil, kindNumber, fmt.Errorf("no samples")
+	}
+
+	kind := kindNumber
+	out := make([]float64, len(items))
+	for i, item := range items {
+		k, n := classify(item)
+		if !isNumericKind(k) {
+			return nil, kindOther, fmt.Errorf(
+				"sample %d is not numeric: %s", i, describeValue(item),
+			)
+		}
+		if k != kindNumber {
+			if kind != kindNumber && kind != k {
+				return nil, kindOther, fmt.Errorf("samples mix units")
+			}
+			kind = k
+		}
+		out[i] = n.(float64)
+	}
+	return out, kind, nil
+}
+
+// unitValue renders a statistic so that classify restores its
+// unit.
+func unitValue(kind valueKind, f float64) any {
+	switch kind {
+	case kindDuration:
+		return time.Duration(f * float64(time.Millisecond))
+	case kindSize:
+		return strconv.FormatFloat(f, 'f', -1, 64) + "B"
+	}
+	return f
+}
+
+// percentValue parses a percentage such as 10, 2.5 or "10%".
+func percentValue(v any) (float64, error) {
+	if s, ok := v.(string); ok {
+		v = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	}
+	if k, n := classify(v); k == kindNumber {
+		return n.(float64), nil
+	}
+	return 0, fmt.Errorf(
+		"no_regression_vs_baseline requires the allowed regression "+
+			"percentage in value, got %s", describeValue(v),
+	)
+}
@@ -0,0 +1,192 @@
+package assertion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Sample statistics tests =====
+
+func TestComputeStats(t *testing.T) {
+	st := ComputeStats([]float64{4, 1, 3, 2, 5})
+	assert.Equal(t, 5, st.Count)
+	assert.Equal(t, float64(1), st.Min)
+	assert.Equal(t, float64(5), st.Max)
+	assert.Equal(t, float64(3), st.Mean)
+	assert.InDelta(t, 1.5811, st.Stddev, 1e-4)
+	assert.Equal(t, float64(3), st.P50)
+	assert.InDelta(t, 4.6, st.P90, 1e-9)
+	assert.InDelta(t, 4.8, st.P95, 1e-9)
+	assert.InDelta(t, 4.96, st.P99, 1e-9)
+
+	one := ComputeStats([]float64{7})
+	assert.Equal(t, float64(7), one.P99)
+	assert.Zero(t, one.Stddev)
+	assert.Equal(t, SampleStats{}, ComputeStats(nil))
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+	assert.Equal(t, float64(10), Percentile(sorted, 0))
+	assert.Equal(t, float64(25), Percentile(sorted, 50))
+	assert.Equal(t, float64(40), Percentile(sorted, 100))
+	assert.Equal(t, float64(40), Percentile(sorted, 150))
+	assert.Zero(t, Percentile(nil, 50))
+}
+
+func TestSamplesOf(t *testing.T) {
+	samples, kind, err := samplesOf([]any{"10ms", 20, time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, kindDuration, kind)
+	assert.Equal(t, []float64{10, 20, 1000}, samples)
+
+	samples, kind, err = samplesOf(`[1, 2.5]`)
+	require.NoError(t, err)
+	assert.Equal(t, kindNumber, kind)
+	assert.Equal(t, []float64{1, 2.5}, samples)
+
+	samples, _, err = samplesOf([]int{3, 4})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{3, 4}, samples)
+
+	samples, _, err = samplesOf(12)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{12}, samples)
+
+	for want, v := range map[string]any{
+		"no samples":     []any{},
+		"is not numeric": []any{1, "slow"},
+		"mix units":      []any{"1ms", "1KB"},
+		"invalid sample": "[1,",
+	} {
+		_, _, err := samplesOf(v)
+		require.Error(t, err, want)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+// ===== Statistical assertion tests =====
+
+func TestStatisticalAssertions(t *testing.T) {
+	e := NewEngine()
+	latencies := []float64{12, 15, 11, 14, 90, 13, 12, 16, 15, 14}
+	tests := []struct {
+		name   string
+		def    Definition
+		passed bool
+	}{
+		{"p95 below", Definition{Type: "p95_below", Value: 100}, true},
+		{"p95 above", Definition{Type: "p95_below", Value: 50}, false},
+		{"p95 duration threshold", Definition{Type: "p95_below", Value: "100ms"}, true},
+		{"p99 above", Definition{Type: "p99_below", Value: 80}, false},
+		{"mean within tolerance", Definition{Type: "mean_within", Value: 20, Tolerance: 2}, true},
+		{"mean outside relative", Definition{Type: "mean_within", Value: 15, RelativeTolerance: 0.1}, false},
+		{"mean between", Definition{Type: "mean_within", Values: []any{"10ms", "25ms"}}, true},
+		{"stddev below", Definition{Type: "stddev_below", Value: 30}, true},
+		{"stddev above", Definition{Type: "stddev_below", Value: 10}, false},
+	}
+	for _, tt := range tests {
+		tt.def.Target = "latency_ms"
+		results := e.EvaluateAll([]Definition{tt.def}, map[string]any{
+			"latency_ms": latencies,
+		})
+		require.Len(t, results, 1, tt.name)
+		assert.Equal(t, tt.passed, results[0].Passed, "%s: %s", tt.name, results[0].Message)
+		assert.Equal(t, "latency_ms", results[0].Target, tt.name)
+		assert.Equal(t, float64(10), results[0].Stats["count"], tt.name)
+	}
+}
+
+func TestStatisticalAssertions_Result(t *testing.T) {
+	e := NewEngine()
+	r := e.Evaluate(Definition{
+		Type: "p95_below", Target: "latency", Value: "20ms",
+	}, []any{"10ms", "12ms", "30ms"})
+	assert.False(t, r.Passed)
+	assert.Equal(t, float64(20), r.Expected)
+	assert.InDelta(t, 28.2, r.Actual, 1e-9)
+	assert.Equal(t, "p95 of 3 samples: 28.2ms is not < 20ms", r.Message)
+	assert.InDelta(t, 28.2, r.Stats["p95"], 1e-9)
+	assert.Equal(t, float64(10), r.Stats["min"])
+
+	r = e.Evaluate(Definition{Type: "stddev_below", Value: 1}, "slow")
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "not numeric")
+	assert.Nil(t, r.Stats)
+
+	r = e.Evaluate(Definition{Type: "mean_within", Value: 1}, []float64{1})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "requires tolerance")
+}
+
+func TestNoRegressionVsBaseline_InlineBaseline(t *testing.T) {
+	e := NewEngine()
+	def := Definition{
+		Type: "no_regression_vs_baseline", Target: "parse_ms",
+		Value: "10%", Values: []any{100, 100, 100},
+	}
+
+	r := e.Evaluate(def, []float64{105, 108, 107})
+	assert.True(t, r.Passed, r.Message)
+	assert.Equal(t, "mean 106.66666666666667 vs baseline 100 (+6.7%, allowed +10%)", r.Message)
+	assert.Equal(t, float64(100), r.Stats["baseline_mean"])
+	assert.InDelta(t, 6.667, r.Stats["change_pct"], 1e-3)
+
+	r = e.Evaluate(def, []float64{120, 118})
+	assert.False(t, r.Passed)
+	assert.InDelta(t, 19.0, r.Actual, 1e-9)
+
+	def.Value = "lots"
+	r = e.Evaluate(def, []float64{1})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "allowed regression percentage")
+
+	r = e.Evaluate(Definition{
+		Type: "no_regression_vs_baseline", Target: "parse_ms", Value: 5,
+	}, []float64{1})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "requires baseline samples")
+}
+
+func TestNoRegressionVsBaseline_SnapshotBaseline(t *testing.T) {
+	e := NewEngine()
+	store, _ := newTestSnapshotStore(t, false)
+	defs := []Definition{{
+		Type: "no_regression_vs_baseline", Target: "parse_ms", Value: 10,
+	}}
+	run := func(s *SnapshotStore, samples []float64) Result {
+		results := e.EvaluateAllWithSnapshots(
+			defs, map[string]any{"parse_ms": samples}, s,
+		)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	r := run(store, []float64{100, 110})
+	assert.True(t, r.Passed)
+	assert.Contains(t, r.Message, "recorded baseline")
+	path := filepath.Join(store.Dir(), "parse_ms.baseline.json")
+	_, err := os.Stat(path)
+	require.NoError(t, err)
+
+	r = run(store, []float64{112, 114})
+	assert.True(t, r.Passed, r.Message)
+	assert.Equal(t, float64(105), r.Stats["baseline_mean"])
+
+	r = run(store, []float64{130, 140})
+	assert.False(t, r.Passed)
+	assert.Contains(t, r.Message, "+28.6%")
+
+	update := NewSnapshotStore(store.Dir(), "", true)
+	r = run(update, []float64{130, 140})
+	assert.True(t, r.Passed)
+	assert.Contains(t, r.Message, "updated baseline")
+
+	r = run(store, []float64{130, 140})
+	assert.True(t, r.Passed, r.Message)
+}
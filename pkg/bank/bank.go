@@ -1,16 +1,23 @@
 package bank
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
 	"gopkg.in/yaml.v3"
 
+	"digital.vasic.challenges/pkg/assertion"
 	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/stepscript"
 )
 
 // Bank manages collections of challenge definitions loaded from files.
@@ -18,12 +25,18 @@ type Bank struct {
 	mu          sync.RWMutex
 	definitions map[challenge.ID]*challenge.Definition
 	sources     []string
+	loaded      map[string]bool
+	origin      map[challenge.ID]string
+	profiles    map[string]Profile
 }
 
 // New creates a new empty Bank.
 func New() *Bank {
 	return &Bank{
 		definitions: make(map[challenge.ID]*challenge.Definition),
+		loaded:      make(map[string]bool),
+		origin:      make(map[challenge.ID]string),
+		profiles:    make(map[string]Profile),
 	}
 }
 
@@ -37,7 +50,63 @@ func New() *Bank {
 // banks without maintaining a second parser path — see HelixQA's
 // banks/*.yaml files and the CLAUDE.md note at pkg/bank which has
 // documented "load definitions from JSON/YAML" as a requirement.
+//
+// Files listed under "include" are loaded first, resolved relative
+// to the including file; a file already loaded is skipped and an
+// include cycle is an error. Defining an ID that is already loaded
+// is a conflict unless the definition is listed under "override",
+// which in turn must replace an existing ID.
+//
+// Loading is all or nothing: on error the bank is left as it was
+// before the call, including the files the failed file included.
 func (b *Bank) LoadFile(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	restore := b.saveLocked()
+	if err := b.loadFileLocked(path, nil); err != nil {
+		restore()
+		return err
+	}
+	return nil
+}
+
+// saveLocked returns a function restoring the loaded state of
+// the bank to what it is now. Definitions are replaced rather than
+// modified, so the maps are copied shallowly.
+func (b *Bank) saveLocked() func() {
+	definitions := maps.Clone(b.definitions)
+	sources := slices.Clone(b.sources)
+	loaded := maps.Clone(b.loaded)
+	origin := maps.Clone(b.origin)
+	profiles := maps.Clone(b.profiles)
+	return func() {
+		b.definitions = definitions
+		b.sources = sources
+		b.loaded = loaded
+		b.origin = origin
+		b.profiles = profiles
+	}
+}
+
+// loadFileLocked loads path and its includes. stack holds the
+// absolute paths of the files including it.
+func (b *Bank) loadFileLocked(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve bank file %s: %w", path, err)
+	}
+	for i, p := range stack {
+		if p == abs {
+			cycle := append(append([]string(nil), stack[i:]...), abs)
+			return fmt.Errorf(
+				"include cycle: %s", strings.Join(cycle, " -> "),
+			)
+		}
+	}
+	if b.loaded[abs] {
+		return nil
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return fmt.Errorf("read bank file %s: %w", path, err)
@@ -48,20 +117,126 @@ func (b *Bank) LoadFile(path string) error {
 		return err
 	}
 
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	// Schema and example files are relative to the bank file.
+	for i := range file.Challenges {
+		assertion.ResolveFileReferences(
+			file.Challenges[i].Assertions, filepath.Dir(abs),
+		)
+	}
+	for i := range file.Override {
+		assertion.ResolveFileReferences(
+			file.Override[i].Assertions, filepath.Dir(abs),
+		)
+	}
 
+	stack = append(stack, abs)
+	for _, pattern := range file.Include {
+		matches, err := resolveInclude(path, pattern)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			if err := b.loadFileLocked(m, stack); err != nil {
+				return fmt.Errorf("include from %s: %w", path, err)
+			}
+		}
+	}
+
+	// Validate the whole file before registering anything, so a
+	// bad definition leaves the bank as it was.
+	defined := make(map[challenge.ID]bool, len(file.Challenges))
 	for i := range file.Challenges {
 		def := &file.Challenges[i]
 		if def.ID == "" {
 			return fmt.Errorf("challenge at index %d in %s has no ID", i, path)
 		}
+		first, dup := b.origin[def.ID]
+		if defined[def.ID] {
+			dup, first = true, path
+		}
+		if dup {
+			return fmt.Errorf(
+				"challenge %s in %s conflicts with the definition in %s; "+
+					"list it under override to replace it",
+				def.ID, path, first,
+			)
+		}
+		defined[def.ID] = true
+	}
+	for i := range file.Override {
+		def := &file.Override[i]
+		if _, ok := b.origin[def.ID]; !ok && !defined[def.ID] {
+			return fmt.Errorf(
+				"override %q in %s: no such challenge is loaded",
+				def.ID, path,
+			)
+		}
+	}
+	profiles := make(map[string]Profile, len(file.Profiles))
+	for name, p := range file.Profiles {
+		// Merge into a copy: mergeProfile fills the maps of dst.
+		var merged Profile
+		_ = mergeProfile(name, &merged, b.profiles[name])
+		if err := mergeProfile(name, &merged, p); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		profiles[name] = merged
+	}
+
+	for i := range file.Challenges {
+		def := &file.Challenges[i]
 		b.definitions[def.ID] = def
+		b.origin[def.ID] = path
 	}
+	for i := range file.Override {
+		def := &file.Override[i]
+		b.definitions[def.ID] = def
+		b.origin[def.ID] = path
+	}
+	for name, p := range profiles {
+		b.profiles[name] = p
+	}
+
+	b.loaded[abs] = true
 	b.sources = append(b.sources, path)
 	return nil
 }
 
+// resolveInclude expands an include pattern relative to the
+// including file. Directories are loaded recursively. A pattern
+// matching nothing is an error.
+func resolveInclude(from, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(from), pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("include %q in %s: %w", pattern, from, err)
+	}
+	var files []string
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", m, err)
+		}
+		if !info.IsDir() {
+			files = append(files, m)
+			continue
+		}
+		tree, err := treeFiles(m, LoadOptions{})
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, tree...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf(
+			"include %q in %s matches no bank files", pattern, from,
+		)
+	}
+	return files, nil
+}
+
 // parseBankFile routes a raw file byte slice through the correct
 // parser based on the filename extension. Centralised so LoadFile and
 // any future loader variants share the same format detection.
@@ -73,8 +248,13 @@ func (b *Bank) LoadFile(path string) error {
 // BankFile and challenge.Definition without duplicating them as yaml
 // tags — the single source of truth for field naming stays in one
 // place and snake_case tags like `estimated_duration` keep working.
+//
+// Assertions may be written as compact lines (see
+// assertion.ParseDefinition); they are parsed into typed
+// definitions here.
 func parseBankFile(path string, data []byte) (*BankFile, error) {
 	var file BankFile
+	jsonBytes := data
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".yaml", ".yml":
@@ -83,26 +263,153 @@ func parseBankFile(path string, data []byte) (*BankFile, error) {
 			return nil, fmt.Errorf("parse bank file %s: %w", path, err)
 		}
 		normalised := normaliseYAMLValue(raw)
-		jsonBytes, err := json.Marshal(normalised)
+		var err error
+		jsonBytes, err = json.Marshal(normalised)
 		if err != nil {
 			return nil, fmt.Errorf("marshal bank file %s: %w", path, err)
 		}
-		if err := json.Unmarshal(jsonBytes, &file); err != nil {
-			return nil, fmt.Errorf("parse bank file %s: %w", path, err)
-		}
-	default:
-		if err := json.Unmarshal(data, &file); err != nil {
-			return nil, fmt.Errorf("parse bank file %s: %w", path, err)
+	}
+	lines, expanded, err := assertionLines(jsonBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse bank file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(expanded, &file); err != nil {
+		if len(lines) > 0 {
+			// Offsets refer to the rewritten document.
+			return nil, fmt.Errorf("parse bank file %s: %v", path, err)
 		}
+		return nil, fmt.Errorf("parse bank file %s: %w", path, err)
+	}
+	for _, l := range lines {
+		l.apply(&file)
 	}
 	// HelixQA banks use "test_cases" as the root key — fold those
 	// into Challenges so every caller only ever reads one slice.
 	if len(file.Challenges) == 0 && len(file.TestCases) > 0 {
 		file.Challenges = file.TestCases
 	}
+	// Step scripts (root "steps" list) become a single definition
+	// executed by the stepscript action backend.
+	if len(file.Challenges) == 0 && stepscript.IsScript(jsonBytes) {
+		script, err := stepscript.Parse(jsonBytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse bank file %s: %w", path, err)
+		}
+		def, err := script.ToDefinition()
+		if err != nil {
+			return nil, fmt.Errorf("parse bank file %s: %w", path, err)
+		}
+		file.Challenges = []challenge.Definition{*def}
+	}
 	return &file, nil
 }
 
+// assertionLine is a compact assertion line of a bank file,
+// located by definition list ("challenges", "test_cases" or
+// "override"), definition index and the indices of the
+// assertion within nested assertion lists.
+type assertionLine struct {
+	list  string
+	index int
+	path  []int
+	field string
+	def   assertion.Definition
+}
+
+// assertionLineError reports an invalid compact assertion line.
+type assertionLineError struct {
+	assertionLine
+	err error
+}
+
+func (e *assertionLineError) Error() string {
+	return fmt.Sprintf("%s: %v", e.field, e.err)
+}
+
+func (e *assertionLineError) Unwrap() error { return e.err }
+
+// assertionLines parses the compact assertion lines of a JSON
+// bank document and returns them with the document rewritten
+// to hold empty assertion objects in their place. A document
+// without lines, or one that is not a JSON object, is returned
+// unchanged for json.Unmarshal to decode or reject.
+func assertionLines(data []byte) ([]assertionLine, []byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root map[string]interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, data, nil
+	}
+
+	var lines []assertionLine
+	for _, list := range []string{"challenges", "test_cases", "override"} {
+		items, _ := root[list].([]interface{})
+		for i, item := range items {
+			def, _ := item.(map[string]interface{})
+			at := assertionLine{
+				list: list, index: i,
+				field: fmt.Sprintf("%s[%d].assertions", list, i),
+			}
+			if err := collectAssertionLines(def, at, &lines); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil, data, nil
+	}
+	out, err := json.Marshal(root)
+	return lines, out, err
+}
+
+// collectAssertionLines parses the string items of the
+// "assertions" list of obj, recursing into sub-assertions. at
+// locates the list.
+func collectAssertionLines(
+	obj map[string]interface{},
+	at assertionLine,
+	lines *[]assertionLine,
+) error {
+	items, _ := obj["assertions"].([]interface{})
+	for j, item := range items {
+		line := at
+		line.path = append(append([]int(nil), at.path...), j)
+		line.field = fmt.Sprintf("%s[%d]", at.field, j)
+		switch x := item.(type) {
+		case string:
+			def, err := assertion.ParseDefinition(x)
+			if err != nil {
+				return &assertionLineError{assertionLine: line, err: err}
+			}
+			line.def = def
+			items[j] = map[string]interface{}{}
+			*lines = append(*lines, line)
+		case map[string]interface{}:
+			line.field += ".assertions"
+			if err := collectAssertionLines(x, line, lines); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply stores the parsed definition in file.
+func (l assertionLine) apply(file *BankFile) {
+	defs := file.Challenges
+	switch l.list {
+	case "test_cases":
+		defs = file.TestCases
+	case "override":
+		defs = file.Override
+	}
+	list := defs[l.index].Assertions
+	for _, i := range l.path[:len(l.path)-1] {
+		list = list[i].Assertions
+	}
+	list[l.path[len(l.path)-1]] = assertion.ToChallengeDef(l.def)
+}
+
 // normaliseYAMLValue recursively walks a value produced by
 // yaml.Unmarshal and converts every map[interface{}]interface{} into
 // map[string]interface{}. yaml.v3 can return interface-keyed maps
@@ -137,8 +444,7 @@ func normaliseYAMLValue(v interface{}) interface{} {
 
 // LoadDir loads all .json, .yaml, and .yml files from a directory.
 // Other extensions are ignored. Subdirectories are not recursed into;
-// callers that need recursive loading should compose their own
-// walker on top of LoadFile.
+// use LoadTree for nested bank trees.
 func (b *Bank) LoadDir(dir string) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -159,6 +465,128 @@ func (b *Bank) LoadDir(dir string) error {
 	return nil
 }
 
+// LoadOptions configures LoadTree.
+type LoadOptions struct {
+	// Include, when non-empty, restricts loading to files
+	// matching at least one glob pattern.
+	Include []string
+
+	// Exclude skips files and directories matching any glob
+	// pattern.
+	Exclude []string
+
+	// Profile, when set, is applied after loading.
+	Profile string
+}
+
+// LoadTree loads every .json, .yaml and .yml file below root,
+// recursing into subdirectories in lexical order. Patterns are
+// matched against the slash-separated path relative to root and
+// support "**" for any number of directories; a pattern without
+// a "/" matches the base name at any depth.
+func (b *Bank) LoadTree(root string, opts LoadOptions) error {
+	files, err := treeFiles(root, opts)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := b.LoadFile(f); err != nil {
+			return err
+		}
+	}
+	if opts.Profile != "" {
+		return b.ApplyProfile(opts.Profile)
+	}
+	return nil
+}
+
+// treeFiles lists the bank files below root selected by opts.
+func treeFiles(root string, opts LoadOptions) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(
+		p string, d fs.DirEntry, err error,
+	) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if matchAny(opts.Exclude, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !isBankFile(p) {
+			return nil
+		}
+		if len(opts.Include) > 0 && !matchAny(opts.Include, rel) {
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk bank tree %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// isBankFile reports whether path has a bank file extension.
+func isBankFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".json" || ext == ".yaml" || ext == ".yml"
+}
+
+// matchAny reports whether rel matches one of patterns.
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated relative path against a
+// pattern in which "**" matches any number of path segments.
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(
+		strings.Split(pattern, "/"), strings.Split(rel, "/"),
+	)
+}
+
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
+
 // Get retrieves a challenge definition by ID.
 func (b *Bank) Get(id challenge.ID) (*challenge.Definition, bool) {
 	b.mu.RLock()
@@ -6,7 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"digital.vasic.challenges/pkg/assertion"
 	"digital.vasic.challenges/pkg/challenge"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -153,3 +155,275 @@ func TestBank_LoadDir_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "read bank directory")
 }
+
+// ===== Tree, include and override tests =====
+
+// writeTree writes files (relative path to content) below dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func bankJSON(ids ...string) string {
+	file := BankFile{Version: "1.0"}
+	for _, id := range ids {
+		file.Challenges = append(file.Challenges, challenge.Definition{
+			ID: challenge.ID(id), Name: id,
+		})
+	}
+	data, _ := json.Marshal(file)
+	return string(data)
+}
+
+func TestBank_LoadTree(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"root.json":              bankJSON("root"),
+		"mobile/login.yaml":      "version: \"1\"\nchallenges: [{id: login, name: L}]\n",
+		"mobile/deep/pay.json":   bankJSON("pay"),
+		"mobile/deep/notes.txt":  "ignored",
+		"drafts/wip.json":        bankJSON("wip"),
+		"mobile/deep/skip.json":  bankJSON("skip"),
+		"mobile/deep/other.json": bankJSON("other"),
+	})
+
+	b := New()
+	require.NoError(t, b.LoadTree(dir, LoadOptions{}))
+	assert.Equal(t, 6, b.Count())
+
+	b = New()
+	require.NoError(t, b.LoadTree(dir, LoadOptions{
+		Include: []string{"mobile/**/*.json", "*.yaml"},
+		Exclude: []string{"drafts", "skip.json"},
+	}))
+	assert.Equal(t, 3, b.Count())
+	for _, id := range []challenge.ID{"login", "pay", "other"} {
+		_, ok := b.Get(id)
+		assert.True(t, ok, id)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"*.json", "a/b/c.json", true},
+		{"*.json", "a/b/c.yaml", false},
+		{"a/*.json", "a/c.json", true},
+		{"a/*.json", "a/b/c.json", false},
+		{"a/**/*.json", "a/c.json", true},
+		{"a/**/*.json", "a/b/d/c.json", true},
+		{"**/deep", "x/y/deep", true},
+		{"b/**", "a/b/c.json", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.rel),
+			"%s vs %s", tt.pattern, tt.rel)
+	}
+}
+
+func TestBank_LoadFile_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"main.yaml": `version: "1"
+include: [shared/*.json, extra]
+challenges:
+  - id: main
+    name: Main
+    dependencies: [base]
+`,
+		"shared/base.json":   bankJSON("base"),
+		"shared/helper.json": bankJSON("helper"),
+		"extra/x/more.json":  bankJSON("more"),
+	})
+
+	b := New()
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "main.yaml")))
+	assert.Equal(t, 4, b.Count())
+	sources := b.Sources()
+	require.Len(t, sources, 4)
+	assert.Contains(t, sources[3], "main.yaml")
+
+	// Files pulled in by an include are not loaded twice.
+	require.NoError(t, b.LoadDir(filepath.Join(dir, "shared")))
+	assert.Len(t, b.Sources(), 4)
+}
+
+func TestBank_LoadFile_IncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.yaml":     "version: \"1\"\ninclude: [b.yaml]\nchallenges: [{id: a, name: A}]\n",
+		"b.yaml":     "version: \"1\"\ninclude: [a.yaml]\nchallenges: [{id: b, name: B}]\n",
+		"empty.yaml": "version: \"1\"\ninclude: [nothing/*.json]\n",
+	})
+
+	err := New().LoadFile(filepath.Join(dir, "a.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "include cycle")
+
+	err = New().LoadFile(filepath.Join(dir, "empty.yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "matches no bank files")
+}
+
+func TestBank_LoadFile_ConflictAndOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"a.json": bankJSON("shared"),
+		"b.json": bankJSON("shared"),
+		"c.json": `{"version": "1", "override": [
+			{"id": "shared", "name": "Replaced"}]}`,
+		"d.json": `{"version": "1", "override": [
+			{"id": "ghost", "name": "Ghost"}]}`,
+	})
+
+	b := New()
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "a.json")))
+	err := b.LoadFile(filepath.Join(dir, "b.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "challenge shared in")
+	assert.Contains(t, err.Error(), "a.json")
+	assert.Contains(t, err.Error(), "override")
+
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "c.json")))
+	def, _ := b.Get("shared")
+	assert.Equal(t, "Replaced", def.Name)
+
+	err = b.LoadFile(filepath.Join(dir, "d.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `override "ghost"`)
+}
+
+func TestBank_LoadFile_AllOrNothing(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"base.json": `{"version": "1", "challenges": [{"id": "base", "name": "Base"}],
+			"profiles": {"ci": {"environment": {"MODE": "ci"}}}}`,
+		"conflict.json":  bankJSON("fresh", "base", "later"),
+		"duplicate.json": bankJSON("one", "one"),
+		"override.json": `{"version": "1",
+			"challenges": [{"id": "fresh", "name": "Fresh"}],
+			"override": [{"id": "base", "name": "Replaced"},
+				{"id": "ghost", "name": "Ghost"}]}`,
+		"profile.json": `{"version": "1",
+			"challenges": [{"id": "fresh", "name": "Fresh"}],
+			"profiles": {"ci": {"environment": {"EXTRA": "1", "MODE": "nightly"}}}}`,
+		"include.json": `{"version": "1", "include": ["extra/*.json"],
+			"challenges": [{"id": "base", "name": "Again"}]}`,
+		"extra/more.json": bankJSON("more"),
+	})
+
+	b := New()
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "base.json")))
+	for _, name := range []string{
+		"conflict.json", "duplicate.json", "override.json",
+		"profile.json", "include.json",
+	} {
+		require.Error(t, b.LoadFile(filepath.Join(dir, name)), name)
+
+		assert.Equal(t, 1, b.Count(), name)
+		def, _ := b.Get("base")
+		assert.Equal(t, "Base", def.Name, name)
+		assert.Len(t, b.Sources(), 1, name)
+		assert.Equal(t, map[string]string{"MODE": "ci"},
+			b.profiles["ci"].Environment, name)
+	}
+
+	// The include dropped with include.json loads on its own.
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "extra", "more.json")))
+	assert.Equal(t, 2, b.Count())
+}
+
+func TestBank_LoadTree_Profile(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"nested/bank.yaml": profileBank,
+	})
+
+	b := New()
+	require.NoError(t, b.LoadTree(dir, LoadOptions{Profile: "nightly"}))
+	def, _ := b.Get("logout")
+	assert.Equal(t, "full", def.Environment["DEPTH"])
+
+	err := New().LoadTree(dir, LoadOptions{Profile: "weekly"})
+	assert.Error(t, err)
+}
+
+func TestBank_LoadTree_NotFound(t *testing.T) {
+	err := New().LoadTree("/nonexistent/tree", LoadOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "walk bank tree")
+}
+
+func TestBank_LoadFile_CompactAssertions(t *testing.T) {
+	p := writeTempBank(t, "compact.yaml", `version: "1"
+challenges:
+  - id: compact
+    name: Compact
+    assertions:
+      - "latency | between(50ms, 250ms, severity=warning) | too slow"
+      - type: all_of
+        target: body
+        assertions:
+          - not_empty
+          - "contains('a:b')"
+override: []
+`)
+
+	b := New()
+	require.NoError(t, b.LoadFile(p))
+	def, ok := b.Get("compact")
+	require.True(t, ok)
+	require.Len(t, def.Assertions, 2)
+
+	a := def.Assertions[0]
+	assert.Equal(t, "between", a.Type)
+	assert.Equal(t, "latency", a.Target)
+	assert.Equal(t, []any{50 * time.Millisecond, 250 * time.Millisecond}, a.Values)
+	assert.Equal(t, "warning", a.Severity)
+	assert.Equal(t, "too slow", a.Message)
+
+	require.Len(t, def.Assertions[1].Assertions, 2)
+	assert.Equal(t, "not_empty", def.Assertions[1].Assertions[0].Type)
+	assert.Equal(t, "a:b", def.Assertions[1].Assertions[1].Value)
+}
+
+func TestBank_LoadFile_CompactAssertionError(t *testing.T) {
+	p := writeTempBank(t, "bad.json", `{"version": "1", "challenges": [
+  {"id": "a", "name": "A", "assertions": ["out | equals(1"]}
+]}`)
+
+	err := New().LoadFile(p)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "challenges[0].assertions[0]: invalid assertion")
+}
+
+func TestBank_LoadFile_RelativeSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"schemas/status.json": `{"type": "string", "enum": ["ok"]}`,
+		"bank.json": `{"version": "1", "challenges": [
+  {"id": "a", "name": "A", "assertions": [
+    {"type": "json_schema", "target": "status", "value": "schemas/status.json"}
+  ]}
+]}`,
+	})
+	// The schema resolves against the bank file, not the working
+	// directory.
+	t.Chdir(t.TempDir())
+
+	b := New()
+	require.NoError(t, b.LoadFile(filepath.Join(dir, "bank.json")))
+	def, ok := b.Get("a")
+	require.True(t, ok)
+	schema := def.Assertions[0]
+	assert.Equal(t, filepath.Join(dir, "schemas", "status.json"), schema.Value)
+
+	r := assertion.NewEngine().Evaluate(assertion.FromChallengeDef(schema), "ok")
+	assert.True(t, r.Passed, r.Message)
+}
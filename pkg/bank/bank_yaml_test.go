@@ -161,6 +161,31 @@ challenges:
 		"error should wrap the file path and match the JSON-path error shape, got: %v", err)
 }
 
+func TestBank_LoadFile_StepScriptYAML(t *testing.T) {
+	b := New()
+	require.NoError(t, b.LoadFile(filepath.Join(
+		"..", "..", "banks", "yole",
+		"file-browser-save-functionality.yaml",
+	)))
+	require.Equal(t, 1, b.Count())
+
+	def, ok := b.Get("file-browser-save-functionality")
+	require.True(t, ok)
+	assert.Equal(t, "script", def.Category)
+	assert.Equal(t, "10m", def.EstimatedDuration)
+	assert.Contains(t, string(def.Configuration), `"backend":"steps"`)
+}
+
+func TestBank_LoadFile_StepScriptInvalid(t *testing.T) {
+	p := writeTempBank(t, "script.yaml", `name: broken
+steps:
+  - name: no-type
+`)
+	err := New().LoadFile(p)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no type")
+}
+
 func TestNormaliseYAMLValue_InterfaceKeyedMap(t *testing.T) {
 	// yaml.v3 can produce map[interface{}]interface{} for nested
 	// maps with non-string-looking keys; we accept anything yaml
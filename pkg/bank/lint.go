@@ -0,0 +1,787 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/stepscript"
+)
+
+// Severity classifies a lint Issue.
+type Severity string
+
+const (
+	// SeverityError marks issues that break loading or running
+	// the bank.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks suspicious but tolerated content,
+	// such as fields the loader ignores.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found by a Linter. Line and Column are
+// 1-based and zero when the position is unknown.
+type Issue struct {
+	File        string       `json:"file"`
+	Line        int          `json:"line,omitempty"`
+	Column      int          `json:"column,omitempty"`
+	ChallengeID challenge.ID `json:"challenge_id,omitempty"`
+	Field       string       `json:"field,omitempty"`
+	Severity    Severity     `json:"severity"`
+	Message     string       `json:"message"`
+}
+
+// String formats the issue as "file:line:col: severity: [id]
+// field: message".
+func (i Issue) String() string {
+	var b strings.Builder
+	b.WriteString(i.File)
+	if i.Line > 0 {
+		fmt.Fprintf(&b, ":%d", i.Line)
+		if i.Column > 0 {
+			fmt.Fprintf(&b, ":%d", i.Column)
+		}
+	}
+	fmt.Fprintf(&b, ": %s: ", i.Severity)
+	if i.ChallengeID != "" {
+		fmt.Fprintf(&b, "[%s] ", i.ChallengeID)
+	}
+	if i.Field != "" {
+		fmt.Fprintf(&b, "%s: ", i.Field)
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
+// HasErrors reports whether issues contains at least one
+// SeverityError issue.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LintOptions configures a Linter.
+type LintOptions struct {
+	// Engine, when set, is used to reject unknown assertion
+	// types and, through its descriptors, invalid values.
+	// Engines that do not expose HasEvaluator and Validate
+	// (like assertion.DefaultEngine does) are not consulted.
+	Engine assertion.Engine
+
+	// Steps resolves step types of step scripts. Nil selects
+	// stepscript.DefaultStepRegistry.
+	Steps *stepscript.StepRegistry
+}
+
+// Linter validates bank files against the bank schema and
+// checks the loaded set as a whole: duplicate IDs across
+// files, dangling dependency references and dependency cycles.
+// Call LintFile or LintDir for every input, then Finish.
+type Linter struct {
+	opts   LintOptions
+	issues []Issue
+	defs   map[challenge.ID]lintDef
+	order  []challenge.ID
+}
+
+// lintDef is a definition seen by the Linter with its source
+// position.
+type lintDef struct {
+	def  challenge.Definition
+	file string
+	pos  position
+}
+
+// position is a 1-based source position.
+type position struct {
+	line, col int
+}
+
+// NewLinter creates a Linter.
+func NewLinter(opts LintOptions) *Linter {
+	if opts.Steps == nil {
+		opts.Steps = stepscript.DefaultStepRegistry
+	}
+	return &Linter{
+		opts: opts,
+		defs: make(map[challenge.ID]lintDef),
+	}
+}
+
+// Lint lints every path (files or directories, see LintDir) and
+// returns the issues found.
+func Lint(paths []string, opts LintOptions) []Issue {
+	l := NewLinter(opts)
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			l.add(Issue{File: p, Field: "file", Message: err.Error()})
+			continue
+		}
+		if info.IsDir() {
+			l.LintDir(p)
+		} else {
+			l.LintFile(p)
+		}
+	}
+	return l.Finish()
+}
+
+// LintDir lints the .json, .yaml and .yml files of dir, the
+// same files LoadDir loads.
+func (l *Linter) LintDir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		l.add(Issue{File: dir, Field: "file", Message: err.Error()})
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		l.LintFile(filepath.Join(dir, entry.Name()))
+	}
+}
+
+// LintFile lints a single bank file.
+func (l *Linter) LintFile(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.add(Issue{File: path, Field: "file", Message: err.Error()})
+		return
+	}
+
+	file, err := parseBankFile(path, data)
+	if err != nil {
+		var lineErr *assertionLineError
+		if errors.As(err, &lineErr) {
+			l.add(lineErr.issue(path, data))
+			return
+		}
+		pos := errorPosition(err, data)
+		l.add(Issue{
+			File: path, Line: pos.line, Column: pos.col,
+			Field: "syntax", Message: err.Error(),
+		})
+		return
+	}
+
+	// JSON is valid YAML, so one node tree provides positions
+	// for both formats. A failure only loses positions.
+	var doc yaml.Node
+	_ = yaml.Unmarshal(data, &doc)
+	root := documentRoot(&doc)
+
+	if stepscript.IsScript(jsonDocument(path, data)) {
+		l.lintScript(path, data, root)
+	} else {
+		l.lintRoot(path, file, root)
+	}
+
+	items := challengeNodes(root)
+	for i := range file.Challenges {
+		var node *yaml.Node
+		if i < len(items) {
+			node = items[i]
+		}
+		l.lintDefinition(path, file.Challenges[i], node)
+	}
+}
+
+// Finish runs the checks that need every file and returns all
+// issues sorted by file and position.
+func (l *Linter) Finish() []Issue {
+	for _, id := range l.order {
+		d := l.defs[id]
+		for _, dep := range d.def.Dependencies {
+			if dep == id {
+				continue // reported by lintDefinition
+			}
+			if _, ok := l.defs[dep]; !ok {
+				l.addDef(d, "dependencies", SeverityError, fmt.Sprintf(
+					"dependency %s is not defined in any loaded bank", dep,
+				))
+			}
+		}
+	}
+	for _, cycle := range l.cycles() {
+		d := l.defs[cycle[0]]
+		parts := make([]string, len(cycle))
+		for i, id := range cycle {
+			parts[i] = string(id)
+		}
+		l.addDef(d, "dependencies", SeverityError, fmt.Sprintf(
+			"dependency cycle: %s -> %s",
+			strings.Join(parts, " -> "), cycle[0],
+		))
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.issues
+}
+
+// lintRoot checks the bank file envelope.
+func (l *Linter) lintRoot(path string, file *BankFile, root *yaml.Node) {
+	if file.Version == "" {
+		l.add(Issue{
+			File: path, Line: nodeLine(root), Column: nodeColumn(root),
+			Field: "version", Message: "version is required",
+		})
+	}
+	if len(file.Challenges) == 0 {
+		l.add(Issue{
+			File: path, Line: nodeLine(root), Column: nodeColumn(root),
+			Field: "challenges", Severity: SeverityWarning,
+			Message: "bank file defines no challenges",
+		})
+	}
+	l.unknownFields(path, "", root, bankFileFields)
+}
+
+// lintScript checks a step script document.
+func (l *Linter) lintScript(path string, data []byte, root *yaml.Node) {
+	script, err := stepscript.Parse(jsonDocument(path, data))
+	if err != nil {
+		return // reported as a parse error by parseBankFile
+	}
+	steps := sequenceNodes(mappingValue(root, "steps"))
+	for i, st := range script.Steps {
+		if _, ok := l.opts.Steps.Lookup(st.Type); ok {
+			continue
+		}
+		var node *yaml.Node
+		if i < len(steps) {
+			node = mappingValue(steps[i], "type")
+		}
+		l.add(Issue{
+			File: path, Line: nodeLine(node), Column: nodeColumn(node),
+			ChallengeID: script.ChallengeID(),
+			Field:       fmt.Sprintf("steps[%d].type", i),
+			Message:     fmt.Sprintf("unknown step type %s", st.Type),
+		})
+	}
+}
+
+// lintDefinition checks a single challenge definition and
+// records it for the cross-file checks.
+func (l *Linter) lintDefinition(
+	path string,
+	def challenge.Definition,
+	node *yaml.Node,
+) {
+	issue := func(field string, sev Severity, value *yaml.Node, msg string) {
+		if value == nil {
+			value = node
+		}
+		l.add(Issue{
+			File: path, Line: nodeLine(value), Column: nodeColumn(value),
+			ChallengeID: def.ID, Field: field, Severity: sev,
+			Message: msg,
+		})
+	}
+
+	if def.ID == "" {
+		issue("id", SeverityError, nil, "challenge ID is required")
+	}
+	if def.Name == "" {
+		issue("name", SeverityError, mappingValue(node, "name"),
+			"challenge name is required")
+	}
+	if def.EstimatedDuration != "" {
+		if _, err := time.ParseDuration(def.EstimatedDuration); err != nil {
+			issue("estimated_duration", SeverityError,
+				mappingValue(node, "estimated_duration"),
+				fmt.Sprintf("invalid duration %q", def.EstimatedDuration))
+		}
+	}
+	if err := validTimeout(def.Timeout); err != nil {
+		issue("timeout", SeverityError, mappingValue(node, "timeout"),
+			err.Error())
+	}
+	for _, dep := range def.Dependencies {
+		if dep == def.ID && dep != "" {
+			issue("dependencies", SeverityError,
+				mappingValue(node, "dependencies"),
+				"challenge depends on itself")
+		}
+	}
+
+	l.lintAssertions(path, def.ID, "assertions", def.Assertions,
+		sequenceNodes(mappingValue(node, "assertions")), issue)
+
+	inputs := sequenceNodes(mappingValue(node, "inputs"))
+	for i, in := range def.Inputs {
+		var inNode *yaml.Node
+		if i < len(inputs) {
+			inNode = mappingValue(inputs[i], "source")
+		}
+		if !validInputSource(in.Source) {
+			issue(fmt.Sprintf("inputs[%d].source", i), SeverityError,
+				inNode, fmt.Sprintf("unsupported input source %q", in.Source))
+		}
+	}
+
+	outputs := sequenceNodes(mappingValue(node, "outputs"))
+	for i, out := range def.Outputs {
+		if knownOutputTypes[strings.ToLower(out.Type)] {
+			continue
+		}
+		var outNode *yaml.Node
+		if i < len(outputs) {
+			outNode = mappingValue(outputs[i], "type")
+		}
+		issue(fmt.Sprintf("outputs[%d].type", i), SeverityWarning,
+			outNode, fmt.Sprintf(
+				"unknown output type %q is treated as string", out.Type,
+			))
+	}
+
+	l.unknownFields(path, def.ID, node, definitionFields)
+
+	if def.ID == "" {
+		return
+	}
+	pos := position{nodeLine(node), nodeColumn(node)}
+	if first, dup := l.defs[def.ID]; dup {
+		issue("id", SeverityError, mappingValue(node, "id"), fmt.Sprintf(
+			"duplicate ID %s (first defined at %s:%d)",
+			def.ID, first.file, first.pos.line,
+		))
+		return
+	}
+	l.defs[def.ID] = lintDef{def: def, file: path, pos: pos}
+	l.order = append(l.order, def.ID)
+}
+
+// isFileReference reports whether an assertion value names a
+// file rather than holding an inline JSON document.
+func isFileReference(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	s = strings.TrimSpace(s)
+	return s != "" && s[0] != '{' && s[0] != '['
+}
+
+// lintAssertions checks a list of assertions and, recursively,
+// the sub-assertions of composite types. prefix is the field
+// path of the list (e.g., "assertions[2].assertions").
+func (l *Linter) lintAssertions(
+	path string,
+	id challenge.ID,
+	prefix string,
+	defs []challenge.AssertionDef,
+	nodes []*yaml.Node,
+	issue func(field string, sev Severity, value *yaml.Node, msg string),
+) {
+	for i, a := range defs {
+		var an *yaml.Node
+		if i < len(nodes) {
+			an = nodes[i]
+		}
+		field := fmt.Sprintf("%s[%d]", prefix, i)
+		switch {
+		case a.Type == "":
+			issue(field+".type", SeverityError, an, "assertion type is required")
+		case !l.knownAssertion(a.Type):
+			issue(field+".type", SeverityError, mappingValue(an, "type"),
+				fmt.Sprintf("unknown assertion type %s", a.Type))
+		default:
+			if err := l.validateAssertion(a); err != nil {
+				node := mappingValue(an, "value")
+				if node == nil {
+					node = an // a missing value
+				}
+				issue(field+".value", SeverityError, node, err.Error())
+			}
+		}
+		if src, ok := a.Value.(string); ok && a.Type == "expr" {
+			if _, err := assertion.CompileExpr(src); err != nil {
+				issue(field+".value", SeverityError,
+					mappingValue(an, "value"), err.Error())
+			}
+		}
+		if a.Type == assertion.TypeJSONSchema && !isFileReference(a.Value) {
+			if _, err := assertion.ParseSchema(a.Value); err != nil {
+				issue(field+".value", SeverityError,
+					mappingValue(an, "value"), err.Error())
+			}
+		}
+		if a.Target != "" {
+			if _, err := assertion.ParsePath(a.Target); err != nil {
+				issue(field+".target", SeverityError,
+					mappingValue(an, "target"), err.Error())
+			}
+		}
+		for j, ignore := range a.Ignore {
+			if _, err := assertion.ParsePath(ignore); err != nil {
+				issue(fmt.Sprintf("%s.ignore[%d]", field, j), SeverityError,
+					mappingValue(an, "ignore"), err.Error())
+			}
+		}
+		if !challenge.ValidSeverity(a.Severity) {
+			issue(field+".severity", SeverityError,
+				mappingValue(an, "severity"),
+				fmt.Sprintf("unknown severity %s", a.Severity))
+		}
+		l.unknownFields(path, id, an, assertionFields)
+
+		children := sequenceNodes(mappingValue(an, "assertions"))
+		switch {
+		case assertion.IsComposite(a.Type) && len(a.Assertions) == 0:
+			issue(field+".assertions", SeverityError, an,
+				fmt.Sprintf("%s requires sub-assertions", a.Type))
+		case a.Type == assertion.TypeNot && len(a.Assertions) != 1:
+			issue(field+".assertions", SeverityError,
+				mappingValue(an, "assertions"),
+				"not takes exactly one sub-assertion")
+		case !assertion.IsComposite(a.Type) && len(a.Assertions) > 0:
+			issue(field+".assertions", SeverityWarning,
+				mappingValue(an, "assertions"),
+				fmt.Sprintf("sub-assertions are ignored by type %s", a.Type))
+		}
+		l.lintAssertions(path, id, field+".assertions", a.Assertions,
+			children, issue)
+	}
+}
+
+// validateAssertion checks the arguments of a against the
+// engine's descriptor of its type, when the engine has one.
+func (l *Linter) validateAssertion(a challenge.AssertionDef) error {
+	validator, ok := l.opts.Engine.(interface {
+		Validate(assertion.Definition) error
+	})
+	if !ok {
+		return nil
+	}
+	return validator.Validate(assertion.FromChallengeDef(a))
+}
+
+// knownAssertion reports whether the engine accepts typ. Without
+// an engine that can answer, every type is accepted.
+func (l *Linter) knownAssertion(typ string) bool {
+	checker, ok := l.opts.Engine.(interface {
+		HasEvaluator(string) bool
+	})
+	if !ok {
+		return true
+	}
+	return checker.HasEvaluator(typ)
+}
+
+// unknownFields warns about mapping keys the loader ignores.
+func (l *Linter) unknownFields(
+	path string,
+	id challenge.ID,
+	node *yaml.Node,
+	known map[string]bool,
+) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if known[key.Value] {
+			continue
+		}
+		l.add(Issue{
+			File: path, Line: key.Line, Column: key.Column,
+			ChallengeID: id, Field: key.Value,
+			Severity: SeverityWarning,
+			Message:  "unknown field is ignored by the loader",
+		})
+	}
+}
+
+// cycles returns each dependency cycle once, starting at its
+// smallest ID.
+func (l *Linter) cycles() [][]challenge.ID {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[challenge.ID]int, len(l.defs))
+	seen := make(map[string]bool)
+	var out [][]challenge.ID
+	var stack []challenge.ID
+
+	var visit func(id challenge.ID)
+	visit = func(id challenge.ID) {
+		state[id] = active
+		stack = append(stack, id)
+		for _, dep := range l.defs[id].def.Dependencies {
+			if _, ok := l.defs[dep]; !ok || dep == id {
+				continue
+			}
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case active:
+				start := len(stack) - 1
+				for stack[start] != dep {
+					start--
+				}
+				cycle := canonicalCycle(stack[start:])
+				key := fmt.Sprint(cycle)
+				if !seen[key] {
+					seen[key] = true
+					out = append(out, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+
+	ids := append([]challenge.ID(nil), l.order...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+	return out
+}
+
+// canonicalCycle rotates cycle to start at its smallest ID.
+func canonicalCycle(cycle []challenge.ID) []challenge.ID {
+	first := 0
+	for i, id := range cycle {
+		if id < cycle[first] {
+			first = i
+		}
+	}
+	out := make([]challenge.ID, 0, len(cycle))
+	out = append(out, cycle[first:]...)
+	return append(out, cycle[:first]...)
+}
+
+// add records an issue, defaulting its severity to error.
+func (l *Linter) add(i Issue) {
+	if i.Severity == "" {
+		i.Severity = SeverityError
+	}
+	l.issues = append(l.issues, i)
+}
+
+// addDef records an issue located at a definition.
+func (l *Linter) addDef(d lintDef, field string, sev Severity, msg string) {
+	l.add(Issue{
+		File: d.file, Line: d.pos.line, Column: d.pos.col,
+		ChallengeID: d.def.ID, Field: field, Severity: sev,
+		Message: msg,
+	})
+}
+
+// validInputSource reports whether source is understood by
+// DefinitionChallenge.
+func validInputSource(source string) bool {
+	switch source {
+	case challenge.InputSourceEnv, challenge.InputSourceConfig:
+		return true
+	}
+	id, _, ok := challenge.ParseDependencySource(source)
+	return ok && id != ""
+}
+
+var knownOutputTypes = map[string]bool{
+	challenge.OutputTypeString: true,
+	challenge.OutputTypeNumber: true,
+	challenge.OutputTypeBool:   true,
+	"boolean":                  true,
+	challenge.OutputTypeJSON:   true,
+	challenge.OutputTypeFile:   true,
+}
+
+// Known keys derived from the json tags of the loaded types, so
+// the schema follows the structs.
+var (
+	bankFileFields   = jsonFields(reflect.TypeOf(BankFile{}))
+	definitionFields = jsonFields(reflect.TypeOf(challenge.Definition{}))
+	assertionFields  = jsonFields(reflect.TypeOf(challenge.AssertionDef{}))
+)
+
+// jsonFields returns the JSON field names of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	out := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// jsonDocument returns the JSON form of a bank document.
+func jsonDocument(path string, data []byte) []byte {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext != ".yaml" && ext != ".yml" {
+		return data
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	out, err := json.Marshal(normaliseYAMLValue(raw))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+)`)
+
+// errorPosition extracts the source position of a parse error.
+func errorPosition(err error, data []byte) position {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the offending byte; point at it.
+		return offsetPosition(data, syntaxErr.Offset-1)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return offsetPosition(data, typeErr.Offset)
+	}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return position{line: line}
+	}
+	return position{}
+}
+
+// issue reports the error at its position in the assertion
+// line of data.
+func (e *assertionLineError) issue(path string, data []byte) Issue {
+	var doc yaml.Node
+	_ = yaml.Unmarshal(data, &doc)
+	var node *yaml.Node
+	if defs := sequenceNodes(mappingValue(documentRoot(&doc), e.list)); e.index < len(defs) {
+		node = defs[e.index]
+	}
+	for _, i := range e.path {
+		items := sequenceNodes(mappingValue(node, "assertions"))
+		node = nil
+		if i < len(items) {
+			node = items[i]
+		}
+	}
+
+	col := nodeColumn(node)
+	var syntaxErr *assertion.SyntaxError
+	if node != nil && errors.As(e.err, &syntaxErr) {
+		if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+			col++ // the opening quote
+		}
+		col += syntaxErr.Pos
+	}
+	return Issue{
+		File: path, Line: nodeLine(node), Column: col,
+		Field: e.field, Message: e.err.Error(),
+	}
+}
+
+// offsetPosition converts a byte offset into a line and column.
+func offsetPosition(data []byte, offset int64) position {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	pos := position{line: 1, col: 1}
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			pos.line++
+			pos.col = 1
+		} else {
+			pos.col++
+		}
+	}
+	return pos
+}
+
+// documentRoot returns the top-level node of a parsed document.
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return nil
+}
+
+// challengeNodes returns the definition nodes of a bank
+// document, honouring the "test_cases" alias.
+func challengeNodes(root *yaml.Node) []*yaml.Node {
+	if items := sequenceNodes(mappingValue(root, "challenges")); len(items) > 0 {
+		return items
+	}
+	return sequenceNodes(mappingValue(root, "test_cases"))
+}
+
+// mappingValue returns the value node of key in mapping node n.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sequenceNodes returns the items of sequence node n.
+func sequenceNodes(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+func nodeLine(n *yaml.Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.Line
+}
+
+func nodeColumn(n *yaml.Node) int {
+	if n == nil {
+		return 0
+	}
+	return n.Column
+}
@@ -0,0 +1,431 @@
+package bank
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLintFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+// findIssue returns the first issue whose field and message
+// contain the given fragments.
+func findIssue(issues []Issue, field, msg string) (Issue, bool) {
+	for _, i := range issues {
+		if strings.Contains(i.Field, field) &&
+			strings.Contains(i.Message, msg) {
+			return i, true
+		}
+	}
+	return Issue{}, false
+}
+
+// ===== Lint tests =====
+
+func TestLint_CleanBank(t *testing.T) {
+	dir := t.TempDir()
+	writeLintFile(t, dir, "ok.json", `{
+  "version": "1.0",
+  "name": "ok",
+  "challenges": [
+    {"id": "a", "name": "A", "estimated_duration": "30s",
+     "assertions": [{"type": "not_empty", "target": "out"}]},
+    {"id": "b", "name": "B", "dependencies": ["a"],
+     "inputs": [{"name": "x", "source": "dependency:a.out"}],
+     "outputs": [{"name": "n", "type": "number"}]}
+  ]
+}`)
+
+	issues := Lint([]string{dir}, LintOptions{Engine: assertion.NewEngine()})
+	assert.Empty(t, issues)
+	assert.False(t, HasErrors(issues))
+}
+
+func TestLint_DefinitionChecks(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "bad.json", `{
+  "version": "1.0",
+  "challenges": [
+    {
+      "id": "a",
+      "name": "A",
+      "estimated_duration": "soon",
+      "dependencies": ["ghost"],
+      "assertions": [{"type": "bogus_type", "target": "x"}],
+      "inputs": [{"name": "i", "source": "somewhere"}],
+      "outputs": [{"name": "o", "type": "blob"}],
+      "colour": "red"
+    },
+    {"id": "a"}
+  ]
+}`)
+
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	assert.True(t, HasErrors(issues))
+
+	dur, ok := findIssue(issues, "estimated_duration", `invalid duration "soon"`)
+	require.True(t, ok)
+	assert.Equal(t, path, dur.File)
+	assert.Equal(t, 7, dur.Line)
+	assert.Equal(t, challenge.ID("a"), dur.ChallengeID)
+
+	typ, ok := findIssue(issues, "assertions[0].type", "unknown assertion type bogus_type")
+	require.True(t, ok)
+	assert.Equal(t, 9, typ.Line)
+
+	_, ok = findIssue(issues, "dependencies", "dependency ghost is not defined")
+	assert.True(t, ok)
+	_, ok = findIssue(issues, "inputs[0].source", "unsupported input source")
+	assert.True(t, ok)
+	_, ok = findIssue(issues, "id", "duplicate ID a")
+	assert.True(t, ok)
+	_, ok = findIssue(issues, "name", "challenge name is required")
+	assert.True(t, ok)
+
+	out, ok := findIssue(issues, "outputs[0].type", "unknown output type")
+	require.True(t, ok)
+	assert.Equal(t, SeverityWarning, out.Severity)
+	colour, ok := findIssue(issues, "colour", "unknown field")
+	require.True(t, ok)
+	assert.Equal(t, SeverityWarning, colour.Severity)
+	assert.Equal(t, 12, colour.Line)
+}
+
+func TestLint_NoEngineAcceptsAnyAssertion(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "b.json", `{"version": "1",
+  "challenges": [{"id": "a", "name": "A",
+    "assertions": [{"type": "bogus_type", "target": "x"}]}]}`)
+
+	issues := Lint([]string{path}, LintOptions{})
+	assert.Empty(t, issues)
+}
+
+func TestLint_CrossFileDuplicatesAndCycles(t *testing.T) {
+	dir := t.TempDir()
+	writeLintFile(t, dir, "one.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    dependencies: [b]
+  - id: shared
+    name: Shared
+`)
+	writeLintFile(t, dir, "two.json", `{"version": "1", "challenges": [
+  {"id": "b", "name": "B", "dependencies": ["c"]},
+  {"id": "c", "name": "C", "dependencies": ["a"]},
+  {"id": "shared", "name": "Again"}
+]}`)
+
+	issues := Lint([]string{dir}, LintOptions{})
+
+	dup, ok := findIssue(issues, "id", "duplicate ID shared")
+	require.True(t, ok)
+	assert.Contains(t, dup.File, "two.json")
+	assert.Contains(t, dup.Message, "one.yaml:6")
+
+	cycle, ok := findIssue(issues, "dependencies", "dependency cycle: a -> b -> c -> a")
+	require.True(t, ok)
+	assert.Contains(t, cycle.File, "one.yaml")
+	assert.Equal(t, 3, cycle.Line)
+}
+
+func TestLint_YAMLSyntaxPosition(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "broken.yaml", `version: "1"
+challenges:
+  - id: a
+    name: [unclosed
+`)
+
+	issues := Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "syntax", issues[0].Field)
+	assert.Greater(t, issues[0].Line, 0)
+}
+
+func TestLint_JSONSyntaxPosition(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "broken.json", "{\n  \"version\": \"1\",\n  \"challenges\": [,]\n}")
+
+	issues := Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Equal(t, 18, issues[0].Column)
+	assert.Contains(t, issues[0].String(), "broken.json:3:18: error: syntax:")
+}
+
+func TestLint_StepScript(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "script.yaml", `name: script
+steps:
+  - name: one
+    type: shell
+    command: "true"
+  - name: two
+    type: teleport
+`)
+
+	issues := Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "steps[1].type", issues[0].Field)
+	assert.Equal(t, 7, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "unknown step type teleport")
+}
+
+func TestLint_MissingPath(t *testing.T) {
+	issues := Lint([]string{"/nonexistent/bank.json"}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "file", issues[0].Field)
+}
+
+func TestIssue_String(t *testing.T) {
+	i := Issue{
+		File: "b.json", Line: 3, Column: 5, ChallengeID: "x",
+		Field: "id", Severity: SeverityWarning, Message: "m",
+	}
+	assert.Equal(t, "b.json:3:5: warning: [x] id: m", i.String())
+	assert.Equal(t, "b.json: error: m",
+		Issue{File: "b.json", Severity: SeverityError, Message: "m"}.String())
+}
+
+// ===== ValidateFile YAML tests =====
+
+func TestValidateFile_YAML(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "bank.yaml", `challenges:
+  - id: a
+`)
+	errs := ValidateFile(path)
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.ElementsMatch(t, []string{"version", "name"}, fields)
+
+	bad := writeLintFile(t, dir, "bad.yml", "challenges: [\n")
+	errs = ValidateFile(bad)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "yaml", errs[0].Field)
+}
+
+func TestLint_InvalidTimeout(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "t.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    timeout: soon
+`)
+	issues := Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "timeout", issues[0].Field)
+	assert.Equal(t, 5, issues[0].Line)
+}
+
+func TestLint_InvalidTargetPath(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "p.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: not_empty
+        target: "items[*].id"
+      - type: not_empty
+        target: "items[oops"
+`)
+	issues := Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[1].target", issues[0].Field)
+	assert.Equal(t, 9, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "unclosed [")
+}
+
+func TestLint_InvalidExpression(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "e.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: expr
+        value: "latency_ms < 200 && len(items) >= 3"
+      - type: expr
+        value: "latency_ms <"
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[1].value", issues[0].Field)
+	assert.Equal(t, 9, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "invalid expression")
+}
+
+func TestLint_CompositeAssertions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "c.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: any_of
+        target: status
+        assertions:
+          - type: equals
+            value: ok
+          - type: nope
+      - type: not
+        assertions: []
+      - type: not_empty
+        target: status
+        assertions:
+          - type: equals
+            value: ok
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 3)
+	unknown, ok := findIssue(issues, "assertions[0].assertions[1].type", "unknown assertion type nope")
+	require.True(t, ok)
+	assert.Equal(t, 11, unknown.Line)
+
+	empty, ok := findIssue(issues, "assertions[1].assertions", "requires sub-assertions")
+	require.True(t, ok)
+	assert.Equal(t, SeverityError, empty.Severity)
+
+	ignored, ok := findIssue(issues, "assertions[2].assertions", "ignored by type not_empty")
+	require.True(t, ok)
+	assert.Equal(t, SeverityWarning, ignored.Severity)
+}
+
+func TestLint_InvalidJSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "s.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: json_schema
+        target: response
+        value:
+          type: object
+          required: [id]
+      - type: json_schema
+        target: response
+        value: schemas/user.json
+      - type: json_schema
+        target: response
+        value:
+          type: text
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[2].value", issues[0].Field)
+	assert.Equal(t, 17, issues[0].Line)
+	assert.Contains(t, issues[0].Message, `unknown type "text"`)
+}
+
+func TestLint_InvalidSnapshotIgnorePath(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "s.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: matches_snapshot
+        target: response
+        ignore: [meta.timestamp, "items[?"]
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[0].ignore[1]", issues[0].Field)
+	assert.Equal(t, SeverityError, issues[0].Severity)
+}
+
+func TestLint_UnknownSeverity(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "s.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: not_empty
+        target: response
+        severity: warning
+      - type: not_empty
+        target: response
+        severity: fatal
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[1].severity", issues[0].Field)
+	assert.Equal(t, 11, issues[0].Line)
+	assert.Equal(t, "unknown severity fatal", issues[0].Message)
+}
+
+func TestLint_CompactAssertions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "c.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - "out | contains(ok)"
+      - "out | bogus_type()"
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "assertions[1].type", issues[0].Field)
+	assert.Equal(t, "unknown assertion type bogus_type", issues[0].Message)
+
+	path = writeLintFile(t, dir, "c.json", `{"version": "1", "challenges": [
+  {"id": "b", "name": "B", "assertions": [
+    {"type": "all_of", "assertions": ["not_empty", "out | equals(1 2)"]}
+  ]}
+]}`)
+	issues = Lint([]string{path}, LintOptions{})
+	require.Len(t, issues, 1)
+	assert.Equal(t, "challenges[0].assertions[0].assertions[1]", issues[0].Field)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Equal(t, 68, issues[0].Column)
+	assert.Contains(t, issues[0].Message, `expected "," or ")"`)
+}
+
+func TestLint_InvalidAssertionValue(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "v.yaml", `version: "1"
+challenges:
+  - id: a
+    name: A
+    assertions:
+      - type: min_length
+        target: response
+        value: 10
+      - type: min_length
+        target: response
+        value: ten
+      - type: contains
+        target: response
+`)
+	issues := Lint([]string{path}, LintOptions{Engine: assertion.NewEngine()})
+	require.Len(t, issues, 2)
+	assert.Equal(t, "assertions[1].value", issues[0].Field)
+	assert.Equal(t, 11, issues[0].Line)
+	assert.Equal(t, `min_length value must be integer, got "ten"`, issues[0].Message)
+	assert.Equal(t, "assertions[2].value", issues[1].Field)
+	assert.Equal(t, 12, issues[1].Line)
+	assert.Equal(t, "contains requires a value", issues[1].Message)
+
+	assert.Empty(t, Lint([]string{path}, LintOptions{}))
+}
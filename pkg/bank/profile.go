@@ -0,0 +1,224 @@
+package bank
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Profile is a named set of patches (e.g., "ci", "nightly")
+// applied to the loaded definitions by Bank.ApplyProfile.
+type Profile struct {
+	Description string `json:"description,omitempty"`
+
+	// Timeout, when set, becomes the timeout of every
+	// definition not patched individually.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Environment is merged into every definition's
+	// environment.
+	Environment map[string]string `json:"environment,omitempty"`
+
+	// Challenges holds per-challenge patches keyed by ID.
+	Challenges map[challenge.ID]ProfilePatch `json:"challenges,omitempty"`
+}
+
+// ProfilePatch patches a single definition.
+type ProfilePatch struct {
+	Timeout     string            `json:"timeout,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Assertions  []AssertionPatch  `json:"assertions,omitempty"`
+}
+
+// AssertionPatch replaces the expected value of the assertions
+// checking Target, narrowed to one assertion type when Type is
+// set. It must match at least one assertion.
+type AssertionPatch struct {
+	Target string `json:"target"`
+	Type   string `json:"type,omitempty"`
+	Value  any    `json:"value,omitempty"`
+	Values []any  `json:"values,omitempty"`
+}
+
+// Profiles returns the names of the loaded profiles, sorted.
+func (b *Bank) Profiles() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.profileNamesLocked()
+}
+
+// ApplyProfile patches the loaded definitions with the named
+// profile. Nothing is changed when the profile is unknown,
+// patches a challenge that is not loaded, has an invalid
+// timeout or an assertion patch that matches no assertion.
+// Definitions are replaced, so pointers returned by Get before
+// the call keep their previous values.
+func (b *Bank) ApplyProfile(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	p, ok := b.profiles[name]
+	if !ok {
+		return fmt.Errorf(
+			"unknown profile %q (available: %s)",
+			name, strings.Join(b.profileNamesLocked(), ", "),
+		)
+	}
+	if err := validTimeout(p.Timeout); err != nil {
+		return fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	patched := make(map[challenge.ID]*challenge.Definition, len(b.definitions))
+	for id, def := range b.definitions {
+		d := copyDefinition(def)
+		if p.Timeout != "" {
+			d.Timeout = p.Timeout
+		}
+		d.Environment = mergeEnv(d.Environment, p.Environment)
+		patched[id] = d
+	}
+
+	ids := make([]challenge.ID, 0, len(p.Challenges))
+	for id := range p.Challenges {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		d, ok := patched[id]
+		if !ok {
+			return fmt.Errorf(
+				"profile %s: challenge %s is not loaded", name, id,
+			)
+		}
+		if err := applyPatch(d, p.Challenges[id]); err != nil {
+			return fmt.Errorf("profile %s: challenge %s: %w", name, id, err)
+		}
+	}
+
+	b.definitions = patched
+	return nil
+}
+
+// applyPatch applies a single challenge patch to d.
+func applyPatch(d *challenge.Definition, patch ProfilePatch) error {
+	if err := validTimeout(patch.Timeout); err != nil {
+		return err
+	}
+	if patch.Timeout != "" {
+		d.Timeout = patch.Timeout
+	}
+	d.Environment = mergeEnv(d.Environment, patch.Environment)
+
+	for _, ap := range patch.Assertions {
+		matched := false
+		for i := range d.Assertions {
+			a := &d.Assertions[i]
+			if a.Target != ap.Target ||
+				(ap.Type != "" && a.Type != ap.Type) {
+				continue
+			}
+			if ap.Value != nil {
+				a.Value = ap.Value
+			}
+			if ap.Values != nil {
+				a.Values = ap.Values
+			}
+			matched = true
+		}
+		if !matched {
+			return fmt.Errorf(
+				"assertion patch for target %q matches no assertion",
+				ap.Target,
+			)
+		}
+	}
+	return nil
+}
+
+// mergeProfile merges a profile with the same name from another
+// file into dst. Settings defined differently in both are a
+// conflict.
+func mergeProfile(name string, dst *Profile, src Profile) error {
+	conflict := func(what string) error {
+		return fmt.Errorf("profile %s: conflicting %s", name, what)
+	}
+	if src.Timeout != "" {
+		if dst.Timeout != "" && dst.Timeout != src.Timeout {
+			return conflict("timeout")
+		}
+		dst.Timeout = src.Timeout
+	}
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	for k, v := range src.Environment {
+		if old, ok := dst.Environment[k]; ok && old != v {
+			return conflict(fmt.Sprintf("environment value %s", k))
+		}
+		if dst.Environment == nil {
+			dst.Environment = make(map[string]string)
+		}
+		dst.Environment[k] = v
+	}
+	for id, patch := range src.Challenges {
+		if old, ok := dst.Challenges[id]; ok &&
+			!reflect.DeepEqual(old, patch) {
+			return conflict(fmt.Sprintf("patch for challenge %s", id))
+		}
+		if dst.Challenges == nil {
+			dst.Challenges = make(map[challenge.ID]ProfilePatch)
+		}
+		dst.Challenges[id] = patch
+	}
+	return nil
+}
+
+func (b *Bank) profileNamesLocked() []string {
+	names := make([]string, 0, len(b.profiles))
+	for name := range b.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validTimeout checks an optional duration string.
+func validTimeout(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := time.ParseDuration(s); err != nil {
+		return fmt.Errorf("invalid timeout %q", s)
+	}
+	return nil
+}
+
+// copyDefinition copies the parts of def a profile may change.
+func copyDefinition(def *challenge.Definition) *challenge.Definition {
+	d := *def
+	d.Assertions = append([]challenge.AssertionDef(nil), def.Assertions...)
+	if def.Environment != nil {
+		d.Environment = mergeEnv(nil, def.Environment)
+	}
+	return &d
+}
+
+// mergeEnv returns base overlaid with extra. base is returned
+// unchanged when extra is empty.
+func mergeEnv(base, extra map[string]string) map[string]string {
+	if len(extra) == 0 {
+		return base
+	}
+	out := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
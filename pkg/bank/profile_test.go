@@ -0,0 +1,153 @@
+package bank
+
+import (
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileBank = `version: "1"
+challenges:
+  - id: login
+    name: Login
+    timeout: 1m
+    environment:
+      HOST: local
+    assertions:
+      - type: max_latency
+        target: latency_ms
+        value: 500
+      - type: not_empty
+        target: token
+  - id: logout
+    name: Logout
+profiles:
+  ci:
+    timeout: 30s
+    environment:
+      CI: "true"
+    challenges:
+      login:
+        timeout: 2m
+        environment:
+          HOST: ci-host
+        assertions:
+          - target: latency_ms
+            type: max_latency
+            value: 2000
+  nightly:
+    environment:
+      DEPTH: full
+`
+
+// ===== Profile tests =====
+
+func TestBank_ApplyProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "bank.yaml", profileBank)
+
+	b := New()
+	require.NoError(t, b.LoadFile(path))
+	assert.Equal(t, []string{"ci", "nightly"}, b.Profiles())
+
+	before, _ := b.Get("login")
+	require.NoError(t, b.ApplyProfile("ci"))
+
+	login, _ := b.Get("login")
+	assert.Equal(t, "2m", login.Timeout)
+	assert.Equal(t, map[string]string{"HOST": "ci-host", "CI": "true"},
+		login.Environment)
+	assert.Equal(t, float64(2000), login.Assertions[0].Value)
+	assert.Nil(t, login.Assertions[1].Value)
+
+	logout, _ := b.Get("logout")
+	assert.Equal(t, "30s", logout.Timeout)
+	assert.Equal(t, map[string]string{"CI": "true"}, logout.Environment)
+
+	// Earlier pointers keep the unpatched values.
+	assert.Equal(t, "1m", before.Timeout)
+	assert.Equal(t, float64(500), before.Assertions[0].Value)
+}
+
+func TestBank_ApplyProfile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := writeLintFile(t, dir, "bank.json", `{"version": "1",
+  "challenges": [{"id": "a", "name": "A",
+    "assertions": [{"type": "max_latency", "target": "ms", "value": 1}]}],
+  "profiles": {
+    "ghost": {"challenges": {"missing": {"timeout": "1s"}}},
+    "slow": {"timeout": "forever"},
+    "nomatch": {"challenges": {"a": {"assertions": [
+      {"target": "ms", "type": "min_latency", "value": 5}]}}}
+  }}`)
+
+	b := New()
+	require.NoError(t, b.LoadFile(path))
+
+	err := b.ApplyProfile("prod")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown profile "prod"`)
+	assert.Contains(t, err.Error(), "ghost, nomatch, slow")
+
+	err = b.ApplyProfile("ghost")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "challenge missing is not loaded")
+
+	err = b.ApplyProfile("slow")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid timeout "forever"`)
+
+	err = b.ApplyProfile("nomatch")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "matches no assertion")
+
+	a, _ := b.Get("a")
+	assert.Equal(t, float64(1), a.Assertions[0].Value)
+}
+
+func TestBank_ProfilesMergeAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeLintFile(t, dir, "a.yaml", `version: "1"
+challenges: [{id: a, name: A}]
+profiles:
+  ci: {environment: {X: "1"}}
+`)
+	writeLintFile(t, dir, "b.yaml", `version: "1"
+challenges: [{id: b, name: B}]
+profiles:
+  ci: {timeout: 10s, environment: {X: "1", Y: "2"}}
+`)
+
+	b := New()
+	require.NoError(t, b.LoadDir(dir))
+	require.NoError(t, b.ApplyProfile("ci"))
+	def, _ := b.Get("a")
+	assert.Equal(t, "10s", def.Timeout)
+	assert.Equal(t, map[string]string{"X": "1", "Y": "2"}, def.Environment)
+
+	writeLintFile(t, dir, "c.yaml", `version: "1"
+challenges: [{id: c, name: C}]
+profiles:
+  ci: {environment: {X: "other"}}
+`)
+	err := New().LoadDir(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile ci: conflicting environment value X")
+}
+
+func TestMergeProfile_ChallengePatchConflict(t *testing.T) {
+	dst := Profile{Challenges: map[challenge.ID]ProfilePatch{
+		"a": {Timeout: "1s"},
+	}}
+	require.NoError(t, mergeProfile("p", &dst, Profile{
+		Challenges: map[challenge.ID]ProfilePatch{"a": {Timeout: "1s"}},
+	}))
+	err := mergeProfile("p", &dst, Profile{
+		Challenges: map[challenge.ID]ProfilePatch{"a": {Timeout: "2s"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "patch for challenge a")
+}
@@ -11,6 +11,11 @@ import "digital.vasic.challenges/pkg/challenge"
 //
 // parseBankFile merges TestCases into Challenges when Challenges is
 // empty so downstream code only ever reads the Challenges slice.
+//
+// Include lists files (relative to the bank file, globs allowed)
+// loaded before this one. Override replaces definitions loaded
+// earlier; redefining an ID under Challenges is a conflict.
+// Profiles are named patches applied with Bank.ApplyProfile.
 type BankFile struct {
 	Version     string                 `json:"version"`
 	Name        string                 `json:"name"`
@@ -18,4 +23,7 @@ type BankFile struct {
 	Challenges  []challenge.Definition `json:"challenges"`
 	TestCases   []challenge.Definition `json:"test_cases,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Include     []string               `json:"include,omitempty"`
+	Override    []challenge.Definition `json:"override,omitempty"`
+	Profiles    map[string]Profile     `json:"profiles,omitempty"`
 }
@@ -1,9 +1,10 @@
 package bank
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // ValidationError represents a validation issue found in a bank file.
@@ -21,6 +22,8 @@ func (e ValidationError) Error() string {
 }
 
 // ValidateFile validates a bank file structure and returns all errors found.
+// JSON and YAML files are accepted, like LoadFile. For the full
+// schema and cross-file checks use Lint.
 func ValidateFile(path string) []ValidationError {
 	var errors []ValidationError
 
@@ -29,9 +32,14 @@ func ValidateFile(path string) []ValidationError {
 		return []ValidationError{{Field: "file", Message: err.Error(), Index: -1}}
 	}
 
-	var file BankFile
-	if err := json.Unmarshal(data, &file); err != nil {
-		return []ValidationError{{Field: "json", Message: err.Error(), Index: -1}}
+	file, err := parseBankFile(path, data)
+	if err != nil {
+		field := "json"
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			field = "yaml"
+		}
+		return []ValidationError{{Field: field, Message: err.Error(), Index: -1}}
 	}
 
 	if file.Version == "" {
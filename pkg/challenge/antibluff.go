@@ -57,18 +57,20 @@ func (r *Result) RecordAction(description string) {
 }
 
 // ValidateAntiBluff inspects a completed Result and returns
-// ErrBluffPass if the result claims Status=Passed but doesn't carry
-// the positive evidence required by Constitution §11.4. Returns nil
-// for any non-Passed status (Failed/Skipped/Error are honest by
-// definition — the §11.4 covenant only constrains PASS).
+// ErrBluffPass if the result claims Status=Passed (or
+// Status=PassedWithWarnings) but doesn't carry the positive evidence
+// required by Constitution §11.4. Returns nil for any non-passing
+// status (Failed/Skipped/Error are honest by definition — the §11.4
+// covenant only constrains PASS).
 //
-// Required for Status=Passed:
+// Required for a passing status:
 //
 //   • RecordedActions is non-empty (at least one action ran).
 //   • Assertions is non-empty (at least one expectation was checked).
-//   • At least one assertion has Passed=true (something was
-//     positively confirmed; assertion-list of all failures with
-//     Status=Passed is the canonical bluff pattern this guards).
+//   • At least one assertion with a severity other than info has
+//     Passed=true (something was positively confirmed; assertion-list
+//     of all failures with Status=Passed is the canonical bluff
+//     pattern this guards, and info-only assertions confirm nothing).
 //
 // Composed assertions are evaluated as a single passing assertion
 // (the engine collapses them into one AssertionResult). Outputs and
@@ -80,7 +82,7 @@ func ValidateAntiBluff(r *Result) error {
 	if r == nil {
 		return errors.New("nil result")
 	}
-	if r.Status != StatusPassed {
+	if !IsPassing(r.Status) {
 		return nil
 	}
 	if len(r.RecordedActions) == 0 {
@@ -90,9 +92,9 @@ func ValidateAntiBluff(r *Result) error {
 		return fmt.Errorf("%w: challenge %q has Status=Passed but no assertions evaluated", ErrBluffPass, r.ChallengeID)
 	}
 	for _, a := range r.Assertions {
-		if a.Passed {
+		if a.Passed && a.Severity != SeverityInfo {
 			return nil
 		}
 	}
-	return fmt.Errorf("%w: challenge %q has Status=Passed but every assertion (%d) reports Passed=false", ErrBluffPass, r.ChallengeID, len(r.Assertions))
+	return fmt.Errorf("%w: challenge %q has Status=Passed but no assertion (%d) above info severity reports Passed=true", ErrBluffPass, r.ChallengeID, len(r.Assertions))
 }
@@ -165,3 +165,48 @@ func TestRecordAction_NilReceiver(t *testing.T) {
 	var r *Result
 	r.RecordAction("noop")
 }
+
+// TestValidate_PassWithWarnings — a StatusPassedWithWarnings result
+// is held to the same evidence requirements as StatusPassed.
+func TestValidate_PassWithWarnings(t *testing.T) {
+	warning := failingAssertion()
+	warning.Severity = SeverityWarning
+	r := &Result{
+		ChallengeID:     "test-pass-warnings",
+		Status:          StatusPassedWithWarnings,
+		RecordedActions: []string{"action"},
+		Assertions:      []AssertionResult{warning, passingAssertion()},
+	}
+	if err := ValidateAntiBluff(r); err != nil {
+		t.Fatalf("expected nil for warnings Pass with a passing assertion; got %v", err)
+	}
+
+	r.RecordedActions = nil
+	if err := ValidateAntiBluff(r); !errors.Is(err, ErrBluffPass) {
+		t.Fatalf("expected ErrBluffPass for warnings Pass without actions; got %v", err)
+	}
+}
+
+// TestValidate_PassWithOnlyInfoAssertions — passing info assertions
+// confirm nothing, so at least one passing assertion above info
+// severity is still required.
+func TestValidate_PassWithOnlyInfoAssertions(t *testing.T) {
+	info := passingAssertion()
+	info.Severity = SeverityInfo
+	r := &Result{
+		ChallengeID:     "test-pass-info-only",
+		Status:          StatusPassed,
+		RecordedActions: []string{"action"},
+		Assertions:      []AssertionResult{info, failingAssertion()},
+	}
+	if err := ValidateAntiBluff(r); !errors.Is(err, ErrBluffPass) {
+		t.Fatalf("expected ErrBluffPass for info-only Pass; got %v", err)
+	}
+
+	warning := passingAssertion()
+	warning.Severity = SeverityWarning
+	r.Assertions = append(r.Assertions, warning)
+	if err := ValidateAntiBluff(r); err != nil {
+		t.Fatalf("expected nil once a passing warning assertion is present; got %v", err)
+	}
+}
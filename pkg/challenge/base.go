@@ -22,6 +22,7 @@ type BaseChallenge struct {
 	logger       Logger
 	assertions   AssertionEngine
 	progress     *ProgressReporter
+	artifacts    map[string]string
 }
 
 // NewBaseChallenge creates a BaseChallenge with the given identity
@@ -115,6 +116,7 @@ func (b *BaseChallenge) Configure(config *Config) error {
 		return fmt.Errorf("config must not be nil")
 	}
 	b.config = config
+	b.artifacts = nil
 
 	if err := os.MkdirAll(b.ResultsDir(), 0o755); err != nil {
 		return fmt.Errorf(
@@ -184,6 +186,16 @@ func (b *BaseChallenge) GetEnv(
 	return b.config.GetEnv(key, fallback)
 }
 
+// Input returns an input value injected by the runner from an
+// upstream output (see InputDeclarer).
+func (b *BaseChallenge) Input(name string) (any, bool) {
+	if b.config == nil {
+		return nil, false
+	}
+	v, ok := b.config.Inputs[name]
+	return v, ok
+}
+
 // jsonMarshalIndent is a variable for dependency injection in tests.
 var jsonMarshalIndent = json.MarshalIndent
 
@@ -246,7 +258,10 @@ func (b *BaseChallenge) WriteMarkdownReport(
 }
 
 // ReadDependencyResult reads and unmarshals the result JSON
-// produced by an upstream dependency.
+// produced by an upstream dependency. The configured path may
+// name the result file itself or a results directory, in which
+// case the result.json written by WriteJSONResult is located
+// inside it.
 func (b *BaseChallenge) ReadDependencyResult(
 	depID ID,
 ) (*Result, error) {
@@ -260,6 +275,7 @@ func (b *BaseChallenge) ReadDependencyResult(
 			depID,
 		)
 	}
+	path = dependencyResultPath(path, depID)
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf(
@@ -275,8 +291,34 @@ func (b *BaseChallenge) ReadDependencyResult(
 	return &result, nil
 }
 
+// dependencyResultPath maps a dependency path to the result
+// JSON file it refers to. Files are returned unchanged; for a
+// directory the candidates <dir>/<id>/result.json,
+// <dir>/result.json and <dir>/results/result.json are tried in
+// that order.
+func dependencyResultPath(path string, depID ID) string {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return path
+	}
+	candidates := []string{
+		filepath.Join(path, string(depID), "result.json"),
+		filepath.Join(path, "result.json"),
+		filepath.Join(path, "results", "result.json"),
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return candidates[0]
+}
+
 // EvaluateAssertions uses the AssertionEngine to evaluate all
 // assertions from a Definition against the provided values.
+// Engines implementing ScopedAssertionEngine are given the
+// challenge's AssertionScope; the artifacts they write are
+// added to the Logs of results built by CreateResult.
 func (b *BaseChallenge) EvaluateAssertions(
 	defs []AssertionDef,
 	values map[string]any,
@@ -293,7 +335,37 @@ func (b *BaseChallenge) EvaluateAssertions(
 		}
 		return results
 	}
-	return b.assertions.EvaluateAll(defs, values)
+	scoped, ok := b.assertions.(ScopedAssertionEngine)
+	if !ok {
+		return b.assertions.EvaluateAll(defs, values)
+	}
+	results, artifacts := scoped.EvaluateAllScoped(
+		defs, values, b.AssertionScope(),
+	)
+	for name, path := range artifacts {
+		if b.artifacts == nil {
+			b.artifacts = make(map[string]string)
+		}
+		b.artifacts[name] = path
+	}
+	return results
+}
+
+// AssertionScope returns the scope assertions of this
+// challenge are evaluated in.
+func (b *BaseChallenge) AssertionScope() AssertionScope {
+	scope := AssertionScope{
+		ChallengeID:  b.id,
+		SnapshotsDir: filepath.Join(b.ResultsDir(), "snapshots"),
+		ArtifactsDir: b.LogsDir(),
+	}
+	if b.config != nil {
+		if b.config.SnapshotsDir != "" {
+			scope.SnapshotsDir = b.config.SnapshotsDir
+		}
+		scope.UpdateSnapshots = b.config.UpdateSnapshots
+	}
+	return scope
 }
 
 // CreateResult builds a Result pre-populated with this
@@ -315,7 +387,7 @@ func (b *BaseChallenge) CreateResult(
 		EndTime:       end,
 		Duration:      end.Sub(start),
 		Assertions:    assertions,
-		Metrics:       metrics,
+		Metrics:       withAssertionStats(metrics, assertions),
 		Outputs:       outputs,
 		Logs: LogPaths{
 			ChallengeLog: filepath.Join(
@@ -330,11 +402,54 @@ func (b *BaseChallenge) CreateResult(
 			APIResponses: filepath.Join(
 				b.LogsDir(), "api_responses.log",
 			),
+			Artifacts: copyArtifacts(b.artifacts),
 		},
 		Error: errMsg,
 	}
 }
 
+// withAssertionStats returns metrics with the statistics of
+// statistical assertions added as "<target>.<stat>". metrics
+// is copied before adding and returned as is when there are
+// no statistics.
+func withAssertionStats(
+	metrics map[string]MetricValue,
+	assertions []AssertionResult,
+) map[string]MetricValue {
+	var out map[string]MetricValue
+	for _, a := range assertions {
+		if len(a.Stats) == 0 {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]MetricValue, len(metrics)+len(a.Stats))
+			for k, v := range metrics {
+				out[k] = v
+			}
+		}
+		for stat, v := range a.Stats {
+			name := a.Target + "." + stat
+			out[name] = MetricValue{Name: name, Value: v}
+		}
+	}
+	if out == nil {
+		return metrics
+	}
+	return out
+}
+
+// copyArtifacts copies an artifact map, keeping nil for none.
+func copyArtifacts(artifacts map[string]string) map[string]string {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(artifacts))
+	for k, v := range artifacts {
+		out[k] = v
+	}
+	return out
+}
+
 // logInfo logs at info level if a logger is available.
 func (b *BaseChallenge) logInfo(msg string, args ...any) {
 	if b.logger != nil {
@@ -340,6 +340,61 @@ func TestBaseChallenge_EvaluateAssertions_WithEngine(
 	}
 }
 
+// scopedAssertionEngine records the scope it is called with
+// and reports one artifact.
+type scopedAssertionEngine struct {
+	mockAssertionEngine
+	scope AssertionScope
+}
+
+func (s *scopedAssertionEngine) EvaluateAllScoped(
+	defs []AssertionDef,
+	values map[string]any,
+	scope AssertionScope,
+) ([]AssertionResult, map[string]string) {
+	s.scope = scope
+	return s.EvaluateAll(defs, values), map[string]string{
+		"snapshot:out": filepath.Join(scope.ArtifactsDir, "out.diff"),
+	}
+}
+
+func TestBaseChallenge_EvaluateAssertions_Scoped(t *testing.T) {
+	tmpDir := t.TempDir()
+	b := NewBaseChallenge("scoped", "Scoped", "desc", "unit", nil)
+	engine := &scopedAssertionEngine{}
+	b.SetAssertionEngine(engine)
+	require.NoError(t, b.Configure(&Config{
+		ResultsDir:      filepath.Join(tmpDir, "results"),
+		LogsDir:         filepath.Join(tmpDir, "logs"),
+		UpdateSnapshots: true,
+	}))
+
+	results := b.EvaluateAssertions(
+		[]AssertionDef{{Type: "matches_snapshot", Target: "out"}},
+		map[string]any{"out": "v"},
+	)
+	require.Len(t, results, 1)
+	assert.Equal(t, AssertionScope{
+		ChallengeID:     "scoped",
+		SnapshotsDir:    filepath.Join(tmpDir, "results", "scoped", "snapshots"),
+		ArtifactsDir:    filepath.Join(tmpDir, "logs", "scoped"),
+		UpdateSnapshots: true,
+	}, engine.scope)
+
+	r := b.CreateResult(StatusFailed, time.Now(), results, nil, nil, "")
+	assert.Equal(t, map[string]string{
+		"snapshot:out": filepath.Join(tmpDir, "logs", "scoped", "out.diff"),
+	}, r.Logs.Artifacts)
+
+	require.NoError(t, b.Configure(&Config{
+		ResultsDir:   filepath.Join(tmpDir, "results"),
+		LogsDir:      filepath.Join(tmpDir, "logs"),
+		SnapshotsDir: filepath.Join(tmpDir, "golden"),
+	}))
+	assert.Equal(t, filepath.Join(tmpDir, "golden"), b.AssertionScope().SnapshotsDir)
+	assert.Nil(t, b.CreateResult(StatusPassed, time.Now(), nil, nil, nil, "").Logs.Artifacts)
+}
+
 func TestBaseChallenge_CreateResult(t *testing.T) {
 	tmpDir := t.TempDir()
 	b := NewBaseChallenge(
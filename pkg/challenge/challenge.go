@@ -1,6 +1,9 @@
 package challenge
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ID uniquely identifies a challenge.
 type ID string
@@ -45,6 +48,24 @@ type Challenge interface {
 	Cleanup(ctx context.Context) error
 }
 
+// Tagged is implemented by challenges that carry selection
+// metadata. Challenges backed by a Definition get their tags and
+// labels from it; Go challenges may implement Tagged directly.
+type Tagged interface {
+	// Tags returns free-form tags (e.g., "smoke", "slow").
+	Tags() []string
+
+	// Labels returns key/value labels (e.g., "team").
+	Labels() map[string]string
+}
+
+// TimeoutDeclarer is implemented by challenges that declare
+// their own execution timeout. A positive DeclaredTimeout
+// overrides Config.Timeout and the runner default.
+type TimeoutDeclarer interface {
+	DeclaredTimeout() time.Duration
+}
+
 // Logger defines the minimal logging interface used by challenges.
 // Implementations should be provided by the logging package.
 type Logger interface {
@@ -77,3 +98,33 @@ type AssertionEngine interface {
 		values map[string]any,
 	) []AssertionResult
 }
+
+// AssertionScope carries the per-challenge state needed by
+// assertions such as "matches_snapshot".
+type AssertionScope struct {
+	// ChallengeID identifies the challenge being evaluated.
+	ChallengeID ID
+
+	// SnapshotsDir holds the recorded snapshots.
+	SnapshotsDir string
+
+	// ArtifactsDir receives files written during evaluation,
+	// such as snapshot diffs.
+	ArtifactsDir string
+
+	// UpdateSnapshots rewrites snapshots instead of comparing.
+	UpdateSnapshots bool
+}
+
+// ScopedAssertionEngine is implemented by assertion engines
+// that can evaluate assertions within an AssertionScope. It
+// returns the results and the artifacts written, keyed by name.
+type ScopedAssertionEngine interface {
+	AssertionEngine
+
+	EvaluateAllScoped(
+		assertions []AssertionDef,
+		values map[string]any,
+		scope AssertionScope,
+	) ([]AssertionResult, map[string]string)
+}
@@ -37,6 +37,21 @@ type Config struct {
 	// their result JSON files, allowing challenges to read
 	// outputs from upstream dependencies.
 	Dependencies map[ID]string `json:"dependencies"`
+
+	// Inputs holds input values injected by the runner from
+	// upstream outputs, keyed by input name (see
+	// InputDeclarer).
+	Inputs map[string]any `json:"inputs,omitempty"`
+
+	// SnapshotsDir is the directory holding the recorded
+	// snapshots of "matches_snapshot" assertions. Empty selects
+	// <ResultsDir>/snapshots under the challenge results
+	// directory.
+	SnapshotsDir string `json:"snapshots_dir,omitempty"`
+
+	// UpdateSnapshots rewrites recorded snapshots with the
+	// current values instead of comparing against them.
+	UpdateSnapshots bool `json:"update_snapshots,omitempty"`
 }
 
 // NewConfig creates a Config with sensible defaults.
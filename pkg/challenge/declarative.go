@@ -0,0 +1,609 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Input source identifiers understood by DefinitionChallenge.
+const (
+	InputSourceEnv              = "env"
+	InputSourceConfig           = "config"
+	InputSourceDependencyPrefix = "dependency:"
+)
+
+// DefinitionConfig is the decoded form of
+// Definition.Configuration consumed by DefinitionChallenge.
+//
+// Example:
+//
+//	"configuration": {
+//	  "backend": "shell",
+//	  "params":  {"command": "./scripts/check.sh"},
+//	  "inputs":  {"thread_count": 10}
+//	}
+type DefinitionConfig struct {
+	// Backend is the name of the registered ActionBackend that
+	// performs the challenge action (e.g., "shell").
+	Backend string `json:"backend"`
+
+	// Params holds backend-specific parameters. They are passed
+	// to the backend verbatim.
+	Params json.RawMessage `json:"params,omitempty"`
+
+	// Inputs supplies the values of inputs whose Source is
+	// "config".
+	Inputs map[string]any `json:"inputs,omitempty"`
+}
+
+// ActionRequest carries everything an ActionBackend needs to
+// perform the action of a declarative challenge.
+type ActionRequest struct {
+	// Definition is the challenge definition being executed.
+	Definition *Definition
+
+	// Params holds the backend-specific parameters from the
+	// definition configuration.
+	Params json.RawMessage
+
+	// Inputs holds the resolved input values keyed by input
+	// name. Optional inputs that could not be resolved are
+	// absent.
+	Inputs map[string]any
+
+	// Environment holds the runtime environment from the
+	// challenge Config.
+	Environment map[string]string
+
+	// ResultsDir is the challenge results directory.
+	ResultsDir string
+
+	// LogsDir is the challenge logs directory.
+	LogsDir string
+}
+
+// ActionResult is what an ActionBackend produced.
+type ActionResult struct {
+	// Outputs holds named output values. Values keep their
+	// native type (string, float64, bool, []any, map[string]any)
+	// so assertions can evaluate them directly.
+	Outputs map[string]any
+
+	// Metrics holds named metric values collected by the
+	// backend.
+	Metrics map[string]MetricValue
+
+	// Series holds named sample series collected by the
+	// backend, such as per-request latencies.
+	Series map[string]MetricSeries
+
+	// Assertions holds checks the backend evaluated itself
+	// (e.g., one per script step). They are reported ahead of
+	// the definition's own Assertions.
+	Assertions []AssertionResult
+
+	// Actions describes what the backend actually did. Each
+	// entry is recorded on the Result via RecordAction.
+	Actions []string
+}
+
+// ActionBackend performs the action of a declarative challenge.
+// Implementations must honour ctx cancellation.
+type ActionBackend interface {
+	Run(ctx context.Context, req *ActionRequest) (*ActionResult, error)
+}
+
+// ActionValidator is implemented by ActionBackends that can
+// check preconditions before execution (e.g., the current
+// platform is supported). A non-nil error makes
+// DefinitionChallenge.Validate fail, which the runner reports
+// as skipped.
+type ActionValidator interface {
+	ValidateAction(req *ActionRequest) error
+}
+
+// ActionBackendFunc adapts an ordinary function to the
+// ActionBackend interface.
+type ActionBackendFunc func(
+	ctx context.Context,
+	req *ActionRequest,
+) (*ActionResult, error)
+
+// Run calls f(ctx, req).
+func (f ActionBackendFunc) Run(
+	ctx context.Context,
+	req *ActionRequest,
+) (*ActionResult, error) {
+	return f(ctx, req)
+}
+
+var (
+	actionBackendsMu sync.RWMutex
+	actionBackends   = map[string]ActionBackend{
+		"shell": &ShellActionBackend{},
+	}
+)
+
+// RegisterActionBackend makes an ActionBackend available to
+// definitions under the given name. Returns an error if the
+// name is empty or already registered.
+func RegisterActionBackend(name string, b ActionBackend) error {
+	if name == "" {
+		return fmt.Errorf("action backend name must not be empty")
+	}
+	if b == nil {
+		return fmt.Errorf("action backend %s must not be nil", name)
+	}
+
+	actionBackendsMu.Lock()
+	defer actionBackendsMu.Unlock()
+
+	if _, exists := actionBackends[name]; exists {
+		return fmt.Errorf(
+			"action backend already registered: %s", name,
+		)
+	}
+	actionBackends[name] = b
+	return nil
+}
+
+// LookupActionBackend returns the ActionBackend registered
+// under name.
+func LookupActionBackend(name string) (ActionBackend, bool) {
+	actionBackendsMu.RLock()
+	defer actionBackendsMu.RUnlock()
+	b, ok := actionBackends[name]
+	return b, ok
+}
+
+// ActionBackends returns the sorted names of all registered
+// action backends.
+func ActionBackends() []string {
+	actionBackendsMu.RLock()
+	defer actionBackendsMu.RUnlock()
+	names := make([]string, 0, len(actionBackends))
+	for name := range actionBackends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefinitionChallenge executes a declarative Definition without
+// a dedicated Go type. It resolves the definition's Inputs,
+// runs the ActionBackend named in its configuration, maps the
+// produced outputs and metrics into a values map and evaluates
+// the definition's Assertions against it.
+type DefinitionChallenge struct {
+	BaseChallenge
+
+	def      *Definition
+	settings DefinitionConfig
+	backend  ActionBackend
+	inputs   map[string]any
+}
+
+// NewDefinitionChallenge creates a DefinitionChallenge for def.
+// Returns an error if def is nil, has no ID, or carries a
+// configuration that cannot be decoded.
+func NewDefinitionChallenge(
+	def *Definition,
+) (*DefinitionChallenge, error) {
+	if def == nil {
+		return nil, fmt.Errorf("definition must not be nil")
+	}
+	if def.ID == "" {
+		return nil, fmt.Errorf("definition has no ID")
+	}
+
+	var settings DefinitionConfig
+	if len(def.Configuration) > 0 {
+		if err := json.Unmarshal(
+			def.Configuration, &settings,
+		); err != nil {
+			return nil, fmt.Errorf(
+				"definition %s: parse configuration: %w",
+				def.ID, err,
+			)
+		}
+	}
+
+	return &DefinitionChallenge{
+		BaseChallenge: NewBaseChallenge(
+			def.ID, def.Name, def.Description,
+			def.Category, def.Dependencies,
+		),
+		def:      def,
+		settings: settings,
+	}, nil
+}
+
+// Definition returns the underlying declarative definition.
+func (d *DefinitionChallenge) Definition() *Definition {
+	return d.def
+}
+
+// Settings returns the decoded definition configuration.
+func (d *DefinitionChallenge) Settings() DefinitionConfig {
+	return d.settings
+}
+
+// SetActionBackend overrides the backend named in the
+// definition configuration.
+func (d *DefinitionChallenge) SetActionBackend(b ActionBackend) {
+	d.backend = b
+}
+
+// DeclaredInputs returns the definition inputs. It implements
+// InputDeclarer.
+func (d *DefinitionChallenge) DeclaredInputs() []Input {
+	return d.def.Inputs
+}
+
+// DeclaredTimeout returns the definition timeout, or zero when
+// it is unset or invalid. It implements TimeoutDeclarer.
+func (d *DefinitionChallenge) DeclaredTimeout() time.Duration {
+	if d.def.Timeout == "" {
+		return 0
+	}
+	t, err := time.ParseDuration(d.def.Timeout)
+	if err != nil {
+		return 0
+	}
+	return t
+}
+
+// Inputs returns the inputs resolved by the last Validate or
+// Execute call.
+func (d *DefinitionChallenge) Inputs() map[string]any {
+	return d.inputs
+}
+
+// RetryPolicy returns the retry policy declared by the
+// definition, or nil. It implements Retryable.
+func (d *DefinitionChallenge) RetryPolicy() *RetryPolicy {
+	return d.def.Retry
+}
+
+// Tags returns the definition tags. It implements Tagged.
+func (d *DefinitionChallenge) Tags() []string {
+	return d.def.Tags
+}
+
+// Labels returns the definition labels. It implements Tagged.
+func (d *DefinitionChallenge) Labels() map[string]string {
+	return d.def.Labels
+}
+
+// Validate checks that an action backend is available and that
+// every required input can be resolved.
+func (d *DefinitionChallenge) Validate(ctx context.Context) error {
+	if err := d.BaseChallenge.Validate(ctx); err != nil {
+		return err
+	}
+	backend, err := d.resolveBackend()
+	if err != nil {
+		return err
+	}
+	inputs, err := d.resolveInputs()
+	if err != nil {
+		return err
+	}
+	d.inputs = inputs
+
+	if v, ok := backend.(ActionValidator); ok {
+		if err := v.ValidateAction(d.actionRequest()); err != nil {
+			return fmt.Errorf("challenge %s: %w", d.id, err)
+		}
+	}
+	return nil
+}
+
+// Execute runs the action backend, evaluates the definition
+// assertions against its outputs and metrics, and writes the
+// result JSON so downstream challenges can consume it.
+func (d *DefinitionChallenge) Execute(
+	ctx context.Context,
+) (*Result, error) {
+	start := time.Now()
+
+	backend, err := d.resolveBackend()
+	if err != nil {
+		return nil, err
+	}
+	// Inputs are resolved on every execution: a retry, resumed
+	// or repeated run may see different upstream outputs.
+	inputs, err := d.resolveInputs()
+	if err != nil {
+		return nil, err
+	}
+	d.inputs = inputs
+
+	d.logInfo(
+		"executing definition challenge",
+		"id", d.id, "backend", d.settings.Backend,
+	)
+
+	ar, runErr := backend.Run(ctx, d.actionRequest())
+	if ar == nil {
+		ar = &ActionResult{}
+	}
+	if runErr != nil {
+		result := d.CreateResult(
+			StatusError, start, nil, ar.Metrics, nil,
+			fmt.Sprintf("action backend failed: %v", runErr),
+		)
+		for _, a := range ar.Actions {
+			result.RecordAction(a)
+		}
+		result.RecordAction(fmt.Sprintf(
+			"DefinitionChallenge: backend %q failed for %s",
+			d.settings.Backend, d.id,
+		))
+		return result, nil
+	}
+
+	values := make(map[string]any, len(ar.Outputs)+len(ar.Metrics))
+	outputs := make(map[string]string, len(ar.Outputs))
+	for name, v := range ar.Outputs {
+		values[name] = v
+		outputs[name] = stringifyOutput(v)
+	}
+
+	metrics := make(map[string]MetricValue, len(ar.Metrics))
+	for name, m := range ar.Metrics {
+		metrics[name] = m
+	}
+	series := make(map[string]MetricSeries, len(ar.Series))
+	for name, s := range ar.Series {
+		series[name] = s
+	}
+	// Declared metrics may also be reported as numeric outputs,
+	// or as lists of numbers for sample series.
+	for _, name := range d.def.Metrics {
+		if _, ok := metrics[name]; ok {
+			continue
+		}
+		if _, ok := series[name]; ok {
+			continue
+		}
+		if f, ok := numericOutput(ar.Outputs[name]); ok {
+			metrics[name] = MetricValue{Name: name, Value: f}
+		} else if samples, ok := numericSeries(ar.Outputs[name]); ok {
+			series[name] = MetricSeries{Name: name, Samples: samples}
+		}
+	}
+	for name, m := range metrics {
+		if _, ok := values[name]; !ok {
+			values[name] = m.Value
+		}
+	}
+	for name, s := range series {
+		if _, ok := values[name]; !ok {
+			values[name] = s.Samples
+		}
+	}
+
+	assertions := append(
+		[]AssertionResult{}, ar.Assertions...,
+	)
+	assertions = append(
+		assertions,
+		d.EvaluateAssertions(d.def.Assertions, values)...,
+	)
+	result := d.CreateResult(
+		AssertionStatus(assertions), start, assertions, metrics, outputs, "",
+	)
+	if len(series) > 0 {
+		result.Series = series
+	}
+	if invalid := ValidateOutputs(
+		d.def.Outputs, result, d.ResultsDir(),
+	); len(invalid) > 0 {
+		result.Assertions = append(result.Assertions, invalid...)
+		result.Status = StatusFailed
+	}
+	for _, a := range ar.Actions {
+		result.RecordAction(a)
+	}
+	result.RecordAction(fmt.Sprintf(
+		"DefinitionChallenge: ran backend %q for %s "+
+			"(%d outputs, %d metrics)",
+		d.settings.Backend, d.id, len(outputs), len(metrics),
+	))
+
+	if err := d.WriteJSONResult(result); err != nil {
+		d.logError("failed to write result", "err", err)
+	}
+
+	return result, nil
+}
+
+// actionRequest builds the request handed to the backend.
+func (d *DefinitionChallenge) actionRequest() *ActionRequest {
+	return &ActionRequest{
+		Definition:  d.def,
+		Params:      d.settings.Params,
+		Inputs:      d.inputs,
+		Environment: d.environment(),
+		ResultsDir:  d.ResultsDir(),
+		LogsDir:     d.LogsDir(),
+	}
+}
+
+// environment returns the config environment overlaid with the
+// definition environment.
+func (d *DefinitionChallenge) environment() map[string]string {
+	var base map[string]string
+	if d.config != nil {
+		base = d.config.Environment
+	}
+	if len(d.def.Environment) == 0 {
+		return base
+	}
+	env := make(map[string]string, len(base)+len(d.def.Environment))
+	for k, v := range base {
+		env[k] = v
+	}
+	for k, v := range d.def.Environment {
+		env[k] = v
+	}
+	return env
+}
+
+// resolveBackend returns the explicitly set backend or the one
+// registered under the configured backend name.
+func (d *DefinitionChallenge) resolveBackend() (ActionBackend, error) {
+	if d.backend != nil {
+		return d.backend, nil
+	}
+	if d.settings.Backend == "" {
+		return nil, fmt.Errorf(
+			"challenge %s: no action backend configured", d.id,
+		)
+	}
+	b, ok := LookupActionBackend(d.settings.Backend)
+	if !ok {
+		return nil, fmt.Errorf(
+			"challenge %s: unknown action backend: %s",
+			d.id, d.settings.Backend,
+		)
+	}
+	return b, nil
+}
+
+// resolveInputs resolves every declared input from its source.
+// Unresolved optional inputs are omitted; an unresolved
+// required input is an error.
+func (d *DefinitionChallenge) resolveInputs() (map[string]any, error) {
+	inputs := make(map[string]any, len(d.def.Inputs))
+	for _, in := range d.def.Inputs {
+		v, ok, err := d.resolveInput(in)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"challenge %s: input %s: %w", d.id, in.Name, err,
+			)
+		}
+		if !ok {
+			if in.Required {
+				return nil, fmt.Errorf(
+					"challenge %s: required input %s "+
+						"(source %s) not resolved",
+					d.id, in.Name, in.Source,
+				)
+			}
+			continue
+		}
+		inputs[in.Name] = v
+	}
+	return inputs, nil
+}
+
+// resolveInput looks up a single input value from its source.
+func (d *DefinitionChallenge) resolveInput(
+	in Input,
+) (any, bool, error) {
+	switch {
+	case in.Source == InputSourceEnv:
+		env := d.environment()
+		for _, key := range []string{in.Name, strings.ToUpper(in.Name)} {
+			if v, ok := env[key]; ok {
+				return v, true, nil
+			}
+		}
+		for _, key := range []string{in.Name, strings.ToUpper(in.Name)} {
+			if v, ok := os.LookupEnv(key); ok {
+				return v, true, nil
+			}
+		}
+		return nil, false, nil
+
+	case in.Source == InputSourceConfig:
+		v, ok := d.settings.Inputs[in.Name]
+		return v, ok, nil
+
+	case strings.HasPrefix(in.Source, InputSourceDependencyPrefix):
+		depID, output, _ := ParseDependencySource(
+			in.Source, d.def.Dependencies...,
+		)
+		if depID == "" {
+			return nil, false, fmt.Errorf(
+				"dependency source has no challenge ID",
+			)
+		}
+		// Values injected by the runner take precedence over
+		// reading the upstream result from disk.
+		if d.config != nil {
+			if v, ok := d.config.Inputs[in.Name]; ok {
+				return v, true, nil
+			}
+		}
+		if output == "" {
+			output = in.Name
+		}
+		dep, err := d.ReadDependencyResult(depID)
+		if err != nil {
+			return nil, false, nil
+		}
+		v, ok := dep.OutputValue(output)
+		return v, ok, nil
+	}
+
+	return nil, false, fmt.Errorf(
+		"unsupported input source: %q", in.Source,
+	)
+}
+
+// stringifyOutput renders an output value for Result.Outputs.
+// Strings are kept verbatim; everything else is JSON encoded.
+func stringifyOutput(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// numericSeries converts a non-empty list of numbers to
+// samples.
+func numericSeries(v any) ([]float64, bool) {
+	if samples, ok := v.([]float64); ok {
+		return samples, len(samples) > 0
+	}
+	list, ok := v.([]any)
+	if !ok || len(list) == 0 {
+		return nil, false
+	}
+	samples := make([]float64, len(list))
+	for i, item := range list {
+		f, ok := numericOutput(item)
+		if !ok {
+			return nil, false
+		}
+		samples[i] = f
+	}
+	return samples, true
+}
+
+// numericOutput reports whether v is a number and returns it as
+// float64.
+func numericOutput(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package challenge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// shellActionParams are the "params" accepted by the "shell"
+// action backend.
+type shellActionParams struct {
+	// Command is run with "bash -c".
+	Command string `json:"command"`
+
+	// WorkDir is the working directory. Relative paths are
+	// resolved against PROJECT_ROOT from the environment when
+	// set.
+	WorkDir string `json:"work_dir,omitempty"`
+}
+
+// ShellActionBackend is the built-in "shell" ActionBackend. It
+// runs params.command through bash and exposes "stdout",
+// "stderr" and "exit_code" as outputs. When stdout is a JSON
+// object, each of its top-level keys becomes an additional
+// output so scripts can report structured values.
+//
+// Resolved inputs are exported to the command as INPUT_<NAME>
+// environment variables (name upper-cased).
+type ShellActionBackend struct{}
+
+// Run executes the configured shell command.
+func (s *ShellActionBackend) Run(
+	ctx context.Context,
+	req *ActionRequest,
+) (*ActionResult, error) {
+	var params shellActionParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("parse shell params: %w", err)
+		}
+	}
+	if strings.TrimSpace(params.Command) == "" {
+		return nil, fmt.Errorf("shell backend: command is required")
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", params.Command)
+	cmd.WaitDelay = 2 * time.Second
+
+	if params.WorkDir != "" {
+		dir := params.WorkDir
+		if root := req.Environment["PROJECT_ROOT"]; root != "" &&
+			!filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		cmd.Dir = dir
+	}
+
+	cmd.Env = os.Environ()
+	keys := make([]string, 0, len(req.Environment))
+	for k := range req.Environment {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(
+			cmd.Env, fmt.Sprintf("%s=%s", k, req.Environment[k]),
+		)
+	}
+	names := make([]string, 0, len(req.Inputs))
+	for name := range req.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cmd.Env = append(cmd.Env, fmt.Sprintf(
+			"INPUT_%s=%s", strings.ToUpper(name),
+			stringifyOutput(req.Inputs[name]),
+		))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := runCommand(cmd)
+
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || ctx.Err() != nil {
+			return &ActionResult{
+				Actions: []string{fmt.Sprintf(
+					"shell: bash -c %q failed to run", params.Command,
+				)},
+			}, fmt.Errorf("shell backend: %w", err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	out := strings.TrimSpace(stdout.String())
+	outputs := map[string]any{}
+	var structured map[string]any
+	if strings.HasPrefix(out, "{") &&
+		json.Unmarshal([]byte(out), &structured) == nil {
+		for k, v := range structured {
+			outputs[k] = v
+		}
+	}
+	outputs["stdout"] = out
+	outputs["stderr"] = strings.TrimSpace(stderr.String())
+	outputs["exit_code"] = exitCode
+
+	return &ActionResult{
+		Outputs: outputs,
+		Actions: []string{fmt.Sprintf(
+			"shell: bash -c %q exit_code=%d", params.Command, exitCode,
+		)},
+	}, nil
+}
@@ -0,0 +1,507 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// valueEngine is an AssertionEngine that passes "equals"
+// assertions when fmt.Sprint of the value matches the expected
+// value and fails every other type.
+type valueEngine struct{}
+
+func (valueEngine) Evaluate(a AssertionDef, v any) AssertionResult {
+	passed := a.Type == "equals" &&
+		fmt.Sprint(v) == fmt.Sprint(a.Value)
+	return AssertionResult{
+		Type: a.Type, Target: a.Target,
+		Expected: a.Value, Actual: v, Passed: passed,
+	}
+}
+
+func (e valueEngine) EvaluateAll(
+	defs []AssertionDef, values map[string]any,
+) []AssertionResult {
+	out := make([]AssertionResult, len(defs))
+	for i, d := range defs {
+		v, ok := values[d.Target]
+		if !ok {
+			out[i] = AssertionResult{
+				Type: d.Type, Target: d.Target,
+				Message: "target not found",
+			}
+			continue
+		}
+		out[i] = e.Evaluate(d, v)
+	}
+	return out
+}
+
+func newDeclarativeConfig(t *testing.T, id ID) *Config {
+	t.Helper()
+	dir := t.TempDir()
+	return &Config{
+		ChallengeID:  id,
+		ResultsDir:   filepath.Join(dir, "results"),
+		LogsDir:      filepath.Join(dir, "logs"),
+		Environment:  map[string]string{},
+		Dependencies: map[ID]string{},
+	}
+}
+
+func mustDefinitionChallenge(
+	t *testing.T, def *Definition,
+) *DefinitionChallenge {
+	t.Helper()
+	dc, err := NewDefinitionChallenge(def)
+	require.NoError(t, err)
+	dc.SetAssertionEngine(valueEngine{})
+	return dc
+}
+
+func TestNewDefinitionChallenge_Errors(t *testing.T) {
+	_, err := NewDefinitionChallenge(nil)
+	assert.Error(t, err)
+
+	_, err = NewDefinitionChallenge(&Definition{Name: "x"})
+	assert.Error(t, err)
+
+	_, err = NewDefinitionChallenge(&Definition{
+		ID:            "bad",
+		Configuration: json.RawMessage(`[1,2]`),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parse configuration")
+}
+
+func TestDefinitionChallenge_Identity(t *testing.T) {
+	def := &Definition{
+		ID: "def-1", Name: "Def", Description: "d",
+		Category: "core", Dependencies: []ID{"up"},
+		Configuration: json.RawMessage(`{"backend":"shell"}`),
+	}
+	dc := mustDefinitionChallenge(t, def)
+	assert.Equal(t, ID("def-1"), dc.ID())
+	assert.Equal(t, "core", dc.Category())
+	assert.Equal(t, []ID{"up"}, dc.Dependencies())
+	assert.Equal(t, "shell", dc.Settings().Backend)
+	assert.Same(t, def, dc.Definition())
+}
+
+func TestDefinitionChallenge_Tagged(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:     "def-1",
+		Tags:   []string{"smoke"},
+		Labels: map[string]string{"team": "core"},
+	})
+	var tagged Tagged = dc
+	assert.Equal(t, []string{"smoke"}, tagged.Tags())
+	assert.Equal(t, "core", tagged.Labels()["team"])
+}
+
+func TestDefinitionChallenge_Validate_NoBackend(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{ID: "nb"})
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "nb")))
+	err := dc.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no action backend")
+}
+
+func TestDefinitionChallenge_Validate_UnknownBackend(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:            "ub",
+		Configuration: json.RawMessage(`{"backend":"nope"}`),
+	})
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "ub")))
+	err := dc.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown action backend")
+}
+
+func TestDefinitionChallenge_Validate_RequiredInputMissing(
+	t *testing.T,
+) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "ri",
+		Inputs: []Input{
+			{Name: "threads", Source: "config", Required: true},
+		},
+		Configuration: json.RawMessage(`{"backend":"shell"}`),
+	})
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "ri")))
+	err := dc.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required input threads")
+}
+
+func TestDefinitionChallenge_Validate_UnsupportedSource(
+	t *testing.T,
+) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:            "us",
+		Inputs:        []Input{{Name: "x", Source: "magic"}},
+		Configuration: json.RawMessage(`{"backend":"shell"}`),
+	})
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "us")))
+	err := dc.Validate(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported input source")
+}
+
+func TestDefinitionChallenge_ResolvesInputs(t *testing.T) {
+	cfg := newDeclarativeConfig(t, "ins")
+
+	// Upstream result written where a runner would put it.
+	upDir := t.TempDir()
+	upResult := &Result{
+		ChallengeID: "up",
+		Outputs:     map[string]string{"apk_path": "/tmp/app.apk"},
+	}
+	data, err := json.Marshal(upResult)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(upDir, "up"), 0o755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(upDir, "up", "result.json"), data, 0o644,
+	))
+	cfg.Dependencies["up"] = upDir
+	cfg.Environment["PROJECT_ROOT"] = "/src"
+	t.Setenv("DECLARATIVE_TEST_TOKEN", "from-process")
+
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "ins",
+		Inputs: []Input{
+			{Name: "project_root", Source: "env", Required: true},
+			{Name: "declarative_test_token", Source: "env"},
+			{Name: "threads", Source: "config", Required: true},
+			{Name: "apk_path", Source: "dependency:up", Required: true},
+			{Name: "optional", Source: "config"},
+		},
+		Configuration: json.RawMessage(
+			`{"backend":"shell","inputs":{"threads":10}}`,
+		),
+	})
+	require.NoError(t, dc.Configure(cfg))
+	require.NoError(t, dc.Validate(context.Background()))
+
+	inputs := dc.Inputs()
+	assert.Equal(t, "/src", inputs["project_root"])
+	assert.Equal(t, "from-process", inputs["declarative_test_token"])
+	assert.Equal(t, float64(10), inputs["threads"])
+	assert.Equal(t, "/tmp/app.apk", inputs["apk_path"])
+	_, hasOptional := inputs["optional"]
+	assert.False(t, hasOptional)
+}
+
+func TestDefinitionChallenge_Execute_ResolvesInputsEachRun(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:           "rerun",
+		Dependencies: []ID{"up"},
+		Inputs: []Input{
+			{Name: "build", Source: "dependency:up", Required: true},
+		},
+	})
+	var seen []any
+	dc.SetActionBackend(ActionBackendFunc(func(
+		_ context.Context, req *ActionRequest,
+	) (*ActionResult, error) {
+		seen = append(seen, req.Inputs["build"])
+		return &ActionResult{}, nil
+	}))
+
+	// A rerun (or retry, or resume) sees the upstream output of
+	// its own run, not the one resolved first.
+	for _, build := range []string{"1", "2"} {
+		cfg := newDeclarativeConfig(t, "rerun")
+		cfg.Inputs = map[string]any{"build": build}
+		require.NoError(t, dc.Configure(cfg))
+		require.NoError(t, dc.Validate(context.Background()))
+		cfg.Inputs["build"] = build + "-retry"
+		_, err := dc.Execute(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []any{"1-retry", "2-retry"}, seen)
+	assert.Equal(t, "2-retry", dc.Inputs()["build"])
+}
+
+func TestDefinitionChallenge_Execute_EvaluatesAssertions(
+	t *testing.T,
+) {
+	var got *ActionRequest
+	backend := ActionBackendFunc(func(
+		_ context.Context, req *ActionRequest,
+	) (*ActionResult, error) {
+		got = req
+		return &ActionResult{
+			Outputs: map[string]any{
+				"status":  "ok",
+				"threads": float64(10),
+			},
+			Metrics: map[string]MetricValue{
+				"latency_ms": {Name: "latency_ms", Value: 42, Unit: "ms"},
+			},
+			Actions: []string{"backend: did work"},
+		}, nil
+	})
+
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:      "exec",
+		Name:    "Exec",
+		Metrics: []string{"threads"},
+		Assertions: []AssertionDef{
+			{Type: "equals", Target: "status", Value: "ok"},
+			{Type: "equals", Target: "latency_ms", Value: 42},
+			{Type: "equals", Target: "threads", Value: 10},
+		},
+		Configuration: json.RawMessage(
+			`{"backend":"custom","params":{"k":"v"}}`,
+		),
+	})
+	dc.SetActionBackend(backend)
+	cfg := newDeclarativeConfig(t, "exec")
+	require.NoError(t, dc.Configure(cfg))
+	require.NoError(t, dc.Validate(context.Background()))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusPassed, result.Status)
+	require.Len(t, result.Assertions, 3)
+	assert.True(t, result.AllPassed())
+	assert.Equal(t, "ok", result.Outputs["status"])
+	assert.Equal(t, "10", result.Outputs["threads"])
+	assert.Equal(t, float64(10), result.Metrics["threads"].Value)
+	assert.Equal(t, "ms", result.Metrics["latency_ms"].Unit)
+	assert.Contains(t, result.RecordedActions, "backend: did work")
+	assert.NoError(t, ValidateAntiBluff(result))
+
+	require.NotNil(t, got)
+	assert.JSONEq(t, `{"k":"v"}`, string(got.Params))
+
+	_, statErr := os.Stat(
+		filepath.Join(dc.ResultsDir(), "result.json"),
+	)
+	assert.NoError(t, statErr)
+}
+
+func TestDefinitionChallenge_Execute_FailedAssertion(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "fail",
+		Assertions: []AssertionDef{
+			{Type: "equals", Target: "status", Value: "ok"},
+		},
+	})
+	dc.SetActionBackend(ActionBackendFunc(func(
+		context.Context, *ActionRequest,
+	) (*ActionResult, error) {
+		return &ActionResult{
+			Outputs: map[string]any{"status": "degraded"},
+		}, nil
+	}))
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "fail")))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, result.Status)
+}
+
+// seriesEngine passes every assertion whose value is a list
+// and reports its length as a statistic.
+type seriesEngine struct{ valueEngine }
+
+func (seriesEngine) EvaluateAll(
+	defs []AssertionDef, values map[string]any,
+) []AssertionResult {
+	out := make([]AssertionResult, len(defs))
+	for i, d := range defs {
+		n := -1
+		switch v := values[d.Target].(type) {
+		case []float64:
+			n = len(v)
+		case []any:
+			n = len(v)
+		}
+		out[i] = AssertionResult{
+			Type: d.Type, Target: d.Target, Passed: n > 0,
+			Stats: map[string]float64{"count": float64(n)},
+		}
+	}
+	return out
+}
+
+func TestDefinitionChallenge_Execute_SampleSeries(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:      "series",
+		Metrics: []string{"latency_ms", "status"},
+		Assertions: []AssertionDef{
+			{Type: "p95_below", Target: "latency_ms", Value: 50},
+			{Type: "p95_below", Target: "rps", Value: 50},
+		},
+	})
+	dc.SetAssertionEngine(seriesEngine{})
+	dc.SetActionBackend(ActionBackendFunc(func(
+		context.Context, *ActionRequest,
+	) (*ActionResult, error) {
+		return &ActionResult{
+			Outputs: map[string]any{
+				"latency_ms": []any{float64(12), float64(15), float64(30)},
+				"status":     []any{"ok"},
+			},
+			Series: map[string]MetricSeries{
+				"rps": {Name: "rps", Samples: []float64{90, 110}, Unit: "req/s"},
+			},
+			Actions: []string{"backend: sampled"},
+		}, nil
+	}))
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "series")))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusPassed, result.Status)
+	assert.Equal(t, map[string]MetricSeries{
+		"latency_ms": {Name: "latency_ms", Samples: []float64{12, 15, 30}},
+		"rps":        {Name: "rps", Samples: []float64{90, 110}, Unit: "req/s"},
+	}, result.Series)
+	assert.Equal(t, float64(3), result.Metrics["latency_ms.count"].Value)
+	assert.Equal(t, float64(2), result.Metrics["rps.count"].Value)
+	assert.NotContains(t, result.Metrics, "status")
+}
+
+func TestDefinitionChallenge_Execute_BackendError(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{ID: "berr"})
+	dc.SetActionBackend(ActionBackendFunc(func(
+		context.Context, *ActionRequest,
+	) (*ActionResult, error) {
+		return nil, fmt.Errorf("connection refused")
+	}))
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "berr")))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusError, result.Status)
+	assert.Contains(t, result.Error, "connection refused")
+	assert.NotEmpty(t, result.RecordedActions)
+}
+
+func TestShellActionBackend_StructuredStdout(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "sh",
+		Inputs: []Input{
+			{Name: "expected", Source: "config", Required: true},
+		},
+		Assertions: []AssertionDef{
+			{Type: "equals", Target: "echoed", Value: "hello"},
+			{Type: "equals", Target: "count", Value: 3},
+			{Type: "equals", Target: "exit_code", Value: 0},
+		},
+		Configuration: json.RawMessage(`{
+			"backend": "shell",
+			"params": {"command": "printf '{\"echoed\":\"%s\",\"count\":3}' \"$INPUT_EXPECTED\""},
+			"inputs": {"expected": "hello"}
+		}`),
+	})
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "sh")))
+	require.NoError(t, dc.Validate(context.Background()))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusPassed, result.Status, result.Assertions)
+	assert.Equal(t, "0", result.Outputs["exit_code"])
+}
+
+func TestShellActionBackend_NonZeroExit(t *testing.T) {
+	b := &ShellActionBackend{}
+	ar, err := b.Run(context.Background(), &ActionRequest{
+		Params: json.RawMessage(`{"command":"echo out; exit 3"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, ar.Outputs["exit_code"])
+	assert.Equal(t, "out", ar.Outputs["stdout"])
+	require.Len(t, ar.Actions, 1)
+}
+
+func TestShellActionBackend_SortedEnvironment(t *testing.T) {
+	originalRunner := runCommand
+	t.Cleanup(func() { runCommand = originalRunner })
+	var env []string
+	runCommand = func(cmd *exec.Cmd) error {
+		env = cmd.Env[len(cmd.Env)-4:]
+		return nil
+	}
+
+	b := &ShellActionBackend{}
+	_, err := b.Run(context.Background(), &ActionRequest{
+		Params:      json.RawMessage(`{"command":"true"}`),
+		Environment: map[string]string{"ZONE": "eu", "APP": "web"},
+		Inputs:      map[string]any{"version": "1.2.0", "artifact": "app.tgz"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"APP=web", "ZONE=eu",
+		"INPUT_ARTIFACT=app.tgz", "INPUT_VERSION=1.2.0",
+	}, env)
+}
+
+func TestShellActionBackend_MissingCommand(t *testing.T) {
+	b := &ShellActionBackend{}
+	_, err := b.Run(context.Background(), &ActionRequest{})
+	assert.Error(t, err)
+}
+
+func TestRegisterActionBackend(t *testing.T) {
+	noop := ActionBackendFunc(func(
+		context.Context, *ActionRequest,
+	) (*ActionResult, error) {
+		return &ActionResult{}, nil
+	})
+
+	assert.Error(t, RegisterActionBackend("", noop))
+	assert.Error(t, RegisterActionBackend("x", nil))
+	assert.Error(t, RegisterActionBackend("shell", noop))
+
+	require.NoError(t, RegisterActionBackend("test-noop", noop))
+	_, ok := LookupActionBackend("test-noop")
+	assert.True(t, ok)
+	assert.Contains(t, ActionBackends(), "test-noop")
+}
+
+func TestDefinitionChallenge_TimeoutAndEnvironment(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID:          "env",
+		Timeout:     "90s",
+		Environment: map[string]string{"HOST": "ci", "MODE": "fast"},
+		Inputs:      []Input{{Name: "host", Source: InputSourceEnv}},
+	})
+	assert.Equal(t, 90*time.Second, dc.DeclaredTimeout())
+
+	var seen map[string]string
+	dc.SetActionBackend(ActionBackendFunc(func(
+		_ context.Context, req *ActionRequest,
+	) (*ActionResult, error) {
+		seen = req.Environment
+		return &ActionResult{}, nil
+	}))
+	cfg := newDeclarativeConfig(t, "env")
+	cfg.Environment["HOST"] = "local"
+	cfg.Environment["USER"] = "me"
+	require.NoError(t, dc.Configure(cfg))
+	require.NoError(t, dc.Validate(context.Background()))
+	assert.Equal(t, "ci", dc.Inputs()["host"])
+
+	_, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"HOST": "ci", "MODE": "fast", "USER": "me",
+	}, seen)
+	assert.Equal(t, "local", cfg.Environment["HOST"])
+
+	bad := mustDefinitionChallenge(t, &Definition{ID: "bad", Timeout: "soon"})
+	assert.Zero(t, bad.DeclaredTimeout())
+}
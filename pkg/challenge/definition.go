@@ -17,6 +17,20 @@ type Definition struct {
 	Assertions        []AssertionDef  `json:"assertions"`
 	Metrics           []string        `json:"metrics"`
 	Configuration     json.RawMessage `json:"configuration,omitempty"`
+	Retry             *RetryPolicy    `json:"retry,omitempty"`
+
+	// Timeout bounds a single execution (e.g., "2m"). It
+	// takes precedence over the config and runner timeouts.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Environment holds values added to the challenge
+	// environment; they override Config.Environment.
+	Environment map[string]string `json:"environment,omitempty"`
+
+	// Tags and Labels are free-form selection metadata (e.g.,
+	// tags "smoke", "slow"; label "team": "payments").
+	Tags   []string          `json:"tags,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Input describes a named input parameter for a challenge.
@@ -24,8 +38,9 @@ type Input struct {
 	// Name is the parameter name.
 	Name string `json:"name"`
 
-	// Source describes where the input comes from (e.g., "env",
-	// "dependency:<id>", "config").
+	// Source describes where the input comes from: "env",
+	// "config", "dependency:<id>" (the output named like the
+	// input) or "dependency:<id>.<output>".
 	Source string `json:"source"`
 
 	// Required indicates whether the input must be present
@@ -38,8 +53,9 @@ type Output struct {
 	// Name is the output identifier.
 	Name string `json:"name"`
 
-	// Type describes the output format (e.g., "string",
-	// "json", "file").
+	// Type is the output type: "string", "number", "bool",
+	// "json" or "file". Produced outputs are checked against
+	// it (see ValidateOutputs).
 	Type string `json:"type"`
 
 	// Description explains what this output represents.
@@ -60,9 +76,30 @@ type AssertionDef struct {
 	Value any `json:"value,omitempty"`
 
 	// Values holds expected values for multi-value assertions
-	// (e.g., "one_of").
+	// (e.g., "one_of", "between").
 	Values []any `json:"values,omitempty"`
 
+	// Tolerance is the absolute tolerance of numeric
+	// comparisons such as "equals" and "approx".
+	Tolerance any `json:"tolerance,omitempty"`
+
+	// RelativeTolerance is a tolerance as a fraction of the
+	// expected value.
+	RelativeTolerance float64 `json:"relative_tolerance,omitempty"`
+
+	// Assertions holds the sub-assertions of composite types
+	// such as "all_of", "any_of" and "not".
+	Assertions []AssertionDef `json:"assertions,omitempty"`
+
+	// Ignore lists paths skipped when comparing JSON snapshots
+	// in "matches_snapshot" assertions.
+	Ignore []string `json:"ignore,omitempty"`
+
+	// Severity is one of the Severity* constants; empty means
+	// SeverityError. Failed warning and info assertions are
+	// reported but do not fail the challenge.
+	Severity string `json:"severity,omitempty"`
+
 	// Message is a human-readable description shown on failure.
 	Message string `json:"message"`
 }
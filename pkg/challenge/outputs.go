@@ -0,0 +1,201 @@
+package challenge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Output types understood by ValidateOutputs. Outputs of any
+// other type are kept as strings.
+const (
+	OutputTypeString = "string"
+	OutputTypeNumber = "number"
+	OutputTypeBool   = "bool"
+	OutputTypeJSON   = "json"
+	OutputTypeFile   = "file"
+)
+
+// InputDeclarer is implemented by challenges that declare named
+// inputs. The runner resolves inputs whose source is
+// "dependency:<id>.<output>" from upstream results and injects
+// them into Config.Inputs before Configure.
+type InputDeclarer interface {
+	DeclaredInputs() []Input
+}
+
+// ParseDependencySource splits a "dependency:<id>.<output>"
+// input source. The output is empty for the short form
+// "dependency:<id>", in which case the input name is used. ok
+// is false when source is not a dependency source.
+//
+// Challenge IDs may contain dots, so the declared dependencies
+// deps are matched first: the longest of them that the source
+// names, alone or followed by "." and an output, is the ID.
+// Otherwise the source is split at its last dot.
+func ParseDependencySource(
+	source string,
+	deps ...ID,
+) (depID ID, output string, ok bool) {
+	rest, ok := strings.CutPrefix(source, InputSourceDependencyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	best := -1
+	for i, dep := range deps {
+		d := string(dep)
+		if d == "" || (rest != d && !strings.HasPrefix(rest, d+".")) {
+			continue
+		}
+		if best < 0 || len(d) > len(deps[best]) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		depID = deps[best]
+		output = strings.TrimPrefix(rest[len(depID):], ".")
+		return depID, output, true
+	}
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		return ID(rest[:i]), rest[i+1:], true
+	}
+	return ID(rest), "", true
+}
+
+// ConvertOutput converts the string form of an output to the
+// Go value of typ: float64 for number, bool for bool, the
+// decoded value for json and the path for file. Unknown types
+// are returned as strings.
+func ConvertOutput(typ, raw string) (any, error) {
+	switch strings.ToLower(typ) {
+	case OutputTypeNumber:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return nil, fmt.Errorf("not a number: %q", raw)
+		}
+		return f, nil
+	case OutputTypeBool, "boolean":
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("not a bool: %q", raw)
+		}
+		return b, nil
+	case OutputTypeJSON:
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return v, nil
+	}
+	return raw, nil
+}
+
+// ValidateOutputs converts the produced outputs declared in
+// decls to their typed values and stores them in
+// result.TypedOutputs. Relative file outputs are resolved
+// against baseDir and must exist. Declared outputs that were
+// not produced are ignored. One failed AssertionResult of type
+// "output_type" is returned per invalid output.
+func ValidateOutputs(
+	decls []Output,
+	result *Result,
+	baseDir string,
+) []AssertionResult {
+	var failures []AssertionResult
+	for _, decl := range decls {
+		raw, ok := result.Outputs[decl.Name]
+		if !ok {
+			continue
+		}
+		v, err := ConvertOutput(decl.Type, raw)
+		if err == nil && strings.EqualFold(decl.Type, OutputTypeFile) {
+			v, err = resolveFileOutput(raw, baseDir)
+		}
+		if err != nil {
+			failures = append(failures, AssertionResult{
+				Type:     "output_type",
+				Target:   decl.Name,
+				Expected: decl.Type,
+				Actual:   raw,
+				Message: fmt.Sprintf(
+					"output %s is not a valid %s: %v",
+					decl.Name, decl.Type, err,
+				),
+			})
+			continue
+		}
+		if result.TypedOutputs == nil {
+			result.TypedOutputs = make(map[string]any)
+		}
+		result.TypedOutputs[decl.Name] = v
+	}
+	return failures
+}
+
+// resolveFileOutput checks that a file artifact exists and
+// returns its path.
+func resolveFileOutput(path, baseDir string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	if !filepath.IsAbs(path) && baseDir != "" {
+		if _, err := os.Stat(path); err != nil {
+			path = filepath.Join(baseDir, path)
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("file artifact: %w", err)
+	}
+	return path, nil
+}
+
+// OutputValue returns the typed value of a named output when one
+// was recorded, otherwise its string form.
+func (r *Result) OutputValue(name string) (any, bool) {
+	if v, ok := r.TypedOutputs[name]; ok {
+		return v, true
+	}
+	v, ok := r.Outputs[name]
+	return v, ok
+}
+
+// ResolveDependencyInputs resolves the inputs whose source is a
+// dependency from the upstream results. Inputs with other
+// sources are ignored. A required input whose upstream result
+// or output is missing is an error.
+func ResolveDependencyInputs(
+	inputs []Input,
+	upstream map[ID]*Result,
+) (map[string]any, error) {
+	ids := make([]ID, 0, len(upstream))
+	for id := range upstream {
+		ids = append(ids, id)
+	}
+	resolved := make(map[string]any)
+	for _, in := range inputs {
+		depID, output, ok := ParseDependencySource(in.Source, ids...)
+		if !ok {
+			continue
+		}
+		if output == "" {
+			output = in.Name
+		}
+		if res := upstream[depID]; res != nil {
+			if v, ok := res.OutputValue(output); ok {
+				resolved[in.Name] = v
+				continue
+			}
+		}
+		if in.Required {
+			return nil, fmt.Errorf(
+				"required input %s (source %s) not resolved: "+
+					"dependency %s produced no output %s",
+				in.Name, in.Source, depID, output,
+			)
+		}
+	}
+	return resolved, nil
+}
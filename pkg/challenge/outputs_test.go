@@ -0,0 +1,223 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== ParseDependencySource tests =====
+
+func TestParseDependencySource(t *testing.T) {
+	id, out, ok := ParseDependencySource("dependency:build.apk_path")
+	assert.True(t, ok)
+	assert.Equal(t, ID("build"), id)
+	assert.Equal(t, "apk_path", out)
+
+	id, out, ok = ParseDependencySource("dependency:build")
+	assert.True(t, ok)
+	assert.Equal(t, ID("build"), id)
+	assert.Empty(t, out)
+
+	id, out, ok = ParseDependencySource("dependency:v1.2.build.out")
+	assert.True(t, ok)
+	assert.Equal(t, ID("v1.2.build"), id)
+	assert.Equal(t, "out", out)
+
+	_, _, ok = ParseDependencySource("env")
+	assert.False(t, ok)
+}
+
+func TestParseDependencySource_DeclaredDependencies(t *testing.T) {
+	deps := []ID{"a", "a.b", "v1.2"}
+
+	id, out, ok := ParseDependencySource("dependency:a.b", deps...)
+	assert.True(t, ok)
+	assert.Equal(t, ID("a.b"), id)
+	assert.Empty(t, out)
+
+	id, out, _ = ParseDependencySource("dependency:a.b.apk_path", deps...)
+	assert.Equal(t, ID("a.b"), id)
+	assert.Equal(t, "apk_path", out)
+
+	id, out, _ = ParseDependencySource("dependency:a.c", deps...)
+	assert.Equal(t, ID("a"), id)
+	assert.Equal(t, "c", out)
+
+	// Prefixes must end at a dot.
+	id, out, _ = ParseDependencySource("dependency:v1.23.out", deps...)
+	assert.Equal(t, ID("v1.23"), id)
+	assert.Equal(t, "out", out)
+}
+
+// ===== ConvertOutput tests =====
+
+func TestConvertOutput(t *testing.T) {
+	tests := []struct {
+		typ  string
+		raw  string
+		want any
+	}{
+		{OutputTypeString, "hello", "hello"},
+		{OutputTypeNumber, " 42.5 ", 42.5},
+		{OutputTypeBool, "true", true},
+		{"boolean", "0", false},
+		{OutputTypeJSON, `{"a":[1,2]}`, map[string]any{
+			"a": []any{float64(1), float64(2)},
+		}},
+		{"custom", "kept", "kept"},
+	}
+	for _, tt := range tests {
+		got, err := ConvertOutput(tt.typ, tt.raw)
+		require.NoError(t, err, tt.typ)
+		assert.Equal(t, tt.want, got, tt.typ)
+	}
+
+	for typ, raw := range map[string]string{
+		OutputTypeNumber: "many",
+		OutputTypeBool:   "maybe",
+		OutputTypeJSON:   "{broken",
+	} {
+		_, err := ConvertOutput(typ, raw)
+		assert.Error(t, err, typ)
+	}
+}
+
+// ===== ValidateOutputs tests =====
+
+func TestValidateOutputs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "app.apk"), []byte("apk"), 0o644,
+	))
+
+	result := &Result{Outputs: map[string]string{
+		"count":   "3",
+		"ok":      "yes",
+		"apk":     "app.apk",
+		"missing": "gone.apk",
+		"payload": `{"k":"v"}`,
+	}}
+	failures := ValidateOutputs([]Output{
+		{Name: "count", Type: OutputTypeNumber},
+		{Name: "ok", Type: OutputTypeBool},
+		{Name: "apk", Type: OutputTypeFile},
+		{Name: "missing", Type: OutputTypeFile},
+		{Name: "payload", Type: OutputTypeJSON},
+		{Name: "not_produced", Type: OutputTypeNumber},
+	}, result, dir)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, "ok", failures[0].Target)
+	assert.Equal(t, "output_type", failures[0].Type)
+	assert.False(t, failures[0].Passed)
+	assert.Equal(t, "missing", failures[1].Target)
+
+	assert.Equal(t, float64(3), result.TypedOutputs["count"])
+	assert.Equal(t, filepath.Join(dir, "app.apk"), result.TypedOutputs["apk"])
+	assert.Equal(t, map[string]any{"k": "v"}, result.TypedOutputs["payload"])
+	assert.NotContains(t, result.TypedOutputs, "not_produced")
+}
+
+func TestResult_OutputValue(t *testing.T) {
+	r := &Result{
+		Outputs:      map[string]string{"n": "3", "s": "x"},
+		TypedOutputs: map[string]any{"n": float64(3)},
+	}
+	v, ok := r.OutputValue("n")
+	assert.True(t, ok)
+	assert.Equal(t, float64(3), v)
+	v, ok = r.OutputValue("s")
+	assert.True(t, ok)
+	assert.Equal(t, "x", v)
+	_, ok = r.OutputValue("none")
+	assert.False(t, ok)
+}
+
+// ===== ResolveDependencyInputs tests =====
+
+func TestResolveDependencyInputs(t *testing.T) {
+	upstream := map[ID]*Result{
+		"build": {
+			Outputs:      map[string]string{"size": "12", "apk_path": "/a.apk"},
+			TypedOutputs: map[string]any{"size": float64(12)},
+		},
+		"web.build": {Outputs: map[string]string{"web.build": "/site"}},
+	}
+	got, err := ResolveDependencyInputs([]Input{
+		{Name: "bytes", Source: "dependency:build.size", Required: true},
+		{Name: "apk_path", Source: "dependency:build"},
+		{Name: "opt", Source: "dependency:build.nothing"},
+		{Name: "home", Source: "env", Required: true},
+		{Name: "web.build", Source: "dependency:web.build", Required: true},
+	}, upstream)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"bytes": float64(12), "apk_path": "/a.apk", "web.build": "/site",
+	}, got)
+
+	_, err = ResolveDependencyInputs([]Input{
+		{Name: "x", Source: "dependency:build.nothing", Required: true},
+	}, upstream)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required input x")
+
+	_, err = ResolveDependencyInputs([]Input{
+		{Name: "x", Source: "dependency:absent.out", Required: true},
+	}, upstream)
+	assert.Error(t, err)
+}
+
+// ===== DefinitionChallenge typed data tests =====
+
+func TestDefinitionChallenge_InjectedDependencyInput(t *testing.T) {
+	cfg := newDeclarativeConfig(t, "down")
+	cfg.Inputs = map[string]any{"bytes": float64(12)}
+
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "down",
+		Inputs: []Input{{
+			Name: "bytes", Source: "dependency:build.size", Required: true,
+		}},
+		Configuration: json.RawMessage(`{"backend":"shell"}`),
+	})
+	assert.Len(t, dc.DeclaredInputs(), 1)
+	require.NoError(t, dc.Configure(cfg))
+	require.NoError(t, dc.Validate(context.Background()))
+	assert.Equal(t, float64(12), dc.Inputs()["bytes"])
+
+	v, ok := dc.Input("bytes")
+	assert.True(t, ok)
+	assert.Equal(t, float64(12), v)
+}
+
+func TestDefinitionChallenge_Execute_InvalidTypedOutput(t *testing.T) {
+	dc := mustDefinitionChallenge(t, &Definition{
+		ID: "typed",
+		Outputs: []Output{
+			{Name: "count", Type: OutputTypeNumber},
+			{Name: "ready", Type: OutputTypeBool},
+		},
+	})
+	dc.SetActionBackend(ActionBackendFunc(func(
+		context.Context, *ActionRequest,
+	) (*ActionResult, error) {
+		return &ActionResult{
+			Outputs: map[string]any{"count": 7, "ready": "soon"},
+			Actions: []string{"ran"},
+		}, nil
+	}))
+	require.NoError(t, dc.Configure(newDeclarativeConfig(t, "typed")))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, result.Status)
+	assert.Equal(t, float64(7), result.TypedOutputs["count"])
+	require.Len(t, result.Assertions, 1)
+	assert.Equal(t, "ready", result.Assertions[0].Target)
+}
@@ -12,6 +12,11 @@ const (
 	StatusTimedOut = "timed_out"
 	StatusStuck    = "stuck"
 	StatusError    = "error"
+
+	// StatusPassedWithWarnings marks a challenge whose blocking
+	// assertions passed but at least one warning assertion
+	// failed (see AssertionStatus).
+	StatusPassedWithWarnings = "passed_with_warnings"
 )
 
 // Result captures the complete outcome of a challenge execution,
@@ -42,10 +47,19 @@ type Result struct {
 	// execution.
 	Metrics map[string]MetricValue `json:"metrics"`
 
+	// Series holds named sample series collected during
+	// execution, such as the latencies of repeated requests.
+	Series map[string]MetricSeries `json:"series,omitempty"`
+
 	// Outputs holds named string outputs produced by the
 	// challenge.
 	Outputs map[string]string `json:"outputs"`
 
+	// TypedOutputs holds outputs converted to their declared
+	// type (see ValidateOutputs). Outputs keeps the string
+	// form of every output.
+	TypedOutputs map[string]any `json:"typed_outputs,omitempty"`
+
 	// Logs contains paths to log files written during execution.
 	Logs LogPaths `json:"logs"`
 
@@ -61,6 +75,33 @@ type Result struct {
 	// "you must record what the runtime actually did before
 	// claiming PASS" guarantee. Constitution §11.4.
 	RecordedActions []string `json:"recorded_actions,omitempty"`
+
+	// Attempts lists every execution attempt when the
+	// challenge ran under a RetryPolicy. The other fields
+	// describe the final attempt.
+	Attempts []Attempt `json:"attempts,omitempty"`
+
+	// Flaky is true when the challenge passed only after at
+	// least one failed attempt.
+	Flaky bool `json:"flaky,omitempty"`
+}
+
+// Attempt records the outcome of a single execution attempt.
+type Attempt struct {
+	// Number is the 1-based attempt number.
+	Number int `json:"number"`
+
+	// Status is the status the attempt ended with.
+	Status string `json:"status"`
+
+	// StartTime is when the attempt began.
+	StartTime time.Time `json:"start_time"`
+
+	// Duration is the wall-clock time of the attempt.
+	Duration time.Duration `json:"duration"`
+
+	// Error is the attempt error message, if any.
+	Error string `json:"error,omitempty"`
 }
 
 // AssertionResult captures the outcome of a single assertion
@@ -83,6 +124,19 @@ type AssertionResult struct {
 
 	// Message is a human-readable description of the result.
 	Message string `json:"message"`
+
+	// Severity is the severity of the assertion definition;
+	// empty means SeverityError.
+	Severity string `json:"severity,omitempty"`
+
+	// Stats holds the statistics computed by statistical
+	// assertions over a sample series (e.g., "mean", "p95").
+	// CreateResult records them in Result.Metrics as
+	// "<target>.<stat>".
+	Stats map[string]float64 `json:"stats,omitempty"`
+
+	// Children holds the sub-results of a composite assertion.
+	Children []AssertionResult `json:"children,omitempty"`
 }
 
 // MetricValue represents a single named metric with its unit.
@@ -98,6 +152,19 @@ type MetricValue struct {
 	Unit string `json:"unit"`
 }
 
+// MetricSeries is a named series of metric samples, checked by
+// statistical assertions such as "p95_below".
+type MetricSeries struct {
+	// Name is the series identifier.
+	Name string `json:"name"`
+
+	// Samples holds the sample values in collection order.
+	Samples []float64 `json:"samples"`
+
+	// Unit describes the measurement unit of the samples.
+	Unit string `json:"unit"`
+}
+
 // LogPaths holds file paths for logs generated during challenge
 // execution.
 type LogPaths struct {
@@ -112,12 +179,18 @@ type LogPaths struct {
 
 	// APIResponses logs inbound API response details.
 	APIResponses string `json:"api_responses"`
+
+	// Artifacts maps names to other files written during
+	// execution, such as snapshot diffs ("snapshot:<name>").
+	Artifacts map[string]string `json:"artifacts,omitempty"`
 }
 
-// AllPassed returns true if every assertion in the result passed.
+// AllPassed returns true if every blocking assertion in the
+// result passed. Failed warning and info assertions are
+// ignored.
 func (r *Result) AllPassed() bool {
 	for _, a := range r.Assertions {
-		if !a.Passed {
+		if !a.Passed && a.IsBlocking() {
 			return false
 		}
 	}
@@ -127,8 +200,8 @@ func (r *Result) AllPassed() bool {
 // IsFinal returns true if the status is a terminal state.
 func (r *Result) IsFinal() bool {
 	switch r.Status {
-	case StatusPassed, StatusFailed, StatusSkipped,
-		StatusTimedOut, StatusStuck, StatusError:
+	case StatusPassed, StatusPassedWithWarnings, StatusFailed,
+		StatusSkipped, StatusTimedOut, StatusStuck, StatusError:
 		return true
 	}
 	return false
@@ -0,0 +1,124 @@
+package challenge
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultRetryOn lists the statuses retried when a RetryPolicy
+// does not specify RetryOn: outcomes that usually indicate a
+// transient problem rather than a real failure.
+var DefaultRetryOn = []string{StatusTimedOut, StatusError, StatusStuck}
+
+// RetryPolicy controls how often a challenge is re-executed
+// when an attempt ends in a retryable status.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including
+	// the first. Values below 2 disable retries.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt.
+	Backoff time.Duration
+
+	// Multiplier scales Backoff after every attempt. Values
+	// below 1 keep the delay constant.
+	Multiplier float64
+
+	// RetryOn lists the statuses that trigger a retry. Empty
+	// selects DefaultRetryOn.
+	RetryOn []string
+}
+
+// retryPolicyJSON is the wire form of RetryPolicy, with the
+// backoff written as a duration string (e.g., "2s").
+type retryPolicyJSON struct {
+	MaxAttempts int      `json:"max_attempts"`
+	Backoff     string   `json:"backoff,omitempty"`
+	Multiplier  float64  `json:"multiplier,omitempty"`
+	RetryOn     []string `json:"retry_on,omitempty"`
+}
+
+// MarshalJSON encodes the policy with a duration-string
+// backoff.
+func (p RetryPolicy) MarshalJSON() ([]byte, error) {
+	w := retryPolicyJSON{
+		MaxAttempts: p.MaxAttempts,
+		Multiplier:  p.Multiplier,
+		RetryOn:     p.RetryOn,
+	}
+	if p.Backoff > 0 {
+		w.Backoff = p.Backoff.String()
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes the policy, parsing the backoff as a
+// duration string.
+func (p *RetryPolicy) UnmarshalJSON(data []byte) error {
+	var w retryPolicyJSON
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	var backoff time.Duration
+	if w.Backoff != "" {
+		d, err := time.ParseDuration(w.Backoff)
+		if err != nil {
+			return fmt.Errorf("invalid retry backoff %q: %w", w.Backoff, err)
+		}
+		backoff = d
+	}
+	*p = RetryPolicy{
+		MaxAttempts: w.MaxAttempts,
+		Backoff:     backoff,
+		Multiplier:  w.Multiplier,
+		RetryOn:     w.RetryOn,
+	}
+	return nil
+}
+
+// Enabled reports whether the policy allows more than one
+// attempt.
+func (p *RetryPolicy) Enabled() bool {
+	return p != nil && p.MaxAttempts > 1
+}
+
+// ShouldRetry reports whether an attempt that ended with status
+// is retried.
+func (p *RetryPolicy) ShouldRetry(status string) bool {
+	if !p.Enabled() {
+		return false
+	}
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = DefaultRetryOn
+	}
+	for _, s := range retryOn {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the backoff before the attempt following
+// attempt number n (1-based).
+func (p *RetryPolicy) Delay(n int) time.Duration {
+	if p == nil || p.Backoff <= 0 {
+		return 0
+	}
+	d := float64(p.Backoff)
+	if p.Multiplier > 1 {
+		for i := 1; i < n; i++ {
+			d *= p.Multiplier
+		}
+	}
+	return time.Duration(d)
+}
+
+// Retryable is implemented by challenges that declare their own
+// RetryPolicy. It overrides the runner-wide policy; returning
+// nil falls back to it.
+type Retryable interface {
+	RetryPolicy() *RetryPolicy
+}
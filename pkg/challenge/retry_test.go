@@ -0,0 +1,82 @@
+package challenge
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Enabled(t *testing.T) {
+	var nilPolicy *RetryPolicy
+	assert.False(t, nilPolicy.Enabled())
+	assert.False(t, (&RetryPolicy{MaxAttempts: 1}).Enabled())
+	assert.True(t, (&RetryPolicy{MaxAttempts: 2}).Enabled())
+}
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 3}
+	assert.True(t, p.ShouldRetry(StatusTimedOut))
+	assert.True(t, p.ShouldRetry(StatusError))
+	assert.True(t, p.ShouldRetry(StatusStuck))
+	assert.False(t, p.ShouldRetry(StatusFailed))
+	assert.False(t, p.ShouldRetry(StatusPassed))
+
+	p.RetryOn = []string{StatusFailed}
+	assert.True(t, p.ShouldRetry(StatusFailed))
+	assert.False(t, p.ShouldRetry(StatusError))
+
+	var nilPolicy *RetryPolicy
+	assert.False(t, nilPolicy.ShouldRetry(StatusError))
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := &RetryPolicy{Backoff: 100 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 100*time.Millisecond, p.Delay(3))
+
+	p.Multiplier = 2
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 400*time.Millisecond, p.Delay(3))
+
+	var nilPolicy *RetryPolicy
+	assert.Zero(t, nilPolicy.Delay(1))
+}
+
+func TestRetryPolicy_JSON(t *testing.T) {
+	var def Definition
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "r",
+		"retry": {
+			"max_attempts": 3, "backoff": "2s",
+			"multiplier": 1.5, "retry_on": ["timed_out"]
+		}
+	}`), &def))
+	require.NotNil(t, def.Retry)
+	assert.Equal(t, 3, def.Retry.MaxAttempts)
+	assert.Equal(t, 2*time.Second, def.Retry.Backoff)
+	assert.Equal(t, []string{StatusTimedOut}, def.Retry.RetryOn)
+
+	data, err := json.Marshal(def.Retry)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"max_attempts": 3, "backoff": "2s",
+		"multiplier": 1.5, "retry_on": ["timed_out"]
+	}`, string(data))
+
+	var bad RetryPolicy
+	assert.Error(t, json.Unmarshal([]byte(`{"backoff":"soon"}`), &bad))
+}
+
+func TestDefinitionChallenge_RetryPolicy(t *testing.T) {
+	dc, err := NewDefinitionChallenge(&Definition{
+		ID: "r", Retry: &RetryPolicy{MaxAttempts: 2},
+	})
+	require.NoError(t, err)
+
+	var rc Retryable = dc
+	assert.Equal(t, 2, rc.RetryPolicy().MaxAttempts)
+}
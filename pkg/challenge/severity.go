@@ -0,0 +1,67 @@
+package challenge
+
+// Assertion severities. A failed assertion fails the challenge
+// unless its severity is SeverityWarning or SeverityInfo. An
+// empty severity means SeverityError.
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// ValidSeverity returns true if s is empty or one of the
+// Severity* constants.
+func ValidSeverity(s string) bool {
+	switch s {
+	case "", SeverityCritical, SeverityError,
+		SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// IsBlocking returns true if a failure of the assertion fails
+// the challenge, i.e. its severity is neither SeverityWarning
+// nor SeverityInfo. The severities of composite sub-results
+// are reported but only the top-level result counts.
+func (a AssertionResult) IsBlocking() bool {
+	return a.Severity != SeverityWarning && a.Severity != SeverityInfo
+}
+
+// IsPassing returns true if status is StatusPassed or
+// StatusPassedWithWarnings.
+func IsPassing(status string) bool {
+	return status == StatusPassed || status == StatusPassedWithWarnings
+}
+
+// AssertionStatus returns the status implied by assertions:
+// StatusFailed if a blocking assertion failed,
+// StatusPassedWithWarnings if a warning failed and
+// StatusPassed otherwise. Failed info assertions are recorded
+// but do not change the status.
+func AssertionStatus(assertions []AssertionResult) string {
+	status := StatusPassed
+	for _, a := range assertions {
+		switch {
+		case a.Passed:
+		case a.IsBlocking():
+			return StatusFailed
+		case a.Severity == SeverityWarning:
+			status = StatusPassedWithWarnings
+		}
+	}
+	return status
+}
+
+// Warnings returns the failed assertions of r with severity
+// SeverityWarning.
+func (r *Result) Warnings() []AssertionResult {
+	var out []AssertionResult
+	for _, a := range r.Assertions {
+		if !a.Passed && a.Severity == SeverityWarning {
+			out = append(out, a)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package challenge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidSeverity(t *testing.T) {
+	for _, s := range []string{
+		"", SeverityCritical, SeverityError, SeverityWarning, SeverityInfo,
+	} {
+		assert.True(t, ValidSeverity(s), s)
+	}
+	assert.False(t, ValidSeverity("fatal"))
+}
+
+func TestAssertionResult_IsBlocking(t *testing.T) {
+	assert.True(t, AssertionResult{}.IsBlocking())
+	assert.True(t, AssertionResult{Severity: SeverityCritical}.IsBlocking())
+	assert.True(t, AssertionResult{Severity: SeverityError}.IsBlocking())
+	assert.False(t, AssertionResult{Severity: SeverityWarning}.IsBlocking())
+	assert.False(t, AssertionResult{Severity: SeverityInfo}.IsBlocking())
+}
+
+func TestAssertionStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions []AssertionResult
+		want       string
+	}{
+		{"none", nil, StatusPassed},
+		{"all passed", []AssertionResult{{Passed: true}}, StatusPassed},
+		{"failed info", []AssertionResult{
+			{Passed: true}, {Severity: SeverityInfo},
+		}, StatusPassed},
+		{"failed warning", []AssertionResult{
+			{Passed: true}, {Severity: SeverityWarning},
+		}, StatusPassedWithWarnings},
+		{"failed default", []AssertionResult{
+			{Severity: SeverityWarning}, {},
+		}, StatusFailed},
+		{"failed critical", []AssertionResult{
+			{Passed: true}, {Severity: SeverityCritical},
+		}, StatusFailed},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, AssertionStatus(tt.assertions), tt.name)
+	}
+}
+
+func TestIsPassing(t *testing.T) {
+	assert.True(t, IsPassing(StatusPassed))
+	assert.True(t, IsPassing(StatusPassedWithWarnings))
+	assert.False(t, IsPassing(StatusFailed))
+	assert.False(t, IsPassing(StatusSkipped))
+}
+
+func TestResult_Warnings(t *testing.T) {
+	r := &Result{
+		Status: StatusPassedWithWarnings,
+		Assertions: []AssertionResult{
+			{Type: "not_empty", Passed: true},
+			{Type: "max_latency", Severity: SeverityWarning},
+			{Type: "contains", Severity: SeverityInfo},
+			{Type: "equals", Passed: true, Severity: SeverityWarning},
+		},
+	}
+	warnings := r.Warnings()
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, "max_latency", warnings[0].Type)
+	assert.True(t, r.AllPassed())
+	assert.True(t, r.IsFinal())
+
+	r.Assertions = append(r.Assertions, AssertionResult{Type: "regex"})
+	assert.False(t, r.AllPassed())
+}
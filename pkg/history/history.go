@@ -0,0 +1,161 @@
+// Package history loads the run history log written by
+// report.AppendToHistory and answers trend queries over it:
+// pass rates, duration trends, the run a challenge started
+// failing in, flakiness and streaks.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Entry represents a single challenge run in the history log.
+// Each entry is one JSON line.
+type Entry struct {
+	Timestamp        time.Time `json:"timestamp"`
+	ChallengeID      string    `json:"challenge_id"`
+	Status           string    `json:"status"`
+	Duration         string    `json:"duration"`
+	AssertionsPassed int       `json:"assertions_passed"`
+	AssertionsTotal  int       `json:"assertions_total"`
+	ResultsPath      string    `json:"results_path"`
+	Attempts         int       `json:"attempts,omitempty"`
+	Flaky            bool      `json:"flaky,omitempty"`
+}
+
+// NewEntry returns the history entry of a challenge result
+// whose files are stored under resultsPath.
+func NewEntry(result *challenge.Result, resultsPath string) Entry {
+	assertionsPassed := 0
+	for _, a := range result.Assertions {
+		if a.Passed {
+			assertionsPassed++
+		}
+	}
+	return Entry{
+		Timestamp:        result.EndTime,
+		ChallengeID:      string(result.ChallengeID),
+		Status:           result.Status,
+		Duration:         result.Duration.String(),
+		AssertionsPassed: assertionsPassed,
+		AssertionsTotal:  len(result.Assertions),
+		ResultsPath:      resultsPath,
+		Attempts:         len(result.Attempts),
+		Flaky:            result.Flaky,
+	}
+}
+
+// Passed returns true if the run passed, with or without
+// warnings.
+func (e Entry) Passed() bool {
+	return challenge.IsPassing(e.Status)
+}
+
+// Elapsed returns the parsed Duration of the run, or zero if
+// it is not a valid duration.
+func (e Entry) Elapsed() time.Duration {
+	d, err := time.ParseDuration(e.Duration)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// History is an index of history entries by challenge. Each
+// challenge's runs are ordered by timestamp, oldest first.
+type History struct {
+	entries []Entry
+	runs    map[string][]Entry
+}
+
+// New indexes entries. Entries with equal timestamps keep
+// their order.
+func New(entries []Entry) *History {
+	h := &History{
+		entries: make([]Entry, len(entries)),
+		runs:    make(map[string][]Entry),
+	}
+	copy(h.entries, entries)
+	sort.SliceStable(h.entries, func(i, j int) bool {
+		return h.entries[i].Timestamp.Before(h.entries[j].Timestamp)
+	})
+	for _, e := range h.entries {
+		h.runs[e.ChallengeID] = append(h.runs[e.ChallengeID], e)
+	}
+	return h
+}
+
+// Load reads and indexes the history logs at paths. Blank
+// lines are ignored; a line that is not a valid entry is an
+// error naming the file and line.
+func Load(paths ...string) (*History, error) {
+	var entries []Entry
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read history: %w", err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			var e Entry
+			if err := json.Unmarshal(text, &e); err != nil {
+				return nil, fmt.Errorf(
+					"%s:%d: invalid history entry: %w",
+					path, line, err,
+				)
+			}
+			entries = append(entries, e)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read history %s: %w", path, err)
+		}
+	}
+	return New(entries), nil
+}
+
+// Len returns the number of entries.
+func (h *History) Len() int {
+	return len(h.entries)
+}
+
+// Entries returns all entries, oldest first.
+func (h *History) Entries() []Entry {
+	out := make([]Entry, len(h.entries))
+	copy(out, h.entries)
+	return out
+}
+
+// Challenges returns the IDs of the challenges in the history,
+// sorted.
+func (h *History) Challenges() []string {
+	ids := make([]string, 0, len(h.runs))
+	for id := range h.runs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Runs returns the last n runs of a challenge, oldest first.
+// n <= 0 returns every run.
+func (h *History) Runs(challengeID string, n int) []Entry {
+	runs := h.runs[challengeID]
+	if n > 0 && len(runs) > n {
+		runs = runs[len(runs)-n:]
+	}
+	out := make([]Entry, len(runs))
+	copy(out, runs)
+	return out
+}
@@ -0,0 +1,183 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+var base = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// entries builds the runs of a challenge from an outcome
+// string: "p" passed, "f" failed, "r" passed after a retry.
+// Run i takes i+1 seconds and ends i hours after base.
+func entries(id, outcomes string) []Entry {
+	out := make([]Entry, len(outcomes))
+	for i, c := range outcomes {
+		e := Entry{
+			Timestamp:   base.Add(time.Duration(i) * time.Hour),
+			ChallengeID: id,
+			Status:      "passed",
+			Duration:    (time.Duration(i+1) * time.Second).String(),
+		}
+		switch c {
+		case 'f':
+			e.Status = "failed"
+		case 'r':
+			e.Flaky = true
+		}
+		out[i] = e
+	}
+	return out
+}
+
+func writeHistory(t *testing.T, name string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644))
+	return path
+}
+
+// ===== Load tests =====
+
+func TestLoad(t *testing.T) {
+	a := writeHistory(t, "a.jsonl",
+		`{"timestamp":"2026-01-01T02:00:00Z","challenge_id":"x","status":"failed","duration":"2s"}`,
+		"",
+		`{"timestamp":"2026-01-01T00:00:00Z","challenge_id":"x","status":"passed","duration":"1s"}`,
+	)
+	b := writeHistory(t, "b.jsonl",
+		`{"timestamp":"2026-01-01T01:00:00Z","challenge_id":"y","status":"passed_with_warnings","duration":"bad"}`,
+	)
+
+	h, err := Load(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, 3, h.Len())
+	assert.Equal(t, []string{"x", "y"}, h.Challenges())
+
+	all := h.Entries()
+	assert.Equal(t, "x", all[0].ChallengeID)
+	assert.Equal(t, "y", all[1].ChallengeID)
+	assert.True(t, all[1].Passed())
+	assert.Zero(t, all[1].Elapsed())
+	assert.Equal(t, 2*time.Second, all[2].Elapsed())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.Error(t, err)
+
+	path := writeHistory(t, "bad.jsonl", `{"challenge_id":"x"}`, `{oops`)
+	_, err = Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad.jsonl:2: invalid history entry")
+}
+
+func TestNewEntry(t *testing.T) {
+	e := NewEntry(&challenge.Result{
+		ChallengeID: "x",
+		Status:      challenge.StatusPassed,
+		EndTime:     base,
+		Duration:    1500 * time.Millisecond,
+		Assertions: []challenge.AssertionResult{
+			{Passed: true}, {Passed: false},
+		},
+		Attempts: []challenge.Attempt{{}, {}},
+		Flaky:    true,
+	}, "/results/x")
+	assert.Equal(t, Entry{
+		Timestamp: base, ChallengeID: "x", Status: "passed",
+		Duration: "1.5s", AssertionsPassed: 1, AssertionsTotal: 2,
+		ResultsPath: "/results/x", Attempts: 2, Flaky: true,
+	}, e)
+}
+
+// ===== Query tests =====
+
+func TestHistory_Runs(t *testing.T) {
+	h := New(entries("x", "pfpff"))
+	assert.Len(t, h.Runs("x", 0), 5)
+	runs := h.Runs("x", 2)
+	require.Len(t, runs, 2)
+	assert.Equal(t, base.Add(3*time.Hour), runs[0].Timestamp)
+	assert.Empty(t, h.Runs("missing", 3))
+}
+
+func TestHistory_PassRate(t *testing.T) {
+	h := New(entries("x", "ffppr"))
+	assert.InDelta(t, 0.6, h.PassRate("x", 0), 1e-9)
+	assert.InDelta(t, 1.0, h.PassRate("x", 3), 1e-9)
+	assert.Zero(t, h.PassRate("missing", 3))
+}
+
+func TestHistory_DurationTrend(t *testing.T) {
+	h := New(entries("x", "pppp"))
+	assert.Equal(t,
+		[]time.Duration{3 * time.Second, 4 * time.Second},
+		h.DurationTrend("x", 2))
+}
+
+func TestHistory_FirstFailing(t *testing.T) {
+	h := New(append(entries("x", "ppfpfff"), entries("y", "pf")...))
+
+	e, ok := h.FirstFailing("x")
+	require.True(t, ok)
+	assert.Equal(t, base.Add(4*time.Hour), e.Timestamp)
+
+	e, ok = h.FirstFailing("y")
+	require.True(t, ok)
+	assert.Equal(t, base.Add(time.Hour), e.Timestamp)
+
+	_, ok = New(entries("z", "fp")).FirstFailing("z")
+	assert.False(t, ok)
+	_, ok = New(entries("z", "ff")).FirstFailing("missing")
+	assert.False(t, ok)
+
+	e, ok = New(entries("z", "ff")).FirstFailing("z")
+	require.True(t, ok)
+	assert.Equal(t, base, e.Timestamp)
+}
+
+func TestHistory_MostFlaky(t *testing.T) {
+	var all []Entry
+	all = append(all, entries("stable", "pppp")...)
+	all = append(all, entries("flipping", "pfpf")...)
+	all = append(all, entries("retried", "prpp")...)
+	all = append(all, entries("broken", "ffff")...)
+	h := New(all)
+
+	flaky := h.MostFlaky(0, 0)
+	require.Len(t, flaky, 2)
+	assert.Equal(t, Flakiness{
+		ChallengeID: "flipping", Runs: 4, Flips: 3, Score: 0.75,
+	}, flaky[0])
+	assert.Equal(t, "retried", flaky[1].ChallengeID)
+	assert.Equal(t, 1, flaky[1].FlakyPasses)
+
+	assert.Len(t, h.MostFlaky(0, 1), 1)
+	// Over the last run alone nothing changes outcome.
+	assert.Empty(t, h.MostFlaky(1, 0))
+}
+
+func TestHistory_LongestStreak(t *testing.T) {
+	h := New(entries("x", "ppfffpppfp"))
+
+	pass := h.LongestStreak("x", true)
+	assert.Equal(t, Streak{
+		Passed: true, Length: 3,
+		Start: base.Add(5 * time.Hour), End: base.Add(7 * time.Hour),
+	}, pass)
+
+	fail := h.LongestStreak("x", false)
+	assert.Equal(t, 3, fail.Length)
+	assert.Equal(t, base.Add(2*time.Hour), fail.Start)
+
+	assert.Zero(t, h.LongestStreak("missing", true).Length)
+}
@@ -0,0 +1,137 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// PassRate returns the fraction of the last n runs of a
+// challenge that passed (see Runs), or 0 if it has no runs.
+func (h *History) PassRate(challengeID string, n int) float64 {
+	runs := h.Runs(challengeID, n)
+	if len(runs) == 0 {
+		return 0
+	}
+	passed := 0
+	for _, e := range runs {
+		if e.Passed() {
+			passed++
+		}
+	}
+	return float64(passed) / float64(len(runs))
+}
+
+// DurationTrend returns the durations of the last n runs of a
+// challenge, oldest first.
+func (h *History) DurationTrend(challengeID string, n int) []time.Duration {
+	runs := h.Runs(challengeID, n)
+	out := make([]time.Duration, len(runs))
+	for i, e := range runs {
+		out[i] = e.Elapsed()
+	}
+	return out
+}
+
+// FirstFailing returns the run a challenge started failing in:
+// the first run after its last pass. It returns false if the
+// latest run passed or the challenge has no runs.
+func (h *History) FirstFailing(challengeID string) (Entry, bool) {
+	runs := h.runs[challengeID]
+	if len(runs) == 0 || runs[len(runs)-1].Passed() {
+		return Entry{}, false
+	}
+	i := len(runs) - 1
+	for i > 0 && !runs[i-1].Passed() {
+		i--
+	}
+	return runs[i], true
+}
+
+// Flakiness describes how often a challenge changed outcome.
+type Flakiness struct {
+	// ChallengeID identifies the challenge.
+	ChallengeID string `json:"challenge_id"`
+
+	// Runs is the number of runs considered.
+	Runs int `json:"runs"`
+
+	// Flips counts the runs whose outcome (passed or not)
+	// differs from the run before.
+	Flips int `json:"flips"`
+
+	// FlakyPasses counts the runs that passed only after a
+	// retry.
+	FlakyPasses int `json:"flaky_passes"`
+
+	// Score is (Flips + FlakyPasses) / Runs.
+	Score float64 `json:"score"`
+}
+
+// MostFlaky ranks the challenges whose last n runs changed
+// outcome or passed after a retry, most flaky first, and
+// returns at most limit of them (all when limit <= 0).
+func (h *History) MostFlaky(n, limit int) []Flakiness {
+	var out []Flakiness
+	for _, id := range h.Challenges() {
+		runs := h.Runs(id, n)
+		f := Flakiness{ChallengeID: id, Runs: len(runs)}
+		for i, e := range runs {
+			if i > 0 && e.Passed() != runs[i-1].Passed() {
+				f.Flips++
+			}
+			if e.Flaky {
+				f.FlakyPasses++
+			}
+		}
+		if f.Flips+f.FlakyPasses == 0 {
+			continue
+		}
+		f.Score = float64(f.Flips+f.FlakyPasses) / float64(f.Runs)
+		out = append(out, f)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// Streak is a run of consecutive passes or failures.
+type Streak struct {
+	// Passed is true for a streak of passes.
+	Passed bool `json:"passed"`
+
+	// Length is the number of runs in the streak.
+	Length int `json:"length"`
+
+	// Start and End are the timestamps of the first and last
+	// runs of the streak.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// LongestStreak returns the longest streak of passes (or of
+// failures, when passed is false) of a challenge; the earliest
+// one when several are equally long. The streak has zero
+// Length if there is none.
+func (h *History) LongestStreak(challengeID string, passed bool) Streak {
+	best := Streak{Passed: passed}
+	cur := Streak{Passed: passed}
+	for _, e := range h.runs[challengeID] {
+		if e.Passed() != passed {
+			cur.Length = 0
+			continue
+		}
+		if cur.Length == 0 {
+			cur.Start = e.Timestamp
+		}
+		cur.Length++
+		cur.End = e.Timestamp
+		if cur.Length > best.Length {
+			best = cur
+		}
+	}
+	return best
+}
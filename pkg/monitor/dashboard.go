@@ -88,6 +88,34 @@ func (d *DashboardData) UpdateFromEvent(event ChallengeEvent) {
 	d.recalcSummary()
 }
 
+// UpdateFromResult sets the state of a challenge from its final
+// result, e.g. when replaying a stored run.
+func (d *DashboardData) UpdateFromResult(result *challenge.Result) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.Challenges[result.ChallengeID]
+	state.ID = result.ChallengeID
+	state.Name = result.ChallengeName
+	switch {
+	case challenge.IsPassing(result.Status):
+		state.Status = "passed"
+	case result.Status == challenge.StatusSkipped,
+		result.Status == challenge.StatusTimedOut:
+		state.Status = result.Status
+	default:
+		state.Status = "failed"
+	}
+	start, end := result.StartTime, result.EndTime
+	state.StartTime = &start
+	state.EndTime = &end
+	state.Duration = result.Duration
+	state.Message = result.Error
+
+	d.Challenges[result.ChallengeID] = state
+	d.recalcSummary()
+}
+
 func (d *DashboardData) recalcSummary() {
 	s := DashboardSummary{}
 	for _, ch := range d.Challenges {
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"digital.vasic.challenges/pkg/challenge"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,6 +50,30 @@ func TestDashboardData_FailedEvent(t *testing.T) {
 	assert.Equal(t, 1, snap.Summary.Failed)
 }
 
+func TestDashboardData_UpdateFromResult(t *testing.T) {
+	d := NewDashboardData("run-5")
+	d.UpdateFromResult(&challenge.Result{
+		ChallengeID: "ch-1", ChallengeName: "Passes",
+		Status: challenge.StatusPassed, Duration: time.Second,
+	})
+	d.UpdateFromResult(&challenge.Result{
+		ChallengeID: "ch-2", ChallengeName: "Errors",
+		Status: challenge.StatusError, Error: "boom",
+	})
+	d.UpdateFromResult(&challenge.Result{
+		ChallengeID: "ch-3", Status: challenge.StatusSkipped,
+	})
+
+	snap := d.Snapshot()
+	assert.Equal(t, "passed", snap.Challenges["ch-1"].Status)
+	assert.Equal(t, time.Second, snap.Challenges["ch-1"].Duration)
+	assert.Equal(t, "failed", snap.Challenges["ch-2"].Status)
+	assert.Equal(t, "boom", snap.Challenges["ch-2"].Message)
+	assert.Equal(t, "skipped", snap.Challenges["ch-3"].Status)
+	assert.Equal(t, 3, snap.Summary.Total)
+	assert.Equal(t, float64(50), snap.Summary.PassRate)
+}
+
 func TestDashboardData_SetStatus(t *testing.T) {
 	d := NewDashboardData("run-3")
 	d.SetStatus("completed")
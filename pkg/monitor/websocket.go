@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 )
 
@@ -19,11 +20,22 @@ type WebSocketServer struct {
 	mu        sync.RWMutex
 	collector *EventCollector
 	dashboard *DashboardData
+	runs      RunSource
 	clients   map[chan []byte]struct{}
 	addr      string
 	server    *http.Server
 }
 
+// RunSource provides the dashboards of past runs, e.g. from a
+// results database. store.Store implements it.
+type RunSource interface {
+	// RunIDs returns the IDs of the available runs.
+	RunIDs() []string
+
+	// Dashboard returns the dashboard state of a run.
+	Dashboard(runID string) (*DashboardData, error)
+}
+
 // NewWebSocketServer creates a new SSE server for live monitoring.
 func NewWebSocketServer(addr string, collector *EventCollector, dashboard *DashboardData) *WebSocketServer {
 	return &WebSocketServer{
@@ -34,11 +46,22 @@ func NewWebSocketServer(addr string, collector *EventCollector, dashboard *Dashb
 	}
 }
 
+// SetRunSource serves past runs from src alongside the live
+// run: /runs lists their IDs and /runs/<id> returns the
+// dashboard of one.
+func (s *WebSocketServer) SetRunSource(src RunSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs = src
+}
+
 // Start begins serving the SSE endpoint.
 func (s *WebSocketServer) Start(ctx context.Context) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/events", s.handleSSE)
 	mux.HandleFunc("/dashboard", s.handleDashboard)
+	mux.HandleFunc("/runs", s.handleRuns)
+	mux.HandleFunc("/runs/", s.handleRun)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ok"))
@@ -132,6 +155,40 @@ func (s *WebSocketServer) handleDashboard(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(snap)
 }
 
+func (s *WebSocketServer) handleRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	src := s.runs
+	s.mu.RUnlock()
+	if src == nil {
+		http.NotFound(w, r)
+		return
+	}
+	ids := src.RunIDs()
+	if ids == nil {
+		ids = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ids)
+}
+
+func (s *WebSocketServer) handleRun(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	src := s.runs
+	s.mu.RUnlock()
+	runID := strings.TrimPrefix(r.URL.Path, "/runs/")
+	if src == nil || runID == "" {
+		http.NotFound(w, r)
+		return
+	}
+	dashboard, err := src.Dashboard(runID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dashboard.Snapshot())
+}
+
 func (s *WebSocketServer) broadcast(data []byte) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
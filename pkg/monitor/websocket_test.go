@@ -445,6 +445,50 @@ func TestWebSocketServer_handleDashboard(t *testing.T) {
 	}
 }
 
+// fakeRunSource serves one stored run.
+type fakeRunSource struct{}
+
+func (fakeRunSource) RunIDs() []string { return []string{"run-1"} }
+
+func (fakeRunSource) Dashboard(runID string) (*DashboardData, error) {
+	if runID != "run-1" {
+		return nil, fmt.Errorf("unknown run: %s", runID)
+	}
+	d := NewDashboardData(runID)
+	d.SetStatus("completed")
+	return d, nil
+}
+
+func TestWebSocketServer_handleRuns(t *testing.T) {
+	server := NewWebSocketServer(":0", NewEventCollector(), NewDashboardData("live"))
+	get := func(handler http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// Without a run source there are no stored runs to serve.
+	assert.Equal(t, http.StatusNotFound, get(server.handleRuns, "/runs").Code)
+	assert.Equal(t, http.StatusNotFound, get(server.handleRun, "/runs/run-1").Code)
+
+	server.SetRunSource(fakeRunSource{})
+	rec := get(server.handleRuns, "/runs")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `["run-1"]`, rec.Body.String())
+
+	rec = get(server.handleRun, "/runs/run-1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var data DashboardData
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &data))
+	assert.Equal(t, "run-1", data.RunID)
+	assert.Equal(t, "completed", data.Status)
+
+	rec = get(server.handleRun, "/runs/missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "unknown run: missing")
+	assert.Equal(t, http.StatusNotFound, get(server.handleRun, "/runs/").Code)
+}
+
 func TestWebSocketServer_broadcast(t *testing.T) {
 	t.Run("broadcasts to all clients", func(t *testing.T) {
 		collector := NewEventCollector()
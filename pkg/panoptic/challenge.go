@@ -29,22 +29,9 @@ func (a *EngineAdapter) Evaluate(
 	def challenge.AssertionDef,
 	value any,
 ) challenge.AssertionResult {
-	result := a.engine.Evaluate(assertion.Definition{
-		Type:    def.Type,
-		Target:  def.Target,
-		Value:   def.Value,
-		Values:  def.Values,
-		Message: def.Message,
-	}, value)
-
-	return challenge.AssertionResult{
-		Type:     result.Type,
-		Target:   result.Target,
-		Expected: result.Expected,
-		Actual:   result.Actual,
-		Passed:   result.Passed,
-		Message:  result.Message,
-	}
+	return assertion.ToChallengeResult(
+		a.engine.Evaluate(assertion.FromChallengeDef(def), value),
+	)
 }
 
 // EvaluateAll delegates to the assertion engine, converting
@@ -53,34 +40,10 @@ func (a *EngineAdapter) EvaluateAll(
 	defs []challenge.AssertionDef,
 	values map[string]any,
 ) []challenge.AssertionResult {
-	assertionDefs := make([]assertion.Definition, len(defs))
-	for i, d := range defs {
-		assertionDefs[i] = assertion.Definition{
-			Type:    d.Type,
-			Target:  d.Target,
-			Value:   d.Value,
-			Values:  d.Values,
-			Message: d.Message,
-		}
-	}
-
-	results := a.engine.EvaluateAll(assertionDefs, values)
-
-	challengeResults := make(
-		[]challenge.AssertionResult, len(results),
+	results := a.engine.EvaluateAll(
+		assertion.FromChallengeDefs(defs), values,
 	)
-	for i, r := range results {
-		challengeResults[i] = challenge.AssertionResult{
-			Type:     r.Type,
-			Target:   r.Target,
-			Expected: r.Expected,
-			Actual:   r.Actual,
-			Passed:   r.Passed,
-			Message:  r.Message,
-		}
-	}
-
-	return challengeResults
+	return assertion.ToChallengeResults(results)
 }
 
 // PanopticChallenge executes Panoptic UI tests as a Challenge.
@@ -223,13 +186,7 @@ func (c *PanopticChallenge) Execute(
 	)
 
 	// Determine status.
-	status := challenge.StatusPassed
-	for _, a := range assertions {
-		if !a.Passed {
-			status = challenge.StatusFailed
-			break
-		}
-	}
+	status := challenge.AssertionStatus(assertions)
 
 	// If no assertions but exit code non-zero, fail.
 	if len(assertions) == 0 && result.ExitCode != 0 {
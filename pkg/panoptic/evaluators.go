@@ -9,7 +9,8 @@ import (
 )
 
 // RegisterEvaluators registers all 8 Panoptic-specific assertion
-// evaluators with the given engine.
+// evaluators with the given engine, described in its Catalog as
+// owned by the panoptic plugin.
 func RegisterEvaluators(engine *assertion.DefaultEngine) error {
 	evaluators := map[string]assertion.Evaluator{
 		"screenshot_exists":   evaluateScreenshotExists,
@@ -22,8 +23,12 @@ func RegisterEvaluators(engine *assertion.DefaultEngine) error {
 		"app_count":           evaluateAppCount,
 	}
 
+	descriptors := evaluatorDescriptors()
 	for name, eval := range evaluators {
-		if err := engine.Register(name, eval); err != nil {
+		desc := descriptors[name]
+		desc.Name = name
+		desc.Plugin = PluginName
+		if err := engine.RegisterDescriptor(desc, eval); err != nil {
 			return fmt.Errorf(
 				"register evaluator %s: %w", name, err,
 			)
@@ -32,6 +37,69 @@ func RegisterEvaluators(engine *assertion.DefaultEngine) error {
 	return nil
 }
 
+// evaluatorDescriptors documents the Panoptic evaluators for
+// the assertion catalog.
+func evaluatorDescriptors() map[string]assertion.Descriptor {
+	count := assertion.ValueSchema{
+		Kinds: []assertion.ArgKind{assertion.ArgInteger},
+	}
+	return map[string]assertion.Descriptor{
+		"screenshot_exists": {
+			Description: "At least value screenshots (default 1) were captured.",
+			Target:      assertion.ArgList,
+			Value:       count,
+			Examples:    []string{"screenshots | screenshot_exists(3)"},
+		},
+		"video_exists": {
+			Description: "At least value videos (default 1) were recorded.",
+			Target:      assertion.ArgList,
+			Value:       count,
+			Examples:    []string{"videos | video_exists()"},
+		},
+		"no_ui_errors": {
+			Description: "The AI error report file is missing, empty or reports no errors.",
+			Target:      assertion.ArgString,
+			Examples:    []string{"ai_error_report | no_ui_errors()"},
+		},
+		"ai_confidence_above": {
+			Description: "The AI confidence is at least value (default 0.75).",
+			Target:      assertion.ArgNumber,
+			Value: assertion.ValueSchema{
+				Kinds: []assertion.ArgKind{assertion.ArgNumber},
+			},
+			Examples: []string{"ai_confidence | ai_confidence_above(0.9)"},
+		},
+		"all_apps_passed": {
+			Description: "Every tested app succeeded.",
+			Target:      assertion.ArgBool,
+			Examples:    []string{"all_apps_passed | all_apps_passed()"},
+		},
+		"max_duration": {
+			Description: "No app ran longer than value milliseconds.",
+			Target:      assertion.ArgNumber,
+			Value: assertion.ValueSchema{
+				Kinds:    []assertion.ArgKind{assertion.ArgInteger},
+				Required: true,
+			},
+			Examples: []string{"max_duration_ms | max_duration(60000)"},
+		},
+		"report_exists": {
+			Description: "The HTML or JSON report was generated.",
+			Target:      assertion.ArgBool,
+			Examples:    []string{"report_html_exists | report_exists()"},
+		},
+		"app_count": {
+			Description: "Exactly value apps were tested.",
+			Target:      assertion.ArgNumber,
+			Value: assertion.ValueSchema{
+				Kinds:    []assertion.ArgKind{assertion.ArgInteger},
+				Required: true,
+			},
+			Examples: []string{"app_count | app_count(3)"},
+		},
+	}
+}
+
 // evaluateScreenshotExists checks that at least N screenshots
 // were captured. Target key: "screenshots" ([]any of strings)
 // or "total_screenshots" (int). Value: minimum count.
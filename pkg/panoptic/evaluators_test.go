@@ -28,6 +28,28 @@ func TestRegisterEvaluators(t *testing.T) {
 	}
 }
 
+func TestRegisterEvaluators_Catalog(t *testing.T) {
+	engine := assertion.NewEngine()
+	require.NoError(t, RegisterEvaluators(engine))
+
+	var described int
+	for _, d := range engine.Catalog() {
+		if d.Plugin != PluginName {
+			continue
+		}
+		described++
+		assert.NotEmpty(t, d.Description, d.Name)
+		require.NotEmpty(t, d.Examples, d.Name)
+		for _, ex := range d.Examples {
+			def, err := assertion.ParseDefinition(ex)
+			require.NoError(t, err, ex)
+			assert.Equal(t, d.Name, def.Type, ex)
+			assert.NoError(t, engine.Validate(def), ex)
+		}
+	}
+	assert.Equal(t, 8, described)
+}
+
 func TestRegisterEvaluators_Duplicate(t *testing.T) {
 	engine := assertion.NewEngine()
 	err := RegisterEvaluators(engine)
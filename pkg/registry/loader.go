@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/bank"
 	"digital.vasic.challenges/pkg/challenge"
 )
 
@@ -71,6 +74,33 @@ func LoadDefinitionsFromDir(
 	return nil
 }
 
+// LoadDefinitionsFromTree loads a nested bank tree through
+// bank.Bank.LoadTree, honouring include, override and profile
+// directives, and registers the resulting definitions in ID
+// order.
+func LoadDefinitionsFromTree(
+	reg Registry,
+	root string,
+	opts bank.LoadOptions,
+) error {
+	b := bank.New()
+	if err := b.LoadTree(root, opts); err != nil {
+		return err
+	}
+
+	defs := b.All()
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].ID < defs[j].ID
+	})
+	for _, def := range defs {
+		if err := reg.RegisterDefinition(def); err != nil {
+			return fmt.Errorf("definition %s: %w", def.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // loadDefinitionsFromBytes unmarshals a bank file and
 // registers its definitions.
 func loadDefinitionsFromBytes(
@@ -88,6 +118,11 @@ func loadDefinitionsFromBytes(
 
 	for i := range bank.Challenges {
 		def := &bank.Challenges[i]
+		// Schema and example files are relative to the bank
+		// file.
+		assertion.ResolveFileReferences(
+			def.Assertions, filepath.Dir(source),
+		)
 		if err := reg.RegisterDefinition(def); err != nil {
 			return fmt.Errorf(
 				"definition %s from %s: %w",
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"digital.vasic.challenges/pkg/bank"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -55,6 +57,23 @@ func TestLoadDefinitionsFromFile_JSON(t *testing.T) {
 	assert.Len(t, defs[1].Assertions, 1)
 }
 
+func TestLoadDefinitionsFromFile_RelativeSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "bank.json")
+	require.NoError(t, os.WriteFile(p, []byte(`{"version": "1.0", "challenges": [
+		{"id": "a", "name": "A", "assertions": [
+			{"type": "json_shape_matches", "target": "body", "value": "examples/body.json"}
+		]}
+	]}`), 0644))
+
+	r := NewRegistry()
+	require.NoError(t, LoadDefinitionsFromFile(r, p))
+	def, err := r.GetDefinition("a")
+	require.NoError(t, err)
+	assert.Equal(t,
+		filepath.Join(dir, "examples", "body.json"), def.Assertions[0].Value)
+}
+
 func TestLoadDefinitionsFromFile_NotFound(t *testing.T) {
 	r := NewRegistry()
 	err := LoadDefinitionsFromFile(r, "/nonexistent.json")
@@ -198,3 +217,34 @@ func TestLoadDefinitionsFromDir_SkipsSubdirectories(t *testing.T) {
 	require.Len(t, defs, 1)
 	assert.Equal(t, "Main", defs[0].Name)
 }
+
+func TestLoadDefinitionsFromTree(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "nested", "deeper")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, "top.yaml"),
+		[]byte("version: \"1\"\nchallenges: [{id: b, name: B}]\n"+
+			"profiles:\n  ci: {timeout: 5s}\n"),
+		0644,
+	))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(sub, "deep.json"),
+		[]byte(`{"version": "1", "challenges": [{"id": "a", "name": "A"}]}`),
+		0644,
+	))
+
+	r := NewRegistry()
+	require.NoError(t, LoadDefinitionsFromTree(
+		r, dir, bank.LoadOptions{Profile: "ci"},
+	))
+	defs := r.ListDefinitions()
+	require.Len(t, defs, 2)
+	assert.Equal(t, "A", defs[0].Name)
+	assert.Equal(t, "5s", defs[1].Timeout)
+
+	err := LoadDefinitionsFromTree(
+		NewRegistry(), "/nonexistent", bank.LoadOptions{},
+	)
+	assert.Error(t, err)
+}
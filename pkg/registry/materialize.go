@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+
+	"digital.vasic.challenges/pkg/assertion"
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// DefinitionFactory turns a declarative definition into an
+// executable Challenge.
+type DefinitionFactory func(
+	def *challenge.Definition,
+) (challenge.Challenge, error)
+
+// NewDefinitionFactory returns a DefinitionFactory that builds
+// a challenge.DefinitionChallenge per definition, evaluating
+// assertions with engine. A nil engine selects
+// assertion.NewEngine().
+func NewDefinitionFactory(
+	engine challenge.AssertionEngine,
+) DefinitionFactory {
+	if engine == nil {
+		engine = assertion.NewChallengeAdapter(nil)
+	}
+	return func(
+		def *challenge.Definition,
+	) (challenge.Challenge, error) {
+		dc, err := challenge.NewDefinitionChallenge(def)
+		if err != nil {
+			return nil, err
+		}
+		dc.SetAssertionEngine(engine)
+		return dc, nil
+	}
+}
+
+// SetDefinitionFactory installs the factory used to
+// materialize definitions into challenges and immediately
+// materializes every registered definition that has no
+// challenge yet. Passing nil stops further materialization;
+// already materialized challenges are kept.
+func (r *DefaultRegistry) SetDefinitionFactory(
+	f DefinitionFactory,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factory = f
+	if f == nil {
+		return nil
+	}
+
+	ids := make([]challenge.ID, 0, len(r.definitions))
+	for id := range r.definitions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if err := r.materializeLocked(r.definitions[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsMaterialized reports whether the challenge registered under
+// id was built from its definition rather than registered as a
+// Go implementation.
+func (r *DefaultRegistry) IsMaterialized(id challenge.ID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.materialized[id]
+}
+
+// materializeLocked builds and registers a challenge for def
+// when a factory is set and no challenge with the same ID
+// exists. The caller must hold r.mu.
+func (r *DefaultRegistry) materializeLocked(
+	def *challenge.Definition,
+) error {
+	if r.factory == nil {
+		return nil
+	}
+	if _, exists := r.challenges[def.ID]; exists {
+		return nil
+	}
+
+	c, err := r.factory(def)
+	if err != nil {
+		return fmt.Errorf(
+			"materialize definition %s: %w", def.ID, err,
+		)
+	}
+	if c == nil {
+		return nil
+	}
+
+	r.challenges[def.ID] = c
+	r.materialized[def.ID] = true
+	return nil
+}
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefinitionFactory_MaterializesExisting(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.RegisterDefinition(&challenge.Definition{
+		ID: "def-a", Name: "A",
+		Configuration: json.RawMessage(`{"backend":"shell"}`),
+	}))
+	assert.Equal(t, 0, r.Count())
+
+	require.NoError(t, r.SetDefinitionFactory(
+		NewDefinitionFactory(nil),
+	))
+	assert.Equal(t, 1, r.Count())
+	assert.True(t, r.IsMaterialized("def-a"))
+
+	c, err := r.Get("def-a")
+	require.NoError(t, err)
+	_, ok := c.(*challenge.DefinitionChallenge)
+	assert.True(t, ok)
+}
+
+func TestRegisterDefinition_MaterializesWithFactory(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.SetDefinitionFactory(
+		NewDefinitionFactory(nil),
+	))
+	require.NoError(t, r.RegisterDefinition(&challenge.Definition{
+		ID: "def-b", Dependencies: []challenge.ID{"def-a"},
+	}))
+
+	c, err := r.Get("def-b")
+	require.NoError(t, err)
+	assert.Equal(t, []challenge.ID{"def-a"}, c.Dependencies())
+}
+
+func TestRegister_ReplacesMaterialized(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.SetDefinitionFactory(
+		NewDefinitionFactory(nil),
+	))
+	require.NoError(t, r.RegisterDefinition(
+		&challenge.Definition{ID: "dup"},
+	))
+
+	goImpl := newStub("dup")
+	require.NoError(t, r.Register(goImpl))
+	assert.False(t, r.IsMaterialized("dup"))
+
+	c, err := r.Get("dup")
+	require.NoError(t, err)
+	assert.Same(t, goImpl, c)
+
+	// A Go implementation is never replaced.
+	assert.Error(t, r.Register(newStub("dup")))
+}
+
+func TestRegisterDefinition_KeepsGoImplementation(t *testing.T) {
+	r := NewRegistry()
+	goImpl := newStub("go")
+	require.NoError(t, r.Register(goImpl))
+	require.NoError(t, r.SetDefinitionFactory(
+		NewDefinitionFactory(nil),
+	))
+	require.NoError(t, r.RegisterDefinition(
+		&challenge.Definition{ID: "go"},
+	))
+
+	c, err := r.Get("go")
+	require.NoError(t, err)
+	assert.Same(t, goImpl, c)
+}
+
+func TestRegisterDefinition_FactoryError(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.SetDefinitionFactory(func(
+		*challenge.Definition,
+	) (challenge.Challenge, error) {
+		return nil, errors.New("boom")
+	}))
+
+	err := r.RegisterDefinition(&challenge.Definition{ID: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "materialize definition x")
+	_, getErr := r.GetDefinition("x")
+	assert.Error(t, getErr)
+}
+
+func TestClear_ResetsMaterialized(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.SetDefinitionFactory(
+		NewDefinitionFactory(nil),
+	))
+	require.NoError(t, r.RegisterDefinition(
+		&challenge.Definition{ID: "c"},
+	))
+	r.Clear()
+	assert.False(t, r.IsMaterialized("c"))
+	assert.Equal(t, 0, r.Count())
+}
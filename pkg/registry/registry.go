@@ -53,19 +53,40 @@ type Registry interface {
 	Count() int
 }
 
+// Selectable is implemented by registries that can select
+// challenges by their metadata. It is separate from Registry so
+// that existing Registry implementations keep compiling; callers
+// type-assert for it.
+type Selectable interface {
+	// Select returns challenges matching the selector, sorted
+	// by ID.
+	Select(sel *Selector) []challenge.Challenge
+
+	// Metadata returns the selection metadata of a challenge.
+	Metadata(id challenge.ID) (Metadata, error)
+}
+
+var (
+	_ Registry   = (*DefaultRegistry)(nil)
+	_ Selectable = (*DefaultRegistry)(nil)
+)
+
 // DefaultRegistry is the standard Registry implementation.
 // It is safe for concurrent use.
 type DefaultRegistry struct {
-	mu          sync.RWMutex
-	challenges  map[challenge.ID]challenge.Challenge
-	definitions map[challenge.ID]*challenge.Definition
+	mu           sync.RWMutex
+	challenges   map[challenge.ID]challenge.Challenge
+	definitions  map[challenge.ID]*challenge.Definition
+	factory      DefinitionFactory
+	materialized map[challenge.ID]bool
 }
 
 // NewRegistry creates a new, empty DefaultRegistry.
 func NewRegistry() *DefaultRegistry {
 	return &DefaultRegistry{
-		challenges:  make(map[challenge.ID]challenge.Challenge),
-		definitions: make(map[challenge.ID]*challenge.Definition),
+		challenges:   make(map[challenge.ID]challenge.Challenge),
+		definitions:  make(map[challenge.ID]*challenge.Definition),
+		materialized: make(map[challenge.ID]bool),
 	}
 }
 
@@ -73,7 +94,9 @@ func NewRegistry() *DefaultRegistry {
 var Default = NewRegistry()
 
 // Register adds a challenge to the registry. Returns an error
-// if a challenge with the same ID is already registered.
+// if a challenge with the same ID is already registered. A
+// challenge materialized from a definition is replaced: Go
+// implementations always take precedence.
 func (r *DefaultRegistry) Register(
 	c challenge.Challenge,
 ) error {
@@ -81,19 +104,23 @@ func (r *DefaultRegistry) Register(
 	defer r.mu.Unlock()
 
 	id := c.ID()
-	if _, exists := r.challenges[id]; exists {
+	if _, exists := r.challenges[id]; exists &&
+		!r.materialized[id] {
 		return fmt.Errorf(
 			"challenge already registered: %s", id,
 		)
 	}
 
 	r.challenges[id] = c
+	delete(r.materialized, id)
 	return nil
 }
 
 // RegisterDefinition adds a declarative challenge definition.
 // Returns an error if a definition with the same ID already
-// exists.
+// exists. When a DefinitionFactory is set and no challenge
+// with the same ID is registered, an executable challenge is
+// materialized from the definition.
 func (r *DefaultRegistry) RegisterDefinition(
 	def *challenge.Definition,
 ) error {
@@ -107,6 +134,9 @@ func (r *DefaultRegistry) RegisterDefinition(
 		)
 	}
 
+	if err := r.materializeLocked(def); err != nil {
+		return err
+	}
 	r.definitions[def.ID] = def
 	return nil
 }
@@ -213,6 +243,44 @@ func (r *DefaultRegistry) ListByCategory(
 	return out
 }
 
+// Select returns the challenges matching sel, sorted by ID. A
+// nil selector matches every challenge.
+func (r *DefaultRegistry) Select(
+	sel *Selector,
+) []challenge.Challenge {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []challenge.Challenge
+	for id, c := range r.challenges {
+		if sel.Match(challengeMetadata(c, r.definitions[id])) {
+			out = append(out, c)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID() < out[j].ID()
+	})
+	return out
+}
+
+// Metadata returns the selection metadata of a registered
+// challenge, combining its definition and Tagged values.
+func (r *DefaultRegistry) Metadata(
+	id challenge.ID,
+) (Metadata, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, exists := r.challenges[id]
+	if !exists {
+		return Metadata{}, fmt.Errorf(
+			"challenge not found: %s", id,
+		)
+	}
+	return challengeMetadata(c, r.definitions[id]), nil
+}
+
 // GetDependencyOrder returns challenges in topological order
 // using Kahn's algorithm. Returns an error if a dependency
 // cycle is detected.
@@ -257,6 +325,7 @@ func (r *DefaultRegistry) Clear() {
 	r.definitions = make(
 		map[challenge.ID]*challenge.Definition,
 	)
+	r.materialized = make(map[challenge.ID]bool)
 }
 
 // Count returns the number of registered challenges.
@@ -0,0 +1,484 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Metadata is the view of a challenge that a Selector matches
+// against.
+type Metadata struct {
+	ID       challenge.ID
+	Name     string
+	Category string
+	Tags     []string
+	Labels   map[string]string
+}
+
+// Selector is a parsed challenge selection expression. The
+// grammar is:
+//
+//	expr    = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" expr ")" | term
+//	term    = word                          (has tag)
+//	        | field ("=" | "!=") value
+//	        | field ("~" | "!~") regexp
+//	        | field "in" "(" value { "," value } ")"
+//
+// Fields are id, name, category and tag; any other field names
+// a label (label.<key> is accepted as well). Values are bare
+// words or quoted strings. Examples:
+//
+//	category=security && !slow
+//	tag in (smoke, p1)
+//	id~^example-sec-
+//
+// The empty expression matches every challenge.
+type Selector struct {
+	expr string
+	root selectorNode
+}
+
+// ParseSelector parses a selector expression.
+func ParseSelector(expr string) (*Selector, error) {
+	toks, err := lexSelector(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+	}
+	sel := &Selector{expr: strings.TrimSpace(expr)}
+	if len(toks) == 1 { // only tokEOF
+		return sel, nil
+	}
+
+	p := &selectorParser{toks: toks}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf(
+			"unexpected %q at position %d",
+			p.peek().text, p.peek().pos,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+	}
+	sel.root = root
+	return sel, nil
+}
+
+// String returns the source expression.
+func (s *Selector) String() string {
+	return s.expr
+}
+
+// Match reports whether m satisfies the selector.
+func (s *Selector) Match(m Metadata) bool {
+	if s == nil || s.root == nil {
+		return true
+	}
+	return s.root.match(&m)
+}
+
+// challengeMetadata builds the Metadata of c. Definition values
+// take precedence, matching ListByCategory; tags from a Tagged
+// challenge are merged with the definition tags.
+func challengeMetadata(
+	c challenge.Challenge,
+	def *challenge.Definition,
+) Metadata {
+	m := Metadata{
+		ID:       c.ID(),
+		Name:     c.Name(),
+		Category: c.Category(),
+		Labels:   make(map[string]string),
+	}
+	seen := make(map[string]bool)
+	addTags := func(tags []string) {
+		for _, t := range tags {
+			if !seen[t] {
+				seen[t] = true
+				m.Tags = append(m.Tags, t)
+			}
+		}
+	}
+
+	if t, ok := c.(challenge.Tagged); ok {
+		addTags(t.Tags())
+		for k, v := range t.Labels() {
+			m.Labels[k] = v
+		}
+	}
+	if def != nil {
+		if def.Name != "" {
+			m.Name = def.Name
+		}
+		m.Category = def.Category
+		addTags(def.Tags)
+		for k, v := range def.Labels {
+			m.Labels[k] = v
+		}
+	}
+	sort.Strings(m.Tags)
+	return m
+}
+
+// --- evaluation ---
+
+type selectorNode interface {
+	match(m *Metadata) bool
+}
+
+type andNode struct{ left, right selectorNode }
+
+func (n *andNode) match(m *Metadata) bool {
+	return n.left.match(m) && n.right.match(m)
+}
+
+type orNode struct{ left, right selectorNode }
+
+func (n *orNode) match(m *Metadata) bool {
+	return n.left.match(m) || n.right.match(m)
+}
+
+type notNode struct{ inner selectorNode }
+
+func (n *notNode) match(m *Metadata) bool {
+	return !n.inner.match(m)
+}
+
+// tagNode matches challenges carrying a tag.
+type tagNode struct{ tag string }
+
+func (n *tagNode) match(m *Metadata) bool {
+	for _, t := range m.Tags {
+		if t == n.tag {
+			return true
+		}
+	}
+	return false
+}
+
+// compareNode compares a field against values or a regexp. The
+// comparison succeeds when any value of the field matches; the
+// negated operators succeed when none does.
+type compareNode struct {
+	field  string
+	op     tokenKind
+	values []string
+	re     *regexp.Regexp
+}
+
+func (n *compareNode) match(m *Metadata) bool {
+	var hit bool
+	for _, v := range fieldValues(m, n.field) {
+		if n.re != nil {
+			hit = n.re.MatchString(v)
+		} else {
+			for _, want := range n.values {
+				if v == want {
+					hit = true
+					break
+				}
+			}
+		}
+		if hit {
+			break
+		}
+	}
+	if n.op == tokNeq || n.op == tokNotMatch {
+		return !hit
+	}
+	return hit
+}
+
+// fieldValues returns the values of field for m. A label that
+// is not set has no values.
+func fieldValues(m *Metadata, field string) []string {
+	switch field {
+	case "id":
+		return []string{string(m.ID)}
+	case "name":
+		return []string{m.Name}
+	case "category":
+		return []string{m.Category}
+	case "tag", "tags":
+		return m.Tags
+	}
+	key := strings.TrimPrefix(field, "label.")
+	if v, ok := m.Labels[key]; ok {
+		return []string{v}
+	}
+	return nil
+}
+
+// --- parsing ---
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokMatch
+	tokNotMatch
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lexSelector splits expr into tokens, ending with tokEOF.
+func lexSelector(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		two := ""
+		if i+1 < len(expr) {
+			two = expr[i : i+2]
+		}
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case two == "&&":
+			toks = append(toks, token{tokAnd, two, i})
+			i += 2
+		case two == "||":
+			toks = append(toks, token{tokOr, two, i})
+			i += 2
+		case two == "!=":
+			toks = append(toks, token{tokNeq, two, i})
+			i += 2
+		case two == "!~":
+			toks = append(toks, token{tokNotMatch, two, i})
+			i += 2
+		case two == "==":
+			toks = append(toks, token{tokEq, two, i})
+			i += 2
+		case ch == '!':
+			toks = append(toks, token{tokNot, "!", i})
+			i++
+		case ch == '=':
+			toks = append(toks, token{tokEq, "=", i})
+			i++
+		case ch == '~':
+			toks = append(toks, token{tokMatch, "~", i})
+			i++
+		case ch == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case ch == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case ch == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case ch == '"' || ch == '\'':
+			end := strings.IndexByte(expr[i+1:], ch)
+			if end < 0 {
+				return nil, fmt.Errorf(
+					"unterminated string at position %d", i,
+				)
+			}
+			toks = append(toks, token{
+				tokString, expr[i+1 : i+1+end], i,
+			})
+			i += end + 2
+		case ch == '&' || ch == '|':
+			return nil, fmt.Errorf(
+				"unexpected %q at position %d (use && or ||)",
+				string(ch), i,
+			)
+		default:
+			start := i
+			for i < len(expr) &&
+				!strings.ContainsRune(" \t\n\r()!,=~&|\"'", rune(expr[i])) {
+				i++
+			}
+			toks = append(toks, token{
+				tokWord, expr[start:i], start,
+			})
+		}
+	}
+	return append(toks, token{tokEOF, "end of input", len(expr)}), nil
+}
+
+type selectorParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *selectorParser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *selectorParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *selectorParser) parseOr() (selectorNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseAnd() (selectorNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseUnary() (selectorNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *selectorParser) parsePrimary() (selectorNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf(
+				"expected ) at position %d, got %q",
+				closing.pos, closing.text,
+			)
+		}
+		return inner, nil
+	case tokWord, tokString:
+		return p.parseTerm(t)
+	default:
+		return nil, fmt.Errorf(
+			"unexpected %q at position %d", t.text, t.pos,
+		)
+	}
+}
+
+// parseTerm parses what follows a field name or bare tag.
+func (p *selectorParser) parseTerm(field token) (selectorNode, error) {
+	op := p.peek()
+	switch {
+	case op.kind == tokEq || op.kind == tokNeq:
+		p.next()
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{
+			field: field.text, op: op.kind, values: []string{v},
+		}, nil
+	case op.kind == tokMatch || op.kind == tokNotMatch:
+		p.next()
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid regexp for %s: %w", field.text, err,
+			)
+		}
+		return &compareNode{field: field.text, op: op.kind, re: re}, nil
+	case op.kind == tokWord && op.text == "in":
+		p.next()
+		values, err := p.valueList()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{
+			field: field.text, op: tokEq, values: values,
+		}, nil
+	}
+	return &tagNode{tag: field.text}, nil
+}
+
+// value consumes a single word or string.
+func (p *selectorParser) value() (string, error) {
+	t := p.next()
+	if t.kind != tokWord && t.kind != tokString {
+		return "", fmt.Errorf(
+			"expected value at position %d, got %q", t.pos, t.text,
+		)
+	}
+	return t.text, nil
+}
+
+// valueList consumes "(" value { "," value } ")".
+func (p *selectorParser) valueList() ([]string, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, fmt.Errorf(
+			"expected ( after in at position %d, got %q",
+			t.pos, t.text,
+		)
+	}
+	var values []string
+	for {
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		t := p.next()
+		switch t.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, fmt.Errorf(
+				"expected , or ) at position %d, got %q",
+				t.pos, t.text,
+			)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taggedStub is a Go challenge implementing challenge.Tagged.
+type taggedStub struct {
+	*stubChallenge
+	tags   []string
+	labels map[string]string
+}
+
+func (s *taggedStub) Tags() []string            { return s.tags }
+func (s *taggedStub) Labels() map[string]string { return s.labels }
+
+func selectorMeta() Metadata {
+	return Metadata{
+		ID:       "example-sec-login",
+		Name:     "Login hardening",
+		Category: "security",
+		Tags:     []string{"p1", "slow"},
+		Labels:   map[string]string{"team": "payments"},
+	}
+}
+
+// ===== ParseSelector tests =====
+
+func TestSelector_Match(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"category=security", true},
+		{"category==security", true},
+		{"category!=security", false},
+		{"category=security && !slow", false},
+		{"category=security && !fast", true},
+		{"slow", true},
+		{"!slow", false},
+		{"tag in (smoke, p1)", true},
+		{"tag in (smoke)", false},
+		{"tag=p1", true},
+		{"tag!=p1", false},
+		{"id~^example-sec-", true},
+		{"id!~^example-sec-", false},
+		{"id~^other", false},
+		{"team=payments", true},
+		{"label.team=payments", true},
+		{"team in (billing, payments)", true},
+		{"owner=x", false},
+		{"owner!=x", true},
+		{`name="Login hardening"`, true},
+		{"name='Login hardening'", true},
+		{"smoke || category=security", true},
+		{"smoke || category=ui && p1", false},
+		{"(smoke || category=security) && p1", true},
+		{"!(slow && p1)", false},
+		{`id~"^example-(sec|ops)-"`, true},
+	}
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, sel.Match(selectorMeta()), tt.expr)
+	}
+}
+
+func TestParseSelector_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"category=",
+		"category=security &&",
+		"(smoke",
+		"smoke)",
+		"tag in smoke",
+		"tag in (smoke",
+		"tag in (smoke p1)",
+		"id~(",
+		`name="open`,
+		"smoke & p1",
+		"&&",
+		"smoke p1",
+	} {
+		_, err := ParseSelector(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestParseSelector_ErrorMessage(t *testing.T) {
+	_, err := ParseSelector("category=security &&")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid selector "category=security &&"`)
+	assert.Contains(t, err.Error(), "position 20")
+}
+
+func TestSelector_String(t *testing.T) {
+	sel, err := ParseSelector("  smoke && p1 ")
+	require.NoError(t, err)
+	assert.Equal(t, "smoke && p1", sel.String())
+}
+
+func TestSelector_NilMatchesAll(t *testing.T) {
+	var sel *Selector
+	assert.True(t, sel.Match(Metadata{}))
+}
+
+// ===== Registry selection tests =====
+
+func TestDefaultRegistry_Select(t *testing.T) {
+	r := NewRegistry()
+
+	sec := newStub("example-sec-a")
+	sec.category = "security"
+	require.NoError(t, r.Register(sec))
+	require.NoError(t, r.Register(&taggedStub{
+		stubChallenge: newStub("go-smoke"),
+		tags:          []string{"smoke"},
+		labels:        map[string]string{"team": "core"},
+	}))
+	require.NoError(t, r.Register(newStub("plain")))
+	require.NoError(t, r.RegisterDefinition(&challenge.Definition{
+		ID:       "example-sec-a",
+		Category: "security",
+		Tags:     []string{"slow"},
+		Labels:   map[string]string{"team": "payments"},
+	}))
+
+	ids := func(expr string) []challenge.ID {
+		sel, err := ParseSelector(expr)
+		require.NoError(t, err)
+		var out []challenge.ID
+		for _, c := range r.Select(sel) {
+			out = append(out, c.ID())
+		}
+		return out
+	}
+
+	assert.Equal(t, []challenge.ID{"example-sec-a"}, ids("slow"))
+	assert.Equal(t, []challenge.ID{"go-smoke"}, ids("tag in (smoke, p1)"))
+	assert.Equal(t, []challenge.ID{"go-smoke", "plain"}, ids("!slow"))
+	assert.Equal(t, []challenge.ID{"example-sec-a"},
+		ids("id~^example-sec- && team=payments"))
+	assert.Len(t, ids(""), 3)
+	assert.Empty(t, ids("nothing"))
+}
+
+func TestDefaultRegistry_Metadata(t *testing.T) {
+	r := NewRegistry()
+	require.NoError(t, r.Register(&taggedStub{
+		stubChallenge: newStub("a"),
+		tags:          []string{"smoke", "p1"},
+		labels:        map[string]string{"team": "core", "tier": "1"},
+	}))
+	require.NoError(t, r.RegisterDefinition(&challenge.Definition{
+		ID:       "a",
+		Name:     "Defined A",
+		Category: "api",
+		Tags:     []string{"p1", "nightly"},
+		Labels:   map[string]string{"team": "payments"},
+	}))
+
+	m, err := r.Metadata("a")
+	require.NoError(t, err)
+	assert.Equal(t, "Defined A", m.Name)
+	assert.Equal(t, "api", m.Category)
+	assert.Equal(t, []string{"nightly", "p1", "smoke"}, m.Tags)
+	assert.Equal(t, map[string]string{
+		"team": "payments", "tier": "1",
+	}, m.Labels)
+
+	_, err = r.Metadata("missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,690 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
+)
+
+// Bundle defaults.
+const (
+	defaultThumbnailCount   = 3
+	defaultLogExcerptBytes  = 4096
+	defaultMaxImageBytes    = 2 << 20
+	defaultThumbnailTimeout = 30 * time.Second
+)
+
+// ThumbnailExtractor extracts key frame thumbnails of a video
+// into outputDir and returns their paths.
+// userflow.RecordingValidator implements it.
+type ThumbnailExtractor interface {
+	ExtractThumbnails(
+		ctx context.Context,
+		filePath, outputDir string,
+		count int,
+	) ([]string, error)
+}
+
+// BundleOptions configures a BundleReporter.
+type BundleOptions struct {
+	// Categories maps challenge IDs to the categories offered
+	// by the category filter.
+	Categories map[challenge.ID]string
+
+	// Dependencies maps challenge IDs to the challenges they
+	// depend on, drawn as the dependency graph of the run.
+	Dependencies map[challenge.ID][]challenge.ID
+
+	// Thumbnails, when set, extracts key frames of video
+	// outputs and artifacts to embed.
+	Thumbnails ThumbnailExtractor
+
+	// ThumbnailCount is the number of key frames per video
+	// (default 3).
+	ThumbnailCount int
+
+	// ThumbnailTimeout bounds the extraction of the thumbnails
+	// of one video (default 30s).
+	ThumbnailTimeout time.Duration
+
+	// LogExcerptBytes is the size of the tail of each log file
+	// that is embedded (default 4096).
+	LogExcerptBytes int
+
+	// MaxImageBytes is the size above which images are listed
+	// by path instead of embedded (default 2 MiB).
+	MaxImageBytes int64
+}
+
+// BundleReporter generates self-contained HTML reports: images
+// and video thumbnails are embedded as base64, logs as
+// excerpts, and the master summary adds status and category
+// filters and a dependency graph. The pages need no external
+// files and can be copied off the machine that produced them.
+type BundleReporter struct {
+	html *HTMLReporter
+	opts BundleOptions
+}
+
+// NewBundleReporter creates a new single-file HTML bundle
+// reporter.
+func NewBundleReporter(
+	outputDir string,
+	opts BundleOptions,
+) *BundleReporter {
+	if opts.ThumbnailCount <= 0 {
+		opts.ThumbnailCount = defaultThumbnailCount
+	}
+	if opts.ThumbnailTimeout <= 0 {
+		opts.ThumbnailTimeout = defaultThumbnailTimeout
+	}
+	if opts.LogExcerptBytes <= 0 {
+		opts.LogExcerptBytes = defaultLogExcerptBytes
+	}
+	if opts.MaxImageBytes <= 0 {
+		opts.MaxImageBytes = defaultMaxImageBytes
+	}
+	return &BundleReporter{
+		html: NewHTMLReporter(outputDir),
+		opts: opts,
+	}
+}
+
+// SetHistory sets the run history the master summary renders
+// a trend section from. A nil history omits the section.
+func (r *BundleReporter) SetHistory(h *history.History) {
+	r.html.SetHistory(h)
+}
+
+// GenerateReport creates a self-contained HTML report for a
+// single challenge result.
+func (r *BundleReporter) GenerateReport(
+	result *challenge.Result,
+) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.WriteReport(&buf, result); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteReport writes a self-contained HTML report for a single
+// challenge result to the specified writer.
+func (r *BundleReporter) WriteReport(
+	w io.Writer,
+	result *challenge.Result,
+) error {
+	var buf bytes.Buffer
+	r.html.writeHeaderWithStyle(
+		&buf, "Challenge Report: "+result.ChallengeName, bundleCSS,
+	)
+	fmt.Fprintf(
+		&buf, "<h1>Challenge Report: %s</h1>\n",
+		html.EscapeString(result.ChallengeName),
+	)
+	r.writeChallenge(&buf, result)
+	r.html.writeFooter(&buf)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// GenerateMasterSummary creates a self-contained HTML bundle of
+// all challenge results with filters and a dependency graph.
+func (r *BundleReporter) GenerateMasterSummary(
+	results []*challenge.Result,
+) ([]byte, error) {
+	var buf bytes.Buffer
+	title := "Challenges Framework - Run Report"
+	r.html.writeHeaderWithStyle(&buf, title, bundleCSS)
+	fmt.Fprintf(&buf, "<h1>%s</h1>\n", title)
+	fmt.Fprintf(
+		&buf, "<p><strong>Generated:</strong> %s</p>\n",
+		time.Now().Format(time.RFC3339),
+	)
+
+	r.html.writeMasterStats(&buf, results)
+	r.html.writeTrends(&buf, results)
+	r.writeFilters(&buf, results)
+	r.writeGraph(&buf, results)
+	for _, result := range results {
+		r.writeChallenge(&buf, result)
+	}
+	fmt.Fprintf(&buf, "<script>%s</script>\n", bundleScript)
+	r.html.writeFooter(&buf)
+	return buf.Bytes(), nil
+}
+
+// writeChallenge writes the section of a challenge result.
+func (r *BundleReporter) writeChallenge(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	category := r.opts.Categories[result.ChallengeID]
+	fmt.Fprintf(
+		w,
+		"<section class=\"challenge\" id=\"%s\" "+
+			"data-status=\"%s\" data-category=\"%s\">\n",
+		anchorID(result.ChallengeID),
+		html.EscapeString(result.Status),
+		html.EscapeString(category),
+	)
+	fmt.Fprintf(
+		w, "<h2 class=\"challenge-title\">%s <small>%s</small></h2>\n",
+		html.EscapeString(result.ChallengeName),
+		html.EscapeString(string(result.ChallengeID)),
+	)
+	if category != "" {
+		fmt.Fprintf(
+			w, "<p><strong>Category:</strong> %s</p>\n",
+			html.EscapeString(category),
+		)
+	}
+	if deps := r.opts.Dependencies[result.ChallengeID]; len(deps) > 0 {
+		links := make([]string, len(deps))
+		for i, dep := range deps {
+			links[i] = fmt.Sprintf(
+				"<a href=\"#%s\">%s</a>",
+				anchorID(dep), html.EscapeString(string(dep)),
+			)
+		}
+		fmt.Fprintf(
+			w, "<p><strong>Depends on:</strong> %s</p>\n",
+			strings.Join(links, ", "),
+		)
+	}
+
+	r.html.writeSummaryTable(w, result)
+	r.html.writeAttemptsSection(w, result)
+	r.html.writeMetricsSection(w, result)
+	r.writeAssertionTree(w, result)
+	r.writeArtifacts(w, result)
+	r.writeLogExcerpts(w, result)
+	fmt.Fprintln(w, "</section>")
+}
+
+// writeAssertionTree writes the assertions as nested,
+// collapsible details with their expected and actual values.
+// Failed assertions are expanded.
+func (r *BundleReporter) writeAssertionTree(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	if len(result.Assertions) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "<h3>Assertions</h3>")
+	fmt.Fprintln(w, "<ul class=\"tree\">")
+	for _, a := range result.Assertions {
+		writeAssertionNode(w, a)
+	}
+	fmt.Fprintln(w, "</ul>")
+}
+
+// writeAssertionNode writes an assertion and its sub-results.
+func writeAssertionNode(w io.Writer, a challenge.AssertionResult) {
+	cls, mark := "status-failed", "&#10008;"
+	switch {
+	case a.Passed:
+		cls, mark = "status-passed", "&#10004;"
+	case !a.IsBlocking():
+		cls = "status-warning"
+	}
+	open := ""
+	if !a.Passed {
+		open = " open"
+	}
+	fmt.Fprintf(
+		w,
+		"<li><details%s><summary><span class=\"%s\">%s</span> "+
+			"<code>%s</code> %s &mdash; %s</summary>\n",
+		open, cls, mark,
+		html.EscapeString(a.Type), html.EscapeString(a.Target),
+		html.EscapeString(assertionPassedLabel(a)+": "+a.Message),
+	)
+	fmt.Fprintf(
+		w,
+		"<dl><dt>Expected</dt><dd><code>%s</code></dd>"+
+			"<dt>Actual</dt><dd><code>%s</code></dd></dl>\n",
+		html.EscapeString(formatValue(a.Expected)),
+		html.EscapeString(formatValue(a.Actual)),
+	)
+	if len(a.Children) > 0 {
+		fmt.Fprintln(w, "<ul class=\"tree\">")
+		for _, c := range a.Children {
+			writeAssertionNode(w, c)
+		}
+		fmt.Fprintln(w, "</ul>")
+	}
+	fmt.Fprintln(w, "</details></li>")
+}
+
+// writeArtifacts writes the outputs and artifacts of a result
+// that are files: images are embedded, videos as thumbnails
+// when a ThumbnailExtractor is set, other files by path.
+// Outputs that are plain values rather than files are left out.
+func (r *BundleReporter) writeArtifacts(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	type artifact struct{ name, path string }
+	var files []artifact
+	add := func(name, path string) {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			files = append(files, artifact{name, path})
+		}
+	}
+	for _, name := range sortedKeys(result.Outputs) {
+		add(name, result.Outputs[name])
+	}
+	for _, name := range sortedKeys(result.Logs.Artifacts) {
+		add(name, result.Logs.Artifacts[name])
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "<h3>Artifacts</h3>")
+	var other []artifact
+	for _, f := range files {
+		ext := strings.ToLower(filepath.Ext(f.path))
+		var embedded bool
+		switch {
+		case imageTypes[ext] != "":
+			embedded = r.writeImage(w, f.name, f.path)
+		case videoTypes[ext]:
+			embedded = r.writeThumbnails(w, f.name, f.path)
+		}
+		if !embedded {
+			other = append(other, f)
+		}
+	}
+	if len(other) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "<table>")
+	fmt.Fprintln(w, "<tr><th>Name</th><th>Path</th></tr>")
+	for _, f := range other {
+		fmt.Fprintf(
+			w, "<tr><td>%s</td><td><code>%s</code></td></tr>\n",
+			html.EscapeString(f.name), html.EscapeString(f.path),
+		)
+	}
+	fmt.Fprintln(w, "</table>")
+}
+
+// imageTypes maps the extensions of embedded images to their
+// media types.
+var imageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// videoTypes lists the extensions of videos whose thumbnails
+// are embedded.
+var videoTypes = map[string]bool{
+	".mp4": true, ".webm": true, ".mkv": true,
+	".mov": true, ".avi": true,
+}
+
+// writeImage embeds the image at path and reports whether it
+// did; missing and oversized images are not embedded.
+func (r *BundleReporter) writeImage(w io.Writer, name, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > r.opts.MaxImageBytes {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	writeFigure(w, name, imageTypes[strings.ToLower(filepath.Ext(path))], data)
+	return true
+}
+
+// writeThumbnails embeds key frames of the video at path and
+// reports whether it did.
+func (r *BundleReporter) writeThumbnails(w io.Writer, name, path string) bool {
+	if r.opts.Thumbnails == nil {
+		return false
+	}
+	dir, err := os.MkdirTemp("", "report-thumbnails-")
+	if err != nil {
+		return false
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), r.opts.ThumbnailTimeout,
+	)
+	defer cancel()
+	thumbs, err := r.opts.Thumbnails.ExtractThumbnails(
+		ctx, path, dir, r.opts.ThumbnailCount,
+	)
+	if err != nil || len(thumbs) == 0 {
+		return false
+	}
+	embedded := false
+	for i, thumb := range thumbs {
+		data, err := os.ReadFile(thumb)
+		if err != nil {
+			continue
+		}
+		mediaType := imageTypes[strings.ToLower(filepath.Ext(thumb))]
+		if mediaType == "" {
+			mediaType = "image/png"
+		}
+		writeFigure(w, fmt.Sprintf("%s (frame %d)", name, i+1), mediaType, data)
+		embedded = true
+	}
+	return embedded
+}
+
+// writeFigure writes an embedded image with its caption.
+func writeFigure(w io.Writer, caption, mediaType string, data []byte) {
+	fmt.Fprintf(
+		w,
+		"<figure><img src=\"data:%s;base64,%s\" alt=\"%s\">"+
+			"<figcaption>%s</figcaption></figure>\n",
+		mediaType, base64.StdEncoding.EncodeToString(data),
+		html.EscapeString(caption), html.EscapeString(caption),
+	)
+}
+
+// writeLogExcerpts embeds the tail of each log file of a
+// result.
+func (r *BundleReporter) writeLogExcerpts(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	logs := [][2]string{
+		{"Challenge Log", result.Logs.ChallengeLog},
+		{"Output Log", result.Logs.OutputLog},
+		{"API Requests", result.Logs.APIRequests},
+		{"API Responses", result.Logs.APIResponses},
+	}
+	wrote := false
+	for _, l := range logs {
+		if l[1] == "" {
+			continue
+		}
+		if !wrote {
+			fmt.Fprintln(w, "<h3>Logs</h3>")
+			wrote = true
+		}
+		excerpt, err := logExcerpt(l[1], r.opts.LogExcerptBytes)
+		if err != nil {
+			fmt.Fprintf(
+				w, "<p>%s: <code>%s</code> (not readable)</p>\n",
+				l[0], html.EscapeString(l[1]),
+			)
+			continue
+		}
+		fmt.Fprintf(
+			w,
+			"<details><summary>%s <code>%s</code></summary>"+
+				"<pre>%s</pre></details>\n",
+			l[0], html.EscapeString(l[1]), html.EscapeString(excerpt),
+		)
+	}
+}
+
+// logExcerpt returns the last limit bytes of the file at path,
+// noting how much was cut.
+func logExcerpt(path string, limit int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if len(data) <= limit {
+		return strings.ToValidUTF8(string(data), "?"), nil
+	}
+	cut := len(data) - limit
+	tail := strings.ToValidUTF8(string(data[cut:]), "?")
+	return fmt.Sprintf("... (%d bytes truncated)\n%s", cut, tail), nil
+}
+
+// writeFilters writes the status and category filters of the
+// master summary.
+func (r *BundleReporter) writeFilters(
+	w io.Writer,
+	results []*challenge.Result,
+) {
+	statuses := make(map[string]bool)
+	categories := make(map[string]bool)
+	for _, res := range results {
+		statuses[res.Status] = true
+		if c := r.opts.Categories[res.ChallengeID]; c != "" {
+			categories[c] = true
+		}
+	}
+	fmt.Fprintln(w, "<div class=\"filters\">")
+	writeSelect(w, "filter-status", "Status", statuses)
+	if len(categories) > 0 {
+		writeSelect(w, "filter-category", "Category", categories)
+	}
+	fmt.Fprintln(w, "</div>")
+}
+
+// writeSelect writes a labelled filter select with an "all"
+// option followed by the sorted values.
+func writeSelect(w io.Writer, id, label string, values map[string]bool) {
+	fmt.Fprintf(
+		w, "<label>%s <select id=\"%s\"><option value=\"\">all</option>",
+		label, id,
+	)
+	for _, v := range sortedKeys(values) {
+		fmt.Fprintf(
+			w, "<option value=\"%s\">%s</option>",
+			html.EscapeString(v), html.EscapeString(v),
+		)
+	}
+	fmt.Fprintln(w, "</select></label>")
+}
+
+// Dependency graph layout in pixels.
+const (
+	graphNodeWidth  = 180
+	graphNodeHeight = 28
+	graphColumnGap  = 50
+	graphRowGap     = 12
+)
+
+// writeGraph draws the dependencies between the results as an
+// inline SVG: one column per dependency depth, nodes coloured
+// by status and linked to their sections.
+func (r *BundleReporter) writeGraph(
+	w io.Writer,
+	results []*challenge.Result,
+) {
+	present := make(map[challenge.ID]*challenge.Result, len(results))
+	for _, res := range results {
+		present[res.ChallengeID] = res
+	}
+	hasEdges := false
+	for _, res := range results {
+		for _, dep := range r.opts.Dependencies[res.ChallengeID] {
+			if present[dep] != nil {
+				hasEdges = true
+			}
+		}
+	}
+	if !hasEdges {
+		return
+	}
+
+	depth := make(map[challenge.ID]int, len(results))
+	var level func(id challenge.ID, visiting map[challenge.ID]bool) int
+	level = func(id challenge.ID, visiting map[challenge.ID]bool) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		if visiting[id] {
+			return 0 // a cycle; the runner rejects them
+		}
+		visiting[id] = true
+		d := 0
+		for _, dep := range r.opts.Dependencies[id] {
+			if present[dep] != nil {
+				d = max(d, level(dep, visiting)+1)
+			}
+		}
+		depth[id] = d
+		return d
+	}
+	type point struct{ x, y int }
+	pos := make(map[challenge.ID]point, len(results))
+	rows := make(map[int]int)
+	width, height := 0, 0
+	for _, res := range results {
+		col := level(res.ChallengeID, make(map[challenge.ID]bool))
+		p := point{
+			x: col * (graphNodeWidth + graphColumnGap),
+			y: rows[col] * (graphNodeHeight + graphRowGap),
+		}
+		rows[col]++
+		pos[res.ChallengeID] = p
+		width = max(width, p.x+graphNodeWidth)
+		height = max(height, p.y+graphNodeHeight)
+	}
+
+	fmt.Fprintln(w, "<h2>Dependency Graph</h2>")
+	fmt.Fprintf(
+		w,
+		"<svg class=\"graph\" width=\"%d\" height=\"%d\" "+
+			"viewBox=\"0 0 %d %d\" role=\"img\">\n",
+		width, height, width, height,
+	)
+	for _, res := range results {
+		to := pos[res.ChallengeID]
+		for _, dep := range r.opts.Dependencies[res.ChallengeID] {
+			from, ok := pos[dep]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(
+				w,
+				"<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" "+
+					"stroke=\"#95a5a6\"/>\n",
+				from.x+graphNodeWidth, from.y+graphNodeHeight/2,
+				to.x, to.y+graphNodeHeight/2,
+			)
+		}
+	}
+	for _, res := range results {
+		p := pos[res.ChallengeID]
+		label := res.ChallengeName
+		if label == "" {
+			label = string(res.ChallengeID)
+		}
+		if len([]rune(label)) > 24 {
+			label = string([]rune(label)[:23]) + "…"
+		}
+		fmt.Fprintf(
+			w,
+			"<a href=\"#%s\" class=\"graph-node\" data-status=\"%s\">"+
+				"<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" "+
+				"rx=\"4\" fill=\"%s\"/><text x=\"%d\" y=\"%d\">%s</text>"+
+				"<title>%s</title></a>\n",
+			anchorID(res.ChallengeID), html.EscapeString(res.Status),
+			p.x, p.y, graphNodeWidth, graphNodeHeight,
+			statusColor(res), p.x+8, p.y+graphNodeHeight/2+4,
+			html.EscapeString(label),
+			html.EscapeString(string(res.ChallengeID)+": "+statusLabel(res)),
+		)
+	}
+	fmt.Fprintln(w, "</svg>")
+}
+
+// statusColor returns the graph fill colour of a result.
+func statusColor(result *challenge.Result) string {
+	switch statusClass(result) {
+	case "status-passed":
+		return "#d5f5e3"
+	case "status-warning":
+		return "#fcf3cf"
+	case "status-flaky":
+		return "#fae5d3"
+	}
+	if result.Status == challenge.StatusSkipped {
+		return "#eaeded"
+	}
+	return "#fadbd8"
+}
+
+// anchorID returns the HTML id of a challenge section.
+func anchorID(id challenge.ID) string {
+	var b strings.Builder
+	b.WriteString("challenge-")
+	for _, c := range string(id) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9', c == '-', c == '_':
+			b.WriteRune(c)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// sortedKeys returns the keys of m, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// bundleCSS styles the bundle-only elements.
+const bundleCSS = `.challenge { border-top: 2px solid #3498db; margin-top: 30px; }
+.challenge h2 { font-size: 1.15em; }
+.challenge-title small { color: #7f8c8d; font-weight: normal; }
+.filters { margin: 15px 0; }
+.filters label { margin-right: 15px; }
+ul.tree { list-style: none; padding-left: 18px; }
+ul.tree dl { margin: 4px 0 4px 18px; }
+ul.tree dt { font-weight: bold; }
+figure { display: inline-block; margin: 5px; }
+figure img { max-width: 300px; border: 1px solid #ddd; }
+pre { background: #2c3e50; color: #ecf0f1; padding: 10px; overflow-x: auto; }
+.graph text { font-size: 12px; fill: #2c3e50; }
+`
+
+// bundleScript applies the status and category filters to the
+// challenge sections and dims filtered-out graph nodes.
+const bundleScript = `(function () {
+  var status = document.getElementById("filter-status");
+  var category = document.getElementById("filter-category");
+  function matches(el) {
+    return (!status || status.value === "" || el.dataset.status === status.value) &&
+      (!category || category.value === "" || el.dataset.category === category.value);
+  }
+  function apply() {
+    document.querySelectorAll("section.challenge").forEach(function (el) {
+      el.hidden = !matches(el);
+    });
+    document.querySelectorAll(".graph-node").forEach(function (el) {
+      var section = document.getElementById(el.getAttribute("href").slice(1));
+      el.style.opacity = section && section.hidden ? 0.25 : 1;
+    });
+  }
+  [status, category].forEach(function (el) {
+    if (el) { el.addEventListener("change", apply); }
+  });
+})();`
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// fakeThumbnails writes count thumbnail files for any video.
+type fakeThumbnails struct {
+	calls    int
+	deadline bool
+}
+
+func (f *fakeThumbnails) ExtractThumbnails(
+	ctx context.Context, _, outputDir string, count int,
+) ([]string, error) {
+	f.calls++
+	_, f.deadline = ctx.Deadline()
+	var paths []string
+	for i := 1; i <= count; i++ {
+		p := filepath.Join(outputDir, fmt.Sprintf("thumb_%d.png", i))
+		if err := os.WriteFile(p, []byte("frame"), 0644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+func TestBundleReporter_GenerateReport(t *testing.T) {
+	dir := t.TempDir()
+	png := filepath.Join(dir, "shot.png")
+	require.NoError(t, os.WriteFile(png, []byte("PNGDATA"), 0644))
+	video := filepath.Join(dir, "run.mp4")
+	require.NoError(t, os.WriteFile(video, []byte("video"), 0644))
+	text := filepath.Join(dir, "report.txt")
+	require.NoError(t, os.WriteFile(text, []byte("report"), 0644))
+	logPath := filepath.Join(dir, "challenge.log")
+	require.NoError(t, os.WriteFile(logPath,
+		[]byte(strings.Repeat("x", 100)+"last <line>\n"), 0644))
+
+	result := makeTestResult()
+	result.Assertions = []challenge.AssertionResult{{
+		Type: "all_of", Target: "body", Message: "1 of 2 failed",
+		Children: []challenge.AssertionResult{
+			{Type: "contains", Target: "body", Passed: true, Expected: "ok"},
+			{Type: "equals", Target: "code", Expected: 200, Actual: 500},
+		},
+	}}
+	result.Outputs = map[string]string{
+		"screenshot": png, "recording": video, "report": text,
+		"status_code": "200", "missing": filepath.Join(dir, "gone.txt"),
+	}
+	result.Logs = challenge.LogPaths{
+		ChallengeLog: logPath, OutputLog: filepath.Join(dir, "missing.log"),
+	}
+	thumbs := &fakeThumbnails{}
+	r := NewBundleReporter(dir, BundleOptions{
+		Thumbnails: thumbs, ThumbnailCount: 2, LogExcerptBytes: 20,
+	})
+
+	data, err := r.GenerateReport(result)
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.Contains(t, out, `<img src="data:image/png;base64,`+
+		base64.StdEncoding.EncodeToString([]byte("PNGDATA"))+`" alt="screenshot">`)
+	assert.Equal(t, 1, thumbs.calls)
+	assert.True(t, thumbs.deadline, "thumbnail extraction is bounded")
+	assert.Contains(t, out, "recording (frame 2)")
+	assert.Contains(t, out, "<code>"+text+"</code>")
+	assert.NotContains(t, out, "<code>200</code></td>")
+	assert.NotContains(t, out, "gone.txt")
+	assert.Contains(t, out, "... (92 bytes truncated)\nxxxxxxxxlast &lt;line&gt;")
+	assert.Contains(t, out, "missing.log</code> (not readable)")
+	assert.Contains(t, out, "<li><details open><summary>")
+	assert.Contains(t, out, "<dt>Expected</dt><dd><code>200</code></dd><dt>Actual</dt><dd><code>500</code></dd>")
+	assert.Contains(t, out, `<section class="challenge" id="challenge-test-001" data-status="passed"`)
+	assert.NotContains(t, out, "<script src")
+	assert.NotContains(t, out, "<link")
+}
+
+func TestBundleReporter_LargeImageNotEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	png := filepath.Join(dir, "big.png")
+	require.NoError(t, os.WriteFile(png, make([]byte, 64), 0644))
+	result := makeTestResult()
+	result.Outputs = map[string]string{"big": png}
+	result.Logs = challenge.LogPaths{}
+
+	r := NewBundleReporter(dir, BundleOptions{MaxImageBytes: 10})
+	data, err := r.GenerateReport(result)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "data:image/png")
+	assert.Contains(t, string(data), "big.png</code>")
+	assert.NotContains(t, string(data), "<h3>Logs</h3>")
+}
+
+func TestBundleReporter_GenerateMasterSummary(t *testing.T) {
+	results := makeTestResults()
+	results = append(results, &challenge.Result{
+		ChallengeID: "test 003", ChallengeName: "Third",
+		Status: challenge.StatusSkipped,
+	})
+	r := NewBundleReporter(t.TempDir(), BundleOptions{
+		Categories: map[challenge.ID]string{"test-001": "api", "test-002": "web"},
+		Dependencies: map[challenge.ID][]challenge.ID{
+			"test-002": {"test-001"},
+			"test 003": {"test-002", "outside-run"},
+		},
+	})
+
+	data, err := r.GenerateMasterSummary(results)
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.Contains(t, out, `<select id="filter-status"><option value="">all</option>`+
+		`<option value="failed">failed</option><option value="passed">passed</option>`+
+		`<option value="skipped">skipped</option></select>`)
+	assert.Contains(t, out, `<option value="api">api</option><option value="web">web</option>`)
+	assert.Contains(t, out, "<h2>Dependency Graph</h2>")
+	assert.Equal(t, 2, strings.Count(out, "<line "))
+	assert.Contains(t, out, `<a href="#challenge-test_003" class="graph-node" data-status="skipped">`+
+		`<rect x="460" y="0"`)
+	assert.Contains(t, out, `<strong>Depends on:</strong> <a href="#challenge-test-002">test-002</a>`)
+	assert.Contains(t, out, `data-category="web"`)
+	assert.Contains(t, out, "<script>(function () {")
+	assert.Equal(t, 3, strings.Count(out, `<section class="challenge"`))
+}
+
+func TestBundleReporter_NoGraphWithoutDependencies(t *testing.T) {
+	r := NewBundleReporter(t.TempDir(), BundleOptions{})
+	data, err := r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Dependency Graph")
+	assert.NotContains(t, string(data), `id="filter-category"`)
+}
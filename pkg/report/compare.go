@@ -0,0 +1,427 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// ChangeKind classifies a challenge in a run comparison.
+type ChangeKind string
+
+// Change kinds, in the order comparison reports list them.
+const (
+	ChangeNewFailure   ChangeKind = "new_failure"
+	ChangeRegressed    ChangeKind = "regressed"
+	ChangeStillFailing ChangeKind = "still_failing"
+	ChangeFixed        ChangeKind = "fixed"
+	ChangeAdded        ChangeKind = "added"
+	ChangeRemoved      ChangeKind = "removed"
+	ChangeUnchanged    ChangeKind = "unchanged"
+)
+
+// changeKinds lists the change kinds in report order.
+var changeKinds = []ChangeKind{
+	ChangeNewFailure, ChangeRegressed, ChangeStillFailing,
+	ChangeFixed, ChangeAdded, ChangeRemoved, ChangeUnchanged,
+}
+
+// CompareOptions sets the thresholds beyond which a passing
+// challenge counts as performance-regressed. Zero thresholds
+// disable the check.
+type CompareOptions struct {
+	// DurationThreshold is the relative duration increase
+	// (0.2 = 20% slower) that is a regression.
+	DurationThreshold float64 `json:"duration_threshold,omitempty"`
+
+	// MinDurationDelta ignores duration increases smaller than
+	// this, so that fast challenges do not regress on noise.
+	MinDurationDelta time.Duration `json:"min_duration_delta,omitempty"`
+
+	// MetricThreshold is the relative metric change that is a
+	// regression: an increase, or a decrease for the metrics
+	// in HigherIsBetter.
+	MetricThreshold float64 `json:"metric_threshold,omitempty"`
+
+	// HigherIsBetter names the metrics (e.g., throughput) for
+	// which a decrease is a regression.
+	HigherIsBetter []string `json:"higher_is_better,omitempty"`
+}
+
+// Delta is a duration or metric change beyond its threshold.
+type Delta struct {
+	// Name is "duration" (in seconds) or the metric name.
+	Name string `json:"name"`
+
+	// Baseline and Current are the compared values.
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+
+	// Change is the relative change, (Current - Baseline) /
+	// Baseline.
+	Change float64 `json:"change"`
+}
+
+// ChallengeComparison is the comparison of one challenge.
+type ChallengeComparison struct {
+	ChallengeID      challenge.ID  `json:"challenge_id"`
+	ChallengeName    string        `json:"challenge_name"`
+	Change           ChangeKind    `json:"change"`
+	BaselineStatus   string        `json:"baseline_status,omitempty"`
+	CurrentStatus    string        `json:"current_status,omitempty"`
+	BaselineDuration time.Duration `json:"baseline_duration,omitempty"`
+	CurrentDuration  time.Duration `json:"current_duration,omitempty"`
+
+	// Deltas lists the duration and metric changes beyond the
+	// thresholds, also for challenges that did not pass.
+	Deltas []Delta `json:"deltas,omitempty"`
+}
+
+// Comparison is the run-to-run comparison of a baseline and a
+// current run.
+type Comparison struct {
+	Baseline    string                `json:"baseline"`
+	Current     string                `json:"current"`
+	GeneratedAt time.Time             `json:"generated_at"`
+	Options     CompareOptions        `json:"options"`
+	Counts      map[ChangeKind]int    `json:"counts"`
+	Challenges  []ChallengeComparison `json:"challenges"`
+}
+
+// Regressed returns true if a challenge newly failed, including
+// one that is new in the current run and does not pass, or, when
+// performance is true, regressed in duration or metrics. Use it
+// to gate merges.
+func (c *Comparison) Regressed(performance bool) bool {
+	if c.Counts[ChangeNewFailure] > 0 {
+		return true
+	}
+	return performance && c.Counts[ChangeRegressed] > 0
+}
+
+// CompareSummaries compares the challenges of two master
+// summaries. A challenge only in the current run is added when
+// it passes and a new failure otherwise. Challenges are listed
+// by change kind (new failures first), then in current run order
+// with removed challenges in baseline order.
+func CompareSummaries(
+	baseline, current *MasterSummary,
+	opts CompareOptions,
+) *Comparison {
+	c := &Comparison{
+		Baseline:    baseline.ID,
+		Current:     current.ID,
+		GeneratedAt: time.Now(),
+		Options:     opts,
+		Counts:      make(map[ChangeKind]int),
+	}
+	base := make(map[challenge.ID]ChallengeSummary, len(baseline.Challenges))
+	for _, cs := range baseline.Challenges {
+		base[cs.ChallengeID] = cs
+	}
+	seen := make(map[challenge.ID]bool, len(current.Challenges))
+	for _, cur := range current.Challenges {
+		seen[cur.ChallengeID] = true
+		cc := ChallengeComparison{
+			ChallengeID:     cur.ChallengeID,
+			ChallengeName:   cur.ChallengeName,
+			CurrentStatus:   cur.Status,
+			CurrentDuration: cur.Duration,
+			Change:          ChangeAdded,
+		}
+		if b, ok := base[cur.ChallengeID]; ok {
+			cc.BaselineStatus = b.Status
+			cc.BaselineDuration = b.Duration
+			cc.Deltas = compareDeltas(b, cur, opts)
+			cc.Change = classify(b, cur, cc.Deltas)
+		} else if !challenge.IsPassing(cur.Status) {
+			// A new challenge that fails gates like any other.
+			cc.Change = ChangeNewFailure
+		}
+		c.Challenges = append(c.Challenges, cc)
+	}
+	for _, b := range baseline.Challenges {
+		if seen[b.ChallengeID] {
+			continue
+		}
+		c.Challenges = append(c.Challenges, ChallengeComparison{
+			ChallengeID:      b.ChallengeID,
+			ChallengeName:    b.ChallengeName,
+			Change:           ChangeRemoved,
+			BaselineStatus:   b.Status,
+			BaselineDuration: b.Duration,
+		})
+	}
+
+	order := make(map[ChangeKind]int, len(changeKinds))
+	for i, k := range changeKinds {
+		order[k] = i
+	}
+	sort.SliceStable(c.Challenges, func(i, j int) bool {
+		return order[c.Challenges[i].Change] < order[c.Challenges[j].Change]
+	})
+	for _, cc := range c.Challenges {
+		c.Counts[cc.Change]++
+	}
+	return c
+}
+
+// CompareResults compares two result sets (see
+// CompareSummaries).
+func CompareResults(
+	baseline, current []*challenge.Result,
+	opts CompareOptions,
+) *Comparison {
+	return CompareSummaries(
+		BuildMasterSummary(baseline), BuildMasterSummary(current), opts,
+	)
+}
+
+// classify returns the change kind of a challenge present in
+// both runs.
+func classify(b, cur ChallengeSummary, deltas []Delta) ChangeKind {
+	wasPassing := challenge.IsPassing(b.Status)
+	isPassing := challenge.IsPassing(cur.Status)
+	switch {
+	case wasPassing && !isPassing:
+		return ChangeNewFailure
+	case !wasPassing && isPassing:
+		return ChangeFixed
+	case !isPassing:
+		return ChangeStillFailing
+	case len(deltas) > 0:
+		return ChangeRegressed
+	}
+	return ChangeUnchanged
+}
+
+// compareDeltas returns the duration and metric changes of a
+// challenge beyond the thresholds of opts, metrics sorted by
+// name.
+func compareDeltas(b, cur ChallengeSummary, opts CompareOptions) []Delta {
+	var deltas []Delta
+	if opts.DurationThreshold > 0 && b.Duration > 0 &&
+		cur.Duration-b.Duration >= opts.MinDurationDelta {
+		d := newDelta("duration", b.Duration.Seconds(), cur.Duration.Seconds())
+		if d.Change > opts.DurationThreshold {
+			deltas = append(deltas, d)
+		}
+	}
+	if opts.MetricThreshold <= 0 {
+		return deltas
+	}
+	higher := make(map[string]bool, len(opts.HigherIsBetter))
+	for _, name := range opts.HigherIsBetter {
+		higher[name] = true
+	}
+	names := make([]string, 0, len(cur.Metrics))
+	for name := range cur.Metrics {
+		if _, ok := b.Metrics[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if b.Metrics[name] == 0 {
+			continue
+		}
+		d := newDelta(name, b.Metrics[name], cur.Metrics[name])
+		worse := d.Change
+		if higher[name] {
+			worse = -worse
+		}
+		if worse > opts.MetricThreshold {
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+// newDelta returns the change from baseline to current.
+func newDelta(name string, baseline, current float64) Delta {
+	return Delta{
+		Name: name, Baseline: baseline, Current: current,
+		Change: (current - baseline) / math.Abs(baseline),
+	}
+}
+
+// LoadMasterSummary reads a master summary written by
+// SaveMasterSummary, or builds one from the results of a JSON
+// reporter summary.
+func LoadMasterSummary(path string) (*MasterSummary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read summary: %w", err)
+	}
+	var doc struct {
+		MasterSummary
+		Results []*challenge.Result `json:"results"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse summary %s: %w", path, err)
+	}
+	if len(doc.Challenges) == 0 && len(doc.Results) > 0 {
+		summary := BuildMasterSummary(doc.Results)
+		summary.ID = path
+		return summary, nil
+	}
+	if doc.ID == "" {
+		doc.ID = path
+	}
+	return &doc.MasterSummary, nil
+}
+
+// changeLabels are the headings of the change kinds.
+var changeLabels = map[ChangeKind]string{
+	ChangeNewFailure:   "New Failure",
+	ChangeRegressed:    "Performance Regressed",
+	ChangeStillFailing: "Still Failing",
+	ChangeFixed:        "Fixed",
+	ChangeAdded:        "Added",
+	ChangeRemoved:      "Removed",
+	ChangeUnchanged:    "Unchanged",
+}
+
+// changeClasses are the CSS classes of the change kinds in the
+// HTML comparison.
+var changeClasses = map[ChangeKind]string{
+	ChangeNewFailure:   "status-failed",
+	ChangeRegressed:    "status-flaky",
+	ChangeStillFailing: "status-failed",
+	ChangeFixed:        "status-passed",
+}
+
+// JSON returns the comparison as indented JSON.
+func (c *Comparison) JSON() ([]byte, error) {
+	return jsonMarshalIndent(c, "", "  ")
+}
+
+// Markdown renders the comparison as Markdown.
+func (c *Comparison) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("# Run Comparison\n\n")
+	fmt.Fprintf(&sb, "**Baseline:** %s\n\n", c.Baseline)
+	fmt.Fprintf(&sb, "**Current:** %s\n\n", c.Current)
+	fmt.Fprintf(&sb, "**Generated:** %s\n\n", c.GeneratedAt.Format(time.RFC3339))
+
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Change | Challenges |\n")
+	sb.WriteString("|--------|------------|\n")
+	for _, k := range changeKinds {
+		if n := c.Counts[k]; n > 0 {
+			fmt.Fprintf(&sb, "| %s | %d |\n", changeLabels[k], n)
+		}
+	}
+
+	if len(c.Challenges) > 0 {
+		sb.WriteString("\n## Challenges\n\n")
+		sb.WriteString("| Challenge | Change | Baseline | Current | Duration | Deltas |\n")
+		sb.WriteString("|-----------|--------|----------|---------|----------|--------|\n")
+		for _, cc := range c.Challenges {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n",
+				comparisonName(cc), changeLabels[cc.Change],
+				statusOrDash(cc.BaselineStatus), statusOrDash(cc.CurrentStatus),
+				durationChange(cc), deltaList(cc.Deltas))
+		}
+	}
+
+	sb.WriteString("\n---\n\n")
+	sb.WriteString("*Generated by Challenges Framework*\n")
+	return sb.String()
+}
+
+// HTML renders the comparison as a standalone HTML page.
+func (c *Comparison) HTML() string {
+	var buf bytes.Buffer
+	r := &HTMLReporter{}
+	r.writeHeader(&buf, "Challenges Framework - Run Comparison")
+	fmt.Fprintln(&buf, "<h1>Challenges Framework - Run Comparison</h1>")
+	fmt.Fprintf(&buf,
+		"<p><strong>Baseline:</strong> %s<br><strong>Current:</strong> %s<br>"+
+			"<strong>Generated:</strong> %s</p>\n",
+		html.EscapeString(c.Baseline), html.EscapeString(c.Current),
+		c.GeneratedAt.Format(time.RFC3339))
+
+	fmt.Fprintln(&buf, "<h2>Summary</h2>")
+	fmt.Fprintln(&buf, "<table>")
+	fmt.Fprintln(&buf, "<tr><th>Change</th><th>Challenges</th></tr>")
+	for _, k := range changeKinds {
+		if n := c.Counts[k]; n > 0 {
+			fmt.Fprintf(&buf, "<tr><td class=\"%s\">%s</td><td>%d</td></tr>\n",
+				changeClasses[k], changeLabels[k], n)
+		}
+	}
+	fmt.Fprintln(&buf, "</table>")
+
+	if len(c.Challenges) > 0 {
+		fmt.Fprintln(&buf, "<h2>Challenges</h2>")
+		fmt.Fprintln(&buf, "<table>")
+		fmt.Fprintln(&buf,
+			"<tr><th>Challenge</th><th>Change</th><th>Baseline</th>"+
+				"<th>Current</th><th>Duration</th><th>Deltas</th></tr>")
+		for _, cc := range c.Challenges {
+			fmt.Fprintf(&buf,
+				"<tr><td>%s</td><td class=\"%s\">%s</td><td>%s</td>"+
+					"<td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				html.EscapeString(comparisonName(cc)),
+				changeClasses[cc.Change], changeLabels[cc.Change],
+				html.EscapeString(statusOrDash(cc.BaselineStatus)),
+				html.EscapeString(statusOrDash(cc.CurrentStatus)),
+				html.EscapeString(durationChange(cc)),
+				html.EscapeString(deltaList(cc.Deltas)))
+		}
+		fmt.Fprintln(&buf, "</table>")
+	}
+	r.writeFooter(&buf)
+	return buf.String()
+}
+
+// comparisonName returns the name of a compared challenge, or
+// its ID when it has none.
+func comparisonName(cc ChallengeComparison) string {
+	if cc.ChallengeName == "" {
+		return string(cc.ChallengeID)
+	}
+	return cc.ChallengeName
+}
+
+// statusOrDash returns the upper-case status, or "-" for a
+// challenge missing from a run.
+func statusOrDash(status string) string {
+	if status == "" {
+		return "-"
+	}
+	return strings.ToUpper(status)
+}
+
+// durationChange describes the durations of a challenge in
+// both runs.
+func durationChange(cc ChallengeComparison) string {
+	switch {
+	case cc.BaselineStatus == "":
+		return cc.CurrentDuration.String()
+	case cc.CurrentStatus == "":
+		return cc.BaselineDuration.String()
+	}
+	return fmt.Sprintf("%v -> %v", cc.BaselineDuration, cc.CurrentDuration)
+}
+
+// deltaList describes deltas as "name +12%" items.
+func deltaList(deltas []Delta) string {
+	if len(deltas) == 0 {
+		return "-"
+	}
+	items := make([]string, len(deltas))
+	for i, d := range deltas {
+		items[i] = fmt.Sprintf("%s %+.0f%%", d.Name, d.Change*100)
+	}
+	return strings.Join(items, ", ")
+}
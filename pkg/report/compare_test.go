@@ -0,0 +1,189 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// runSummary builds a master summary of challenges.
+func runSummary(id string, challenges ...ChallengeSummary) *MasterSummary {
+	return &MasterSummary{ID: id, Challenges: challenges}
+}
+
+func summaryOf(id, status string, d time.Duration, metrics map[string]float64) ChallengeSummary {
+	return ChallengeSummary{
+		ChallengeID: challenge.ID(id), ChallengeName: "Name " + id,
+		Status: status, Duration: d, Metrics: metrics,
+	}
+}
+
+func TestCompareSummaries(t *testing.T) {
+	const passed, failed = challenge.StatusPassed, challenge.StatusFailed
+	baseline := runSummary("base",
+		summaryOf("broke", passed, time.Second, nil),
+		summaryOf("fixed", failed, time.Second, nil),
+		summaryOf("still", failed, time.Second, nil),
+		summaryOf("slow", passed, time.Second, nil),
+		summaryOf("same", passed, time.Second, map[string]float64{"latency": 100, "rps": 50}),
+		summaryOf("gone", passed, time.Second, nil),
+	)
+	current := runSummary("cur",
+		summaryOf("new", passed, time.Second, nil),
+		summaryOf("new-broken", challenge.StatusError, time.Second, nil),
+		summaryOf("same", challenge.StatusPassedWithWarnings, time.Second,
+			map[string]float64{"latency": 105, "rps": 60}),
+		summaryOf("slow", passed, 2*time.Second, nil),
+		summaryOf("still", challenge.StatusTimedOut, time.Second, nil),
+		summaryOf("fixed", passed, time.Second, nil),
+		summaryOf("broke", failed, time.Second, nil),
+	)
+	opts := CompareOptions{
+		DurationThreshold: 0.2, MinDurationDelta: 100 * time.Millisecond,
+		MetricThreshold: 0.1, HigherIsBetter: []string{"rps"},
+	}
+
+	c := CompareSummaries(baseline, current, opts)
+	assert.Equal(t, "base", c.Baseline)
+	assert.Equal(t, "cur", c.Current)
+
+	got := make(map[challenge.ID]ChangeKind)
+	var order []challenge.ID
+	for _, cc := range c.Challenges {
+		got[cc.ChallengeID] = cc.Change
+		order = append(order, cc.ChallengeID)
+	}
+	assert.Equal(t, map[challenge.ID]ChangeKind{
+		"broke": ChangeNewFailure, "fixed": ChangeFixed,
+		"still": ChangeStillFailing, "slow": ChangeRegressed,
+		"same": ChangeUnchanged, "gone": ChangeRemoved, "new": ChangeAdded,
+		"new-broken": ChangeNewFailure,
+	}, got)
+	assert.Equal(t, []challenge.ID{
+		"new-broken", "broke", "slow", "still", "fixed", "new", "gone", "same",
+	}, order)
+	assert.Equal(t, 2, c.Counts[ChangeNewFailure])
+	assert.Equal(t, []Delta{{Name: "duration", Baseline: 1, Current: 2, Change: 1}},
+		c.Challenges[2].Deltas)
+	assert.True(t, c.Regressed(false))
+}
+
+func TestCompareSummaries_AddedFailureGates(t *testing.T) {
+	baseline := runSummary("b",
+		summaryOf("a", challenge.StatusPassed, time.Second, nil))
+	current := runSummary("c",
+		summaryOf("a", challenge.StatusPassed, time.Second, nil),
+		summaryOf("new", challenge.StatusFailed, 3*time.Second, nil))
+
+	c := CompareSummaries(baseline, current, CompareOptions{})
+	require.Len(t, c.Challenges, 2)
+	assert.Equal(t, ChangeNewFailure, c.Challenges[0].Change)
+	assert.Zero(t, c.Counts[ChangeAdded])
+	assert.True(t, c.Regressed(false))
+	assert.Contains(t, c.Markdown(),
+		"| Name new | New Failure | - | FAILED | 3s | - |")
+}
+
+func TestCompareSummaries_Metrics(t *testing.T) {
+	const passed = challenge.StatusPassed
+	baseline := runSummary("b", summaryOf("a", passed, time.Second,
+		map[string]float64{"latency": 100, "rps": 50, "zero": 0}))
+	current := runSummary("c", summaryOf("a", passed, 1100*time.Millisecond,
+		map[string]float64{"latency": 120, "rps": 40, "zero": 5}))
+
+	c := CompareSummaries(baseline, current, CompareOptions{
+		DurationThreshold: 0.05, MinDurationDelta: time.Second,
+		MetricThreshold: 0.1, HigherIsBetter: []string{"rps"},
+	})
+	require.Len(t, c.Challenges, 1)
+	cc := c.Challenges[0]
+	assert.Equal(t, ChangeRegressed, cc.Change)
+	require.Len(t, cc.Deltas, 2)
+	assert.Equal(t, "latency", cc.Deltas[0].Name)
+	assert.InDelta(t, 0.2, cc.Deltas[0].Change, 1e-9)
+	assert.Equal(t, "rps", cc.Deltas[1].Name)
+	assert.InDelta(t, -0.2, cc.Deltas[1].Change, 1e-9)
+	assert.False(t, c.Regressed(false))
+	assert.True(t, c.Regressed(true))
+
+	// Zero thresholds disable performance checks.
+	c = CompareSummaries(baseline, current, CompareOptions{})
+	assert.Equal(t, ChangeUnchanged, c.Challenges[0].Change)
+	assert.False(t, c.Regressed(true))
+}
+
+func TestCompareResults(t *testing.T) {
+	baseline := makeTestResults()
+	current := makeTestResults()
+	current[0].Status = challenge.StatusFailed
+	current[0].Metrics["latency"] = challenge.MetricValue{Name: "latency", Value: 500}
+
+	c := CompareResults(baseline, current, CompareOptions{MetricThreshold: 0.5})
+	require.Len(t, c.Challenges, 2)
+	assert.Equal(t, ChangeNewFailure, c.Challenges[0].Change)
+	assert.Equal(t, "latency", c.Challenges[0].Deltas[0].Name)
+	assert.Equal(t, ChangeStillFailing, c.Challenges[1].Change)
+}
+
+func TestLoadMasterSummary(t *testing.T) {
+	dir := t.TempDir()
+	summary := BuildMasterSummary(makeTestResults())
+	require.NoError(t, SaveMasterSummary(summary, dir))
+
+	loaded, err := LoadMasterSummary(filepath.Join(dir, "latest_summary.json"))
+	require.NoError(t, err)
+	assert.Equal(t, summary.ID, loaded.ID)
+	require.Len(t, loaded.Challenges, 2)
+	assert.Equal(t, 120.5, loaded.Challenges[0].Metrics["latency"])
+
+	// A JSON reporter summary holds the results themselves.
+	data, err := NewJSONReporter(dir, true).GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	path := filepath.Join(dir, "summary.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	loaded, err = LoadMasterSummary(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, loaded.ID)
+	assert.Len(t, loaded.Challenges, 2)
+
+	_, err = LoadMasterSummary(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = LoadMasterSummary(path)
+	assert.Error(t, err)
+}
+
+func TestComparison_Output(t *testing.T) {
+	baseline := runSummary("base",
+		summaryOf("a", challenge.StatusPassed, time.Second, nil),
+		summaryOf("gone", challenge.StatusPassed, time.Second, nil))
+	current := runSummary("cur",
+		summaryOf("a", challenge.StatusFailed, 2*time.Second, nil),
+		summaryOf("<new>", challenge.StatusPassed, time.Second, nil))
+	c := CompareSummaries(baseline, current, CompareOptions{DurationThreshold: 0.5})
+
+	md := c.Markdown()
+	assert.Contains(t, md, "# Run Comparison")
+	assert.Contains(t, md, "| New Failure | 1 |")
+	assert.Contains(t, md, "| Name a | New Failure | PASSED | FAILED | 1s -> 2s | duration +100% |")
+	assert.Contains(t, md, "| Name gone | Removed | PASSED | - | 1s | - |")
+
+	page := c.HTML()
+	assert.Contains(t, page, "<h1>Challenges Framework - Run Comparison</h1>")
+	assert.Contains(t, page, `<td class="status-failed">New Failure</td>`)
+	assert.Contains(t, page, "Name &lt;new&gt;")
+
+	data, err := c.JSON()
+	require.NoError(t, err)
+	var decoded Comparison
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 1, decoded.Counts[ChangeAdded])
+	assert.Len(t, decoded.Challenges, 3)
+}
@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"time"
 
 	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
 )
 
 // jsonMarshal is a variable for dependency injection in tests.
@@ -16,16 +16,8 @@ var jsonMarshal = json.Marshal
 var jsonMarshalIndent = json.MarshalIndent
 
 // HistoricalEntry represents a single challenge run in the
-// historical log.
-type HistoricalEntry struct {
-	Timestamp        time.Time `json:"timestamp"`
-	ChallengeID      string    `json:"challenge_id"`
-	Status           string    `json:"status"`
-	Duration         string    `json:"duration"`
-	AssertionsPassed int       `json:"assertions_passed"`
-	AssertionsTotal  int       `json:"assertions_total"`
-	ResultsPath      string    `json:"results_path"`
-}
+// historical log. Use history.Load to read the log back.
+type HistoricalEntry = history.Entry
 
 // AppendToHistory adds an entry to the historical log stored
 // at historyPath. Each entry is a single JSON line.
@@ -34,24 +26,7 @@ func AppendToHistory(
 	result *challenge.Result,
 	resultsPath string,
 ) error {
-	assertionsPassed := 0
-	for _, a := range result.Assertions {
-		if a.Passed {
-			assertionsPassed++
-		}
-	}
-
-	entry := HistoricalEntry{
-		Timestamp:        result.EndTime,
-		ChallengeID:      string(result.ChallengeID),
-		Status:           result.Status,
-		Duration:         result.Duration.String(),
-		AssertionsPassed: assertionsPassed,
-		AssertionsTotal:  len(result.Assertions),
-		ResultsPath:      resultsPath,
-	}
-
-	data, err := jsonMarshal(entry)
+	data, err := jsonMarshal(history.NewEntry(result, resultsPath))
 	if err != nil {
 		return fmt.Errorf(
 			"failed to marshal history entry: %w", err,
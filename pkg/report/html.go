@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
 )
 
 // HTMLReporter generates HTML reports from challenge results.
 type HTMLReporter struct {
 	outputDir string
+	history   *history.History
 }
 
 // NewHTMLReporter creates a new HTML reporter.
@@ -21,6 +23,12 @@ func NewHTMLReporter(outputDir string) *HTMLReporter {
 	return &HTMLReporter{outputDir: outputDir}
 }
 
+// SetHistory sets the run history the master summary renders
+// a trend section from. A nil history omits the section.
+func (r *HTMLReporter) SetHistory(h *history.History) {
+	r.history = h
+}
+
 // GenerateReport creates an HTML report for a single challenge
 // result. The returned error is always nil since HTML generation
 // to a bytes.Buffer cannot fail.
@@ -56,6 +64,7 @@ func (r *HTMLReporter) WriteReport(
 	)
 
 	r.writeSummaryTable(w, result)
+	r.writeAttemptsSection(w, result)
 	r.writeMetricsSection(w, result)
 	r.writeAssertionsSection(w, result)
 	r.writeOutputsSection(w, result)
@@ -69,11 +78,6 @@ func (r *HTMLReporter) writeSummaryTable(
 	w io.Writer,
 	result *challenge.Result,
 ) {
-	statusClass := "status-passed"
-	if result.Status != challenge.StatusPassed {
-		statusClass = "status-failed"
-	}
-
 	fmt.Fprintln(w, "<h2>Summary</h2>")
 	fmt.Fprintln(w, "<table>")
 	fmt.Fprintln(w, "<tr><th>Metric</th><th>Value</th></tr>")
@@ -81,7 +85,7 @@ func (r *HTMLReporter) writeSummaryTable(
 		w,
 		"<tr><td>Status</td><td class=\"%s\">"+
 			"<strong>%s</strong></td></tr>\n",
-		statusClass, strings.ToUpper(result.Status),
+		statusClass(result), statusLabel(result),
 	)
 	fmt.Fprintf(
 		w,
@@ -99,6 +103,14 @@ func (r *HTMLReporter) writeSummaryTable(
 		result.Duration,
 	)
 
+	if len(result.Attempts) > 0 {
+		fmt.Fprintf(
+			w,
+			"<tr><td>Attempts</td><td>%d</td></tr>\n",
+			len(result.Attempts),
+		)
+	}
+
 	if result.Error != "" {
 		fmt.Fprintf(
 			w,
@@ -111,6 +123,42 @@ func (r *HTMLReporter) writeSummaryTable(
 	fmt.Fprintln(w, "</table>")
 }
 
+func (r *HTMLReporter) writeAttemptsSection(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	if len(result.Attempts) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "<h2>Attempts</h2>")
+	fmt.Fprintln(w, "<table>")
+	fmt.Fprintln(
+		w,
+		"<tr><th>Attempt</th><th>Status</th>"+
+			"<th>Duration</th><th>Error</th></tr>",
+	)
+
+	for _, a := range result.Attempts {
+		cls := "status-failed"
+		switch a.Status {
+		case challenge.StatusPassed:
+			cls = "status-passed"
+		case challenge.StatusPassedWithWarnings:
+			cls = "status-warning"
+		}
+		fmt.Fprintf(
+			w,
+			"<tr><td>%d</td><td class=\"%s\">%s</td>"+
+				"<td>%v</td><td>%s</td></tr>\n",
+			a.Number, cls, strings.ToUpper(a.Status),
+			a.Duration, html.EscapeString(a.Error),
+		)
+	}
+
+	fmt.Fprintln(w, "</table>")
+}
+
 func (r *HTMLReporter) writeMetricsSection(
 	w io.Writer,
 	result *challenge.Result,
@@ -162,23 +210,10 @@ func (r *HTMLReporter) writeAssertionsSection(
 
 	passedCount := 0
 	for _, a := range result.Assertions {
-		passedStr := "No"
-		cls := "status-failed"
 		if a.Passed {
-			passedStr = "Yes"
-			cls = "status-passed"
 			passedCount++
 		}
-		fmt.Fprintf(
-			w,
-			"<tr><td>%s</td><td>%s</td>"+
-				"<td class=\"%s\">%s</td>"+
-				"<td>%s</td></tr>\n",
-			html.EscapeString(a.Type),
-			html.EscapeString(a.Target),
-			cls, passedStr,
-			html.EscapeString(a.Message),
-		)
+		r.writeAssertionRow(w, a, 0)
 	}
 
 	fmt.Fprintln(w, "</table>")
@@ -190,6 +225,44 @@ func (r *HTMLReporter) writeAssertionsSection(
 		"<p><strong>Pass Rate:</strong> %d/%d (%.0f%%)</p>\n",
 		passedCount, total, pct,
 	)
+	if warnings := len(result.Warnings()); warnings > 0 {
+		fmt.Fprintf(
+			w,
+			"<p class=\"status-warning\"><strong>Warnings:</strong> "+
+				"%d (not failing the challenge)</p>\n",
+			warnings,
+		)
+	}
+}
+
+// writeAssertionRow writes an assertion row and, indented below
+// it, the sub-results of composite assertions.
+func (r *HTMLReporter) writeAssertionRow(
+	w io.Writer,
+	a challenge.AssertionResult,
+	depth int,
+) {
+	cls := "status-failed"
+	switch {
+	case a.Passed:
+		cls = "status-passed"
+	case !a.IsBlocking():
+		cls = "status-warning"
+	}
+	fmt.Fprintf(
+		w,
+		"<tr><td>%s%s</td><td>%s</td>"+
+			"<td class=\"%s\">%s</td>"+
+			"<td>%s</td></tr>\n",
+		assertionIndent(depth),
+		html.EscapeString(a.Type),
+		html.EscapeString(a.Target),
+		cls, html.EscapeString(assertionPassedLabel(a)),
+		html.EscapeString(a.Message),
+	)
+	for _, child := range a.Children {
+		r.writeAssertionRow(w, child, depth+1)
+	}
 }
 
 func (r *HTMLReporter) writeOutputsSection(
@@ -284,6 +357,7 @@ func (r *HTMLReporter) GenerateMasterSummary(
 
 	r.writeMasterOverview(&buf, results)
 	r.writeMasterStats(&buf, results)
+	r.writeTrends(&buf, results)
 	r.writeMasterDetails(&buf, results)
 	r.writeFooter(&buf)
 
@@ -303,17 +377,13 @@ func (r *HTMLReporter) writeMasterOverview(
 	)
 
 	for _, result := range results {
-		cls := "status-passed"
-		if result.Status != challenge.StatusPassed {
-			cls = "status-failed"
-		}
 		fmt.Fprintf(
 			w,
 			"<tr><td>%s</td>"+
 				"<td class=\"%s\">%s</td>"+
 				"<td>%v</td><td>%s</td></tr>\n",
 			html.EscapeString(result.ChallengeName),
-			cls, strings.ToUpper(result.Status),
+			statusClass(result), statusLabel(result),
 			result.Duration,
 			result.EndTime.Format("2006-01-02 15:04:05"),
 		)
@@ -327,11 +397,19 @@ func (r *HTMLReporter) writeMasterStats(
 	results []*challenge.Result,
 ) {
 	passedCount := 0
+	warningCount := 0
+	flakyCount := 0
 	totalDuration := time.Duration(0)
 	for _, res := range results {
-		if res.Status == challenge.StatusPassed {
+		if challenge.IsPassing(res.Status) {
 			passedCount++
 		}
+		if res.Status == challenge.StatusPassedWithWarnings {
+			warningCount++
+		}
+		if res.Flaky {
+			flakyCount++
+		}
 		totalDuration += res.Duration
 	}
 
@@ -349,11 +427,25 @@ func (r *HTMLReporter) writeMasterStats(
 		"<tr><td>Passed</td><td>%d</td></tr>\n",
 		passedCount,
 	)
+	if warningCount > 0 {
+		fmt.Fprintf(
+			w,
+			"<tr><td>Passed With Warnings</td><td>%d</td></tr>\n",
+			warningCount,
+		)
+	}
 	fmt.Fprintf(
 		w,
 		"<tr><td>Failed</td><td>%d</td></tr>\n",
 		len(results)-passedCount,
 	)
+	if flakyCount > 0 {
+		fmt.Fprintf(
+			w,
+			"<tr><td>Flaky</td><td>%d</td></tr>\n",
+			flakyCount,
+		)
+	}
 
 	if len(results) > 0 {
 		pct := float64(passedCount) /
@@ -390,7 +482,7 @@ func (r *HTMLReporter) writeMasterDetails(
 		fmt.Fprintf(
 			w,
 			"<p><strong>Status:</strong> %s</p>\n",
-			strings.ToUpper(result.Status),
+			statusLabel(result),
 		)
 		fmt.Fprintf(
 			w,
@@ -408,7 +500,30 @@ func (r *HTMLReporter) writeMasterDetails(
 	}
 }
 
+// statusClass returns the CSS class for a result status.
+func statusClass(result *challenge.Result) string {
+	switch {
+	case result.Flaky:
+		return "status-flaky"
+	case result.Status == challenge.StatusPassed:
+		return "status-passed"
+	case result.Status == challenge.StatusPassedWithWarnings:
+		return "status-warning"
+	default:
+		return "status-failed"
+	}
+}
+
 func (r *HTMLReporter) writeHeader(w io.Writer, title string) {
+	r.writeHeaderWithStyle(w, title, "")
+}
+
+// writeHeaderWithStyle writes the page header with extra CSS
+// rules appended to the shared style sheet.
+func (r *HTMLReporter) writeHeaderWithStyle(
+	w io.Writer,
+	title, extraCSS string,
+) {
 	fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -443,6 +558,8 @@ th { background: #3498db; color: #fff; }
 tr:nth-child(even) { background: #f2f2f2; }
 .status-passed { color: #27ae60; font-weight: bold; }
 .status-failed { color: #e74c3c; font-weight: bold; }
+.status-flaky { color: #e67e22; font-weight: bold; }
+.status-warning { color: #d4a017; font-weight: bold; }
 code {
   background: #ecf0f1;
   padding: 2px 6px;
@@ -456,10 +573,10 @@ footer {
   color: #7f8c8d;
   font-size: 0.9em;
 }
-</style>
+%s</style>
 </head>
 <body>
-`, html.EscapeString(title))
+`, html.EscapeString(title), extraCSS)
 }
 
 func (r *HTMLReporter) writeFooter(w io.Writer) {
@@ -137,3 +137,38 @@ func TestHTMLReporter_MetricsWithEmptyUnit(t *testing.T) {
 	content := string(data)
 	assert.Contains(t, content, "nounit")
 }
+
+func TestHTMLReporter_FlakyResult(t *testing.T) {
+	r := NewHTMLReporter(t.TempDir())
+
+	data, err := r.GenerateReport(makeFlakyResult())
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "status-flaky")
+	assert.Contains(t, content, "PASSED (FLAKY)")
+	assert.Contains(t, content, "<h2>Attempts</h2>")
+
+	summary, err := r.GenerateMasterSummary(
+		[]*challenge.Result{makeFlakyResult()},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "<td>Flaky</td><td>1</td>")
+}
+
+func TestHTMLReporter_WarningResult(t *testing.T) {
+	r := NewHTMLReporter(t.TempDir())
+
+	data, err := r.GenerateReport(makeWarningResult())
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "PASSED WITH WARNINGS (1)")
+	assert.Contains(t, content, `<td class="status-warning">No (warning)</td>`)
+	assert.Contains(t, content, "<strong>Warnings:</strong> 1")
+
+	summary, err := r.GenerateMasterSummary(
+		[]*challenge.Result{makeWarningResult()},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "<td>Passed</td><td>1</td>")
+	assert.Contains(t, string(summary), "<td>Passed With Warnings</td><td>1</td>")
+}
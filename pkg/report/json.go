@@ -65,7 +65,7 @@ func (r *JSONReporter) GenerateMasterSummary(
 	}
 
 	for _, res := range results {
-		if res.Status == challenge.StatusPassed {
+		if challenge.IsPassing(res.Status) {
 			summary.Passed++
 		} else {
 			summary.Failed++
@@ -0,0 +1,294 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// defaultSuite is the suite of challenges without a category.
+const defaultSuite = "challenges"
+
+// JUnitReporter generates JUnit XML reports from challenge
+// results for CI systems. Categories become test suites,
+// challenges test cases and failed assertions failures.
+type JUnitReporter struct {
+	outputDir  string
+	categories map[challenge.ID]string
+}
+
+// NewJUnitReporter creates a new JUnit XML reporter. categories
+// maps challenge IDs to the suite they are reported in;
+// challenges without a category are reported in the
+// "challenges" suite. categories may be nil.
+func NewJUnitReporter(
+	outputDir string,
+	categories map[challenge.ID]string,
+) *JUnitReporter {
+	return &JUnitReporter{
+		outputDir:  outputDir,
+		categories: categories,
+	}
+}
+
+// junitTestSuites is the <testsuites> root element.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a <testsuite> element.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+// junitTestCase is a <testcase> element.
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	Skipped   *junitMessage  `xml:"skipped,omitempty"`
+	Error     *junitMessage  `xml:"error,omitempty"`
+	Failures  []junitMessage `xml:"failure"`
+	SystemOut string         `xml:"system-out,omitempty"`
+}
+
+// junitMessage is a <failure>, <error> or <skipped> element.
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// GenerateReport creates a JUnit XML report with a single test
+// case for the challenge result.
+func (r *JUnitReporter) GenerateReport(
+	result *challenge.Result,
+) ([]byte, error) {
+	return r.GenerateMasterSummary([]*challenge.Result{result})
+}
+
+// GenerateMasterSummary creates a JUnit XML report of all
+// challenge results, with one suite per category in the order
+// categories first appear.
+func (r *JUnitReporter) GenerateMasterSummary(
+	results []*challenge.Result,
+) ([]byte, error) {
+	root := junitTestSuites{}
+	index := make(map[string]int)
+	var total time.Duration
+	for _, res := range results {
+		name := r.categories[res.ChallengeID]
+		if name == "" {
+			name = defaultSuite
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(root.Suites)
+			index[name] = i
+			root.Suites = append(root.Suites, junitTestSuite{Name: name})
+		}
+		suite := &root.Suites[i]
+		if suite.Timestamp == "" && !res.StartTime.IsZero() {
+			suite.Timestamp = res.StartTime.UTC().Format("2006-01-02T15:04:05")
+		}
+
+		tc := junitCase(name, res)
+		suite.Tests++
+		switch {
+		case tc.Skipped != nil:
+			suite.Skipped++
+		case tc.Error != nil:
+			suite.Errors++
+		case len(tc.Failures) > 0:
+			suite.Failures++
+		}
+		suite.duration += res.Duration
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	for i := range root.Suites {
+		s := &root.Suites[i]
+		s.Time = junitSeconds(s.duration)
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+		root.Errors += s.Errors
+		root.Skipped += s.Skipped
+		total += s.duration
+	}
+	root.Time = junitSeconds(total)
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal junit report: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// WriteReport writes a JUnit XML report to the specified
+// writer.
+func (r *JUnitReporter) WriteReport(
+	w io.Writer,
+	result *challenge.Result,
+) error {
+	data, err := r.GenerateReport(result)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// junitCase maps a challenge result to a test case. Skipped
+// challenges are <skipped>, timeouts, stuck challenges and
+// errors are an <error>, and every failed blocking assertion is
+// a <failure>.
+func junitCase(suite string, res *challenge.Result) junitTestCase {
+	tc := junitTestCase{
+		Name:      string(res.ChallengeID),
+		ClassName: suite,
+		Time:      junitSeconds(res.Duration),
+		SystemOut: systemOut(res),
+	}
+	switch res.Status {
+	case challenge.StatusSkipped:
+		tc.Skipped = &junitMessage{Message: res.Error}
+		return tc
+	case challenge.StatusTimedOut, challenge.StatusStuck,
+		challenge.StatusError:
+		tc.Error = &junitMessage{
+			Message: res.Error, Type: res.Status, Body: res.Error,
+		}
+		return tc
+	}
+	if challenge.IsPassing(res.Status) {
+		return tc
+	}
+	for _, a := range res.Assertions {
+		if a.Passed || !a.IsBlocking() {
+			continue
+		}
+		tc.Failures = append(tc.Failures, junitMessage{
+			Message: a.Message, Type: a.Type, Body: failureBody(a),
+		})
+	}
+	if len(tc.Failures) == 0 {
+		msg := res.Error
+		if msg == "" {
+			msg = "challenge " + res.Status
+		}
+		tc.Failures = append(tc.Failures, junitMessage{
+			Message: msg, Type: res.Status, Body: msg,
+		})
+	}
+	return tc
+}
+
+// failureBody describes a failed assertion: its target, the
+// expected and actual values and its failed sub-results.
+func failureBody(a challenge.AssertionResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "target: %s\n", a.Target)
+	fmt.Fprintf(&b, "expected: %s\n", formatValue(a.Expected))
+	fmt.Fprintf(&b, "actual: %s\n", formatValue(a.Actual))
+	writeFailedChildren(&b, a.Children, 1)
+	return b.String()
+}
+
+// writeFailedChildren lists the failed sub-results of a
+// composite assertion, indented by depth.
+func writeFailedChildren(
+	b *strings.Builder,
+	children []challenge.AssertionResult,
+	depth int,
+) {
+	for _, c := range children {
+		if c.Passed {
+			continue
+		}
+		fmt.Fprintf(b, "%s- %s %s: %s\n",
+			strings.Repeat("  ", depth-1), c.Type, c.Target, c.Message)
+		writeFailedChildren(b, c.Children, depth+1)
+	}
+}
+
+// systemOut lists the outputs and log paths of a result, one
+// "name: value" line each, outputs sorted by name.
+func systemOut(res *challenge.Result) string {
+	var b strings.Builder
+	names := make([]string, 0, len(res.Outputs))
+	for name := range res.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "output %s: %s\n", name, res.Outputs[name])
+	}
+	for _, l := range logPaths(res.Logs) {
+		fmt.Fprintf(&b, "log %s: %s\n", l[0], l[1])
+	}
+	return b.String()
+}
+
+// logPaths returns the non-empty log paths of a result as
+// name/path pairs, artifacts sorted by name.
+func logPaths(logs challenge.LogPaths) [][2]string {
+	var out [][2]string
+	for _, l := range [][2]string{
+		{"challenge_log", logs.ChallengeLog},
+		{"output_log", logs.OutputLog},
+		{"api_requests", logs.APIRequests},
+		{"api_responses", logs.APIResponses},
+	} {
+		if l[1] != "" {
+			out = append(out, l)
+		}
+	}
+	names := make([]string, 0, len(logs.Artifacts))
+	for name := range logs.Artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, [2]string{name, logs.Artifacts[name]})
+	}
+	return out
+}
+
+// formatValue renders an expected or actual value: strings as
+// is, other values as JSON.
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// junitSeconds formats a duration in seconds, as JUnit time
+// attributes are.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
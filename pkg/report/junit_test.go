@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+func TestJUnitReporter_GenerateMasterSummary(t *testing.T) {
+	results := makeTestResults()
+	results[0].Status = challenge.StatusFailed
+	results[0].Assertions[1].Expected = "keyword"
+	results[0].Assertions[1].Actual = map[string]any{"n": 1}
+	results = append(results,
+		&challenge.Result{
+			ChallengeID: "test-003", Status: challenge.StatusSkipped,
+			Error: "dependency failed",
+		},
+		&challenge.Result{
+			ChallengeID: "test-004", Status: challenge.StatusTimedOut,
+			Error: "timed out after 5s",
+		},
+	)
+	r := NewJUnitReporter(t.TempDir(), map[challenge.ID]string{
+		"test-001": "api", "test-003": "api",
+	})
+
+	data, err := r.GenerateMasterSummary(results)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), xml.Header))
+
+	var root junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &root))
+	assert.Equal(t, 4, root.Tests)
+	assert.Equal(t, 2, root.Failures)
+	assert.Equal(t, 1, root.Errors)
+	assert.Equal(t, 1, root.Skipped)
+	assert.Equal(t, "7.000", root.Time)
+	require.Len(t, root.Suites, 2)
+
+	api := root.Suites[0]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, "2026-01-01T00:00:00", api.Timestamp)
+	require.Len(t, api.TestCases, 2)
+	tc := api.TestCases[0]
+	assert.Equal(t, "test-001", tc.Name)
+	assert.Equal(t, "api", tc.ClassName)
+	assert.Equal(t, "5.000", tc.Time)
+	require.Len(t, tc.Failures, 1)
+	assert.Equal(t, "body missing keyword", tc.Failures[0].Message)
+	assert.Equal(t, "contains", tc.Failures[0].Type)
+	assert.Contains(t, tc.Failures[0].Body, "expected: keyword\n")
+	assert.Contains(t, tc.Failures[0].Body, `actual: {"n":1}`)
+	assert.Contains(t, tc.SystemOut, "output result: /tmp/result.json\n")
+	assert.Contains(t, tc.SystemOut, "log challenge_log: /tmp/challenge.log\n")
+	require.NotNil(t, api.TestCases[1].Skipped)
+	assert.Equal(t, "dependency failed", api.TestCases[1].Skipped.Message)
+
+	other := root.Suites[1]
+	assert.Equal(t, defaultSuite, other.Name)
+	require.Len(t, other.TestCases, 2)
+	require.Len(t, other.TestCases[0].Failures, 1)
+	assert.Equal(t, "connection refused", other.TestCases[0].Failures[0].Message)
+	require.NotNil(t, other.TestCases[1].Error)
+	assert.Equal(t, challenge.StatusTimedOut, other.TestCases[1].Error.Type)
+}
+
+func TestJUnitReporter_PassedWithWarnings(t *testing.T) {
+	r := NewJUnitReporter(t.TempDir(), nil)
+
+	data, err := r.GenerateReport(makeWarningResult())
+	require.NoError(t, err)
+
+	var root junitTestSuites
+	require.NoError(t, xml.Unmarshal(data, &root))
+	assert.Equal(t, 1, root.Tests)
+	assert.Zero(t, root.Failures)
+	require.Len(t, root.Suites, 1)
+	assert.Empty(t, root.Suites[0].TestCases[0].Failures)
+}
+
+func TestJUnitReporter_CompositeFailure(t *testing.T) {
+	r := NewJUnitReporter(t.TempDir(), nil)
+	result := makeTestResult()
+	result.Status = challenge.StatusFailed
+	result.Assertions = []challenge.AssertionResult{{
+		Type: "all_of", Target: "body", Message: "1 of 2 failed",
+		Children: []challenge.AssertionResult{
+			{Type: "not_empty", Target: "body", Passed: true},
+			{Type: "contains", Target: "body", Message: "missing ok"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteReport(&buf, result))
+	assert.Contains(t, buf.String(), "- contains body: missing ok")
+	assert.NotContains(t, buf.String(), "- not_empty")
+}
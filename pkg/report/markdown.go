@@ -57,8 +57,7 @@ func (r *MarkdownReporter) WriteReport(
 	fmt.Fprintln(w, "| Metric | Value |")
 	fmt.Fprintln(w, "|--------|-------|")
 	fmt.Fprintf(
-		w, "| Status | **%s** |\n",
-		strings.ToUpper(result.Status),
+		w, "| Status | **%s** |\n", statusLabel(result),
 	)
 	fmt.Fprintf(
 		w, "| Start Time | %s |\n",
@@ -70,10 +69,15 @@ func (r *MarkdownReporter) WriteReport(
 	)
 	fmt.Fprintf(w, "| Duration | %v |\n", result.Duration)
 
+	if len(result.Attempts) > 0 {
+		fmt.Fprintf(w, "| Attempts | %d |\n", len(result.Attempts))
+	}
+
 	if result.Error != "" {
 		fmt.Fprintf(w, "| Error | %s |\n", result.Error)
 	}
 
+	r.writeAttempts(w, result)
 	r.writeMetrics(w, result)
 	r.writeAssertions(w, result)
 	r.writeOutputs(w, result)
@@ -87,6 +91,33 @@ func (r *MarkdownReporter) WriteReport(
 	return nil
 }
 
+func (r *MarkdownReporter) writeAttempts(
+	w io.Writer,
+	result *challenge.Result,
+) {
+	if len(result.Attempts) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Attempts")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Attempt | Status | Duration | Error |")
+	fmt.Fprintln(w, "|---------|--------|----------|-------|")
+
+	for _, a := range result.Attempts {
+		errMsg := a.Error
+		if errMsg == "" {
+			errMsg = "-"
+		}
+		fmt.Fprintf(
+			w, "| %d | %s | %v | %s |\n",
+			a.Number, strings.ToUpper(a.Status),
+			a.Duration, errMsg,
+		)
+	}
+}
+
 func (r *MarkdownReporter) writeMetrics(
 	w io.Writer,
 	result *challenge.Result,
@@ -135,15 +166,10 @@ func (r *MarkdownReporter) writeAssertions(
 
 	passedCount := 0
 	for _, a := range result.Assertions {
-		passed := "No"
 		if a.Passed {
-			passed = "Yes"
 			passedCount++
 		}
-		fmt.Fprintf(
-			w, "| %s | %s | %s | %s |\n",
-			a.Type, a.Target, passed, a.Message,
-		)
+		r.writeAssertionRow(w, a, 0)
 	}
 
 	total := len(result.Assertions)
@@ -152,6 +178,29 @@ func (r *MarkdownReporter) writeAssertions(
 		w, "\n**Pass Rate:** %d/%d (%.0f%%)\n",
 		passedCount, total, pct,
 	)
+	if warnings := len(result.Warnings()); warnings > 0 {
+		fmt.Fprintf(
+			w, "\n**Warnings:** %d (not failing the challenge)\n",
+			warnings,
+		)
+	}
+}
+
+// writeAssertionRow writes an assertion and, indented below
+// it, the sub-results of composite assertions.
+func (r *MarkdownReporter) writeAssertionRow(
+	w io.Writer,
+	a challenge.AssertionResult,
+	depth int,
+) {
+	fmt.Fprintf(
+		w, "| %s%s | %s | %s | %s |\n",
+		assertionIndent(depth), a.Type, a.Target,
+		assertionPassedLabel(a), a.Message,
+	)
+	for _, child := range a.Children {
+		r.writeAssertionRow(w, child, depth+1)
+	}
 }
 
 func (r *MarkdownReporter) writeOutputs(
@@ -230,13 +279,21 @@ func (r *MarkdownReporter) GenerateMasterSummary(
 	)
 
 	passedCount := 0
+	warningCount := 0
+	flakyCount := 0
 	totalDuration := time.Duration(0)
 
 	for _, result := range results {
-		status := strings.ToUpper(result.Status)
-		if result.Status == challenge.StatusPassed {
+		status := statusLabel(result)
+		if challenge.IsPassing(result.Status) {
 			passedCount++
 		}
+		if result.Status == challenge.StatusPassedWithWarnings {
+			warningCount++
+		}
+		if result.Flaky {
+			flakyCount++
+		}
 		totalDuration += result.Duration
 		fmt.Fprintf(
 			&buf, "| %s | %s | %v | %s |\n",
@@ -256,9 +313,17 @@ func (r *MarkdownReporter) GenerateMasterSummary(
 		&buf, "| Total Challenges | %d |\n", len(results),
 	)
 	fmt.Fprintf(&buf, "| Passed | %d |\n", passedCount)
+	if warningCount > 0 {
+		fmt.Fprintf(
+			&buf, "| Passed With Warnings | %d |\n", warningCount,
+		)
+	}
 	fmt.Fprintf(
 		&buf, "| Failed | %d |\n", len(results)-passedCount,
 	)
+	if flakyCount > 0 {
+		fmt.Fprintf(&buf, "| Flaky | %d |\n", flakyCount)
+	}
 
 	if len(results) > 0 {
 		pct := float64(passedCount) /
@@ -290,12 +355,17 @@ func (r *MarkdownReporter) writeChallengeDetails(
 	for _, result := range results {
 		fmt.Fprintf(buf, "### %s\n\n", result.ChallengeName)
 		fmt.Fprintf(
-			buf, "- **Status:** %s\n",
-			strings.ToUpper(result.Status),
+			buf, "- **Status:** %s\n", statusLabel(result),
 		)
 		fmt.Fprintf(
 			buf, "- **Duration:** %v\n", result.Duration,
 		)
+		if len(result.Attempts) > 0 {
+			fmt.Fprintf(
+				buf, "- **Attempts:** %d\n",
+				len(result.Attempts),
+			)
+		}
 
 		if len(result.Metrics) > 0 {
 			fmt.Fprintln(buf, "- **Key Metrics:**")
@@ -319,6 +389,9 @@ func (r *MarkdownReporter) writeChallengeDetails(
 				passed, len(result.Assertions),
 			)
 		}
+		if warnings := len(result.Warnings()); warnings > 0 {
+			fmt.Fprintf(buf, "- **Warnings:** %d\n", warnings)
+		}
 
 		if result.Error != "" {
 			fmt.Fprintf(
@@ -177,3 +177,59 @@ func TestMarkdownReporter_GenerateReport_MetricsWithEmptyUnit(t *testing.T) {
 	// Empty unit should be replaced with "-"
 	assert.Contains(t, content, "nounit")
 }
+
+func TestMarkdownReporter_FlakyResult(t *testing.T) {
+	r := NewMarkdownReporter(t.TempDir())
+
+	data, err := r.GenerateReport(makeFlakyResult())
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "PASSED (FLAKY)")
+	assert.Contains(t, content, "| Attempts | 2 |")
+	assert.Contains(t, content, "## Attempts")
+	assert.Contains(t, content, "| 1 | TIMED_OUT | 3s | timed out |")
+
+	summary, err := r.GenerateMasterSummary(
+		[]*challenge.Result{makeFlakyResult(), makeTestResult()},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "| Flaky | 1 |")
+	assert.Contains(t, string(summary), "- **Attempts:** 2")
+}
+
+func TestMarkdownReporter_GenerateReport_CompositeAssertion(t *testing.T) {
+	r := NewMarkdownReporter(t.TempDir())
+	result := makeTestResult()
+	result.Assertions = []challenge.AssertionResult{{
+		Type: "any_of", Target: "status", Passed: false,
+		Message: "any_of: 0 of 1 sub-assertions passed",
+		Children: []challenge.AssertionResult{{
+			Type: "equals", Target: "status", Message: `"down" != "ok"`,
+		}},
+	}}
+
+	data, err := r.GenerateReport(result)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, `| &#8627; equals | status | No | "down" != "ok" |`)
+	assert.Contains(t, content, "0/1 (0%)")
+}
+
+func TestMarkdownReporter_WarningResult(t *testing.T) {
+	r := NewMarkdownReporter(t.TempDir())
+
+	data, err := r.GenerateReport(makeWarningResult())
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "| Status | **PASSED WITH WARNINGS (1)** |")
+	assert.Contains(t, content, "| contains | body | No (warning) | body missing keyword |")
+	assert.Contains(t, content, "**Warnings:** 1 (not failing the challenge)")
+
+	summary, err := r.GenerateMasterSummary(
+		[]*challenge.Result{makeWarningResult(), makeTestResult()},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, string(summary), "| Passed | 2 |")
+	assert.Contains(t, string(summary), "| Passed With Warnings | 1 |")
+	assert.Contains(t, string(summary), "- **Warnings:** 1")
+}
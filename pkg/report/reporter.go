@@ -2,7 +2,9 @@
 package report
 
 import (
+	"fmt"
 	"io"
+	"strings"
 
 	"digital.vasic.challenges/pkg/challenge"
 )
@@ -22,3 +24,40 @@ type Reporter interface {
 	// WriteReport writes a report to the specified writer.
 	WriteReport(w io.Writer, result *challenge.Result) error
 }
+
+// statusLabel returns the upper-case status shown in reports,
+// marking flaky passes and counting failed warnings.
+func statusLabel(result *challenge.Result) string {
+	label := strings.ToUpper(result.Status)
+	if result.Status == challenge.StatusPassedWithWarnings {
+		label = fmt.Sprintf(
+			"PASSED WITH WARNINGS (%d)", len(result.Warnings()),
+		)
+	}
+	if result.Flaky {
+		label += " (FLAKY)"
+	}
+	return label
+}
+
+// assertionPassedLabel returns "Yes" or "No" for an assertion,
+// followed by its severity when one is declared.
+func assertionPassedLabel(a challenge.AssertionResult) string {
+	label := "No"
+	if a.Passed {
+		label = "Yes"
+	}
+	if a.Severity != "" {
+		label += " (" + a.Severity + ")"
+	}
+	return label
+}
+
+// assertionIndent marks the nesting depth of a composite
+// sub-result in the assertion tables.
+func assertionIndent(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strings.Repeat("&nbsp;&nbsp;", depth-1) + "&#8627; "
+}
@@ -69,6 +69,36 @@ func makeTestResults() []*challenge.Result {
 	}
 }
 
+// makeFlakyResult returns a result that passed on its second
+// attempt.
+func makeFlakyResult() *challenge.Result {
+	r := makeTestResult()
+	r.Flaky = true
+	r.Attempts = []challenge.Attempt{
+		{
+			Number: 1, Status: challenge.StatusTimedOut,
+			Duration: 3 * time.Second, Error: "timed out",
+		},
+		{Number: 2, Status: challenge.StatusPassed, Duration: 2 * time.Second},
+	}
+	return r
+}
+
+// makeWarningResult returns a result whose only failed
+// assertion is a warning.
+func makeWarningResult() *challenge.Result {
+	r := makeTestResult()
+	r.Status = challenge.StatusPassedWithWarnings
+	r.Assertions[1].Severity = challenge.SeverityWarning
+	return r
+}
+
+func TestStatusLabel(t *testing.T) {
+	assert.Equal(t, "PASSED", statusLabel(makeTestResult()))
+	assert.Equal(t, "PASSED (FLAKY)", statusLabel(makeFlakyResult()))
+	assert.Equal(t, "PASSED WITH WARNINGS (1)", statusLabel(makeWarningResult()))
+}
+
 func TestReporter_MarkdownImplementsInterface(t *testing.T) {
 	var _ Reporter = &MarkdownReporter{}
 }
@@ -19,6 +19,7 @@ type MasterSummary struct {
 	TotalChallenges  int                `json:"total_challenges"`
 	PassedChallenges int                `json:"passed_challenges"`
 	FailedChallenges int                `json:"failed_challenges"`
+	FlakyChallenges  int                `json:"flaky_challenges"`
 	TotalDuration    time.Duration      `json:"total_duration"`
 	AveragePassRate  float64            `json:"average_pass_rate"`
 }
@@ -32,6 +33,13 @@ type ChallengeSummary struct {
 	AssertionsPassed int           `json:"assertions_passed"`
 	AssertionsTotal  int           `json:"assertions_total"`
 	ResultsPath      string        `json:"results_path"`
+	Attempts         int           `json:"attempts,omitempty"`
+	Flaky            bool          `json:"flaky,omitempty"`
+	Warnings         int           `json:"warnings,omitempty"`
+
+	// Metrics holds the value of every metric of the result
+	// by name, for run-to-run comparison.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
 }
 
 // BuildMasterSummary creates a master summary from challenge
@@ -65,17 +73,29 @@ func BuildMasterSummary(
 			Duration:         r.Duration,
 			AssertionsPassed: assertionsPassed,
 			AssertionsTotal:  len(r.Assertions),
+			Attempts:         len(r.Attempts),
+			Flaky:            r.Flaky,
+			Warnings:         len(r.Warnings()),
+		}
+		if len(r.Metrics) > 0 {
+			cs.Metrics = make(map[string]float64, len(r.Metrics))
+			for name, m := range r.Metrics {
+				cs.Metrics[name] = m.Value
+			}
 		}
 
 		summary.Challenges = append(summary.Challenges, cs)
 		summary.TotalChallenges++
 		summary.TotalDuration += r.Duration
 
-		if r.Status == challenge.StatusPassed {
+		if challenge.IsPassing(r.Status) {
 			summary.PassedChallenges++
 		} else {
 			summary.FailedChallenges++
 		}
+		if r.Flaky {
+			summary.FlakyChallenges++
+		}
 	}
 
 	if summary.TotalChallenges > 0 {
@@ -173,6 +193,12 @@ func generateSummaryMarkdown(summary *MasterSummary) string {
 
 	for _, c := range summary.Challenges {
 		status := strings.ToUpper(c.Status)
+		if c.Status == challenge.StatusPassedWithWarnings {
+			status = fmt.Sprintf("PASSED WITH WARNINGS (%d)", c.Warnings)
+		}
+		if c.Flaky {
+			status += " (FLAKY)"
+		}
 		assertions := fmt.Sprintf(
 			"%d/%d", c.AssertionsPassed, c.AssertionsTotal,
 		)
@@ -204,6 +230,13 @@ func generateSummaryMarkdown(summary *MasterSummary) string {
 			"| Failed | %d |\n", summary.FailedChallenges,
 		),
 	)
+	if summary.FlakyChallenges > 0 {
+		sb.WriteString(
+			fmt.Sprintf(
+				"| Flaky | %d |\n", summary.FlakyChallenges,
+			),
+		)
+	}
 	sb.WriteString(
 		fmt.Sprintf(
 			"| Pass Rate | %.0f%% |\n",
@@ -168,3 +168,41 @@ func TestAppendToHistory_OpenFileError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "open history file")
 }
+
+func TestBuildMasterSummary_Flaky(t *testing.T) {
+	summary := BuildMasterSummary(
+		[]*challenge.Result{makeFlakyResult(), makeTestResult()},
+	)
+	assert.Equal(t, 1, summary.FlakyChallenges)
+	assert.True(t, summary.Challenges[0].Flaky)
+	assert.Equal(t, 2, summary.Challenges[0].Attempts)
+	assert.Contains(t,
+		generateSummaryMarkdown(summary), "PASSED (FLAKY)",
+	)
+}
+
+func TestBuildMasterSummary_Warnings(t *testing.T) {
+	summary := BuildMasterSummary(
+		[]*challenge.Result{makeWarningResult()},
+	)
+	assert.Equal(t, 1, summary.PassedChallenges)
+	assert.Equal(t, 1, summary.Challenges[0].Warnings)
+	assert.Contains(t,
+		generateSummaryMarkdown(summary), "PASSED WITH WARNINGS (1)",
+	)
+}
+
+func TestAppendToHistory_Flaky(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	require.NoError(t, AppendToHistory(
+		historyPath, makeFlakyResult(), "/tmp/results",
+	))
+
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+
+	var entry HistoricalEntry
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.True(t, entry.Flaky)
+	assert.Equal(t, 2, entry.Attempts)
+}
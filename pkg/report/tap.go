@@ -0,0 +1,157 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// TAPReporter generates Test Anything Protocol (version 13)
+// reports from challenge results. Each challenge is a test
+// point; failed points carry a YAML diagnostic block with the
+// failed assertions, outputs and log paths.
+type TAPReporter struct {
+	outputDir string
+}
+
+// NewTAPReporter creates a new TAP reporter.
+func NewTAPReporter(outputDir string) *TAPReporter {
+	return &TAPReporter{outputDir: outputDir}
+}
+
+// tapDiagnostic is the YAML block following a failed test
+// point.
+type tapDiagnostic struct {
+	Status   string            `yaml:"status"`
+	Duration string            `yaml:"duration"`
+	Error    string            `yaml:"error,omitempty"`
+	Failures []tapFailure      `yaml:"failures,omitempty"`
+	Outputs  map[string]string `yaml:"outputs,omitempty"`
+	Logs     map[string]string `yaml:"logs,omitempty"`
+}
+
+// tapFailure describes a failed assertion.
+type tapFailure struct {
+	Type     string       `yaml:"type"`
+	Target   string       `yaml:"target,omitempty"`
+	Message  string       `yaml:"message,omitempty"`
+	Expected string       `yaml:"expected,omitempty"`
+	Actual   string       `yaml:"actual,omitempty"`
+	Children []tapFailure `yaml:"children,omitempty"`
+}
+
+// GenerateReport creates a TAP report with a single test point
+// for the challenge result.
+func (r *TAPReporter) GenerateReport(
+	result *challenge.Result,
+) ([]byte, error) {
+	return r.GenerateMasterSummary([]*challenge.Result{result})
+}
+
+// GenerateMasterSummary creates a TAP report with one test
+// point per challenge result.
+func (r *TAPReporter) GenerateMasterSummary(
+	results []*challenge.Result,
+) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("TAP version 13\n")
+	fmt.Fprintf(&buf, "1..%d\n", len(results))
+	for i, res := range results {
+		if err := writeTestPoint(&buf, i+1, res); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteReport writes a TAP report to the specified writer.
+func (r *TAPReporter) WriteReport(
+	w io.Writer,
+	result *challenge.Result,
+) error {
+	data, err := r.GenerateReport(result)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// writeTestPoint writes the test point of a result. Skipped
+// challenges pass with a SKIP directive; challenges that did
+// not pass are followed by a diagnostic block.
+func writeTestPoint(w *bytes.Buffer, n int, res *challenge.Result) error {
+	desc := tapEscape(string(res.ChallengeID))
+	if res.ChallengeName != "" {
+		desc = tapEscape(res.ChallengeName) + " (" + desc + ")"
+	}
+	switch {
+	case res.Status == challenge.StatusSkipped:
+		fmt.Fprintf(w, "ok %d - %s # SKIP %s\n", n, desc, tapEscape(res.Error))
+		return nil
+	case challenge.IsPassing(res.Status):
+		fmt.Fprintf(w, "ok %d - %s\n", n, desc)
+		return nil
+	}
+
+	fmt.Fprintf(w, "not ok %d - %s\n", n, desc)
+	diag := tapDiagnostic{
+		Status:   res.Status,
+		Duration: res.Duration.String(),
+		Error:    res.Error,
+		Outputs:  res.Outputs,
+	}
+	for _, a := range res.Assertions {
+		if !a.Passed && a.IsBlocking() {
+			diag.Failures = append(diag.Failures, newTAPFailure(a))
+		}
+	}
+	if paths := logPaths(res.Logs); len(paths) > 0 {
+		diag.Logs = make(map[string]string, len(paths))
+		for _, l := range paths {
+			diag.Logs[l[0]] = l[1]
+		}
+	}
+	data, err := yaml.Marshal(diag)
+	if err != nil {
+		return fmt.Errorf("marshal tap diagnostic: %w", err)
+	}
+	w.WriteString("  ---\n")
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		w.WriteString("  " + line + "\n")
+	}
+	w.WriteString("  ...\n")
+	return nil
+}
+
+// newTAPFailure describes a failed assertion and its failed
+// sub-results.
+func newTAPFailure(a challenge.AssertionResult) tapFailure {
+	f := tapFailure{
+		Type:     a.Type,
+		Target:   a.Target,
+		Message:  a.Message,
+		Expected: formatValue(a.Expected),
+		Actual:   formatValue(a.Actual),
+	}
+	for _, c := range a.Children {
+		if !c.Passed {
+			f.Children = append(f.Children, newTAPFailure(c))
+		}
+	}
+	return f
+}
+
+// tapEscape escapes the characters with a meaning in a test
+// point description: "#" starts a directive and a newline
+// ends the line.
+func tapEscape(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "#", "\\#")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+func TestTAPReporter_GenerateMasterSummary(t *testing.T) {
+	results := append(makeTestResults(), &challenge.Result{
+		ChallengeID: "test-003", ChallengeName: "Third #3",
+		Status: challenge.StatusSkipped, Error: "dependency failed",
+	})
+	r := NewTAPReporter(t.TempDir())
+
+	data, err := r.GenerateMasterSummary(results)
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.True(t, strings.HasPrefix(out, "TAP version 13\n1..3\n"))
+	assert.Contains(t, out, "\nok 1 - Test Challenge (test-001)\n")
+	assert.Contains(t, out, "\nnot ok 2 - Another Challenge (test-002)\n")
+	assert.Contains(t, out, "\nok 3 - Third \\#3 (test-003) # SKIP dependency failed\n")
+
+	start := strings.Index(out, "  ---\n")
+	end := strings.Index(out, "  ...\n")
+	require.True(t, start > 0 && end > start)
+	var diag tapDiagnostic
+	block := strings.ReplaceAll(out[start+len("  ---\n"):end], "\n  ", "\n")
+	require.NoError(t, yaml.Unmarshal([]byte(strings.TrimPrefix(block, "  ")), &diag))
+	assert.Equal(t, challenge.StatusFailed, diag.Status)
+	assert.Equal(t, "2s", diag.Duration)
+	assert.Equal(t, "connection refused", diag.Error)
+	assert.Equal(t, "/tmp/ch2.log", diag.Logs["challenge_log"])
+}
+
+func TestTAPReporter_FailedAssertions(t *testing.T) {
+	result := makeTestResult()
+	result.Status = challenge.StatusFailed
+	result.Assertions[1].Expected = "keyword"
+	r := NewTAPReporter(t.TempDir())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteReport(&buf, result))
+	out := buf.String()
+	assert.Contains(t, out, "1..1\nnot ok 1 - Test Challenge (test-001)\n")
+	assert.Contains(t, out, "    - type: contains\n")
+	assert.Contains(t, out, "      expected: keyword\n")
+	assert.Contains(t, out, "    result: /tmp/result.json\n")
+	assert.NotContains(t, out, "not_empty")
+}
+
+func TestTAPReporter_PassedWithWarnings(t *testing.T) {
+	r := NewTAPReporter(t.TempDir())
+
+	data, err := r.GenerateReport(makeWarningResult())
+	require.NoError(t, err)
+	assert.Equal(t,
+		"TAP version 13\n1..1\nok 1 - Test Challenge (test-001)\n",
+		string(data))
+}
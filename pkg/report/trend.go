@@ -0,0 +1,156 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
+)
+
+// trendRuns is the number of recent runs the trend section
+// covers per challenge.
+const trendRuns = 20
+
+// Sparkline dimensions in pixels.
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+)
+
+// writeTrends writes the trend section of the master summary:
+// per challenge the pass rate, outcomes and durations of its
+// recent runs as inline SVG, its longest pass streak and the
+// run it started failing in, followed by the most flaky
+// challenges.
+func (r *HTMLReporter) writeTrends(
+	w io.Writer,
+	results []*challenge.Result,
+) {
+	h := r.history
+	if h == nil || h.Len() == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "<h2>Trends</h2>")
+	fmt.Fprintf(w, "<p>Last %d runs per challenge.</p>\n", trendRuns)
+	fmt.Fprintln(w, "<table>")
+	fmt.Fprintln(
+		w,
+		"<tr><th>Challenge</th><th>Pass Rate</th>"+
+			"<th>Outcomes</th><th>Duration</th>"+
+			"<th>Longest Pass Streak</th><th>Failing Since</th></tr>",
+	)
+	for _, result := range results {
+		id := string(result.ChallengeID)
+		runs := h.Runs(id, trendRuns)
+		if len(runs) == 0 {
+			continue
+		}
+		durations := h.DurationTrend(id, trendRuns)
+		values := make([]float64, len(durations))
+		for i, d := range durations {
+			values[i] = d.Seconds()
+		}
+		failingSince := "-"
+		if e, ok := h.FirstFailing(id); ok {
+			failingSince = e.Timestamp.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(
+			w,
+			"<tr><td>%s</td><td>%.0f%%</td>"+
+				"<td>%s</td><td>%s</td>"+
+				"<td>%d</td><td>%s</td></tr>\n",
+			html.EscapeString(result.ChallengeName),
+			h.PassRate(id, trendRuns)*100,
+			outcomeStrip(runs), sparkline(values),
+			h.LongestStreak(id, true).Length, failingSince,
+		)
+	}
+	fmt.Fprintln(w, "</table>")
+
+	flaky := h.MostFlaky(trendRuns, 5)
+	if len(flaky) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "<h3>Most Flaky</h3>")
+	fmt.Fprintln(w, "<table>")
+	fmt.Fprintln(
+		w,
+		"<tr><th>Challenge</th><th>Runs</th><th>Outcome Changes</th>"+
+			"<th>Passed After Retry</th></tr>",
+	)
+	for _, f := range flaky {
+		fmt.Fprintf(
+			w,
+			"<tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td></tr>\n",
+			html.EscapeString(f.ChallengeID),
+			f.Runs, f.Flips, f.FlakyPasses,
+		)
+	}
+	fmt.Fprintln(w, "</table>")
+}
+
+// sparkline renders values as an inline SVG line chart scaled
+// between their minimum and maximum.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	points := make([]string, len(values))
+	for i, v := range values {
+		x := float64(sparklineWidth) / 2
+		if len(values) > 1 {
+			x = float64(i) * sparklineWidth / float64(len(values)-1)
+		}
+		y := float64(sparklineHeight) / 2
+		if hi > lo {
+			// SVG y grows downwards; keep a 2px margin.
+			y = 2 + (hi-v)/(hi-lo)*(sparklineHeight-4)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	return fmt.Sprintf(
+		`<svg width="%d" height="%d" viewBox="0 0 %d %d" role="img">`+
+			`<title>%.3fs to %.3fs</title>`+
+			`<polyline points="%s" fill="none" stroke="#3498db" stroke-width="1.5"/></svg>`,
+		sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight,
+		lo, hi, strings.Join(points, " "),
+	)
+}
+
+// outcomeStrip renders runs as an inline SVG row of squares:
+// green for passes, orange for passes after a retry, red
+// otherwise.
+func outcomeStrip(runs []history.Entry) string {
+	const size, gap = 8, 2
+	var b strings.Builder
+	width := len(runs)*(size+gap) - gap
+	fmt.Fprintf(&b,
+		`<svg width="%d" height="%d" viewBox="0 0 %d %d" role="img">`,
+		width, size, width, size)
+	for i, e := range runs {
+		color := "#e74c3c"
+		switch {
+		case e.Passed() && e.Flaky:
+			color = "#e67e22"
+		case e.Passed():
+			color = "#27ae60"
+		}
+		fmt.Fprintf(&b,
+			`<rect x="%d" y="0" width="%d" height="%d" fill="%s">`+
+				`<title>%s %s</title></rect>`,
+			i*(size+gap), size, size, color,
+			e.Timestamp.Format("2006-01-02 15:04"),
+			html.EscapeString(e.Status))
+	}
+	b.WriteString("</svg>")
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/history"
+)
+
+func TestHTMLReporter_GenerateMasterSummary_Trends(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var entries []history.Entry
+	for i, status := range []string{"passed", "failed", "passed", "failed", "failed"} {
+		entries = append(entries, history.Entry{
+			Timestamp:   base.Add(time.Duration(i) * time.Hour),
+			ChallengeID: "test-002",
+			Status:      status,
+			Duration:    (time.Duration(i+1) * time.Second).String(),
+		})
+	}
+	r := NewHTMLReporter(t.TempDir())
+	r.SetHistory(history.New(entries))
+
+	data, err := r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	out := string(data)
+
+	assert.Contains(t, out, "<h2>Trends</h2>")
+	assert.Contains(t, out, "<td>Another Challenge</td><td>40%</td>")
+	assert.Contains(t, out, "<td>1</td><td>2026-01-01 03:00:00</td>")
+	assert.Equal(t, 5, strings.Count(out, "<rect "))
+	assert.Contains(t, out, `<polyline points="0.0,22.0 30.0,17.0 60.0,12.0 90.0,7.0 120.0,2.0"`)
+	assert.Contains(t, out, "<h3>Most Flaky</h3>")
+	assert.Contains(t, out, "<td>test-002</td><td>5</td><td>3</td><td>0</td>")
+	// Results without history have no trend row.
+	assert.NotContains(t, out, "<td>Test Challenge</td><td>")
+}
+
+func TestHTMLReporter_GenerateMasterSummary_NoHistory(t *testing.T) {
+	r := NewHTMLReporter(t.TempDir())
+	data, err := r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Trends")
+
+	r.SetHistory(history.New(nil))
+	data, err = r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Trends")
+}
+
+func TestBundleReporter_GenerateMasterSummary_Trends(t *testing.T) {
+	r := NewBundleReporter(t.TempDir(), BundleOptions{})
+	data, err := r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "Trends")
+
+	r.SetHistory(history.New([]history.Entry{{
+		Timestamp:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ChallengeID: "test-002", Status: "failed", Duration: "1s",
+	}}))
+	data, err = r.GenerateMasterSummary(makeTestResults())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<h2>Trends</h2>")
+	assert.Contains(t, string(data), "<td>Another Challenge</td><td>0%</td>")
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Empty(t, sparkline(nil))
+	assert.Contains(t, sparkline([]float64{2}), `points="60.0,12.0"`)
+	assert.Contains(t, sparkline([]float64{1, 1}), `points="0.0,12.0 120.0,12.0"`)
+}
@@ -0,0 +1,213 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// FailurePolicyMode selects how RunAll and RunSequence react
+// when a challenge does not pass.
+type FailurePolicyMode string
+
+const (
+	// FailurePolicyFailFast skips every remaining challenge
+	// after the first failure.
+	FailurePolicyFailFast FailurePolicyMode = "fail_fast"
+
+	// FailurePolicySkipDependents skips the transitive
+	// dependents of a challenge that did not pass and keeps
+	// running everything else.
+	FailurePolicySkipDependents FailurePolicyMode = "skip_dependents"
+
+	// FailurePolicyContinueAll executes every challenge, even
+	// when its dependencies did not pass.
+	FailurePolicyContinueAll FailurePolicyMode = "continue_all"
+
+	// FailurePolicyMaxFailures behaves like skip_dependents
+	// until MaxFailures challenges have failed, then skips
+	// everything that remains.
+	FailurePolicyMaxFailures FailurePolicyMode = "max_failures"
+)
+
+// FailurePolicy configures failure handling for RunAll and
+// RunSequence. The zero value keeps the legacy behaviour:
+// RunAll executes dependents of failed challenges and
+// RunSequence aborts with an "unmet dependency" error.
+type FailurePolicy struct {
+	// Mode selects the policy.
+	Mode FailurePolicyMode
+
+	// MaxFailures is the failure budget for
+	// FailurePolicyMaxFailures.
+	MaxFailures int
+}
+
+// ParseFailurePolicy parses "fail_fast", "skip_dependents",
+// "continue_all" or "max_failures=N".
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	s = strings.TrimSpace(s)
+	name, arg, hasArg := strings.Cut(s, "=")
+	switch mode := FailurePolicyMode(name); mode {
+	case FailurePolicyFailFast,
+		FailurePolicySkipDependents,
+		FailurePolicyContinueAll:
+		if hasArg {
+			return FailurePolicy{}, fmt.Errorf(
+				"failure policy %s takes no argument", name,
+			)
+		}
+		return FailurePolicy{Mode: mode}, nil
+	case FailurePolicyMaxFailures:
+		n, err := strconv.Atoi(arg)
+		if !hasArg || err != nil || n < 1 {
+			return FailurePolicy{}, fmt.Errorf(
+				"failure policy %s requires a positive count, "+
+					"e.g. max_failures=3", name,
+			)
+		}
+		return FailurePolicy{Mode: mode, MaxFailures: n}, nil
+	default:
+		return FailurePolicy{}, fmt.Errorf(
+			"unknown failure policy: %s", s,
+		)
+	}
+}
+
+// String returns the policy in the form accepted by
+// ParseFailurePolicy.
+func (p FailurePolicy) String() string {
+	if p.Mode == FailurePolicyMaxFailures {
+		return fmt.Sprintf("%s=%d", p.Mode, p.MaxFailures)
+	}
+	return string(p.Mode)
+}
+
+// isFailureStatus reports whether status counts as a failure
+// for fail_fast and max_failures. Skipped challenges block
+// their dependents but do not consume the failure budget.
+func isFailureStatus(status string) bool {
+	switch status {
+	case challenge.StatusFailed,
+		challenge.StatusError,
+		challenge.StatusTimedOut,
+		challenge.StatusStuck:
+		return true
+	}
+	return false
+}
+
+// failureTracker records outcomes during a run and decides,
+// according to a FailurePolicy, whether a challenge must be
+// skipped and why.
+type failureTracker struct {
+	policy  FailurePolicy
+	results map[challenge.ID]*challenge.Result
+	// rootCause maps a challenge that did not pass to the
+	// challenge whose failure caused it (itself unless it was
+	// skipped because of an ancestor).
+	rootCause   map[challenge.ID]challenge.ID
+	failures    int
+	abortReason string
+}
+
+// newFailureTracker creates a failureTracker for policy.
+func newFailureTracker(policy FailurePolicy) *failureTracker {
+	return &failureTracker{
+		policy:    policy,
+		results:   make(map[challenge.ID]*challenge.Result),
+		rootCause: make(map[challenge.ID]challenge.ID),
+	}
+}
+
+// active reports whether a non-legacy policy is configured.
+func (t *failureTracker) active() bool {
+	return t.policy.Mode != ""
+}
+
+// ran reports whether id has a recorded result.
+func (t *failureTracker) ran(id challenge.ID) bool {
+	_, ok := t.results[id]
+	return ok
+}
+
+// record stores the outcome of a challenge and updates the
+// failure budget.
+func (t *failureTracker) record(
+	id challenge.ID,
+	res *challenge.Result,
+) {
+	t.results[id] = res
+	if challenge.IsPassing(res.Status) {
+		return
+	}
+	if _, ok := t.rootCause[id]; !ok {
+		t.rootCause[id] = id
+	}
+	if !isFailureStatus(res.Status) || t.abortReason != "" {
+		return
+	}
+
+	t.failures++
+	switch t.policy.Mode {
+	case FailurePolicyFailFast:
+		t.abortReason = fmt.Sprintf(
+			"run aborted by fail_fast policy: %s did not pass (status %s)",
+			id, res.Status,
+		)
+	case FailurePolicyMaxFailures:
+		if t.failures >= t.policy.MaxFailures {
+			t.abortReason = fmt.Sprintf(
+				"run aborted: max_failures=%d reached, "+
+					"last failure %s (status %s)",
+				t.policy.MaxFailures, id, res.Status,
+			)
+		}
+	}
+}
+
+// skipReason reports whether c must be skipped under the
+// policy and why. When c is skipped because of a dependency the
+// reason names the failed ancestor.
+func (t *failureTracker) skipReason(
+	c challenge.Challenge,
+) (string, bool) {
+	if t.abortReason != "" {
+		return t.abortReason, true
+	}
+	switch t.policy.Mode {
+	case "", FailurePolicyContinueAll:
+		return "", false
+	}
+	return t.blockedBy(c.ID(), c.Dependencies())
+}
+
+// blockedBy reports whether any of deps finished without
+// passing. The root cause is recorded for id so that its own
+// dependents name the same ancestor.
+func (t *failureTracker) blockedBy(
+	id challenge.ID,
+	deps []challenge.ID,
+) (string, bool) {
+	for _, dep := range deps {
+		res, ok := t.results[dep]
+		if !ok || challenge.IsPassing(res.Status) {
+			continue
+		}
+		root := t.rootCause[dep]
+		t.rootCause[id] = root
+		if root == dep {
+			return fmt.Sprintf(
+				"dependency %s did not pass (status %s)",
+				dep, res.Status,
+			), true
+		}
+		return fmt.Sprintf(
+			"dependency %s was skipped: ancestor %s did not pass (status %s)",
+			dep, root, t.results[root].Status,
+		), true
+	}
+	return "", false
+}
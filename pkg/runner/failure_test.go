@@ -0,0 +1,272 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/monitor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statusByID(
+	results []*challenge.Result,
+) map[challenge.ID]*challenge.Result {
+	out := make(map[challenge.ID]*challenge.Result, len(results))
+	for _, r := range results {
+		out[r.ChallengeID] = r
+	}
+	return out
+}
+
+// ===== ParseFailurePolicy tests =====
+
+func TestParseFailurePolicy(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FailurePolicy
+	}{
+		{"fail_fast", FailurePolicy{Mode: FailurePolicyFailFast}},
+		{"skip_dependents", FailurePolicy{Mode: FailurePolicySkipDependents}},
+		{" continue_all ", FailurePolicy{Mode: FailurePolicyContinueAll}},
+		{"max_failures=3", FailurePolicy{
+			Mode: FailurePolicyMaxFailures, MaxFailures: 3,
+		}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFailurePolicy(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+
+	for _, bad := range []string{
+		"", "bogus", "fail_fast=2", "max_failures",
+		"max_failures=0", "max_failures=x",
+	} {
+		_, err := ParseFailurePolicy(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFailurePolicy_String(t *testing.T) {
+	assert.Equal(t, "max_failures=2", FailurePolicy{
+		Mode: FailurePolicyMaxFailures, MaxFailures: 2,
+	}.String())
+	assert.Equal(t, "fail_fast",
+		FailurePolicy{Mode: FailurePolicyFailFast}.String())
+}
+
+// ===== RunAll with FailurePolicy tests =====
+
+func TestRunAll_SkipDependents(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	c := newStub("c", "b")
+	d := newStub("d")
+	reg := setupRegistry(t, a, b, c, d)
+	collector := monitor.NewEventCollector()
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithEventCollector(collector),
+		WithFailurePolicy(FailurePolicy{
+			Mode: FailurePolicySkipDependents,
+		}),
+	)
+
+	results, err := r.RunAll(
+		context.Background(), challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	byID := statusByID(results)
+	require.Len(t, byID, 4)
+
+	assert.Equal(t, challenge.StatusFailed, byID["a"].Status)
+	assert.Equal(t, challenge.StatusSkipped, byID["b"].Status)
+	assert.Contains(t, byID["b"].Error, "dependency a did not pass")
+	assert.Equal(t, challenge.StatusSkipped, byID["c"].Status)
+	assert.Contains(t, byID["c"].Error, "ancestor a did not pass")
+	assert.Equal(t, challenge.StatusPassed, byID["d"].Status)
+	assert.Equal(t, 0, b.executeCalls)
+
+	var reasons []string
+	for _, ev := range collector.Events() {
+		if ev.Type == monitor.EventSkipped {
+			reasons = append(reasons, ev.Message)
+		}
+	}
+	require.Len(t, reasons, 2)
+	assert.Contains(t, reasons[0], "a")
+}
+
+func TestRunAll_FailFast(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b")
+	c := newStub("c")
+	reg := setupRegistry(t, a, b, c)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{Mode: FailurePolicyFailFast}),
+	)
+
+	results, err := r.RunAll(
+		context.Background(), challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	// Dependency order is alphabetical for independent
+	// challenges, so "a" runs first.
+	assert.Equal(t, challenge.StatusFailed, results[0].Status)
+	for _, res := range results[1:] {
+		assert.Equal(t, challenge.StatusSkipped, res.Status)
+		assert.Contains(t, res.Error, "fail_fast")
+		assert.Contains(t, res.Error, "a did not pass")
+	}
+	assert.Equal(t, 0, b.executeCalls)
+	assert.Equal(t, 0, c.executeCalls)
+}
+
+func TestRunAll_ContinueAll(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{Mode: FailurePolicyContinueAll}),
+	)
+
+	results, err := r.RunAll(
+		context.Background(), challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	byID := statusByID(results)
+	assert.Equal(t, challenge.StatusPassed, byID["b"].Status)
+	assert.Equal(t, 1, b.executeCalls)
+}
+
+func TestRunAll_MaxFailures(t *testing.T) {
+	a := failingStub("a")
+	b := failingStub("b")
+	c := newStub("c", "a")
+	d := newStub("d")
+	e := newStub("e")
+	reg := setupRegistry(t, a, b, c, d, e)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{
+			Mode: FailurePolicyMaxFailures, MaxFailures: 2,
+		}),
+	)
+
+	results, err := r.RunAll(
+		context.Background(), challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	byID := statusByID(results)
+	require.Len(t, byID, 5)
+
+	assert.Equal(t, challenge.StatusFailed, byID["a"].Status)
+	assert.Equal(t, challenge.StatusFailed, byID["b"].Status)
+	for _, id := range []challenge.ID{"c", "d", "e"} {
+		assert.Equal(t, challenge.StatusSkipped, byID[id].Status, id)
+	}
+	assert.Contains(t, byID["d"].Error, "max_failures=2 reached")
+}
+
+// ===== RunSequence with FailurePolicy tests =====
+
+func TestRunSequence_SkipDependents(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	c := newStub("c")
+	reg := setupRegistry(t, a, b, c)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{
+			Mode: FailurePolicySkipDependents,
+		}),
+	)
+
+	results, err := r.RunSequence(
+		context.Background(),
+		[]challenge.ID{"a", "b", "c"},
+		challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	byID := statusByID(results)
+	require.Len(t, byID, 3)
+	assert.Equal(t, challenge.StatusSkipped, byID["b"].Status)
+	assert.Contains(t, byID["b"].Error, "dependency a did not pass")
+	assert.Equal(t, challenge.StatusPassed, byID["c"].Status)
+}
+
+func TestRunSequence_ContinueAll(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{Mode: FailurePolicyContinueAll}),
+	)
+
+	results, err := r.RunSequence(
+		context.Background(),
+		[]challenge.ID{"a", "b"},
+		challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 1, b.executeCalls)
+}
+
+func TestRunSequence_NoPolicy_FailedDependencyAborts(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	_, err := r.RunSequence(
+		context.Background(),
+		[]challenge.ID{"a", "b"},
+		challenge.NewConfig(""),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unmet dependency")
+}
+
+func TestRunSequence_Policy_DependencyOutsideSequence(t *testing.T) {
+	reg := setupRegistry(t, newStub("a"), newStub("b", "a"))
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{Mode: FailurePolicyContinueAll}),
+	)
+
+	// A dependency that never ran is still unmet.
+	_, err := r.RunSequence(
+		context.Background(),
+		[]challenge.ID{"b"},
+		challenge.NewConfig(""),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unmet dependency")
+}
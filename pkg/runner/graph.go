@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// graphNode is a challenge scheduled by runGraph.
+type graphNode struct {
+	index      int
+	c          challenge.Challenge
+	pending    int
+	deps       []challenge.ID
+	dependents []challenge.ID
+}
+
+// graphResult is sent by a worker when a node finishes.
+type graphResult struct {
+	id     challenge.ID
+	result *challenge.Result
+	cfg    *challenge.Config
+	err    error
+}
+
+// runGraph executes challenges as a dependency DAG. A challenge
+// starts as soon as every dependency inside the set has passed;
+// independent branches run concurrently, bounded by
+// maxConcurrency. Descendants of a challenge that did not pass
+// are marked StatusSkipped with a reason naming the failed
+// ancestor. Dependencies outside ids are not scheduled and are
+// expected to be supplied via config.Dependencies. Results are
+// returned in the order of ids; an empty ids schedules every
+// registered challenge.
+func runGraph(
+	ctx context.Context,
+	r *DefaultRunner,
+	ids []challenge.ID,
+	config *challenge.Config,
+	maxConcurrency int,
+) ([]*challenge.Result, error) {
+	if maxConcurrency <= 0 {
+		maxConcurrency = 1
+	}
+	if len(ids) == 0 {
+		for _, c := range r.registry.List() {
+			ids = append(ids, c.ID())
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+
+	// topoSort validates that every ID exists and that the set
+	// is acyclic before anything runs.
+	if _, err := r.topoSort(ids); err != nil {
+		return nil, fmt.Errorf("run graph: %w", err)
+	}
+
+	nodes := make(map[challenge.ID]*graphNode, len(ids))
+	for i, id := range ids {
+		if _, dup := nodes[id]; dup {
+			return nil, fmt.Errorf(
+				"run graph: duplicate challenge %s", id,
+			)
+		}
+		c, _ := r.registry.Get(id)
+		nodes[id] = &graphNode{index: i, c: c}
+	}
+	for _, id := range ids {
+		n := nodes[id]
+		for _, dep := range n.c.Dependencies() {
+			parent, ok := nodes[dep]
+			if !ok {
+				continue // dependency outside the graph
+			}
+			n.pending++
+			n.deps = append(n.deps, dep)
+			parent.dependents = append(parent.dependents, id)
+		}
+	}
+
+	depResults := make(map[challenge.ID]string, len(ids))
+	for k, v := range config.Dependencies {
+		depResults[k] = v
+	}
+
+	results := make([]*challenge.Result, len(ids))
+	// The graph always skips descendants of challenges that did
+	// not pass; independent branches keep running.
+	tracker := newFailureTracker(FailurePolicy{
+		Mode: FailurePolicySkipDependents,
+	})
+
+	sem := make(chan struct{}, maxConcurrency)
+	doneCh := make(chan graphResult, len(ids))
+	var wg sync.WaitGroup
+	var firstErr error
+
+	launch := func(id challenge.ID) {
+		cfg := *config
+		cfg.ChallengeID = id
+		cfg.Dependencies = make(
+			map[challenge.ID]string, len(depResults),
+		)
+		for k, v := range depResults {
+			cfg.Dependencies[k] = v
+		}
+
+		wg.Add(1)
+		go func(c challenge.Challenge, cfg *challenge.Config) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				doneCh <- graphResult{id: c.ID(), err: ctx.Err()}
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				doneCh <- graphResult{id: c.ID(), err: err}
+				return
+			}
+
+			result, err := r.executeChallenge(ctx, r.journal, c, cfg)
+			doneCh <- graphResult{
+				id: c.ID(), result: result, cfg: cfg, err: err,
+			}
+		}(nodes[id].c, &cfg)
+	}
+
+	// finish records a terminal result and releases or skips
+	// dependents. It returns the number of nodes completed,
+	// including cascaded skips.
+	var finish func(id challenge.ID, res *challenge.Result) int
+	finish = func(id challenge.ID, res *challenge.Result) int {
+		n := nodes[id]
+		results[n.index] = res
+		tracker.record(id, res)
+		completed := 1
+
+		for _, childID := range n.dependents {
+			child := nodes[childID]
+			child.pending--
+			if child.pending > 0 {
+				continue
+			}
+			if reason, blocked := tracker.blockedBy(
+				childID, child.deps,
+			); blocked {
+				completed += finish(
+					childID, r.skipChallenge(ctx, r.journal, child.c, reason),
+				)
+				continue
+			}
+			launch(childID)
+		}
+		return completed
+	}
+
+	for _, id := range ids {
+		if nodes[id].pending == 0 {
+			launch(id)
+		}
+	}
+
+	for remaining := len(ids); remaining > 0; {
+		gr := <-doneCh
+		res := gr.result
+		if gr.err != nil && firstErr == nil {
+			firstErr = fmt.Errorf(
+				"challenge %s failed: %w", gr.id, gr.err,
+			)
+		}
+		if res == nil {
+			reason := "not executed"
+			if gr.err != nil {
+				reason = fmt.Sprintf("not executed: %v", gr.err)
+			}
+			res = r.skipChallenge(ctx, r.journal, nodes[gr.id].c, reason)
+		}
+		if challenge.IsPassing(res.Status) && gr.cfg != nil {
+			depResults[gr.id] = gr.cfg.ResultsDir
+		}
+		remaining -= finish(gr.id, res)
+	}
+
+	wg.Wait()
+	return results, firstErr
+}
+
+// skipChallenge builds a StatusSkipped result for a challenge
+// that was never executed, emits the skipped event, records it
+// in journal and passes it to the result hooks.
+func (r *DefaultRunner) skipChallenge(
+	ctx context.Context,
+	journal *Journal,
+	c challenge.Challenge,
+	reason string,
+) *challenge.Result {
+	now := time.Now()
+	r.logEvent("challenge_skipped", map[string]any{
+		"challenge_id": c.ID(),
+		"reason":       reason,
+	})
+	if r.eventCollector != nil {
+		r.eventCollector.EmitSkipped(c.ID(), c.Name(), reason)
+	}
+	result := &challenge.Result{
+		ChallengeID:   c.ID(),
+		ChallengeName: c.Name(),
+		Status:        challenge.StatusSkipped,
+		StartTime:     now,
+		EndTime:       now,
+		Metrics:       make(map[string]challenge.MetricValue),
+		Outputs:       make(map[string]string),
+		Error:         reason,
+	}
+	r.recordJournal(ctx, journal, result, "")
+	r.runResultHooks(ctx, result)
+	return result
+}
@@ -0,0 +1,237 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/monitor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depRecorder captures the Config each challenge was configured
+// with so tests can inspect per-challenge Dependencies.
+type depRecorder struct {
+	*stubChallenge
+	mu   sync.Mutex
+	deps map[challenge.ID]string
+}
+
+func (d *depRecorder) Configure(cfg *challenge.Config) error {
+	d.mu.Lock()
+	d.deps = cfg.Dependencies
+	d.mu.Unlock()
+	return d.stubChallenge.Configure(cfg)
+}
+
+func failingStub(id string, deps ...string) *stubChallenge {
+	s := newStub(id, deps...)
+	s.execResult = &challenge.Result{
+		Status:          challenge.StatusFailed,
+		RecordedActions: []string{"stub-action"},
+		Assertions: []challenge.AssertionResult{
+			{Passed: false, Message: "nope"},
+		},
+	}
+	return s
+}
+
+func TestRunGraph_Diamond(t *testing.T) {
+	a := newStub("a")
+	b := newStub("b", "a")
+	c := newStub("c", "a")
+	d := &depRecorder{stubChallenge: newStub("d", "b", "c")}
+	reg := setupRegistryWith(t, a, b, c, d)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	results, err := r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"d", "c", "b", "a"},
+		challenge.NewConfig(""), 4,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	// Results follow the order of the requested IDs.
+	assert.Equal(t, challenge.ID("d"), results[0].ChallengeID)
+	assert.Equal(t, challenge.ID("a"), results[3].ChallengeID)
+	for _, res := range results {
+		assert.Equal(t, challenge.StatusPassed, res.Status)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	assert.Contains(t, d.deps, challenge.ID("a"))
+	assert.Contains(t, d.deps, challenge.ID("b"))
+	assert.Contains(t, d.deps, challenge.ID("c"))
+}
+
+func TestRunGraph_IndependentBranchesRunConcurrently(
+	t *testing.T,
+) {
+	stubs := make([]*stubChallenge, 4)
+	for i := range stubs {
+		stubs[i] = newStub(string(rune('a' + i)))
+		stubs[i].execDelay = 100 * time.Millisecond
+	}
+	reg := setupRegistry(t, stubs...)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	start := time.Now()
+	results, err := r.RunGraph(
+		context.Background(), nil, challenge.NewConfig(""), 4,
+	)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	// Serial execution would take at least 400ms.
+	assert.Less(t, elapsed, 350*time.Millisecond)
+}
+
+func TestRunGraph_DependentWaitsForDependency(t *testing.T) {
+	a := newStub("a")
+	a.execDelay = 50 * time.Millisecond
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	results, err := r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"a", "b"},
+		challenge.NewConfig(""), 2,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.False(t, results[1].StartTime.Before(results[0].EndTime))
+}
+
+func TestRunGraph_SkipsDescendantsOfFailure(t *testing.T) {
+	a := failingStub("a")
+	b := newStub("b", "a")
+	c := newStub("c", "b")
+	d := newStub("d")
+	reg := setupRegistry(t, a, b, c, d)
+	collector := monitor.NewEventCollector()
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithEventCollector(collector),
+	)
+
+	results, err := r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"a", "b", "c", "d"},
+		challenge.NewConfig(""), 2,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, challenge.StatusFailed, results[0].Status)
+	assert.Equal(t, challenge.StatusSkipped, results[1].Status)
+	assert.Contains(t, results[1].Error, "dependency a did not pass")
+	assert.Equal(t, challenge.StatusSkipped, results[2].Status)
+	assert.Contains(t, results[2].Error, "ancestor a")
+	assert.Equal(t, challenge.StatusPassed, results[3].Status)
+
+	assert.Equal(t, 0, b.executeCalls)
+	assert.Equal(t, 0, c.executeCalls)
+
+	skipped := 0
+	for _, ev := range collector.Events() {
+		if ev.Type == monitor.EventSkipped {
+			skipped++
+		}
+	}
+	assert.Equal(t, 2, skipped)
+}
+
+func TestRunGraph_ExternalDependencyFromConfig(t *testing.T) {
+	b := &depRecorder{stubChallenge: newStub("b", "external")}
+	reg := setupRegistryWith(t, b)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	cfg := challenge.NewConfig("")
+	cfg.Dependencies["external"] = "/prior/run"
+
+	results, err := r.RunGraph(
+		context.Background(), []challenge.ID{"b"}, cfg, 1,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	assert.Equal(t, "/prior/run", b.deps["external"])
+}
+
+func TestRunGraph_Errors(t *testing.T) {
+	a := newStub("a", "b")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	_, err := r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"a", "b"},
+		challenge.NewConfig(""), 2,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cyclic")
+
+	_, err = r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"missing"},
+		challenge.NewConfig(""), 2,
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+}
+
+func TestRunGraph_ContextCancelled(t *testing.T) {
+	a := newStub("a")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := r.RunGraph(
+		ctx, []challenge.ID{"a", "b"}, challenge.NewConfig(""), 1,
+	)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	require.Len(t, results, 2)
+	assert.Equal(t, challenge.StatusSkipped, results[0].Status)
+	assert.Equal(t, challenge.StatusSkipped, results[1].Status)
+	assert.Equal(t, 0, a.executeCalls)
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"sync"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// outputStore keeps the latest result of every challenge run by
+// a DefaultRunner so that downstream challenges can consume
+// upstream outputs. It is safe for concurrent use.
+type outputStore struct {
+	mu      sync.RWMutex
+	results map[challenge.ID]*challenge.Result
+}
+
+// newOutputStore creates an empty outputStore.
+func newOutputStore() *outputStore {
+	return &outputStore{
+		results: make(map[challenge.ID]*challenge.Result),
+	}
+}
+
+// put records the result of a challenge.
+func (s *outputStore) put(result *challenge.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[result.ChallengeID] = result
+}
+
+// lookup returns the recorded results of ids that passed.
+func (s *outputStore) lookup(
+	ids []challenge.ID,
+) map[challenge.ID]*challenge.Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[challenge.ID]*challenge.Result, len(ids))
+	for _, id := range ids {
+		if res, ok := s.results[id]; ok &&
+			challenge.IsPassing(res.Status) {
+			out[id] = res
+		}
+	}
+	return out
+}
+
+// injectInputs resolves the dependency inputs declared by c from
+// upstream results and sets config.Inputs to a fresh map holding
+// the caller's inputs and the resolved ones, so that challenges
+// sharing a copied config never share or write its map. It
+// returns an error when a required input cannot be resolved.
+func (r *DefaultRunner) injectInputs(
+	c challenge.Challenge,
+	config *challenge.Config,
+) error {
+	decl, ok := c.(challenge.InputDeclarer)
+	if !ok {
+		return nil
+	}
+	inputs := decl.DeclaredInputs()
+
+	deps := c.Dependencies()
+	var ids []challenge.ID
+	for _, in := range inputs {
+		id, _, ok := challenge.ParseDependencySource(in.Source, deps...)
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	resolved, err := challenge.ResolveDependencyInputs(
+		inputs, r.outputs.lookup(ids),
+	)
+	if err != nil {
+		return err
+	}
+	merged := make(map[string]any, len(config.Inputs)+len(resolved))
+	for k, v := range config.Inputs {
+		merged[k] = v
+	}
+	for k, v := range resolved {
+		merged[k] = v
+	}
+	config.Inputs = merged
+	return nil
+}
@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inputStub declares inputs and records the config it was
+// configured with.
+type inputStub struct {
+	*stubChallenge
+	inputs []challenge.Input
+	seen   map[string]any
+	config *challenge.Config
+}
+
+func (s *inputStub) DeclaredInputs() []challenge.Input {
+	return s.inputs
+}
+
+func (s *inputStub) Configure(cfg *challenge.Config) error {
+	s.seen = cfg.Inputs
+	s.config = cfg
+	return s.stubChallenge.Configure(cfg)
+}
+
+// ===== Dependency input injection tests =====
+
+func TestDefaultRunner_InjectsUpstreamOutputs(t *testing.T) {
+	build := newStub("build")
+	build.execResult.Outputs = map[string]string{"apk_path": "/out/app.apk"}
+	build.execResult.TypedOutputs = map[string]any{"size": float64(12)}
+
+	deploy := &inputStub{
+		stubChallenge: newStub("deploy", "build"),
+		inputs: []challenge.Input{
+			{Name: "apk", Source: "dependency:build.apk_path", Required: true},
+			{Name: "size", Source: "dependency:build.size"},
+			{Name: "env_only", Source: "env"},
+		},
+	}
+	reg := setupRegistryWith(t, build, deploy)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	results, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, challenge.StatusPassed, results[1].Status)
+	assert.Equal(t, map[string]any{
+		"apk": "/out/app.apk", "size": float64(12),
+	}, deploy.seen)
+}
+
+func TestDefaultRunner_MissingRequiredInputFailsValidation(t *testing.T) {
+	build := newStub("build")
+	deploy := &inputStub{
+		stubChallenge: newStub("deploy", "build"),
+		inputs: []challenge.Input{
+			{Name: "apk", Source: "dependency:build.apk_path", Required: true},
+		},
+	}
+	reg := setupRegistryWith(t, build, deploy)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	results, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, challenge.StatusSkipped, results[1].Status)
+	assert.Contains(t, results[1].Error, "validation failed")
+	assert.Contains(t, results[1].Error, "required input apk")
+	assert.Equal(t, 0, deploy.executeCalls)
+	assert.Equal(t, 0, deploy.configureCalls)
+}
+
+func TestDefaultRunner_FailedUpstreamOutputsNotInjected(t *testing.T) {
+	build := newStub("build")
+	build.execResult.Status = challenge.StatusFailed
+	build.execResult.Outputs = map[string]string{"apk_path": "/out/app.apk"}
+	deploy := &inputStub{
+		stubChallenge: newStub("deploy", "build"),
+		inputs: []challenge.Input{
+			{Name: "apk", Source: "dependency:build.apk_path"},
+		},
+	}
+	reg := setupRegistryWith(t, build, deploy)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	_, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	assert.Empty(t, deploy.seen)
+}
+
+func TestDefaultRunner_InputsNotSharedBetweenChallenges(t *testing.T) {
+	build := newStub("build")
+	build.execResult.Outputs = map[string]string{
+		"apk_path": "/out/app.apk", "ipa_path": "/out/app.ipa",
+	}
+	android := &inputStub{
+		stubChallenge: newStub("android", "build"),
+		inputs: []challenge.Input{
+			{Name: "apk", Source: "dependency:build.apk_path"},
+		},
+	}
+	ios := &inputStub{
+		stubChallenge: newStub("ios", "build"),
+		inputs: []challenge.Input{
+			{Name: "ipa", Source: "dependency:build.ipa_path"},
+		},
+	}
+	reg := setupRegistryWith(t, build, android, ios)
+	cfg := challenge.NewConfig("")
+	cfg.Inputs = map[string]any{"region": "eu"}
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	_, err := r.RunGraph(
+		context.Background(),
+		[]challenge.ID{"build", "android", "ios"}, cfg, 2,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"region": "eu"}, cfg.Inputs)
+	assert.Equal(t, map[string]any{
+		"region": "eu", "apk": "/out/app.apk",
+	}, android.seen)
+	assert.Equal(t, map[string]any{
+		"region": "eu", "ipa": "/out/app.ipa",
+	}, ios.seen)
+}
+
+// ===== Declarative bank tests =====
+
+func TestDefaultRunner_RunsDeclarativeExampleBank(t *testing.T) {
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.SetDefinitionFactory(
+		registry.NewDefinitionFactory(nil),
+	))
+	require.NoError(t, registry.LoadDefinitionsFromFile(
+		reg, "../../banks/examples/declarative-shell.json",
+	))
+
+	resultsDir := t.TempDir()
+	cfg := challenge.NewConfig("")
+	cfg.ResultsDir = resultsDir
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(resultsDir))
+	results, err := r.RunAll(context.Background(), cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, res := range results {
+		assert.Equal(t, challenge.StatusPassed, res.Status, res.Error)
+	}
+	assert.Equal(t, "verified app-1.2.0.tar.gz", results[1].Outputs["stdout"])
+}
@@ -0,0 +1,262 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// JournalFileName is the name of the journal file inside a run
+// directory.
+const JournalFileName = "journal.jsonl"
+
+// JournalRecord is one line of a run journal, written when a
+// challenge completes.
+type JournalRecord struct {
+	RunID       string       `json:"run_id"`
+	ChallengeID challenge.ID `json:"challenge_id"`
+	Status      string       `json:"status"`
+	// ResultPath is the JSON file holding the full
+	// challenge.Result.
+	ResultPath string `json:"result_path"`
+	// ResultsDir is the challenge results directory, restored
+	// into Config.Dependencies on resume.
+	ResultsDir string `json:"results_dir"`
+	// Interrupted is true when the run context was already
+	// cancelled as the challenge completed; such results are
+	// re-executed on resume.
+	Interrupted bool      `json:"interrupted,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// Reusable reports whether a resumed run can keep this record
+// instead of executing the challenge again.
+func (rec JournalRecord) Reusable() bool {
+	res := challenge.Result{Status: rec.Status}
+	return res.IsFinal() && !rec.Interrupted
+}
+
+// Journal appends completion records for a single run to
+// <dir>/journal.jsonl and stores each result as
+// <dir>/results/<challenge-id>.json. It is safe for concurrent
+// use.
+type Journal struct {
+	mu    sync.Mutex
+	runID string
+	dir   string
+}
+
+// NewJournal creates a Journal for runID rooted at dir. Nothing
+// is written until the first Record call.
+func NewJournal(dir, runID string) *Journal {
+	return &Journal{runID: runID, dir: dir}
+}
+
+// NewRunID returns a timestamp-based run identifier.
+func NewRunID() string {
+	return "run_" + time.Now().Format("20060102_150405")
+}
+
+// RunID returns the run identifier.
+func (j *Journal) RunID() string {
+	return j.runID
+}
+
+// Dir returns the run directory.
+func (j *Journal) Dir() string {
+	return j.dir
+}
+
+// Path returns the journal file path.
+func (j *Journal) Path() string {
+	return filepath.Join(j.dir, JournalFileName)
+}
+
+// Record writes the result JSON and appends a journal record.
+func (j *Journal) Record(
+	result *challenge.Result,
+	resultsDir string,
+	interrupted bool,
+) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	resultDir := filepath.Join(j.dir, "results")
+	if err := os.MkdirAll(resultDir, 0755); err != nil {
+		return fmt.Errorf("create journal dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf(
+			"marshal result %s: %w", result.ChallengeID, err,
+		)
+	}
+	resultPath := filepath.Join(
+		resultDir, string(result.ChallengeID)+".json",
+	)
+	if err := os.WriteFile(resultPath, data, 0644); err != nil {
+		return fmt.Errorf(
+			"write result %s: %w", result.ChallengeID, err,
+		)
+	}
+
+	line, err := json.Marshal(JournalRecord{
+		RunID:       j.runID,
+		ChallengeID: result.ChallengeID,
+		Status:      result.Status,
+		ResultPath:  resultPath,
+		ResultsDir:  resultsDir,
+		Interrupted: interrupted,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal journal record: %w", err)
+	}
+
+	f, err := os.OpenFile(
+		j.Path(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644,
+	)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	_, err = fmt.Fprintln(f, string(line))
+	return err
+}
+
+// Load reads the journal and returns the latest record per
+// challenge. Malformed lines (e.g., a partial write when the
+// process was killed) are ignored.
+func (j *Journal) Load() (map[challenge.ID]JournalRecord, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.Path())
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	records := make(map[challenge.ID]JournalRecord)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec JournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		if rec.ChallengeID == "" {
+			continue
+		}
+		records[rec.ChallengeID] = rec
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return records, nil
+}
+
+// LoadResult reads the result stored for a journal record.
+func LoadResult(rec JournalRecord) (*challenge.Result, error) {
+	data, err := os.ReadFile(rec.ResultPath)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"read result %s: %w", rec.ChallengeID, err,
+		)
+	}
+	var result challenge.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf(
+			"parse result %s: %w", rec.ChallengeID, err,
+		)
+	}
+	return &result, nil
+}
+
+// Resume continues the journaled run runID with the semantics of
+// RunAll. Challenges whose journal record is final and was not
+// interrupted are not executed again: their stored result is
+// returned and, when passed, their results directory is restored
+// into config.Dependencies for dependents. Everything else is
+// executed and appended to the same journal. The journal is used
+// for this call only; the runner's own run ID and journal, if
+// any, are left as they are.
+func (r *DefaultRunner) Resume(
+	ctx context.Context,
+	runID string,
+	config *challenge.Config,
+) ([]*challenge.Result, error) {
+	if runID == "" {
+		return nil, fmt.Errorf("resume: run id is required")
+	}
+	journal := NewJournal(r.runDir(runID), runID)
+
+	prior, err := journal.Load()
+	if err != nil {
+		return nil, fmt.Errorf("resume run %s: %w", runID, err)
+	}
+	r.logEvent("run_resumed", map[string]any{
+		"run_id":  runID,
+		"journal": journal.Path(),
+		"records": len(prior),
+	})
+	return r.runAll(ctx, config, journal, prior)
+}
+
+// reuseResult returns the journaled result for id when prior
+// holds a reusable record whose result file can be read. It
+// returns the recorded results directory alongside.
+func (r *DefaultRunner) reuseResult(
+	prior map[challenge.ID]JournalRecord,
+	id challenge.ID,
+) (*challenge.Result, string, bool) {
+	rec, ok := prior[id]
+	if !ok || !rec.Reusable() {
+		return nil, "", false
+	}
+	result, err := LoadResult(rec)
+	if err != nil {
+		r.logEvent("journal_warning", map[string]any{
+			"challenge_id": id,
+			"error":        err.Error(),
+		})
+		return nil, "", false
+	}
+	r.outputs.put(result)
+	r.logEvent("challenge_resumed", map[string]any{
+		"challenge_id": id,
+		"status":       result.Status,
+	})
+	return result, rec.ResultsDir, true
+}
+
+// recordJournal appends result to journal, if not nil. A result
+// produced after ctx was cancelled is marked interrupted so that
+// Resume executes it again. Journal errors are logged and never
+// fail the run.
+func (r *DefaultRunner) recordJournal(
+	ctx context.Context,
+	journal *Journal,
+	result *challenge.Result,
+	resultsDir string,
+) {
+	if journal == nil {
+		return
+	}
+	interrupted := ctx.Err() != nil
+	if err := journal.Record(result, resultsDir, interrupted); err != nil {
+		r.logEvent("journal_warning", map[string]any{
+			"challenge_id": result.ChallengeID,
+			"error":        err.Error(),
+		})
+	}
+}
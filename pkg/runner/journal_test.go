@@ -0,0 +1,308 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== Journal tests =====
+
+func TestJournal_RecordAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal(dir, "run_1")
+
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusFailed,
+	}, "/res/a", false))
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusPassed,
+		Outputs:     map[string]string{"k": "v"},
+	}, "/res/a2", false))
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "b",
+		Status:      challenge.StatusError,
+	}, "", true))
+
+	records, err := j.Load()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	a := records["a"]
+	assert.Equal(t, "run_1", a.RunID)
+	assert.Equal(t, challenge.StatusPassed, a.Status)
+	assert.Equal(t, "/res/a2", a.ResultsDir)
+	assert.True(t, a.Reusable())
+
+	res, err := LoadResult(a)
+	require.NoError(t, err)
+	assert.Equal(t, "v", res.Outputs["k"])
+
+	assert.True(t, records["b"].Interrupted)
+	assert.False(t, records["b"].Reusable())
+}
+
+func TestJournal_LoadSkipsMalformedLines(t *testing.T) {
+	dir := t.TempDir()
+	j := NewJournal(dir, "run_1")
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusPassed,
+	}, "", false))
+
+	f, err := os.OpenFile(j.Path(), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"run_id":"run_1","challenge_id":"b","sta`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	records, err := j.Load()
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Contains(t, records, challenge.ID("a"))
+}
+
+func TestJournal_LoadMissing(t *testing.T) {
+	_, err := NewJournal(t.TempDir(), "run_x").Load()
+	assert.Error(t, err)
+}
+
+func TestJournalRecord_Reusable(t *testing.T) {
+	assert.False(t, JournalRecord{Status: challenge.StatusPending}.Reusable())
+	assert.False(t, JournalRecord{Status: challenge.StatusRunning}.Reusable())
+	assert.True(t, JournalRecord{Status: challenge.StatusSkipped}.Reusable())
+	assert.False(t, JournalRecord{
+		Status: challenge.StatusTimedOut, Interrupted: true,
+	}.Reusable())
+}
+
+// ===== Runner journal tests =====
+
+func TestDefaultRunner_WithRunID_WritesJournal(t *testing.T) {
+	resultsDir := t.TempDir()
+	reg := setupRegistry(t, newStub("a"), newStub("b", "a"))
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(resultsDir),
+		WithRunID("run_1"),
+	)
+	assert.Equal(t, "run_1", r.RunID())
+
+	_, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+
+	path := filepath.Join(resultsDir, "runs", "run_1", JournalFileName)
+	assert.FileExists(t, path)
+	records, err := NewJournal(filepath.Dir(path), "run_1").Load()
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.FileExists(t, records["b"].ResultPath)
+}
+
+func TestDefaultRunner_WithoutRunID_NoJournal(t *testing.T) {
+	resultsDir := t.TempDir()
+	reg := setupRegistry(t, newStub("a"))
+	r := NewRunner(WithRegistry(reg), WithResultsDir(resultsDir))
+
+	_, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(resultsDir, "runs"))
+}
+
+// ===== Resume tests =====
+
+func TestDefaultRunner_Resume_ExecutesOnlyUnfinished(t *testing.T) {
+	resultsDir := t.TempDir()
+	a := newStub("a")
+	b := newStub("b", "a")
+	c := newStub("c", "b")
+	reg := setupRegistry(t, a, b, c)
+
+	// Simulate an interrupted run: a passed, b was cut off by
+	// cancellation, c never started.
+	j := NewJournal(filepath.Join(resultsDir, "runs", "run_1"), "run_1")
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusPassed,
+		Outputs:     map[string]string{"from": "journal"},
+	}, "/prev/a", false))
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "b",
+		Status:      challenge.StatusError,
+	}, "/prev/b", true))
+
+	var seen map[challenge.ID]string
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(resultsDir),
+		WithPreHook(func(
+			_ context.Context,
+			ch challenge.Challenge,
+			cfg *challenge.Config,
+		) error {
+			if ch.ID() == "b" {
+				seen = make(map[challenge.ID]string)
+				for k, v := range cfg.Dependencies {
+					seen[k] = v
+				}
+			}
+			return nil
+		}),
+	)
+
+	results, err := r.Resume(
+		context.Background(), "run_1", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Empty(t, r.RunID())
+
+	byID := statusByID(results)
+	assert.Equal(t, "journal", byID["a"].Outputs["from"])
+	assert.Equal(t, challenge.StatusPassed, byID["b"].Status)
+	assert.Equal(t, challenge.StatusPassed, byID["c"].Status)
+
+	assert.Equal(t, 0, a.executeCalls)
+	assert.Equal(t, 1, b.executeCalls)
+	assert.Equal(t, 1, c.executeCalls)
+	assert.Equal(t, "/prev/a", seen["a"])
+
+	records, err := j.Load()
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusPassed, records["b"].Status)
+	assert.False(t, records["b"].Interrupted)
+	assert.Contains(t, records, challenge.ID("c"))
+}
+
+func TestDefaultRunner_Resume_ReusesFailureForPolicy(t *testing.T) {
+	resultsDir := t.TempDir()
+	a := newStub("a")
+	b := newStub("b", "a")
+	reg := setupRegistry(t, a, b)
+
+	j := NewJournal(filepath.Join(resultsDir, "runs", "run_1"), "run_1")
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusFailed,
+	}, "", false))
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(resultsDir),
+		WithFailurePolicy(FailurePolicy{
+			Mode: FailurePolicySkipDependents,
+		}),
+	)
+	results, err := r.Resume(
+		context.Background(), "run_1", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+
+	byID := statusByID(results)
+	assert.Equal(t, challenge.StatusFailed, byID["a"].Status)
+	assert.Equal(t, challenge.StatusSkipped, byID["b"].Status)
+	assert.Equal(t, 0, a.executeCalls)
+	assert.Equal(t, 0, b.executeCalls)
+}
+
+func TestDefaultRunner_Resume_MissingResultFileReruns(t *testing.T) {
+	resultsDir := t.TempDir()
+	a := newStub("a")
+	reg := setupRegistry(t, a)
+
+	j := NewJournal(filepath.Join(resultsDir, "runs", "run_1"), "run_1")
+	require.NoError(t, j.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusPassed,
+	}, "", false))
+	require.NoError(t, os.Remove(
+		filepath.Join(j.Dir(), "results", "a.json"),
+	))
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(resultsDir))
+	_, err := r.Resume(
+		context.Background(), "run_1", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.executeCalls)
+}
+
+func TestDefaultRunner_Resume_KeepsRunnerJournal(t *testing.T) {
+	resultsDir := t.TempDir()
+	a := newStub("a")
+	reg := setupRegistry(t, a)
+
+	resumed := NewJournal(filepath.Join(resultsDir, "runs", "run_1"), "run_1")
+	require.NoError(t, resumed.Record(&challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusError,
+	}, "", true))
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(resultsDir),
+		WithRunID("run_2"),
+	)
+	_, err := r.Resume(
+		context.Background(), "run_1", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "run_2", r.RunID())
+	assert.Equal(t, "run_2", r.journal.RunID())
+
+	// A later RunAll records in the runner's own journal, not in
+	// the resumed one.
+	_, err = r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	records, err := r.journal.Load()
+	require.NoError(t, err)
+	assert.Contains(t, records, challenge.ID("a"))
+	records, err = resumed.Load()
+	require.NoError(t, err)
+	assert.False(t, records["a"].Interrupted)
+	assert.Equal(t, 2, a.executeCalls)
+}
+
+func TestDefaultRunner_Resume_Errors(t *testing.T) {
+	r := NewRunner(
+		WithRegistry(setupRegistry(t, newStub("a"))),
+		WithResultsDir(t.TempDir()),
+	)
+	_, err := r.Resume(context.Background(), "", challenge.NewConfig(""))
+	assert.Error(t, err)
+
+	_, err = r.Resume(
+		context.Background(), "run_missing", challenge.NewConfig(""),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resume run run_missing")
+}
+
+func TestDefaultRunner_Journal_MarksInterrupted(t *testing.T) {
+	resultsDir := t.TempDir()
+	reg := setupRegistry(t, newStub("a"))
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(resultsDir),
+		WithRunID("run_1"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.recordJournal(ctx, r.journal, &challenge.Result{
+		ChallengeID: "a",
+		Status:      challenge.StatusError,
+	}, "")
+
+	records, err := r.journal.Load()
+	require.NoError(t, err)
+	assert.True(t, records["a"].Interrupted)
+}
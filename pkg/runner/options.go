@@ -55,6 +55,14 @@ func WithPostHook(h Hook) RunnerOption {
 	}
 }
 
+// WithResultHook adds a hook that receives the final result of
+// every executed or skipped challenge, e.g. to persist it.
+func WithResultHook(h ResultHook) RunnerOption {
+	return func(r *DefaultRunner) {
+		r.resultHooks = append(r.resultHooks, h)
+	}
+}
+
 // WithStaleThreshold sets the default stale threshold for
 // challenges that do not specify their own. If a challenge
 // reports no progress within this duration, it is declared
@@ -87,3 +95,39 @@ func WithEventCollector(collector *monitor.EventCollector) RunnerOption {
 		r.eventCollector = collector
 	}
 }
+
+// WithFailurePolicy sets how RunAll and RunSequence handle
+// challenges that do not pass (fail_fast, skip_dependents,
+// continue_all, max_failures=N).
+func WithFailurePolicy(policy FailurePolicy) RunnerOption {
+	return func(r *DefaultRunner) {
+		r.failurePolicy = policy
+	}
+}
+
+// WithRetryPolicy sets the runner-wide retry policy. Challenges
+// implementing challenge.Retryable override it.
+func WithRetryPolicy(policy *challenge.RetryPolicy) RunnerOption {
+	return func(r *DefaultRunner) {
+		r.retryPolicy = policy
+	}
+}
+
+// WithRunID enables the run journal for runID. Every completed
+// challenge is recorded under <results-dir>/runs/<runID> so that
+// an interrupted run can be continued with Resume.
+func WithRunID(runID string) RunnerOption {
+	return func(r *DefaultRunner) {
+		r.runID = runID
+	}
+}
+
+// WithUpdateSnapshots makes "matches_snapshot" assertions
+// rewrite their recorded snapshots instead of comparing against
+// them. Snapshots live under <results-dir>/snapshots/<challenge>
+// unless the config sets SnapshotsDir.
+func WithUpdateSnapshots(update bool) RunnerOption {
+	return func(r *DefaultRunner) {
+		r.updateSnapshots = update
+	}
+}
@@ -67,7 +67,7 @@ func runParallel(
 			cfg.ChallengeID = cID
 
 			result, execErr := r.executeChallenge(
-				ctx, c, &cfg,
+				ctx, r.journal, c, &cfg,
 			)
 			resultsCh <- parallelResult{
 				index:  idx,
@@ -52,7 +52,7 @@ func (p *Pipeline) Execute(
 	}
 
 	// Execute via runner.
-	result, err := p.runner.executeChallenge(ctx, c, config)
+	result, err := p.runner.executeChallenge(ctx, p.runner.journal, c, config)
 	if err != nil {
 		return result, err
 	}
@@ -1,6 +1,7 @@
 // Package runner provides the challenge execution engine. It
-// supports single, sequential, and parallel execution modes
-// with configurable timeouts and lifecycle hooks.
+// supports single, sequential, parallel, and dependency-graph
+// execution modes with configurable timeouts and lifecycle
+// hooks.
 package runner
 
 import (
@@ -48,6 +49,54 @@ type Runner interface {
 	) ([]*challenge.Result, error)
 }
 
+// The execution modes below are optional: they are separate
+// from Runner so that existing Runner implementations keep
+// compiling. Callers type-assert for them.
+
+// GraphRunner executes challenges as a dependency graph.
+type GraphRunner interface {
+	// RunGraph executes challenges as a dependency graph,
+	// starting each one as soon as its dependencies have
+	// passed and skipping descendants of failures.
+	RunGraph(
+		ctx context.Context,
+		ids []challenge.ID,
+		config *challenge.Config,
+		maxConcurrency int,
+	) ([]*challenge.Result, error)
+}
+
+// SelectRunner executes the challenges matching a selector.
+type SelectRunner interface {
+	// RunSelected executes the challenges matching a
+	// registry selector expression together with their
+	// transitive dependencies.
+	RunSelected(
+		ctx context.Context,
+		selector string,
+		config *challenge.Config,
+	) ([]*challenge.Result, error)
+}
+
+// Resumer continues journaled runs.
+type Resumer interface {
+	// Resume continues an interrupted RunAll from its run
+	// journal, executing only challenges without a final
+	// result.
+	Resume(
+		ctx context.Context,
+		runID string,
+		config *challenge.Config,
+	) ([]*challenge.Result, error)
+}
+
+var (
+	_ Runner       = (*DefaultRunner)(nil)
+	_ GraphRunner  = (*DefaultRunner)(nil)
+	_ SelectRunner = (*DefaultRunner)(nil)
+	_ Resumer      = (*DefaultRunner)(nil)
+)
+
 // ExecuteHook allows testing of error paths in executeChallenge.
 // It is called after executeChallenge completes and can override
 // the returned error. This is only intended for testing.
@@ -59,15 +108,22 @@ type ExecuteHook func(
 
 // DefaultRunner is the standard Runner implementation.
 type DefaultRunner struct {
-	registry       registry.Registry
-	logger         challenge.Logger
-	eventCollector *monitor.EventCollector
-	timeout        time.Duration
-	staleThreshold time.Duration
-	resultsDir     string
-	preHooks       []Hook
-	postHooks      []Hook
-	executeHook    ExecuteHook // test hook for executeChallenge errors
+	registry        registry.Registry
+	logger          challenge.Logger
+	eventCollector  *monitor.EventCollector
+	timeout         time.Duration
+	staleThreshold  time.Duration
+	resultsDir      string
+	preHooks        []Hook
+	postHooks       []Hook
+	resultHooks     []ResultHook
+	failurePolicy   FailurePolicy
+	retryPolicy     *challenge.RetryPolicy
+	runID           string
+	journal         *Journal
+	updateSnapshots bool
+	outputs         *outputStore
+	executeHook     ExecuteHook // test hook for executeChallenge errors
 }
 
 // Hook is a function invoked before or after challenge
@@ -78,18 +134,55 @@ type Hook func(
 	cfg *challenge.Config,
 ) error
 
+// ResultHook is a function invoked with the final result of a
+// challenge, after retries and after it is recorded in the run
+// journal.
+type ResultHook func(
+	ctx context.Context,
+	result *challenge.Result,
+) error
+
 // NewRunner creates a DefaultRunner with the supplied options.
 func NewRunner(opts ...RunnerOption) *DefaultRunner {
 	r := &DefaultRunner{
 		registry: registry.Default,
 		timeout:  10 * time.Minute,
+		outputs:  newOutputStore(),
 	}
 	for _, opt := range opts {
 		opt(r)
 	}
+	if r.runID != "" {
+		r.journal = NewJournal(r.runDir(r.runID), r.runID)
+	}
 	return r
 }
 
+// RunID returns the identifier of the journaled run, or empty
+// when journaling is disabled.
+func (r *DefaultRunner) RunID() string {
+	return r.runID
+}
+
+// runDir returns the directory holding the journal of runID.
+func (r *DefaultRunner) runDir(runID string) string {
+	base := r.resultsDir
+	if base == "" {
+		base = "results"
+	}
+	return filepath.Join(base, "runs", runID)
+}
+
+// snapshotDir returns the directory holding the snapshots of
+// challenge id.
+func (r *DefaultRunner) snapshotDir(id challenge.ID) string {
+	base := r.resultsDir
+	if base == "" {
+		base = "results"
+	}
+	return filepath.Join(base, "snapshots", string(id))
+}
+
 // Run executes a single challenge by ID.
 func (r *DefaultRunner) Run(
 	ctx context.Context,
@@ -102,15 +195,29 @@ func (r *DefaultRunner) Run(
 			"failed to get challenge: %w", err,
 		)
 	}
-	return r.executeChallenge(ctx, c, config)
+	return r.executeChallenge(ctx, r.journal, c, config)
 }
 
 // RunAll executes all challenges in dependency order. If a
 // challenge passes, its results directory is propagated to
-// downstream dependents.
+// downstream dependents. Challenges that did not pass are
+// handled according to the runner FailurePolicy.
 func (r *DefaultRunner) RunAll(
 	ctx context.Context,
 	config *challenge.Config,
+) ([]*challenge.Result, error) {
+	return r.runAll(ctx, config, r.journal, nil)
+}
+
+// runAll implements RunAll and Resume, recording results in
+// journal. Challenges with a reusable record in prior are not
+// executed; their journaled result is reported instead and, when
+// passed, its results directory is propagated to dependents.
+func (r *DefaultRunner) runAll(
+	ctx context.Context,
+	config *challenge.Config,
+	journal *Journal,
+	prior map[challenge.ID]JournalRecord,
 ) ([]*challenge.Result, error) {
 	ordered, err := r.registry.GetDependencyOrder()
 	if err != nil {
@@ -121,13 +228,30 @@ func (r *DefaultRunner) RunAll(
 
 	var results []*challenge.Result
 	depResults := make(map[challenge.ID]string)
+	tracker := newFailureTracker(r.failurePolicy)
 
 	for _, c := range ordered {
+		if result, dir, ok := r.reuseResult(prior, c.ID()); ok {
+			results = append(results, result)
+			tracker.record(c.ID(), result)
+			if challenge.IsPassing(result.Status) {
+				depResults[c.ID()] = dir
+			}
+			continue
+		}
+
+		if reason, skip := tracker.skipReason(c); skip {
+			result := r.skipChallenge(ctx, journal, c, reason)
+			tracker.record(c.ID(), result)
+			results = append(results, result)
+			continue
+		}
+
 		cfg := *config
 		cfg.ChallengeID = c.ID()
 		cfg.Dependencies = depResults
 
-		result, execErr := r.executeChallenge(ctx, c, &cfg)
+		result, execErr := r.executeChallenge(ctx, journal, c, &cfg)
 		if execErr != nil {
 			return results, fmt.Errorf(
 				"challenge %s failed: %w",
@@ -136,8 +260,9 @@ func (r *DefaultRunner) RunAll(
 		}
 
 		results = append(results, result)
+		tracker.record(c.ID(), result)
 
-		if result.Status == challenge.StatusPassed {
+		if challenge.IsPassing(result.Status) {
 			depResults[c.ID()] = cfg.ResultsDir
 		}
 	}
@@ -147,7 +272,9 @@ func (r *DefaultRunner) RunAll(
 
 // RunSequence executes challenges in dependency order (Kahn topological
 // sort), verifying that each challenge's dependencies have already been
-// executed and passed within this sequence.
+// executed and passed within this sequence. With a FailurePolicy set,
+// dependencies that ran in the sequence but did not pass are handled
+// by the policy instead of aborting with "unmet dependency".
 func (r *DefaultRunner) RunSequence(
 	ctx context.Context,
 	ids []challenge.ID,
@@ -162,6 +289,7 @@ func (r *DefaultRunner) RunSequence(
 
 	var results []*challenge.Result
 	depResults := make(map[challenge.ID]string)
+	tracker := newFailureTracker(r.failurePolicy)
 
 	for _, id := range sorted {
 		c, err := r.registry.Get(id)
@@ -171,20 +299,33 @@ func (r *DefaultRunner) RunSequence(
 			)
 		}
 
+		if reason, skip := tracker.skipReason(c); skip {
+			result := r.skipChallenge(ctx, r.journal, c, reason)
+			tracker.record(id, result)
+			results = append(results, result)
+			continue
+		}
+
 		for _, dep := range c.Dependencies() {
-			if _, exists := depResults[dep]; !exists {
-				return results, fmt.Errorf(
-					"challenge %s has unmet dependency: %s",
-					id, dep,
-				)
+			if _, exists := depResults[dep]; exists {
+				continue
+			}
+			if tracker.active() && tracker.ran(dep) {
+				// Only continue_all reaches here: the other
+				// policies skipped this challenge above.
+				continue
 			}
+			return results, fmt.Errorf(
+				"challenge %s has unmet dependency: %s",
+				id, dep,
+			)
 		}
 
 		cfg := *config
 		cfg.ChallengeID = id
 		cfg.Dependencies = depResults
 
-		result, execErr := r.executeChallenge(ctx, c, &cfg)
+		result, execErr := r.executeChallenge(ctx, r.journal, c, &cfg)
 		if execErr != nil {
 			return results, fmt.Errorf(
 				"challenge %s failed: %w", id, execErr,
@@ -192,8 +333,9 @@ func (r *DefaultRunner) RunSequence(
 		}
 
 		results = append(results, result)
+		tracker.record(id, result)
 
-		if result.Status == challenge.StatusPassed {
+		if challenge.IsPassing(result.Status) {
 			depResults[id] = cfg.ResultsDir
 		}
 	}
@@ -269,11 +411,108 @@ func (r *DefaultRunner) RunParallel(
 	return runParallel(ctx, r, ids, config, maxConcurrency)
 }
 
-// executeChallenge runs a single challenge through its full
+// RunGraph executes the given challenges (every registered
+// challenge when ids is empty) as a dependency DAG using at
+// most maxConcurrency goroutines. Each challenge starts once
+// all of its dependencies have passed and receives their
+// results directories via cfg.Dependencies. Descendants of a
+// challenge that did not pass are reported as StatusSkipped.
+func (r *DefaultRunner) RunGraph(
+	ctx context.Context,
+	ids []challenge.ID,
+	config *challenge.Config,
+	maxConcurrency int,
+) ([]*challenge.Result, error) {
+	return runGraph(ctx, r, ids, config, maxConcurrency)
+}
+
+// executeChallenge runs a challenge with retries and records
+// the final result in journal, if not nil.
+func (r *DefaultRunner) executeChallenge(
+	ctx context.Context,
+	journal *Journal,
+	c challenge.Challenge,
+	config *challenge.Config,
+) (*challenge.Result, error) {
+	result, err := r.executeWithRetry(ctx, c, config)
+	if err == nil && result != nil {
+		r.outputs.put(result)
+		r.recordJournal(ctx, journal, result, config.ResultsDir)
+		r.runResultHooks(ctx, result)
+	}
+	return result, err
+}
+
+// executeWithRetry runs a challenge, retrying it according to
+// its RetryPolicy (per-challenge via challenge.Retryable, else
+// the runner-wide policy). Every attempt is recorded in
+// Result.Attempts; a challenge that passes after a failed
+// attempt is flagged Flaky. The anti-bluff gate applies to each
+// attempt, so the final status is always gated.
+func (r *DefaultRunner) executeWithRetry(
+	ctx context.Context,
+	c challenge.Challenge,
+	config *challenge.Config,
+) (*challenge.Result, error) {
+	policy := r.retryPolicy
+	if rc, ok := c.(challenge.Retryable); ok {
+		if p := rc.RetryPolicy(); p != nil {
+			policy = p
+		}
+	}
+	if !policy.Enabled() {
+		return r.executeAttempt(ctx, c, config)
+	}
+
+	var attempts []challenge.Attempt
+	var firstStart time.Time
+	for n := 1; ; n++ {
+		result, err := r.executeAttempt(ctx, c, config)
+		if err != nil || result == nil {
+			return result, err
+		}
+		if firstStart.IsZero() {
+			firstStart = result.StartTime
+		}
+		attempts = append(attempts, challenge.Attempt{
+			Number:    n,
+			Status:    result.Status,
+			StartTime: result.StartTime,
+			Duration:  result.Duration,
+			Error:     result.Error,
+		})
+
+		retry := n < policy.MaxAttempts &&
+			policy.ShouldRetry(result.Status)
+		if retry {
+			delay := policy.Delay(n)
+			r.logEvent("challenge_retry", map[string]any{
+				"challenge_id":  c.ID(),
+				"attempt":       n,
+				"status":        result.Status,
+				"delay_seconds": delay.Seconds(),
+			})
+			select {
+			case <-time.After(delay):
+				continue
+			case <-ctx.Done():
+			}
+		}
+
+		result.Attempts = attempts
+		result.Flaky = challenge.IsPassing(result.Status) &&
+			len(attempts) > 1
+		result.StartTime = firstStart
+		result.Duration = result.EndTime.Sub(firstStart)
+		return result, nil
+	}
+}
+
+// executeAttempt runs a single challenge through its full
 // lifecycle: setup dir -> pre-hooks -> configure -> validate ->
 // execute with timeout -> evaluate assertions -> post-hooks ->
 // cleanup.
-func (r *DefaultRunner) executeChallenge(
+func (r *DefaultRunner) executeAttempt(
 	ctx context.Context,
 	c challenge.Challenge,
 	config *challenge.Config,
@@ -298,6 +537,13 @@ func (r *DefaultRunner) executeChallenge(
 		return result, nil
 	}
 
+	if config.SnapshotsDir == "" {
+		config.SnapshotsDir = r.snapshotDir(c.ID())
+	}
+	if r.updateSnapshots {
+		config.UpdateSnapshots = true
+	}
+
 	result.Logs = challenge.LogPaths{
 		ChallengeLog: filepath.Join(
 			config.LogsDir, "challenge.log",
@@ -331,6 +577,25 @@ func (r *DefaultRunner) executeChallenge(
 		}
 	}
 
+	// Inject upstream outputs into declared inputs. A missing
+	// required input fails validation.
+	if err := r.injectInputs(c, config); err != nil {
+		result.Status = challenge.StatusSkipped
+		result.Error = fmt.Sprintf(
+			"validation failed: %v", err,
+		)
+		result.EndTime = time.Now()
+		result.Duration = result.EndTime.Sub(result.StartTime)
+		r.logEvent("challenge_skipped", map[string]any{
+			"challenge_id": c.ID(),
+			"reason":       result.Error,
+		})
+		r.emitEvent(monitor.EventSkipped, c.ID(), c.Name(), map[string]interface{}{
+			"reason": result.Error,
+		})
+		return result, nil
+	}
+
 	// Configure.
 	if err := c.Configure(config); err != nil {
 		result.Status = challenge.StatusError
@@ -400,6 +665,10 @@ func (r *DefaultRunner) executeChallenge(
 	if timeout == 0 {
 		timeout = r.timeout
 	}
+	if td, ok := c.(challenge.TimeoutDeclarer); ok &&
+		td.DeclaredTimeout() > 0 {
+		timeout = td.DeclaredTimeout()
+	}
 
 	execCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -504,7 +773,10 @@ func (r *DefaultRunner) executeChallenge(
 		result.Assertions = execResult.Assertions
 		result.RecordedActions = execResult.RecordedActions
 		result.Metrics = execResult.Metrics
+		result.Series = execResult.Series
 		result.Outputs = execResult.Outputs
+		result.TypedOutputs = execResult.TypedOutputs
+		result.Logs.Artifacts = execResult.Logs.Artifacts
 		// Preserve execution status if it indicates failure
 		if execResult.Status == challenge.StatusFailed ||
 			execResult.Status == challenge.StatusTimedOut ||
@@ -524,13 +796,7 @@ func (r *DefaultRunner) executeChallenge(
 	}
 
 	// Determine final status from assertions.
-	result.Status = challenge.StatusPassed
-	for _, a := range result.Assertions {
-		if !a.Passed {
-			result.Status = challenge.StatusFailed
-			break
-		}
-	}
+	result.Status = challenge.AssertionStatus(result.Assertions)
 
 		// Anti-bluff validation is mandatory per Constitution §1, §6.3,
 		// §11.5.7. A Challenge result claiming Status=Passed MUST carry
@@ -538,7 +804,7 @@ func (r *DefaultRunner) executeChallenge(
 		// passing assertion). This gate is never disabled; the env-var
 		// CHALLENGE_ANTIBLUFF_STRICT has been removed as part of the
 		// v2.0.0 constitutional amendment (2026-05-01).
-	if result.Status == challenge.StatusPassed {
+	if challenge.IsPassing(result.Status) {
 		if abErr := challenge.ValidateAntiBluff(result); abErr != nil {
 			result.Status = challenge.StatusFailed
 			if result.Error == "" {
@@ -686,6 +952,22 @@ func newEventEmittingProgressReporter(
 	}
 }
 
+// runResultHooks passes a final result to the result hooks.
+// Hook errors are logged and do not affect the result.
+func (r *DefaultRunner) runResultHooks(
+	ctx context.Context,
+	result *challenge.Result,
+) {
+	for _, hook := range r.resultHooks {
+		if err := hook(ctx, result); err != nil {
+			r.logEvent("result_hook_warning", map[string]any{
+				"challenge_id": result.ChallengeID,
+				"warning":      err.Error(),
+			})
+		}
+	}
+}
+
 // logEvent emits a structured log entry if a logger is
 // configured.
 func (r *DefaultRunner) logEvent(
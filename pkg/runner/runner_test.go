@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -292,6 +293,37 @@ func TestDefaultRunner_Run_TimeoutFromConfig(t *testing.T) {
 	assert.Equal(t, challenge.StatusTimedOut, result.Status)
 }
 
+// timeoutStub declares its own execution timeout.
+type timeoutStub struct {
+	*stubChallenge
+	timeout time.Duration
+}
+
+func (s *timeoutStub) DeclaredTimeout() time.Duration {
+	return s.timeout
+}
+
+func TestDefaultRunner_Run_DeclaredTimeout(t *testing.T) {
+	s := &timeoutStub{stubChallenge: newStub("a"), timeout: 50 * time.Millisecond}
+	s.execDelay = 5 * time.Second
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithTimeout(10*time.Minute),
+		WithResultsDir(t.TempDir()),
+	)
+
+	cfg := challenge.NewConfig("a")
+	cfg.Timeout = 10 * time.Minute
+
+	result, err := r.Run(
+		context.Background(), "a", cfg,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusTimedOut, result.Status)
+}
+
 func TestDefaultRunner_Run_FailedAssertion(t *testing.T) {
 	s := newStub("a")
 	s.execResult = &challenge.Result{
@@ -743,6 +775,36 @@ func TestDefaultRunner_PostHook_ErrorIsWarning(t *testing.T) {
 	assert.Equal(t, challenge.StatusPassed, result.Status)
 }
 
+func TestDefaultRunner_ResultHook(t *testing.T) {
+	var got []*challenge.Result
+	reg := setupRegistry(t, failingStub("a"), newStub("b", "a"))
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithFailurePolicy(FailurePolicy{
+			Mode: FailurePolicySkipDependents,
+		}),
+		WithResultHook(func(
+			_ context.Context,
+			result *challenge.Result,
+		) error {
+			got = append(got, result)
+			return errors.New("store unavailable")
+		}),
+	)
+
+	results, err := r.RunAll(
+		context.Background(), challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	// Executed and skipped challenges reach the hook; hook
+	// errors do not change results.
+	require.Len(t, got, 2)
+	assert.Same(t, results[0], got[0])
+	assert.Equal(t, challenge.StatusFailed, got[0].Status)
+	assert.Equal(t, challenge.StatusSkipped, got[1].Status)
+}
+
 func TestDefaultRunner_MultiplePreHooks_Order(t *testing.T) {
 	var order []string
 	makeHook := func(label string) Hook {
@@ -842,6 +904,11 @@ func TestNewRunner_WithResultsDir(t *testing.T) {
 	assert.Equal(t, "/tmp/results", r.resultsDir)
 }
 
+func TestNewRunner_WithUpdateSnapshots(t *testing.T) {
+	r := NewRunner(WithUpdateSnapshots(true))
+	assert.True(t, r.updateSnapshots)
+}
+
 func TestNewRunner_WithMultipleOptions(t *testing.T) {
 	logger := &stubLogger{}
 	reg := registry.NewRegistry()
@@ -1504,3 +1571,276 @@ func TestDefaultRunner_Run_CleanupWarning(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, challenge.StatusPassed, result.Status)
 }
+
+// ===== DefaultRunner retry tests =====
+
+// sequenceStub returns a different result per Execute call; the
+// last entry repeats once the sequence is exhausted.
+type sequenceStub struct {
+	*stubChallenge
+	results []*challenge.Result
+	policy  *challenge.RetryPolicy
+}
+
+func (s *sequenceStub) Execute(
+	_ context.Context,
+) (*challenge.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := s.executeCalls
+	s.executeCalls++
+	if idx >= len(s.results) {
+		idx = len(s.results) - 1
+	}
+	res := *s.results[idx]
+	return &res, nil
+}
+
+func (s *sequenceStub) RetryPolicy() *challenge.RetryPolicy {
+	return s.policy
+}
+
+func newSequenceStub(id string, statuses ...string) *sequenceStub {
+	s := &sequenceStub{stubChallenge: newStub(id)}
+	for _, st := range statuses {
+		res := &challenge.Result{
+			Status:          st,
+			RecordedActions: []string{"stub-action"},
+			Assertions: []challenge.AssertionResult{
+				{Passed: st == challenge.StatusPassed, Message: st},
+			},
+		}
+		if st != challenge.StatusPassed {
+			res.Error = st + " attempt"
+		}
+		s.results = append(s.results, res)
+	}
+	return s
+}
+
+func TestDefaultRunner_Retry_FlakyPass(t *testing.T) {
+	s := newSequenceStub("a",
+		challenge.StatusError, challenge.StatusPassed,
+	)
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithRetryPolicy(&challenge.RetryPolicy{
+			MaxAttempts: 3, Backoff: time.Millisecond,
+		}),
+	)
+
+	result, err := r.Run(
+		context.Background(), "a", challenge.NewConfig("a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusPassed, result.Status)
+	assert.True(t, result.Flaky)
+	require.Len(t, result.Attempts, 2)
+	assert.Equal(t, challenge.StatusError, result.Attempts[0].Status)
+	assert.Equal(t, "error attempt", result.Attempts[0].Error)
+	assert.Equal(t, 2, result.Attempts[1].Number)
+	assert.Equal(t, 2, s.executeCalls)
+	assert.False(t, result.StartTime.After(result.Attempts[0].StartTime))
+}
+
+func TestDefaultRunner_Retry_ExhaustsAttempts(t *testing.T) {
+	s := newSequenceStub("a", challenge.StatusError)
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithRetryPolicy(&challenge.RetryPolicy{MaxAttempts: 3}),
+	)
+
+	result, err := r.Run(
+		context.Background(), "a", challenge.NewConfig("a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusError, result.Status)
+	assert.False(t, result.Flaky)
+	assert.Len(t, result.Attempts, 3)
+	assert.Equal(t, 3, s.executeCalls)
+}
+
+func TestDefaultRunner_Retry_NotRetriedStatus(t *testing.T) {
+	s := newSequenceStub("a",
+		challenge.StatusFailed, challenge.StatusPassed,
+	)
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithRetryPolicy(&challenge.RetryPolicy{MaxAttempts: 3}),
+	)
+
+	result, err := r.Run(
+		context.Background(), "a", challenge.NewConfig("a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusFailed, result.Status)
+	assert.Len(t, result.Attempts, 1)
+}
+
+func TestDefaultRunner_Retry_PerChallengeOverride(t *testing.T) {
+	s := newSequenceStub("a",
+		challenge.StatusFailed, challenge.StatusPassed,
+	)
+	s.policy = &challenge.RetryPolicy{
+		MaxAttempts: 2,
+		RetryOn:     []string{challenge.StatusFailed},
+	}
+	reg := setupRegistryWith(t, s)
+
+	// No runner-wide policy: the challenge's own policy applies.
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+	)
+
+	result, err := r.Run(
+		context.Background(), "a", challenge.NewConfig("a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusPassed, result.Status)
+	assert.True(t, result.Flaky)
+}
+
+func TestDefaultRunner_Retry_AntiBluffOnFinalAttempt(t *testing.T) {
+	s := newSequenceStub("a",
+		challenge.StatusError, challenge.StatusPassed,
+	)
+	// The passing attempt carries no recorded actions.
+	s.results[1].RecordedActions = nil
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithRetryPolicy(&challenge.RetryPolicy{MaxAttempts: 2}),
+	)
+
+	result, err := r.Run(
+		context.Background(), "a", challenge.NewConfig("a"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusFailed, result.Status)
+	assert.False(t, result.Flaky)
+	require.Len(t, result.Attempts, 2)
+	assert.Equal(t, challenge.StatusFailed, result.Attempts[1].Status)
+}
+
+func TestDefaultRunner_Retry_ContextCancelledDuringBackoff(
+	t *testing.T,
+) {
+	s := newSequenceStub("a", challenge.StatusError)
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(t.TempDir()),
+		WithRetryPolicy(&challenge.RetryPolicy{
+			MaxAttempts: 5, Backoff: time.Hour,
+		}),
+	)
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 50*time.Millisecond,
+	)
+	defer cancel()
+
+	result, err := r.Run(ctx, "a", challenge.NewConfig("a"))
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusError, result.Status)
+	assert.Len(t, result.Attempts, 1)
+}
+
+// ===== Snapshot configuration tests =====
+
+func TestDefaultRunner_SnapshotConfig(t *testing.T) {
+	dir := t.TempDir()
+	s := &inputStub{stubChallenge: newStub("golden")}
+	s.execResult.Logs.Artifacts = map[string]string{
+		"snapshot:body": "/logs/snapshots/body.diff",
+	}
+	reg := setupRegistryWith(t, s)
+
+	r := NewRunner(
+		WithRegistry(reg),
+		WithResultsDir(dir),
+		WithUpdateSnapshots(true),
+	)
+	result, err := r.Run(
+		context.Background(), "golden", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+
+	require.NotNil(t, s.config)
+	assert.Equal(t,
+		filepath.Join(dir, "snapshots", "golden"),
+		s.config.SnapshotsDir)
+	assert.True(t, s.config.UpdateSnapshots)
+	assert.Equal(t,
+		"/logs/snapshots/body.diff",
+		result.Logs.Artifacts["snapshot:body"])
+}
+
+// ===== Assertion severity tests =====
+
+func TestDefaultRunner_WarningsDoNotFailChallenge(t *testing.T) {
+	warned := newStub("warned")
+	warned.execResult.Assertions = append(
+		warned.execResult.Assertions,
+		challenge.AssertionResult{
+			Message: "slow", Severity: challenge.SeverityWarning,
+		},
+		challenge.AssertionResult{
+			Message: "note", Severity: challenge.SeverityInfo,
+		},
+	)
+	dependent := newStub("dependent", "warned")
+	reg := setupRegistry(t, warned, dependent)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	results, err := r.RunAll(context.Background(), challenge.NewConfig(""))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, challenge.StatusPassedWithWarnings, results[0].Status)
+	assert.Len(t, results[0].Warnings(), 1)
+	assert.Equal(t, challenge.StatusPassed, results[1].Status)
+}
+
+func TestDefaultRunner_InfoOnlyEvidenceFails(t *testing.T) {
+	s := newStub("info-only")
+	s.execResult.Assertions = []challenge.AssertionResult{
+		{Passed: true, Message: "logged", Severity: challenge.SeverityInfo},
+	}
+	reg := setupRegistry(t, s)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	result, err := r.Run(
+		context.Background(), "info-only", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusFailed, result.Status)
+	assert.Contains(t, result.Error, "above info severity")
+}
+
+func TestDefaultRunner_MergesSeries(t *testing.T) {
+	s := newStub("sampled")
+	s.execResult.Series = map[string]challenge.MetricSeries{
+		"latency_ms": {Name: "latency_ms", Samples: []float64{10, 12}},
+	}
+	reg := setupRegistry(t, s)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	result, err := r.Run(
+		context.Background(), "sampled", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{10, 12}, result.Series["latency_ms"].Samples)
+}
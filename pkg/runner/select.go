@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/registry"
+)
+
+// RunSelected executes the challenges matching selector (see
+// registry.ParseSelector) with RunSequence semantics.
+// Transitive dependencies of matched challenges are added to
+// the run even when they do not match themselves. A selector
+// that matches nothing, or a registry that does not implement
+// registry.Selectable, is an error.
+func (r *DefaultRunner) RunSelected(
+	ctx context.Context,
+	selector string,
+	config *challenge.Config,
+) ([]*challenge.Result, error) {
+	sel, err := registry.ParseSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("run selected: %w", err)
+	}
+
+	selectable, ok := r.registry.(registry.Selectable)
+	if !ok {
+		return nil, fmt.Errorf(
+			"run selected: registry does not support selection",
+		)
+	}
+	matched := selectable.Select(sel)
+	if len(matched) == 0 {
+		return nil, fmt.Errorf(
+			"run selected: no challenges match %q", selector,
+		)
+	}
+
+	ids, err := r.withDependencies(matched)
+	if err != nil {
+		return nil, fmt.Errorf("run selected: %w", err)
+	}
+	r.logEvent("selection_resolved", map[string]any{
+		"selector":     selector,
+		"matched":      len(matched),
+		"dependencies": len(ids) - len(matched),
+	})
+
+	return r.RunSequence(ctx, ids, config)
+}
+
+// withDependencies returns the IDs of roots followed by every
+// challenge they transitively depend on. Each ID appears once.
+func (r *DefaultRunner) withDependencies(
+	roots []challenge.Challenge,
+) ([]challenge.ID, error) {
+	seen := make(map[challenge.ID]bool, len(roots))
+	ids := make([]challenge.ID, 0, len(roots))
+	queue := make([]challenge.Challenge, 0, len(roots))
+	for _, c := range roots {
+		if !seen[c.ID()] {
+			seen[c.ID()] = true
+			ids = append(ids, c.ID())
+			queue = append(queue, c)
+		}
+	}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, dep := range c.Dependencies() {
+			if seen[dep] {
+				continue
+			}
+			dc, err := r.registry.Get(dep)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"challenge %s has unregistered dependency: %s",
+					c.ID(), dep,
+				)
+			}
+			seen[dep] = true
+			ids = append(ids, dep)
+			queue = append(queue, dc)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ===== RunSelected tests =====
+
+func TestDefaultRunner_RunSelected_PullsInDependencies(t *testing.T) {
+	base := newStub("base")
+	auth := newStub("auth", "base")
+	sec := newStub("example-sec-login", "auth")
+	other := newStub("other")
+	reg := setupRegistry(t, base, auth, sec, other)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	results, err := r.RunSelected(
+		context.Background(), "id~^example-sec-", challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, challenge.ID("base"), results[0].ChallengeID)
+	assert.Equal(t, challenge.ID("auth"), results[1].ChallengeID)
+	assert.Equal(t, challenge.ID("example-sec-login"), results[2].ChallengeID)
+	assert.Equal(t, 0, other.executeCalls)
+}
+
+func TestDefaultRunner_RunSelected_DefinitionTags(t *testing.T) {
+	smoke := newStub("smoke-a")
+	slow := newStub("slow-b")
+	reg := registry.NewRegistry()
+	require.NoError(t, reg.Register(smoke))
+	require.NoError(t, reg.Register(slow))
+	require.NoError(t, reg.RegisterDefinition(&challenge.Definition{
+		ID: "smoke-a", Category: "test", Tags: []string{"smoke"},
+	}))
+	require.NoError(t, reg.RegisterDefinition(&challenge.Definition{
+		ID: "slow-b", Category: "test", Tags: []string{"slow"},
+	}))
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	results, err := r.RunSelected(
+		context.Background(), "category=test && !slow",
+		challenge.NewConfig(""),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, challenge.ID("smoke-a"), results[0].ChallengeID)
+	assert.Equal(t, 0, slow.executeCalls)
+}
+
+func TestDefaultRunner_RunSelected_Errors(t *testing.T) {
+	reg := setupRegistry(t, newStub("a", "missing"))
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	ctx := context.Background()
+
+	_, err := r.RunSelected(ctx, "tag in (", challenge.NewConfig(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid selector")
+
+	_, err = r.RunSelected(ctx, "id=zzz", challenge.NewConfig(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no challenges match")
+
+	_, err = r.RunSelected(ctx, "id=a", challenge.NewConfig(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unregistered dependency: missing")
+}
+
+// plainRegistry hides the optional methods of a registry, like
+// a Registry implementation written before Selectable existed.
+type plainRegistry struct{ registry.Registry }
+
+func TestDefaultRunner_RunSelected_RequiresSelectable(t *testing.T) {
+	reg := plainRegistry{setupRegistry(t, newStub("a"))}
+	var _ registry.Registry = reg
+	_, isSelectable := registry.Registry(reg).(registry.Selectable)
+	require.False(t, isSelectable)
+
+	r := NewRunner(WithRegistry(reg), WithResultsDir(t.TempDir()))
+	_, err := r.RunSelected(context.Background(), "id=a", challenge.NewConfig(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry does not support selection")
+}
@@ -0,0 +1,188 @@
+package stepscript
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Backend is the challenge.ActionBackend that executes step
+// scripts. The action params are the JSON-encoded Script.
+type Backend struct {
+	// Steps resolves step types. Nil selects
+	// DefaultStepRegistry.
+	Steps *StepRegistry
+}
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// RegisterBackend registers a Backend under BackendName with
+// challenge.RegisterActionBackend. It is safe to call more than
+// once; only the first call registers.
+func RegisterBackend() error {
+	registerOnce.Do(func() {
+		if _, ok := challenge.LookupActionBackend(BackendName); ok {
+			return
+		}
+		registerErr = challenge.RegisterActionBackend(
+			BackendName, &Backend{},
+		)
+	})
+	return registerErr
+}
+
+// ValidateAction rejects scripts whose platform list excludes
+// the PLATFORM of the challenge environment, so the runner
+// reports them as skipped.
+func (b *Backend) ValidateAction(req *challenge.ActionRequest) error {
+	s, err := Parse(req.Params)
+	if err != nil {
+		return err
+	}
+	platform := req.Environment["PLATFORM"]
+	if !platformAllowed(s.Platforms, platform) {
+		return fmt.Errorf(
+			"step script %s does not support platform %s (supports %s)",
+			s.Name, platform, strings.Join(s.Platforms, ", "),
+		)
+	}
+	return nil
+}
+
+// Run executes the script steps in order. Each step produces
+// one assertion result whose Target is the step name; failed
+// steps carry their on_failure message. Steps excluded by
+// platform are recorded as skipped actions and produce no
+// assertion.
+func (b *Backend) Run(
+	ctx context.Context,
+	req *challenge.ActionRequest,
+) (*challenge.ActionResult, error) {
+	s, err := Parse(req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout, _ := parseTimeout(s.Timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	steps := b.Steps
+	if steps == nil {
+		steps = DefaultStepRegistry
+	}
+
+	sc := &StepContext{
+		WorkDir:     req.Environment["PROJECT_ROOT"],
+		Platform:    req.Environment["PLATFORM"],
+		Environment: req.Environment,
+	}
+
+	ar := &challenge.ActionResult{
+		Outputs: make(map[string]any),
+	}
+	var passed, failed, skipped int
+
+	for _, st := range s.Steps {
+		if !platformAllowed(st.Platforms, sc.Platform) {
+			skipped++
+			ar.Actions = append(ar.Actions, fmt.Sprintf(
+				"step %s: skipped on platform %s",
+				st.Name, sc.Platform,
+			))
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return ar, fmt.Errorf(
+				"step script %s: interrupted before step %s: %w",
+				s.Name, st.Name, err,
+			)
+		}
+
+		res, action := b.runStep(ctx, steps, sc, st)
+		if res.Passed {
+			passed++
+		} else {
+			failed++
+		}
+		ar.Assertions = append(ar.Assertions, res)
+		ar.Actions = append(ar.Actions, action)
+	}
+
+	ar.Outputs["steps_total"] = len(s.Steps)
+	ar.Outputs["steps_passed"] = passed
+	ar.Outputs["steps_failed"] = failed
+	ar.Outputs["steps_skipped"] = skipped
+	return ar, nil
+}
+
+// runStep executes a single step under its own timeout and
+// converts the outcome into an assertion result.
+func (b *Backend) runStep(
+	ctx context.Context,
+	steps *StepRegistry,
+	sc *StepContext,
+	st Step,
+) (challenge.AssertionResult, string) {
+	res := challenge.AssertionResult{
+		Type:   st.Type,
+		Target: st.Name,
+	}
+
+	fn, ok := steps.Lookup(st.Type)
+	if !ok {
+		res.Message = failureMessage(
+			st, fmt.Sprintf("unknown step type %s", st.Type),
+		)
+		return res, fmt.Sprintf("step %s: unknown type %s", st.Name, st.Type)
+	}
+
+	if timeout, _ := parseTimeout(st.Timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	out, err := fn(ctx, sc, st)
+	if err != nil {
+		res.Message = failureMessage(st, err.Error())
+		return res, fmt.Sprintf("step %s: error: %v", st.Name, err)
+	}
+
+	res.Passed = out.Passed
+	res.Expected = out.Expected
+	res.Actual = out.Actual
+	if out.Passed {
+		res.Message = out.Message
+	} else {
+		res.Message = failureMessage(st, out.Message)
+	}
+
+	action := fmt.Sprintf("step %s: %s", st.Name, out.Action)
+	if out.Action == "" {
+		action = fmt.Sprintf("step %s: ran %s", st.Name, st.Type)
+	}
+	return res, action
+}
+
+// failureMessage prefixes detail with the step on_failure
+// message when one is declared.
+func failureMessage(st Step, detail string) string {
+	msg := strings.TrimSpace(st.OnFailure)
+	switch {
+	case msg == "":
+		return detail
+	case detail == "":
+		return msg
+	default:
+		return msg + ": " + detail
+	}
+}
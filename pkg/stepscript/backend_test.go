@@ -0,0 +1,158 @@
+package stepscript
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+func scriptRequest(
+	t *testing.T, s *Script, env map[string]string,
+) *challenge.ActionRequest {
+	t.Helper()
+	params, err := json.Marshal(s)
+	require.NoError(t, err)
+	return &challenge.ActionRequest{Params: params, Environment: env}
+}
+
+func TestBackend_Run(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "app.txt"), []byte("version 1.0.0"), 0o644,
+	))
+
+	s := &Script{
+		Name: "run",
+		Steps: []Step{
+			{
+				Name: "has-version", Type: "assert_file_contains",
+				Params: map[string]any{
+					"file": "app.txt", "expected_content": "1.0.0",
+				},
+			},
+			{
+				Name: "no-old-version", Type: "assert_file_not_contains",
+				Params: map[string]any{
+					"file": "app.txt", "forbidden_content": "version",
+				},
+				OnFailure: "old version string present\n",
+			},
+			{
+				Name: "web-only", Type: "shell",
+				Params:    map[string]any{"command": "exit 1"},
+				Platforms: []string{"web"},
+			},
+			{Name: "unknown", Type: "teleport"},
+		},
+	}
+
+	ar, err := (&Backend{}).Run(context.Background(), scriptRequest(
+		t, s, map[string]string{
+			"PROJECT_ROOT": root, "PLATFORM": "android",
+		},
+	))
+	require.NoError(t, err)
+	require.Len(t, ar.Assertions, 3)
+
+	assert.True(t, ar.Assertions[0].Passed)
+	assert.Equal(t, "has-version", ar.Assertions[0].Target)
+	assert.Equal(t, "assert_file_contains", ar.Assertions[0].Type)
+
+	assert.False(t, ar.Assertions[1].Passed)
+	assert.Contains(t, ar.Assertions[1].Message,
+		"old version string present: ")
+
+	assert.False(t, ar.Assertions[2].Passed)
+	assert.Contains(t, ar.Assertions[2].Message, "unknown step type")
+
+	assert.Equal(t, 4, ar.Outputs["steps_total"])
+	assert.Equal(t, 1, ar.Outputs["steps_passed"])
+	assert.Equal(t, 2, ar.Outputs["steps_failed"])
+	assert.Equal(t, 1, ar.Outputs["steps_skipped"])
+	assert.Len(t, ar.Actions, 4)
+	assert.Contains(t, ar.Actions[2], "skipped on platform android")
+}
+
+func TestBackend_Run_InvalidParams(t *testing.T) {
+	_, err := (&Backend{}).Run(context.Background(),
+		&challenge.ActionRequest{Params: json.RawMessage(`{}`)},
+	)
+	assert.Error(t, err)
+}
+
+func TestBackend_Run_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := &Script{
+		Name:  "c",
+		Steps: []Step{{Name: "a", Type: "shell", Params: map[string]any{"command": "true"}}},
+	}
+	_, err := (&Backend{}).Run(ctx, scriptRequest(t, s, nil))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "interrupted")
+}
+
+func TestBackend_ValidateAction(t *testing.T) {
+	s := &Script{
+		Name:      "v",
+		Platforms: []string{"android", "desktop"},
+		Steps:     []Step{{Name: "a", Type: "shell"}},
+	}
+	b := &Backend{}
+
+	assert.NoError(t, b.ValidateAction(scriptRequest(
+		t, s, map[string]string{"PLATFORM": "android"},
+	)))
+	assert.NoError(t, b.ValidateAction(scriptRequest(t, s, nil)))
+
+	err := b.ValidateAction(scriptRequest(
+		t, s, map[string]string{"PLATFORM": "web"},
+	))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support platform web")
+}
+
+// TestBackend_DefinitionChallenge runs a script end-to-end through
+// DefinitionChallenge, the path the runner takes.
+func TestBackend_DefinitionChallenge(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, "f.txt"), []byte("hello"), 0o644,
+	))
+	s := &Script{
+		Name: "e2e",
+		Steps: []Step{{
+			Name: "greets", Type: "assert_file_contains",
+			Params: map[string]any{
+				"file": "f.txt", "expected_content": "hello",
+			},
+		}},
+	}
+	def, err := s.ToDefinition()
+	require.NoError(t, err)
+
+	dc, err := challenge.NewDefinitionChallenge(def)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, dc.Configure(&challenge.Config{
+		ChallengeID: def.ID,
+		ResultsDir:  filepath.Join(dir, "results"),
+		LogsDir:     filepath.Join(dir, "logs"),
+		Environment: map[string]string{"PROJECT_ROOT": root},
+	}))
+	require.NoError(t, dc.Validate(context.Background()))
+
+	result, err := dc.Execute(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, challenge.StatusPassed, result.Status)
+	require.Len(t, result.Assertions, 1)
+	assert.Equal(t, "greets", result.Assertions[0].Target)
+	assert.NoError(t, challenge.ValidateAntiBluff(result))
+}
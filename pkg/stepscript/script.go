@@ -0,0 +1,236 @@
+// Package stepscript implements step-based challenge scripts:
+// bank files whose root carries a "steps:" list of typed steps
+// (assert_file_contains, shell, http_probe, ...) instead of a
+// "challenges" list. A script is converted into a single
+// challenge.Definition executed by the "steps" action backend.
+package stepscript
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// BackendName is the action backend name under which step
+// scripts execute.
+const BackendName = "steps"
+
+// DefaultCategory is assigned to definitions built from scripts
+// that do not declare a category.
+const DefaultCategory = "script"
+
+// Script is a step-based challenge script.
+type Script struct {
+	// ID is the challenge ID. Defaults to Name when empty.
+	ID string `json:"id,omitempty"`
+
+	// Name identifies the script.
+	Name string `json:"name"`
+
+	// Version is the script version.
+	Version string `json:"version,omitempty"`
+
+	// Description explains what the script validates.
+	Description string `json:"description,omitempty"`
+
+	// Category groups the resulting challenge.
+	Category string `json:"category,omitempty"`
+
+	// Dependencies lists challenges that must pass first.
+	Dependencies []challenge.ID `json:"dependencies,omitempty"`
+
+	// Platforms restricts the script to the listed platforms.
+	// Empty means every platform.
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Tags and Labels are copied to the definition for
+	// selector-based runs.
+	Tags   []string          `json:"tags,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Timeout bounds the whole script (e.g., "10m").
+	Timeout string `json:"timeout,omitempty"`
+
+	// Steps are executed in order.
+	Steps []Step `json:"steps"`
+
+	// SuccessCriteria and Report are carried through for
+	// tooling; they do not affect execution.
+	SuccessCriteria map[string]any `json:"success_criteria,omitempty"`
+	Report          map[string]any `json:"report,omitempty"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+}
+
+// Step is a single typed step of a Script.
+type Step struct {
+	// Name identifies the step; it becomes the assertion
+	// target.
+	Name string `json:"name"`
+
+	// Type selects the step implementation from the
+	// StepRegistry.
+	Type string `json:"type"`
+
+	// Params holds type-specific parameters.
+	Params map[string]any `json:"params,omitempty"`
+
+	// OnFailure is the message reported when the step fails.
+	OnFailure string `json:"on_failure,omitempty"`
+
+	// Platforms restricts the step to the listed platforms.
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Timeout bounds the step (e.g., "30s").
+	Timeout string `json:"timeout,omitempty"`
+
+	// ExpectedOutputContains lists substrings the output of a
+	// command step must contain.
+	ExpectedOutputContains []string `json:"expected_output_contains,omitempty"`
+}
+
+// Parse decodes a script from JSON. YAML callers normalise to
+// JSON first, exactly like bank.LoadFile does.
+func Parse(data []byte) (*Script, error) {
+	var s Script
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse step script: %w", err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// IsScript reports whether a decoded bank document is a step
+// script, i.e. has a non-empty root "steps" list.
+func IsScript(data []byte) bool {
+	var probe struct {
+		Steps []json.RawMessage `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return len(probe.Steps) > 0
+}
+
+// Validate checks the structural integrity of the script.
+func (s *Script) Validate() error {
+	if s.Name == "" && s.ID == "" {
+		return fmt.Errorf("step script has no name")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("step script %s has no steps", s.Name)
+	}
+	if _, err := parseTimeout(s.Timeout); err != nil {
+		return fmt.Errorf("step script %s: %w", s.Name, err)
+	}
+	seen := make(map[string]bool, len(s.Steps))
+	for i, st := range s.Steps {
+		if st.Name == "" {
+			return fmt.Errorf(
+				"step script %s: step %d has no name", s.Name, i,
+			)
+		}
+		if st.Type == "" {
+			return fmt.Errorf(
+				"step script %s: step %s has no type",
+				s.Name, st.Name,
+			)
+		}
+		if seen[st.Name] {
+			return fmt.Errorf(
+				"step script %s: duplicate step name %s",
+				s.Name, st.Name,
+			)
+		}
+		seen[st.Name] = true
+		if _, err := parseTimeout(st.Timeout); err != nil {
+			return fmt.Errorf(
+				"step script %s: step %s: %w",
+				s.Name, st.Name, err,
+			)
+		}
+	}
+	return nil
+}
+
+// ChallengeID returns the challenge ID of the script.
+func (s *Script) ChallengeID() challenge.ID {
+	if s.ID != "" {
+		return challenge.ID(s.ID)
+	}
+	return challenge.ID(s.Name)
+}
+
+// ToDefinition converts the script into a challenge.Definition
+// executed by the "steps" action backend. It registers that
+// backend on first use.
+func (s *Script) ToDefinition() (*challenge.Definition, error) {
+	if err := RegisterBackend(); err != nil {
+		return nil, err
+	}
+
+	params, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("encode step script %s: %w", s.Name, err)
+	}
+	cfg, err := json.Marshal(challenge.DefinitionConfig{
+		Backend: BackendName,
+		Params:  params,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode step script %s: %w", s.Name, err)
+	}
+
+	category := s.Category
+	if category == "" {
+		category = DefaultCategory
+	}
+	deps := s.Dependencies
+	if deps == nil {
+		deps = []challenge.ID{}
+	}
+
+	return &challenge.Definition{
+		ID:                s.ChallengeID(),
+		Name:              s.Name,
+		Description:       strings.TrimSpace(s.Description),
+		Category:          category,
+		Dependencies:      deps,
+		EstimatedDuration: s.Timeout,
+		Configuration:     cfg,
+		Tags:              s.Tags,
+		Labels:            s.Labels,
+	}, nil
+}
+
+// parseTimeout parses an optional duration string.
+func parseTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// platformAllowed reports whether current is in platforms. An
+// empty list or an empty/"all" current platform allows
+// everything.
+func platformAllowed(platforms []string, current string) bool {
+	if len(platforms) == 0 || current == "" ||
+		strings.EqualFold(current, "all") {
+		return true
+	}
+	for _, p := range platforms {
+		if strings.EqualFold(p, current) {
+			return true
+		}
+	}
+	return false
+}
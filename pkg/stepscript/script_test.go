@@ -0,0 +1,95 @@
+package stepscript
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+const sampleScript = `{
+	"name": "sample-script",
+	"description": "  Checks things.\n",
+	"platforms": ["android"],
+	"timeout": "1m",
+	"steps": [
+		{"name": "a", "type": "assert_file_contains",
+		 "params": {"file": "x.txt", "expected_content": "hi"}},
+		{"name": "b", "type": "shell", "params": {"command": "true"},
+		 "timeout": "5s"}
+	]
+}`
+
+func TestParse_Valid(t *testing.T) {
+	s, err := Parse([]byte(sampleScript))
+	require.NoError(t, err)
+	assert.Equal(t, "sample-script", s.Name)
+	require.Len(t, s.Steps, 2)
+	assert.Equal(t, "5s", s.Steps[1].Timeout)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"malformed", `{`, "parse step script"},
+		{"no name", `{"steps":[{"name":"a","type":"shell"}]}`, "no name"},
+		{"no steps", `{"name":"s"}`, "no steps"},
+		{"bad timeout", `{"name":"s","timeout":"soon","steps":[{"name":"a","type":"shell"}]}`, "invalid timeout"},
+		{"step no name", `{"name":"s","steps":[{"type":"shell"}]}`, "step 0 has no name"},
+		{"step no type", `{"name":"s","steps":[{"name":"a"}]}`, "has no type"},
+		{"duplicate", `{"name":"s","steps":[{"name":"a","type":"shell"},{"name":"a","type":"shell"}]}`, "duplicate step name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestIsScript(t *testing.T) {
+	assert.True(t, IsScript([]byte(sampleScript)))
+	assert.False(t, IsScript([]byte(`{"challenges":[]}`)))
+	assert.False(t, IsScript([]byte(`{"steps":[]}`)))
+	assert.False(t, IsScript([]byte(`not json`)))
+}
+
+func TestScript_ToDefinition(t *testing.T) {
+	s, err := Parse([]byte(sampleScript))
+	require.NoError(t, err)
+
+	def, err := s.ToDefinition()
+	require.NoError(t, err)
+	assert.Equal(t, challenge.ID("sample-script"), def.ID)
+	assert.Equal(t, "Checks things.", def.Description)
+	assert.Equal(t, DefaultCategory, def.Category)
+	assert.Equal(t, "1m", def.EstimatedDuration)
+	assert.NotNil(t, def.Dependencies)
+
+	var cfg challenge.DefinitionConfig
+	require.NoError(t, json.Unmarshal(def.Configuration, &cfg))
+	assert.Equal(t, BackendName, cfg.Backend)
+
+	_, ok := challenge.LookupActionBackend(BackendName)
+	assert.True(t, ok)
+}
+
+func TestScript_ChallengeID(t *testing.T) {
+	assert.Equal(t, challenge.ID("n"), (&Script{Name: "n"}).ChallengeID())
+	assert.Equal(t, challenge.ID("i"), (&Script{ID: "i", Name: "n"}).ChallengeID())
+}
+
+func TestPlatformAllowed(t *testing.T) {
+	assert.True(t, platformAllowed(nil, "android"))
+	assert.True(t, platformAllowed([]string{"android"}, ""))
+	assert.True(t, platformAllowed([]string{"android"}, "all"))
+	assert.True(t, platformAllowed([]string{"Android"}, "android"))
+	assert.False(t, platformAllowed([]string{"web"}, "android"))
+}
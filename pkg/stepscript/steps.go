@@ -0,0 +1,576 @@
+package stepscript
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"digital.vasic.challenges/pkg/assertion"
+)
+
+// StepContext carries the runtime environment shared by all
+// steps of a script execution.
+type StepContext struct {
+	// WorkDir is the directory relative paths are resolved
+	// against (PROJECT_ROOT when set).
+	WorkDir string
+
+	// Platform is the platform being tested, or empty.
+	Platform string
+
+	// Environment holds the challenge runtime environment.
+	Environment map[string]string
+}
+
+// Path resolves p against WorkDir unless it is absolute.
+func (sc *StepContext) Path(p string) string {
+	if filepath.IsAbs(p) || sc.WorkDir == "" {
+		return p
+	}
+	return filepath.Join(sc.WorkDir, p)
+}
+
+// StepOutcome is the result of running a step.
+type StepOutcome struct {
+	// Passed indicates whether the step succeeded.
+	Passed bool
+
+	// Message describes the outcome.
+	Message string
+
+	// Expected and Actual are reported on the assertion.
+	Expected any
+	Actual   any
+
+	// Action describes what the step did; recorded on the
+	// challenge result.
+	Action string
+}
+
+// StepFunc implements a step type. An error means the step
+// could not run at all and is reported as a failure.
+type StepFunc func(
+	ctx context.Context,
+	sc *StepContext,
+	step Step,
+) (StepOutcome, error)
+
+// StepRegistry maps step type names to implementations. It is
+// safe for concurrent use.
+type StepRegistry struct {
+	mu    sync.RWMutex
+	steps map[string]StepFunc
+}
+
+// NewStepRegistry creates a StepRegistry with all built-in step
+// types registered.
+func NewStepRegistry() *StepRegistry {
+	r := &StepRegistry{steps: make(map[string]StepFunc)}
+	r.registerDefaults()
+	return r
+}
+
+// DefaultStepRegistry is the registry used by the "steps"
+// action backend.
+var DefaultStepRegistry = NewStepRegistry()
+
+// registerDefaults registers the built-in step types.
+func (r *StepRegistry) registerDefaults() {
+	r.steps["assert_file_contains"] = stepFileContains
+	r.steps["assert_file_not_contains"] = stepFileNotContains
+	r.steps["assert_file_matches"] = stepFileMatches
+	r.steps["shell"] = stepCommand
+	r.steps["command"] = stepCommand
+	r.steps["gradle_test"] = stepGradleTest
+	r.steps["gradle_build"] = stepGradleBuild
+	r.steps["http_probe"] = stepHTTPProbe
+	r.steps["json_path"] = stepJSONPath
+}
+
+// Register adds a step type. Returns an error if the type is
+// already registered.
+func (r *StepRegistry) Register(stepType string, fn StepFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.steps[stepType]; exists {
+		return fmt.Errorf(
+			"step type already registered: %s", stepType,
+		)
+	}
+	r.steps[stepType] = fn
+	return nil
+}
+
+// Lookup returns the implementation of a step type.
+func (r *StepRegistry) Lookup(stepType string) (StepFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.steps[stepType]
+	return fn, ok
+}
+
+// Types returns the sorted names of all registered step types.
+func (r *StepRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.steps))
+	for name := range r.steps {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// --- built-in steps ---
+
+// stepFileContains checks that params.file contains every
+// params.expected_content entry (string or list).
+func stepFileContains(
+	_ context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	path, content, err := readStepFile(sc, step)
+	if err != nil {
+		return StepOutcome{}, err
+	}
+	expected := stringList(step.Params["expected_content"])
+	if len(expected) == 0 {
+		return StepOutcome{}, fmt.Errorf("params.expected_content is required")
+	}
+
+	out := StepOutcome{
+		Expected: expected,
+		Action:   fmt.Sprintf("read %s and searched %d snippet(s)", path, len(expected)),
+	}
+	for _, want := range expected {
+		if !containsNormalised(content, want) {
+			out.Actual = fmt.Sprintf("missing %q", firstLine(want))
+			out.Message = fmt.Sprintf(
+				"%s does not contain %q", path, firstLine(want),
+			)
+			return out, nil
+		}
+	}
+	out.Passed = true
+	out.Actual = "all snippets present"
+	out.Message = fmt.Sprintf("%s contains all %d snippet(s)", path, len(expected))
+	return out, nil
+}
+
+// stepFileNotContains checks that params.file contains none of
+// the params.forbidden_content entries.
+func stepFileNotContains(
+	_ context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	path, content, err := readStepFile(sc, step)
+	if err != nil {
+		return StepOutcome{}, err
+	}
+	forbidden := stringList(step.Params["forbidden_content"])
+	if len(forbidden) == 0 {
+		return StepOutcome{}, fmt.Errorf("params.forbidden_content is required")
+	}
+
+	out := StepOutcome{
+		Expected: forbidden,
+		Action:   fmt.Sprintf("read %s and checked %d forbidden snippet(s)", path, len(forbidden)),
+	}
+	for _, bad := range forbidden {
+		if containsNormalised(content, bad) {
+			out.Actual = fmt.Sprintf("found %q", firstLine(bad))
+			out.Message = fmt.Sprintf(
+				"%s contains forbidden %q", path, firstLine(bad),
+			)
+			return out, nil
+		}
+	}
+	out.Passed = true
+	out.Actual = "no forbidden snippets"
+	out.Message = fmt.Sprintf("%s contains none of %d forbidden snippet(s)", path, len(forbidden))
+	return out, nil
+}
+
+// stepFileMatches checks that params.pattern matches
+// params.file at least params.min_matches times (default 1).
+func stepFileMatches(
+	_ context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	path, content, err := readStepFile(sc, step)
+	if err != nil {
+		return StepOutcome{}, err
+	}
+	pattern, _ := step.Params["pattern"].(string)
+	if pattern == "" {
+		return StepOutcome{}, fmt.Errorf("params.pattern is required")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return StepOutcome{}, fmt.Errorf("invalid pattern: %w", err)
+	}
+	minMatches := 1
+	if n, ok := intParam(step.Params["min_matches"]); ok {
+		minMatches = n
+	}
+
+	matches := len(re.FindAllStringIndex(content, -1))
+	out := StepOutcome{
+		Expected: fmt.Sprintf(">= %d match(es) of /%s/", minMatches, pattern),
+		Actual:   matches,
+		Action:   fmt.Sprintf("matched /%s/ against %s", pattern, path),
+		Passed:   matches >= minMatches,
+	}
+	out.Message = fmt.Sprintf(
+		"%d match(es) of /%s/ in %s (need %d)",
+		matches, pattern, path, minMatches,
+	)
+	return out, nil
+}
+
+// stepCommand runs params.command with bash and checks the exit
+// code against params.expected_exit_code (default 0) and the
+// combined output against expected_output_contains.
+func stepCommand(
+	ctx context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	command, _ := step.Params["command"].(string)
+	if strings.TrimSpace(command) == "" {
+		return StepOutcome{}, fmt.Errorf("params.command is required")
+	}
+	return runCommandStep(ctx, sc, step, command)
+}
+
+// stepGradleTest runs "./gradlew :<module>:test" optionally
+// filtered by params.test_class.
+func stepGradleTest(
+	ctx context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	task := gradleTask(step.Params, "test")
+	command := "./gradlew " + task
+	if class, _ := step.Params["test_class"].(string); class != "" {
+		command += " --tests " + strconv.Quote(class)
+	}
+	return runCommandStep(ctx, sc, step, command)
+}
+
+// stepGradleBuild runs "./gradlew :<module>:<task>" (task
+// defaults to "build").
+func stepGradleBuild(
+	ctx context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	name, _ := step.Params["task"].(string)
+	if name == "" {
+		name = "build"
+	}
+	return runCommandStep(
+		ctx, sc, step, "./gradlew "+gradleTask(step.Params, name),
+	)
+}
+
+// stepHTTPProbe issues an HTTP request to params.url and checks
+// params.expected_status (default 200) and optionally
+// params.body_contains.
+func stepHTTPProbe(
+	ctx context.Context, _ *StepContext, step Step,
+) (StepOutcome, error) {
+	url, _ := step.Params["url"].(string)
+	if url == "" {
+		return StepOutcome{}, fmt.Errorf("params.url is required")
+	}
+	method, _ := step.Params["method"].(string)
+	if method == "" {
+		method = http.MethodGet
+	}
+	expected := http.StatusOK
+	if n, ok := intParam(step.Params["expected_status"]); ok {
+		expected = n
+	}
+
+	var body io.Reader
+	if b, ok := step.Params["body"].(string); ok {
+		body = strings.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return StepOutcome{}, fmt.Errorf("build request: %w", err)
+	}
+	if headers, ok := step.Params["headers"].(map[string]any); ok {
+		for k, v := range headers {
+			req.Header.Set(k, fmt.Sprint(v))
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return StepOutcome{}, fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	out := StepOutcome{
+		Expected: expected,
+		Actual:   resp.StatusCode,
+		Action:   fmt.Sprintf("%s %s -> %d", method, url, resp.StatusCode),
+	}
+	if resp.StatusCode != expected {
+		out.Message = fmt.Sprintf(
+			"%s %s returned %d, expected %d",
+			method, url, resp.StatusCode, expected,
+		)
+		return out, nil
+	}
+	for _, want := range stringList(step.Params["body_contains"]) {
+		if !strings.Contains(string(data), want) {
+			out.Message = fmt.Sprintf(
+				"%s %s body does not contain %q", method, url, want,
+			)
+			return out, nil
+		}
+	}
+	out.Passed = true
+	out.Message = fmt.Sprintf("%s %s returned %d", method, url, resp.StatusCode)
+	return out, nil
+}
+
+// stepJSONPath reads a JSON document from params.file (or
+// params.json inline), resolves params.path with the assertion
+// path syntax (see assertion.ParsePath) and compares it with
+// params.expected. Without
+// params.expected the step only requires the path to exist.
+func stepJSONPath(
+	_ context.Context, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	path, _ := step.Params["path"].(string)
+	if path == "" {
+		return StepOutcome{}, fmt.Errorf("params.path is required")
+	}
+
+	var raw []byte
+	source := "inline json"
+	if inline, ok := step.Params["json"].(string); ok {
+		raw = []byte(inline)
+	} else {
+		file, content, err := readStepFile(sc, step)
+		if err != nil {
+			return StepOutcome{}, err
+		}
+		raw = []byte(content)
+		source = file
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return StepOutcome{}, fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	out := StepOutcome{Action: fmt.Sprintf("resolved %s in %s", path, source)}
+	actual, err := assertion.LookupPath(doc, path)
+	if err != nil {
+		out.Message = err.Error()
+		return out, nil
+	}
+	out.Actual = actual
+
+	expected, hasExpected := step.Params["expected"]
+	if !hasExpected {
+		out.Passed = true
+		out.Message = fmt.Sprintf("%s exists in %s", path, source)
+		return out, nil
+	}
+	out.Expected = expected
+	if fmt.Sprint(actual) == fmt.Sprint(expected) {
+		out.Passed = true
+		out.Message = fmt.Sprintf("%s == %v", path, expected)
+	} else {
+		out.Message = fmt.Sprintf("%s is %v, expected %v", path, actual, expected)
+	}
+	return out, nil
+}
+
+// --- helpers ---
+
+// commandRunner runs a prepared command. It allows for
+// dependency injection in tests.
+type commandRunner func(cmd *exec.Cmd) error
+
+// runCommand is the function used to run command steps. Can be
+// overridden in tests.
+var runCommand commandRunner = func(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+// runCommandStep executes a bash command in the step context.
+// The step environment is added to the process environment in
+// key order, so the command line is reproducible.
+func runCommandStep(
+	ctx context.Context, sc *StepContext, step Step, command string,
+) (StepOutcome, error) {
+	cmd := exec.CommandContext(ctx, "bash", "-c", command)
+	cmd.WaitDelay = 2 * time.Second
+	cmd.Dir = sc.WorkDir
+	if dir, _ := step.Params["work_dir"].(string); dir != "" {
+		cmd.Dir = sc.Path(dir)
+	}
+	cmd.Env = os.Environ()
+	keys := make([]string, 0, len(sc.Environment))
+	for k := range sc.Environment {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		cmd.Env = append(cmd.Env, k+"="+sc.Environment[k])
+	}
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := runCommand(cmd)
+	exitCode := 0
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || ctx.Err() != nil {
+			return StepOutcome{}, fmt.Errorf("run %q: %w", command, err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+
+	expected := 0
+	if n, ok := intParam(step.Params["expected_exit_code"]); ok {
+		expected = n
+	}
+
+	out := StepOutcome{
+		Expected: expected,
+		Actual:   exitCode,
+		Action:   fmt.Sprintf("bash -c %q exit_code=%d", command, exitCode),
+	}
+	if exitCode != expected {
+		out.Message = fmt.Sprintf(
+			"exit code %d, expected %d: %s",
+			exitCode, expected, lastLine(output.String()),
+		)
+		return out, nil
+	}
+
+	wants := append(
+		stringList(step.Params["expected_output_contains"]),
+		step.ExpectedOutputContains...,
+	)
+	for _, want := range wants {
+		if !strings.Contains(output.String(), want) {
+			out.Message = fmt.Sprintf("output does not contain %q", want)
+			return out, nil
+		}
+	}
+
+	out.Passed = true
+	out.Message = fmt.Sprintf("exit code %d", exitCode)
+	return out, nil
+}
+
+// readStepFile reads params.file relative to the work dir.
+func readStepFile(sc *StepContext, step Step) (string, string, error) {
+	file, _ := step.Params["file"].(string)
+	if file == "" {
+		return "", "", fmt.Errorf("params.file is required")
+	}
+	path := sc.Path(file)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return path, "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return path, string(data), nil
+}
+
+// gradleTask builds the qualified Gradle task name.
+func gradleTask(params map[string]any, task string) string {
+	if module, _ := params["module"].(string); module != "" {
+		return ":" + module + ":" + task
+	}
+	return task
+}
+
+// containsNormalised reports whether haystack contains needle,
+// first verbatim and then with every line trimmed so YAML block
+// indentation does not have to match the file exactly.
+func containsNormalised(haystack, needle string) bool {
+	needle = strings.TrimRight(needle, "\n")
+	if strings.Contains(haystack, needle) {
+		return true
+	}
+	return strings.Contains(trimLines(haystack), trimLines(needle))
+}
+
+// trimLines trims surrounding whitespace from every line.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stringList accepts a string or a list and returns the
+// non-empty string entries.
+func stringList(v any) []string {
+	switch x := v.(type) {
+	case string:
+		if x == "" {
+			return nil
+		}
+		return []string{x}
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, item := range x {
+			if s := fmt.Sprint(item); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return x
+	}
+	return nil
+}
+
+// intParam converts a numeric or numeric-string parameter.
+func intParam(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case string:
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// firstLine returns the first non-empty line of s.
+func firstLine(s string) string {
+	for _, l := range strings.Split(s, "\n") {
+		if t := strings.TrimSpace(l); t != "" {
+			return t
+		}
+	}
+	return s
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
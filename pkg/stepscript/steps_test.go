@@ -0,0 +1,328 @@
+package stepscript
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStepContext(t *testing.T, files map[string]string) *StepContext {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(
+			filepath.Join(dir, name), []byte(content), 0o644,
+		))
+	}
+	return &StepContext{WorkDir: dir, Environment: map[string]string{}}
+}
+
+func runStep(
+	t *testing.T, sc *StepContext, step Step,
+) (StepOutcome, error) {
+	t.Helper()
+	fn, ok := DefaultStepRegistry.Lookup(step.Type)
+	require.True(t, ok, step.Type)
+	return fn(context.Background(), sc, step)
+}
+
+// ===== StepRegistry tests =====
+
+func TestStepRegistry_Builtins(t *testing.T) {
+	r := NewStepRegistry()
+	for _, name := range []string{
+		"assert_file_contains", "assert_file_not_contains",
+		"assert_file_matches", "shell", "command",
+		"gradle_test", "gradle_build", "http_probe", "json_path",
+	} {
+		_, ok := r.Lookup(name)
+		assert.True(t, ok, name)
+	}
+	assert.Contains(t, r.Types(), "json_path")
+}
+
+func TestStepRegistry_Register(t *testing.T) {
+	r := NewStepRegistry()
+	noop := func(context.Context, *StepContext, Step) (StepOutcome, error) {
+		return StepOutcome{Passed: true}, nil
+	}
+	require.NoError(t, r.Register("custom", noop))
+	assert.Error(t, r.Register("custom", noop))
+	assert.Error(t, r.Register("shell", noop))
+}
+
+// ===== file step tests =====
+
+func TestStepFileContains(t *testing.T) {
+	sc := newStepContext(t, map[string]string{
+		"a.kt": "class A {\n    fun x() {\n        y()\n    }\n}\n",
+	})
+
+	out, err := runStep(t, sc, Step{
+		Type: "assert_file_contains",
+		Params: map[string]any{
+			"file": "a.kt",
+			// Indentation differs from the file on purpose.
+			"expected_content": "fun x() {\n    y()\n}\n",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	out, err = runStep(t, sc, Step{
+		Type: "assert_file_contains",
+		Params: map[string]any{
+			"file": "a.kt", "expected_content": []any{"class A", "class B"},
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+	assert.Contains(t, out.Message, "class B")
+
+	_, err = runStep(t, sc, Step{
+		Type:   "assert_file_contains",
+		Params: map[string]any{"file": "missing.kt", "expected_content": "x"},
+	})
+	assert.Error(t, err)
+}
+
+func TestStepFileNotContains(t *testing.T) {
+	sc := newStepContext(t, map[string]string{"v.txt": "version 1.0.0"})
+
+	out, err := runStep(t, sc, Step{
+		Type: "assert_file_not_contains",
+		Params: map[string]any{
+			"file": "v.txt", "forbidden_content": "2.19.3",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed)
+
+	out, err = runStep(t, sc, Step{
+		Type: "assert_file_not_contains",
+		Params: map[string]any{
+			"file": "v.txt", "forbidden_content": "1.0.0",
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+}
+
+func TestStepFileMatches(t *testing.T) {
+	sc := newStepContext(t, map[string]string{"log": "ok 1\nok 2\nfail 3\n"})
+
+	out, err := runStep(t, sc, Step{
+		Type: "assert_file_matches",
+		Params: map[string]any{
+			"file": "log", "pattern": `(?m)^ok \d$`, "min_matches": 2,
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed)
+	assert.Equal(t, 2, out.Actual)
+
+	out, err = runStep(t, sc, Step{
+		Type: "assert_file_matches",
+		Params: map[string]any{
+			"file": "log", "pattern": `ok`, "min_matches": float64(3),
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+
+	_, err = runStep(t, sc, Step{
+		Type:   "assert_file_matches",
+		Params: map[string]any{"file": "log", "pattern": `(`},
+	})
+	assert.Error(t, err)
+}
+
+// ===== command step tests =====
+
+func TestStepCommand(t *testing.T) {
+	sc := newStepContext(t, nil)
+	sc.Environment["GREETING"] = "hello"
+
+	out, err := runStep(t, sc, Step{
+		Type:                   "shell",
+		Params:                 map[string]any{"command": "echo $GREETING world"},
+		ExpectedOutputContains: []string{"hello world"},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	out, err = runStep(t, sc, Step{
+		Type:   "command",
+		Params: map[string]any{"command": "exit 4", "expected_exit_code": 4},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed)
+
+	out, err = runStep(t, sc, Step{
+		Type:   "shell",
+		Params: map[string]any{"command": "echo boom; exit 1"},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+	assert.Contains(t, out.Message, "boom")
+
+	out, err = runStep(t, sc, Step{
+		Type:                   "shell",
+		Params:                 map[string]any{"command": "echo other"},
+		ExpectedOutputContains: []string{"wanted"},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+
+	_, err = runStep(t, sc, Step{Type: "shell"})
+	assert.Error(t, err)
+}
+
+func TestStepCommand_RunCommandSeam(t *testing.T) {
+	original := runCommand
+	t.Cleanup(func() { runCommand = original })
+	var seen *exec.Cmd
+	runCommand = func(cmd *exec.Cmd) error {
+		seen = cmd
+		_, err := cmd.Stdout.Write([]byte("stubbed"))
+		return err
+	}
+
+	sc := newStepContext(t, nil)
+	sc.Environment["ZETA"] = "z"
+	sc.Environment["ALPHA"] = "a"
+	sc.Environment["MID"] = "m"
+	out, err := runStep(t, sc, Step{
+		Type:                   "shell",
+		Params:                 map[string]any{"command": "not-run"},
+		ExpectedOutputContains: []string{"stubbed"},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	require.NotNil(t, seen)
+	assert.Equal(t, []string{"bash", "-c", "not-run"}, seen.Args)
+	assert.Equal(t, []string{"ALPHA=a", "MID=m", "ZETA=z"},
+		seen.Env[len(seen.Env)-3:])
+}
+
+func TestGradleTask(t *testing.T) {
+	assert.Equal(t, ":app:test", gradleTask(map[string]any{"module": "app"}, "test"))
+	assert.Equal(t, "build", gradleTask(nil, "build"))
+}
+
+// ===== http_probe tests =====
+
+func TestStepHTTPProbe(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":"healthy"}`))
+		},
+	))
+	defer srv.Close()
+	sc := newStepContext(t, nil)
+
+	out, err := runStep(t, sc, Step{
+		Type: "http_probe",
+		Params: map[string]any{
+			"url": srv.URL + "/health", "body_contains": "healthy",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	out, err = runStep(t, sc, Step{
+		Type:   "http_probe",
+		Params: map[string]any{"url": srv.URL + "/missing"},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+	assert.Equal(t, http.StatusNotFound, out.Actual)
+
+	out, err = runStep(t, sc, Step{
+		Type: "http_probe",
+		Params: map[string]any{
+			"url": srv.URL + "/health", "body_contains": "degraded",
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+
+	_, err = runStep(t, sc, Step{Type: "http_probe"})
+	assert.Error(t, err)
+}
+
+// ===== json_path tests =====
+
+func TestStepJSONPath(t *testing.T) {
+	sc := newStepContext(t, map[string]string{
+		"pkg.json": `{"app":{"versions":[{"name":"1.0.0"}],"count":3}}`,
+	})
+
+	out, err := runStep(t, sc, Step{
+		Type: "json_path",
+		Params: map[string]any{
+			"file": "pkg.json", "path": "app.versions[0].name",
+			"expected": "1.0.0",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	// Paths resolve like assertion targets.
+	out, err = runStep(t, sc, Step{
+		Type: "json_path",
+		Params: map[string]any{
+			"file": "pkg.json", "path": `$.app["versions"][-1].name`,
+			"expected": "1.0.0",
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	out, err = runStep(t, sc, Step{
+		Type: "json_path",
+		Params: map[string]any{
+			"json": `{"a":{"b":1}}`, "path": "a.b", "expected": 1,
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed, out.Message)
+
+	out, err = runStep(t, sc, Step{
+		Type:   "json_path",
+		Params: map[string]any{"file": "pkg.json", "path": "app.count"},
+	})
+	require.NoError(t, err)
+	assert.True(t, out.Passed)
+
+	out, err = runStep(t, sc, Step{
+		Type: "json_path",
+		Params: map[string]any{
+			"file": "pkg.json", "path": "app.versions[5]",
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+	assert.Contains(t, out.Message, "out of range")
+
+	out, err = runStep(t, sc, Step{
+		Type: "json_path",
+		Params: map[string]any{
+			"file": "pkg.json", "path": "app.count", "expected": 4,
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, out.Passed)
+}
@@ -0,0 +1,276 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/history"
+	"digital.vasic.challenges/pkg/monitor"
+	"digital.vasic.challenges/pkg/report"
+)
+
+// Run summarizes the stored results of a run.
+type Run struct {
+	ID string `json:"id"`
+
+	// StartTime and EndTime span the results of the run.
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+
+	// RecordedAt is the time the last result of the run was
+	// stored; retention ages runs by it.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// MetricPoint is the value of a metric in one run.
+type MetricPoint struct {
+	RunID string    `json:"run_id"`
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Artifact is an output or artifact file of a stored result.
+type Artifact struct {
+	RunID       string       `json:"run_id"`
+	ChallengeID challenge.ID `json:"challenge_id"`
+	Name        string       `json:"name"`
+	Path        string       `json:"path"`
+}
+
+// Assertion is an assertion result of a stored result.
+type Assertion struct {
+	RunID       string       `json:"run_id"`
+	ChallengeID challenge.ID `json:"challenge_id"`
+	challenge.AssertionResult
+}
+
+// Runs returns the stored runs in the order they were first
+// recorded, oldest first.
+func (s *Store) Runs() []Run {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.runs()
+}
+
+// runs implements Runs. The caller holds the lock.
+func (s *Store) runs() []Run {
+	index := make(map[string]int)
+	first := make(map[string]time.Time)
+	var out []Run
+	for _, rec := range s.records {
+		i, ok := index[rec.RunID]
+		if !ok {
+			i = len(out)
+			index[rec.RunID] = i
+			first[rec.RunID] = rec.RecordedAt
+			out = append(out, Run{ID: rec.RunID})
+		}
+		run := &out[i]
+		res := rec.Result
+		run.Total++
+		switch {
+		case challenge.IsPassing(res.Status):
+			run.Passed++
+		case res.Status == challenge.StatusSkipped:
+			run.Skipped++
+		default:
+			run.Failed++
+		}
+		if !res.StartTime.IsZero() &&
+			(run.StartTime.IsZero() || res.StartTime.Before(run.StartTime)) {
+			run.StartTime = res.StartTime
+		}
+		if res.EndTime.After(run.EndTime) {
+			run.EndTime = res.EndTime
+		}
+		if rec.RecordedAt.Before(first[rec.RunID]) {
+			first[rec.RunID] = rec.RecordedAt
+		}
+		if rec.RecordedAt.After(run.RecordedAt) {
+			run.RecordedAt = rec.RecordedAt
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return first[out[i].ID].Before(first[out[j].ID])
+	})
+	return out
+}
+
+// RunIDs returns the IDs of the stored runs, oldest first. With
+// Dashboard it serves stored runs from a monitor server (see
+// monitor.WebSocketServer.SetRunSource).
+func (s *Store) RunIDs() []string {
+	runs := s.Runs()
+	ids := make([]string, len(runs))
+	for i, run := range runs {
+		ids[i] = run.ID
+	}
+	return ids
+}
+
+// Run returns the summary of a stored run.
+func (s *Store) Run(runID string) (Run, bool) {
+	for _, run := range s.Runs() {
+		if run.ID == runID {
+			return run, true
+		}
+	}
+	return Run{}, false
+}
+
+// Latest returns the run first recorded last, or false if the
+// store is empty.
+func (s *Store) Latest() (Run, bool) {
+	runs := s.Runs()
+	if len(runs) == 0 {
+		return Run{}, false
+	}
+	return runs[len(runs)-1], true
+}
+
+// Results returns the results of a run in the order they were
+// stored.
+func (s *Store) Results(runID string) []*challenge.Result {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*challenge.Result
+	for _, rec := range s.records {
+		if rec.RunID == runID {
+			out = append(out, rec.Result)
+		}
+	}
+	return out
+}
+
+// ChallengeRecords returns the last n stored results of a
+// challenge across runs, oldest first. n <= 0 returns all.
+func (s *Store) ChallengeRecords(id challenge.ID, n int) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Record
+	for _, rec := range s.records {
+		if rec.Result.ChallengeID == id {
+			out = append(out, rec)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].RecordedAt.Before(out[j].RecordedAt)
+	})
+	if n > 0 && len(out) > n {
+		out = out[len(out)-n:]
+	}
+	return out
+}
+
+// MetricTrend returns the values of a metric of a challenge in
+// its last n stored results that recorded it, oldest first.
+func (s *Store) MetricTrend(id challenge.ID, name string, n int) []MetricPoint {
+	var out []MetricPoint
+	for _, rec := range s.ChallengeRecords(id, 0) {
+		m, ok := rec.Result.Metrics[name]
+		if !ok {
+			continue
+		}
+		out = append(out, MetricPoint{
+			RunID: rec.RunID, Time: rec.Result.EndTime, Value: m.Value,
+		})
+	}
+	if n > 0 && len(out) > n {
+		out = out[len(out)-n:]
+	}
+	return out
+}
+
+// Artifacts returns the outputs and log artifacts of the
+// results of a run, by challenge and then name.
+func (s *Store) Artifacts(runID string) []Artifact {
+	var out []Artifact
+	for _, res := range s.Results(runID) {
+		start := len(out)
+		for name, path := range res.Outputs {
+			out = append(out, Artifact{runID, res.ChallengeID, name, path})
+		}
+		for name, path := range res.Logs.Artifacts {
+			out = append(out, Artifact{runID, res.ChallengeID, name, path})
+		}
+		added := out[start:]
+		sort.Slice(added, func(i, j int) bool {
+			return added[i].Name < added[j].Name
+		})
+	}
+	return out
+}
+
+// FailedAssertions returns the failed assertions of the
+// results of a run, nested sub-results flattened after their
+// parent.
+func (s *Store) FailedAssertions(runID string) []Assertion {
+	var out []Assertion
+	var walk func(id challenge.ID, results []challenge.AssertionResult)
+	walk = func(id challenge.ID, results []challenge.AssertionResult) {
+		for _, a := range results {
+			if !a.Passed {
+				out = append(out, Assertion{runID, id, a})
+			}
+			walk(id, a.Children)
+		}
+	}
+	for _, res := range s.Results(runID) {
+		walk(res.ChallengeID, res.Assertions)
+	}
+	return out
+}
+
+// History returns the stored results as run history, for the
+// trend section of report.HTMLReporter and
+// report.BundleReporter (see SetHistory).
+func (s *Store) History() *history.History {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]history.Entry, len(s.records))
+	for i, rec := range s.records {
+		entries[i] = history.NewEntry(rec.Result, "")
+	}
+	return history.New(entries)
+}
+
+// MasterSummary returns the master summary of a run, e.g. as
+// the baseline of report.CompareSummaries.
+func (s *Store) MasterSummary(runID string) (*report.MasterSummary, error) {
+	run, ok := s.Run(runID)
+	if !ok {
+		return nil, fmt.Errorf("unknown run: %s", runID)
+	}
+	summary := report.BuildMasterSummary(s.Results(runID))
+	summary.ID = run.ID
+	summary.GeneratedAt = run.RecordedAt
+	return summary, nil
+}
+
+// Dashboard returns the monitor dashboard state of a completed
+// run, for serving stored runs from a monitor server.
+func (s *Store) Dashboard(runID string) (*monitor.DashboardData, error) {
+	run, ok := s.Run(runID)
+	if !ok {
+		return nil, fmt.Errorf("unknown run: %s", runID)
+	}
+	d := monitor.NewDashboardData(run.ID)
+	d.StartTime = run.StartTime
+	for _, res := range s.Results(runID) {
+		d.UpdateFromResult(res)
+	}
+	d.Summary.Elapsed = run.EndTime.Sub(run.StartTime).String()
+	status := "completed"
+	if run.Failed > 0 {
+		status = "failed"
+	}
+	d.SetStatus(status)
+	return d, nil
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retention limits the runs a store keeps. Zero fields disable
+// their limit.
+type Retention struct {
+	// KeepRuns is the number of most recent runs to keep.
+	KeepRuns int `json:"keep_runs,omitempty"`
+
+	// MaxAge removes runs whose last result was stored longer
+	// ago than this.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+}
+
+// Prune removes the runs outside the retention policy and
+// rewrites the database file without them. It first reads the
+// results other processes stored, so they are kept or pruned
+// with the rest. It returns the IDs of the removed runs, oldest
+// first.
+func (s *Store) Prune(policy Retention) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockFile(s.path+".lock", true)
+	if err != nil {
+		return nil, fmt.Errorf("lock store: %w", err)
+	}
+	defer unlock()
+	if err := s.catchUp(); err != nil {
+		return nil, err
+	}
+
+	runs := s.runs()
+	drop := make(map[string]bool)
+	if policy.KeepRuns > 0 && len(runs) > policy.KeepRuns {
+		for _, run := range runs[:len(runs)-policy.KeepRuns] {
+			drop[run.ID] = true
+		}
+	}
+	if policy.MaxAge > 0 {
+		cutoff := s.now().Add(-policy.MaxAge)
+		for _, run := range runs {
+			if run.RecordedAt.Before(cutoff) {
+				drop[run.ID] = true
+			}
+		}
+	}
+	if len(drop) == 0 {
+		return nil, nil
+	}
+
+	var removed []string
+	for _, run := range runs {
+		if drop[run.ID] {
+			removed = append(removed, run.ID)
+		}
+	}
+	kept := s.records[:0:0]
+	for _, rec := range s.records {
+		if !drop[rec.RunID] {
+			kept = append(kept, rec)
+		}
+	}
+	previous, index := s.records, s.index
+	s.records = kept
+	s.reindex()
+	if err := s.rewrite(); err != nil {
+		s.records, s.index = previous, index
+		return nil, err
+	}
+	return removed, nil
+}
@@ -0,0 +1,328 @@
+// Package store persists challenge results across runs in a
+// local file database. Every result is kept whole, with its
+// assertions, metrics, outputs and artifacts, under the run it
+// belongs to. The store records results as the runner produces
+// them (see Recorder), prunes old runs by a retention policy
+// and answers the queries of the reporters and the monitor
+// dashboard.
+//
+// The database is a single JSON-lines file of Records, so it
+// needs no driver. Writers hold an advisory lock on a sibling
+// ".lock" file and first read what other processes appended,
+// so several runs can share one database. A record cut short
+// by an interrupted write is ignored and overwritten by the
+// next write.
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"digital.vasic.challenges/pkg/challenge"
+)
+
+// Record is one stored challenge result. Each record is one
+// JSON line of the database file.
+type Record struct {
+	// RunID identifies the run the result belongs to.
+	RunID string `json:"run_id"`
+
+	// RecordedAt is the time the result was stored.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Result is the full challenge result.
+	Result *challenge.Result `json:"result"`
+}
+
+// recordKey identifies the record of a challenge in a run.
+type recordKey struct {
+	runID string
+	id    challenge.ID
+}
+
+// Store is a file-backed results database. Records are held in
+// memory in the order they were first stored; a later result of
+// a challenge replaces the earlier one of the same run in
+// place, e.g. when an interrupted run is resumed. Queries read
+// the records as of the last Open, Refresh, Save or Prune. It
+// is safe for concurrent use.
+type Store struct {
+	mu      sync.RWMutex
+	path    string
+	records []Record
+	index   map[recordKey]int
+	now     func() time.Time
+
+	// file is the database file last read, offset the end of
+	// its last complete record and lines the number of lines
+	// up to offset.
+	file   os.FileInfo
+	offset int64
+	lines  int
+}
+
+// Open opens the database at path, creating its directory and
+// loading its records. A missing file is an empty store. A
+// line that is not a valid record is an error naming the file
+// and line, unless it is the last one, which an interrupted
+// write may have cut short.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+	s := &Store{path: path, now: time.Now}
+	s.reset()
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Path returns the database file path.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Len returns the number of stored results.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Refresh reads the records other processes stored since the
+// database was last read.
+func (s *Store) Refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := lockFile(s.path+".lock", false)
+	if err != nil {
+		return fmt.Errorf("lock store: %w", err)
+	}
+	defer unlock()
+	return s.catchUp()
+}
+
+// Save stores results under runID and appends them to the
+// database file.
+func (s *Store) Save(runID string, results ...*challenge.Result) error {
+	if runID == "" {
+		return fmt.Errorf("save results: empty run ID")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf bytes.Buffer
+	recs := make([]Record, 0, len(results))
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		rec := Record{RunID: runID, RecordedAt: s.now(), Result: result}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf(
+				"encode result %s: %w", result.ChallengeID, err,
+			)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+		recs = append(recs, rec)
+	}
+	if len(recs) == 0 {
+		return nil
+	}
+
+	unlock, err := lockFile(s.path+".lock", true)
+	if err != nil {
+		return fmt.Errorf("lock store: %w", err)
+	}
+	defer unlock()
+	if err := s.catchUp(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open store: %w", err)
+	}
+	// Overwrite a record cut short by an interrupted write.
+	if err := file.Truncate(s.offset); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write store: %w", err)
+	}
+	if _, err := file.WriteAt(buf.Bytes(), s.offset); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write store: %w", err)
+	}
+	info, err := file.Stat()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("write store: %w", err)
+	}
+	for _, rec := range recs {
+		s.put(rec)
+	}
+	s.file = info
+	s.offset += int64(buf.Len())
+	s.lines += len(recs)
+	return nil
+}
+
+// Recorder returns a hook that saves every result it receives
+// under runID. Pass it to runner.WithResultHook to store the
+// results of a run as they complete.
+func (s *Store) Recorder(
+	runID string,
+) func(context.Context, *challenge.Result) error {
+	return func(_ context.Context, result *challenge.Result) error {
+		return s.Save(runID, result)
+	}
+}
+
+// reset drops the records in memory. The caller holds the
+// write lock.
+func (s *Store) reset() {
+	s.records = nil
+	s.index = make(map[recordKey]int)
+	s.file = nil
+	s.offset = 0
+	s.lines = 0
+}
+
+// reindex rebuilds the index of the records. The caller holds
+// the write lock.
+func (s *Store) reindex() {
+	s.index = make(map[recordKey]int, len(s.records))
+	for i, rec := range s.records {
+		s.index[recordKey{rec.RunID, rec.Result.ChallengeID}] = i
+	}
+}
+
+// put adds rec, replacing the record of the same run and
+// challenge. The caller holds the write lock.
+func (s *Store) put(rec Record) {
+	key := recordKey{rec.RunID, rec.Result.ChallengeID}
+	if i, ok := s.index[key]; ok {
+		s.records[i] = rec
+		return
+	}
+	s.index[key] = len(s.records)
+	s.records = append(s.records, rec)
+}
+
+// catchUp reads the records appended to the database file since
+// it was last read, or all of them when the file was replaced
+// or truncated, e.g. by another process pruning it. The caller
+// holds the write lock and the file lock.
+func (s *Store) catchUp() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		s.reset()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	if s.file == nil || !os.SameFile(s.file, info) ||
+		info.Size() < s.offset {
+		s.reset()
+	}
+	if _, err := file.Seek(s.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	if err := s.parse(data); err != nil {
+		return err
+	}
+	s.file = info
+	return nil
+}
+
+// parse adds the records of data, read at offset. offset and
+// lines advance past the last complete line; an unterminated or
+// invalid last line is left for the next write to overwrite.
+// The caller holds the write lock.
+func (s *Store) parse(data []byte) error {
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			// Cut short by an interrupted write, or complete
+			// but without its newline: either way the next
+			// write overwrites it rather than appending to it.
+			return nil
+		}
+		line, rest := data[:end+1], data[end+1:]
+		if text := bytes.TrimSpace(line); len(text) > 0 {
+			var rec Record
+			err := json.Unmarshal(text, &rec)
+			if err == nil && rec.Result == nil {
+				err = fmt.Errorf("missing result")
+			}
+			if err != nil {
+				if len(bytes.TrimSpace(rest)) == 0 {
+					// Cut short by an interrupted write.
+					return nil
+				}
+				return fmt.Errorf(
+					"%s:%d: invalid store record: %w",
+					s.path, s.lines+1, err,
+				)
+			}
+			s.put(rec)
+		}
+		data = rest
+		s.offset += int64(len(line))
+		s.lines++
+	}
+	return nil
+}
+
+// rewrite replaces the database file with the records in
+// memory. The caller holds the write lock and the file lock.
+func (s *Store) rewrite() error {
+	var buf bytes.Buffer
+	for _, rec := range s.records {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf(
+				"encode result %s: %w", rec.Result.ChallengeID, err,
+			)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("write store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace store: %w", err)
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("replace store: %w", err)
+	}
+	s.file = info
+	s.offset = int64(buf.Len())
+	s.lines = len(s.records)
+	return nil
+}
@@ -0,0 +1,347 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"digital.vasic.challenges/pkg/challenge"
+	"digital.vasic.challenges/pkg/monitor"
+)
+
+var base = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// clock returns a now function that advances by a minute on
+// every call, starting at base.
+func clock() func() time.Time {
+	t := base
+	return func() time.Time {
+		t = t.Add(time.Minute)
+		return t
+	}
+}
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "db", "results.jsonl"))
+	require.NoError(t, err)
+	s.now = clock()
+	return s
+}
+
+func result(id challenge.ID, status string, latency float64) *challenge.Result {
+	return &challenge.Result{
+		ChallengeID:   id,
+		ChallengeName: "Challenge " + string(id),
+		Status:        status,
+		StartTime:     base,
+		EndTime:       base.Add(2 * time.Second),
+		Duration:      2 * time.Second,
+		Assertions: []challenge.AssertionResult{
+			{Type: "not_empty", Target: "body", Passed: true},
+			{
+				Type: "all_of", Target: "body",
+				Passed: status == challenge.StatusPassed,
+				Children: []challenge.AssertionResult{
+					{Type: "contains", Target: "body",
+						Passed: status == challenge.StatusPassed},
+				},
+			},
+		},
+		Metrics: map[string]challenge.MetricValue{
+			"latency": {Name: "latency", Value: latency, Unit: "ms"},
+		},
+		Outputs: map[string]string{"screenshot": "/tmp/" + string(id) + ".png"},
+		Logs: challenge.LogPaths{
+			Artifacts: map[string]string{"har": "/tmp/" + string(id) + ".har"},
+		},
+	}
+}
+
+// ===== Open and Save tests =====
+
+func TestOpen_Empty(t *testing.T) {
+	s := openStore(t)
+	assert.Zero(t, s.Len())
+	assert.Empty(t, s.Runs())
+	_, ok := s.Latest()
+	assert.False(t, ok)
+	assert.DirExists(t, filepath.Dir(s.Path()))
+}
+
+func TestSave_Reopen(t *testing.T) {
+	s := openStore(t)
+	require.NoError(t, s.Save("run-1",
+		result("a", challenge.StatusPassed, 100),
+		result("b", challenge.StatusFailed, 200),
+	))
+	// A resumed run replaces the earlier result of "b".
+	require.NoError(t, s.Save("run-1", result("b", challenge.StatusPassed, 150)))
+	require.NoError(t, s.Save("run-2", nil))
+	assert.Equal(t, 2, s.Len())
+
+	reopened, err := Open(s.Path())
+	require.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+	results := reopened.Results("run-1")
+	require.Len(t, results, 2)
+	assert.Equal(t, challenge.ID("b"), results[1].ChallengeID)
+	assert.Equal(t, challenge.StatusPassed, results[1].Status)
+	assert.Equal(t, 150.0, results[1].Metrics["latency"].Value)
+	assert.Len(t, results[1].Assertions[1].Children, 1)
+}
+
+func TestSave_Errors(t *testing.T) {
+	s := openStore(t)
+	assert.Error(t, s.Save("", result("a", challenge.StatusPassed, 1)))
+
+	s.path = filepath.Join(t.TempDir(), "missing", "results.jsonl")
+	err := s.Save("run-1", result("a", challenge.StatusPassed, 1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock store")
+	assert.Zero(t, s.Len())
+}
+
+func TestOpen_InvalidRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(
+		`{"run_id":"r","result":{"challenge_id":"a"}}`+"\n\n"+
+			`{"run_id":"r"}`+"\n"+
+			`{"run_id":"r","result":{"challenge_id":"b"}}`+"\n",
+	), 0644))
+	_, err := Open(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "results.jsonl:3: invalid store record")
+}
+
+func TestOpen_TornFinalRecord(t *testing.T) {
+	valid := `{"run_id":"r","result":{"challenge_id":"a"}}` + "\n"
+	for name, tail := range map[string]string{
+		"unterminated": `{"run_id":"r","res`,
+		"terminated":   `{"run_id":"r","res` + "\n\n",
+		"no newline":   `{"run_id":"r","result":{"challenge_id":"b"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "results.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(valid+tail), 0644))
+			s, err := Open(path)
+			require.NoError(t, err)
+			assert.Equal(t, 1, s.Len())
+
+			// The next write replaces the torn record.
+			require.NoError(t, s.Save("r", result("c", challenge.StatusPassed, 1)))
+			reopened, err := Open(path)
+			require.NoError(t, err)
+			results := reopened.Results("r")
+			require.Len(t, results, 2)
+			assert.Equal(t, challenge.ID("c"), results[1].ChallengeID)
+		})
+	}
+}
+
+func TestSave_SharedBetweenStores(t *testing.T) {
+	first := openStore(t)
+	second, err := Open(first.Path())
+	require.NoError(t, err)
+	second.now = clock()
+
+	require.NoError(t, first.Save("run-1", result("a", challenge.StatusPassed, 1)))
+	require.NoError(t, second.Save("run-2", result("a", challenge.StatusPassed, 2)))
+	require.NoError(t, first.Save("run-1", result("b", challenge.StatusPassed, 3)))
+	assert.Equal(t, 3, first.Len())
+
+	// second has not read b yet; Refresh catches up.
+	assert.Equal(t, 2, second.Len())
+	require.NoError(t, second.Refresh())
+	assert.Equal(t, 3, second.Len())
+
+	// Pruning in first keeps the run second stored, and second
+	// reloads the rewritten file.
+	require.NoError(t, second.Save("run-3", result("a", challenge.StatusPassed, 4)))
+	removed, err := first.Prune(Retention{KeepRuns: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-1"}, removed)
+	assert.Equal(t, []string{"run-2", "run-3"}, runIDs(first.Runs()))
+	require.NoError(t, second.Refresh())
+	assert.Equal(t, []string{"run-2", "run-3"}, runIDs(second.Runs()))
+}
+
+func runIDs(runs []Run) []string {
+	var ids []string
+	for _, run := range runs {
+		ids = append(ids, run.ID)
+	}
+	return ids
+}
+
+func TestRecorder(t *testing.T) {
+	s := openStore(t)
+	hook := s.Recorder("run-1")
+	require.NoError(t, hook(context.Background(), result("a", challenge.StatusPassed, 1)))
+	assert.Len(t, s.Results("run-1"), 1)
+}
+
+// ===== Query tests =====
+
+// seed stores three runs: run-1 (a passes, b fails), run-2 (a
+// passes, b is skipped) and run-3 (a fails).
+func seed(t *testing.T) *Store {
+	t.Helper()
+	s := openStore(t)
+	require.NoError(t, s.Save("run-1",
+		result("a", challenge.StatusPassed, 100),
+		result("b", challenge.StatusFailed, 50),
+	))
+	skipped := &challenge.Result{ChallengeID: "b", Status: challenge.StatusSkipped}
+	require.NoError(t, s.Save("run-2",
+		result("a", challenge.StatusPassed, 120), skipped,
+	))
+	require.NoError(t, s.Save("run-3", result("a", challenge.StatusFailed, 300)))
+	return s
+}
+
+func TestStore_Runs(t *testing.T) {
+	s := seed(t)
+	runs := s.Runs()
+	require.Len(t, runs, 3)
+	assert.Equal(t, Run{
+		ID: "run-1", StartTime: base, EndTime: base.Add(2 * time.Second),
+		RecordedAt: base.Add(2 * time.Minute),
+		Total:      2, Passed: 1, Failed: 1,
+	}, runs[0])
+	assert.Equal(t, 1, runs[1].Skipped)
+
+	assert.Equal(t, []string{"run-1", "run-2", "run-3"}, s.RunIDs())
+	assert.Equal(t, []string{"run-1", "run-2", "run-3"}, runIDs(runs))
+
+	latest, ok := s.Latest()
+	require.True(t, ok)
+	assert.Equal(t, "run-3", latest.ID)
+	_, ok = s.Run("missing")
+	assert.False(t, ok)
+}
+
+func TestStore_ChallengeRecords(t *testing.T) {
+	s := seed(t)
+	recs := s.ChallengeRecords("a", 2)
+	require.Len(t, recs, 2)
+	assert.Equal(t, "run-2", recs[0].RunID)
+	assert.Equal(t, "run-3", recs[1].RunID)
+	assert.Len(t, s.ChallengeRecords("a", 0), 3)
+}
+
+func TestStore_MetricTrend(t *testing.T) {
+	s := seed(t)
+	points := s.MetricTrend("a", "latency", 0)
+	require.Len(t, points, 3)
+	assert.Equal(t, []float64{100, 120, 300},
+		[]float64{points[0].Value, points[1].Value, points[2].Value})
+	// The skipped result of b recorded no metric.
+	assert.Len(t, s.MetricTrend("b", "latency", 5), 1)
+	assert.Len(t, s.MetricTrend("a", "latency", 1), 1)
+}
+
+func TestStore_Artifacts(t *testing.T) {
+	s := seed(t)
+	assert.Equal(t, []Artifact{
+		{"run-1", "a", "har", "/tmp/a.har"},
+		{"run-1", "a", "screenshot", "/tmp/a.png"},
+		{"run-1", "b", "har", "/tmp/b.har"},
+		{"run-1", "b", "screenshot", "/tmp/b.png"},
+	}, s.Artifacts("run-1"))
+}
+
+func TestStore_FailedAssertions(t *testing.T) {
+	s := seed(t)
+	failed := s.FailedAssertions("run-1")
+	require.Len(t, failed, 2)
+	assert.Equal(t, challenge.ID("b"), failed[0].ChallengeID)
+	assert.Equal(t, "all_of", failed[0].Type)
+	assert.Equal(t, "contains", failed[1].Type)
+	assert.Empty(t, s.FailedAssertions("run-2"))
+}
+
+func TestStore_History(t *testing.T) {
+	s := seed(t)
+	h := s.History()
+	assert.Equal(t, 5, h.Len())
+	assert.InDelta(t, 2.0/3, h.PassRate("a", 0), 1e-9)
+	_, failing := h.FirstFailing("a")
+	assert.True(t, failing)
+}
+
+func TestStore_MasterSummary(t *testing.T) {
+	s := seed(t)
+	summary, err := s.MasterSummary("run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", summary.ID)
+	assert.Equal(t, 2, summary.TotalChallenges)
+	assert.Equal(t, 1, summary.FailedChallenges)
+	assert.Equal(t, 100.0, summary.Challenges[0].Metrics["latency"])
+
+	_, err = s.MasterSummary("missing")
+	assert.EqualError(t, err, "unknown run: missing")
+}
+
+func TestStore_Dashboard(t *testing.T) {
+	s := seed(t)
+	var _ monitor.RunSource = s
+
+	d, err := s.Dashboard("run-2")
+	require.NoError(t, err)
+	snap := d.Snapshot()
+	assert.Equal(t, "run-2", snap.RunID)
+	assert.Equal(t, "completed", snap.Status)
+	assert.Equal(t, 1, snap.Summary.Passed)
+	assert.Equal(t, 1, snap.Summary.Skipped)
+	assert.Equal(t, "skipped", snap.Challenges["b"].Status)
+
+	d, err = s.Dashboard("run-3")
+	require.NoError(t, err)
+	assert.Equal(t, "failed", d.Snapshot().Status)
+
+	_, err = s.Dashboard("missing")
+	assert.Error(t, err)
+}
+
+// ===== Retention tests =====
+
+func TestStore_Prune_KeepRuns(t *testing.T) {
+	s := seed(t)
+	removed, err := s.Prune(Retention{KeepRuns: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-1"}, removed)
+	assert.Len(t, s.Runs(), 2)
+
+	reopened, err := Open(s.Path())
+	require.NoError(t, err)
+	assert.Equal(t, 3, reopened.Len())
+	assert.Empty(t, reopened.Results("run-1"))
+	assert.NoFileExists(t, s.Path()+".tmp")
+}
+
+func TestStore_Prune_MaxAge(t *testing.T) {
+	s := seed(t)
+	// Runs were recorded at base+2m, +4m and +5m; now is +6m.
+	removed, err := s.Prune(Retention{MaxAge: 90 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"run-1", "run-2"}, removed)
+
+	removed, err = s.Prune(Retention{KeepRuns: 5, MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.Len(t, s.Runs(), 1)
+}
+
+func TestStore_Prune_WriteError(t *testing.T) {
+	s := seed(t)
+	s.path = filepath.Join(t.TempDir(), "missing", "results.jsonl")
+	_, err := s.Prune(Retention{KeepRuns: 1})
+	require.Error(t, err)
+	assert.Len(t, s.Runs(), 3)
+}
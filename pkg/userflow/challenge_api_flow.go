@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"digital.vasic.challenges/pkg/assertion"
 	"digital.vasic.challenges/pkg/challenge"
 )
 
@@ -455,12 +456,24 @@ func evaluateStepAssertion(
 		return false
 	case "not_empty":
 		return len(body) > 0
+	case assertion.TypeJSONSchema, assertion.TypeJSONShapeMatches:
+		return structureMatches(sa, body)
 	default:
 		// Unknown assertion types are treated as failed.
 		return false
 	}
 }
 
+// structureMatches reports whether a JSON response satisfies a
+// json_schema or json_shape_matches step assertion.
+func structureMatches(sa StepAssertion, response any) bool {
+	r := assertion.NewEngine().Evaluate(assertion.Definition{
+		Type:  sa.Type,
+		Value: sa.Value,
+	}, response)
+	return r.Passed
+}
+
 // loginActual returns the actual value string for a login
 // assertion.
 func loginActual(token string, err error) string {
@@ -639,6 +639,36 @@ func TestEvaluateStepAssertion(t *testing.T) {
 			body:     []byte(`ok`),
 			expected: false,
 		},
+		{
+			name: "json_schema pass",
+			sa: StepAssertion{
+				Type:  "json_schema",
+				Value: `{"type": "object", "required": ["id"]}`,
+			},
+			code:     200,
+			body:     []byte(`{"id": 1}`),
+			expected: true,
+		},
+		{
+			name: "json_schema fail",
+			sa: StepAssertion{
+				Type:  "json_schema",
+				Value: `{"type": "object", "required": ["id"]}`,
+			},
+			code:     200,
+			body:     []byte(`{"name": "x"}`),
+			expected: false,
+		},
+		{
+			name: "json_shape_matches pass",
+			sa: StepAssertion{
+				Type:  "json_shape_matches",
+				Value: `{"id": 0, "tags": [""]}`,
+			},
+			code:     200,
+			body:     []byte(`{"id": 7, "tags": ["a", "b"]}`),
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"digital.vasic.challenges/pkg/assertion"
 	"digital.vasic.challenges/pkg/challenge"
 )
 
@@ -416,6 +417,8 @@ func evaluateGRPCStepAssertion(
 		return false
 	case "not_empty":
 		return response != ""
+	case assertion.TypeJSONSchema, assertion.TypeJSONShapeMatches:
+		return structureMatches(sa, response)
 	case "stream_count":
 		if expected, ok := sa.Value.(float64); ok {
 			return len(streamResponses) >= int(expected)
@@ -412,6 +412,15 @@ func TestEvaluateGRPCStepAssertion(t *testing.T) {
 			stream: []string{"r1"},
 			want:   false,
 		},
+		{
+			name: "json_shape_matches_type_mismatch",
+			sa: StepAssertion{
+				Type:  "json_shape_matches",
+				Value: `{"status": ""}`,
+			},
+			response: `{"status": 1}`,
+			want:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -32,7 +32,8 @@ func tr(
 }
 
 // RegisterEvaluators registers all 19 userflow assertion
-// evaluators with the given engine.
+// evaluators with the given engine, described in its Catalog as
+// owned by the userflow plugin.
 func RegisterEvaluators(
 	engine *assertion.DefaultEngine,
 ) error {
@@ -58,8 +59,12 @@ func RegisterEvaluators(
 		"generated_test_coverage": evaluateGeneratedTestCoverage,
 	}
 
+	descriptors := evaluatorDescriptors()
 	for name, eval := range evaluators {
-		if err := engine.Register(name, eval); err != nil {
+		desc := descriptors[name]
+		desc.Name = name
+		desc.Plugin = PluginName
+		if err := engine.RegisterDescriptor(desc, eval); err != nil {
 			return fmt.Errorf(
 				"register evaluator %s: %w", name, err,
 			)
@@ -68,6 +73,98 @@ func RegisterEvaluators(
 	return nil
 }
 
+// evaluatorDescriptors documents the userflow evaluators for
+// the assertion catalog.
+func evaluatorDescriptors() map[string]assertion.Descriptor {
+	flag := func(desc, example string) assertion.Descriptor {
+		return assertion.Descriptor{
+			Description: desc,
+			Target:      assertion.ArgBool,
+			Examples:    []string{example},
+		}
+	}
+	threshold := func(
+		kind assertion.ArgKind, target assertion.ArgKind,
+		desc, example string,
+	) assertion.Descriptor {
+		return assertion.Descriptor{
+			Description: desc,
+			Target:      target,
+			Value: assertion.ValueSchema{
+				Kinds:    []assertion.ArgKind{kind},
+				Required: true,
+			},
+			Examples: []string{example},
+		}
+	}
+	return map[string]assertion.Descriptor{
+		"build_succeeds": flag("The build succeeded.",
+			"build | build_succeeds()"),
+		"all_tests_pass": {
+			Description: "The test failure count is 0.",
+			Target:      assertion.ArgNumber,
+			Examples:    []string{"failures | all_tests_pass()"},
+		},
+		"lint_passes": flag("The linter passed.",
+			"lint | lint_passes()"),
+		"app_launches": flag("The app launched.",
+			"launched | app_launches()"),
+		"app_stable": flag("The app stayed up for the stability window.",
+			"stable | app_stable()"),
+		"status_code": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"The HTTP status code equals value.",
+			"status | status_code(200)"),
+		"response_contains": threshold(assertion.ArgString, assertion.ArgString,
+			"The response contains value, matching case.",
+			`body | response_contains("ok")`),
+		"response_not_empty": {
+			Description: "The response string or bytes are not empty.",
+			Target:      assertion.ArgString,
+			Examples:    []string{"body | response_not_empty()"},
+		},
+		"json_field_equals": {
+			Description: "The field equals value, comparing their string forms.",
+			Target:      assertion.ArgAny,
+			Value: assertion.ValueSchema{
+				Kinds: []assertion.ArgKind{assertion.ArgAny},
+			},
+			Examples: []string{`user.name | json_field_equals("admin")`},
+		},
+		"screenshot_exists": {
+			Description: "The screenshot bytes are not empty.",
+			Target:      assertion.ArgAny,
+			Examples:    []string{"screenshot | screenshot_exists()"},
+		},
+		"flow_completes": flag("The flow ran to completion.",
+			"completed | flow_completes()"),
+		"within_duration": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"The duration in milliseconds is at most value.",
+			"duration_ms | within_duration(5000)"),
+		"vision_element_detected": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"At least value elements were detected.",
+			"elements | vision_element_detected(1)"),
+		"vision_confidence_above": threshold(assertion.ArgNumber, assertion.ArgNumber,
+			"The vision confidence is at least value.",
+			"confidence | vision_confidence_above(0.8)"),
+		"video_recorded": flag("A video was recorded.",
+			"recorded | video_recorded()"),
+		"video_duration_within": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"The video is at most value milliseconds long.",
+			"video_ms | video_duration_within(60000)"),
+		"video_integrity": {
+			Description: "The recording has a non-zero file size, duration and frame count.",
+			Target:      assertion.ArgObject,
+			Examples:    []string{"recording | video_integrity()"},
+		},
+		"tests_generated": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"At least value tests were generated.",
+			"generated | tests_generated(10)"),
+		"generated_test_coverage": threshold(assertion.ArgInteger, assertion.ArgNumber,
+			"The generated tests cover at least value categories.",
+			"categories | generated_test_coverage(3)"),
+	}
+}
+
 // toIntVal converts a value to int. Supports int, int64,
 // float64, and float32.
 func toIntVal(v any) (int, bool) {